@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/what-writers-like/backend/internal/infrastructure/config"
+	"github.com/what-writers-like/backend/internal/infrastructure/database"
+	"github.com/what-writers-like/backend/internal/repository/gorm"
+	"github.com/what-writers-like/backend/internal/service"
+)
+
+// backup writes a snapshot bundle of every record, as GET /api/v1/backup
+// does, and lists its tables on standard error.
+func main() {
+	output := flag.String("o", "", "file to write to instead of standard output")
+	flag.Parse()
+
+	if err := run(*output); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(output string) error {
+	cfg, err := config.NewConfig()
+	if err != nil {
+		return err
+	}
+	db, err := database.NewDatabase(cfg)
+	if err != nil {
+		return err
+	}
+	backupService := service.NewBackupService(gorm.NewTransactor(db))
+
+	if output == "" {
+		return backup(backupService, os.Stdout)
+	}
+	file, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	if err := backup(backupService, file); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func backup(backupService service.BackupService, w io.Writer) error {
+	manifest, err := backupService.Backup(w)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "snapshot of %s, schema version %d\n",
+		manifest.CreatedAt.Format("2006-01-02 15:04:05Z"), manifest.SchemaVersion)
+	for _, table := range manifest.Tables {
+		fmt.Fprintf(os.Stderr, "  %-15s %8d rows  sha256 %s\n", table.Name, table.Rows, table.SHA256)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/infrastructure/config"
+	"github.com/what-writers-like/backend/internal/infrastructure/database"
+	"github.com/what-writers-like/backend/internal/repository/gorm"
+	"github.com/what-writers-like/backend/internal/service"
+)
+
+// graphexport writes the opinion graph as GraphML, GEXF, DOT or a Neo4j
+// Cypher script, as GET /api/v1/graph/export does, taking the same filters.
+func main() {
+	format := flag.String("format", string(service.GraphFormatGraphML), "graphml, gexf, dot or cypher")
+	writerIDs := flag.String("writer-ids", "", "comma-separated IDs of the writers whose opinions to export")
+	workIDs := flag.String("work-ids", "", "comma-separated IDs of the works whose opinions to export")
+	sentiment := flag.String("sentiment", "", "comma-separated sentiments of the opinions to export")
+	output := flag.String("o", "", "file to write to instead of standard output")
+	flag.Parse()
+
+	if err := run(*format, *writerIDs, *workIDs, *sentiment, *output); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(formatName, writerIDs, workIDs, sentiment, output string) error {
+	format, err := service.ParseGraphFormat(formatName)
+	if err != nil {
+		return err
+	}
+	filter := service.GraphFilter{}
+	if filter.WriterIDs, err = parseIDs(writerIDs); err != nil {
+		return fmt.Errorf("invalid -writer-ids: %w", err)
+	}
+	if filter.WorkIDs, err = parseIDs(workIDs); err != nil {
+		return fmt.Errorf("invalid -work-ids: %w", err)
+	}
+	if filter.Sentiments, err = domain.ParseSentiments(sentiment); err != nil {
+		return err
+	}
+
+	cfg, err := config.NewConfig()
+	if err != nil {
+		return err
+	}
+	db, err := database.NewDatabase(cfg)
+	if err != nil {
+		return err
+	}
+	graphService := service.NewGraphService(
+		gorm.NewWriterRepository(db),
+		gorm.NewWorkRepository(db),
+		gorm.NewOpinionRepository(db),
+		gorm.NewGraphRepository(db),
+	)
+
+	exportService := service.NewGraphExportService(graphService)
+	if output == "" {
+		return exportService.ExportGraph(os.Stdout, filter, format)
+	}
+	file, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	if err := exportService.ExportGraph(file, filter, format); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func parseIDs(raw string) ([]uint64, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	var ids []uint64
+	for _, part := range strings.Split(raw, ",") {
+		id, err := strconv.ParseUint(strings.TrimSpace(part), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/what-writers-like/backend/internal/infrastructure/config"
+	"github.com/what-writers-like/backend/internal/infrastructure/database"
+	"github.com/what-writers-like/backend/internal/repository/gorm"
+	"github.com/what-writers-like/backend/internal/service"
+)
+
+const usage = "usage: restore -mode replace|merge [-dry-run] [-actor name] <bundle.zip>"
+
+// restore loads a snapshot bundle written by backup, as POST
+// /api/v1/restore does, and prints what it did, or with -dry-run what it
+// would have done. It exits non-zero when any row breaks a rule, in which
+// case nothing is written.
+func main() {
+	mode := flag.String("mode", "", "replace, to delete the records the bundle does not hold, or merge, to keep them")
+	dryRun := flag.Bool("dry-run", false, "check the bundle and report without writing")
+	actor := flag.String("actor", service.SystemActor, "who the audit log records the changes as made by")
+	flag.Parse()
+
+	if err := run(*mode, *dryRun, *actor, flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(modeName string, dryRun bool, actor string, args []string) error {
+	if len(args) != 1 {
+		return errors.New(usage)
+	}
+	mode, err := service.ParseRestoreMode(modeName)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.NewConfig()
+	if err != nil {
+		return err
+	}
+	db, err := database.NewDatabase(cfg)
+	if err != nil {
+		return err
+	}
+	backupService := service.NewBackupService(gorm.NewTransactor(db))
+
+	ctx := service.WithActor(context.Background(), actor)
+	options := service.RestoreOptions{Mode: mode, DryRun: dryRun}
+	report, err := backupService.Restore(ctx, file, info.Size(), options)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("snapshot of %s, schema version %d, %s\n",
+		report.Snapshot.CreatedAt.Format("2006-01-02 15:04:05Z"), report.Snapshot.SchemaVersion, report.Mode)
+	for _, table := range report.Tables {
+		fmt.Printf("  %-15s %8d rows  %d created, %d updated, %d unchanged, %d deleted\n",
+			table.Name, table.Rows, table.Created, table.Updated, table.Unchanged, table.Deleted)
+	}
+	for _, e := range report.Errors {
+		fmt.Printf("%s %d: %s\n", e.Table, e.ID, e.Message)
+	}
+
+	switch {
+	case len(report.Errors) > 0:
+		return fmt.Errorf("%d rows break the rules; nothing was restored", len(report.Errors))
+	case report.DryRun:
+		fmt.Println("dry run; nothing was restored")
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"log"
 	"net/http"
 	"time"
 
@@ -13,23 +14,46 @@ import (
 	"github.com/what-writers-like/backend/internal/infrastructure/config"
 	"github.com/what-writers-like/backend/internal/infrastructure/database"
 	"github.com/what-writers-like/backend/internal/repository/gorm"
+	"github.com/what-writers-like/backend/internal/repository/memory"
 	"github.com/what-writers-like/backend/internal/service"
 )
 
 func main() {
+	cfg, err := config.NewConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	fx.New(
+		fx.Supply(cfg),
+		RepositoryOptions(cfg),
 		fx.Provide(
-			config.NewConfig,
-			database.NewDatabase,
-			gorm.NewWriterRepository,
-			gorm.NewWorkRepository,
-			gorm.NewOpinionRepository,
 			service.NewWriterService,
 			service.NewWorkService,
 			service.NewOpinionService,
+			service.NewSourceService,
+			service.NewWriterAliasService,
+			service.NewSearchService,
+			service.NewGraphService,
+			service.NewGraphExportService,
+			service.NewAuditService,
+			service.NewAuthService,
+			service.NewImportService,
+			service.NewExportService,
+			service.NewBackupService,
 			handler.NewWriterHandler,
 			handler.NewWorkHandler,
 			handler.NewOpinionHandler,
+			handler.NewSourceHandler,
+			handler.NewWriterAliasHandler,
+			handler.NewSearchHandler,
+			handler.NewGraphHandler,
+			handler.NewAuditHandler,
+			handler.NewImportHandler,
+			handler.NewExportHandler,
+			handler.NewBackupHandler,
+			handler.NewAuthHandler,
+			handler.NewAuthMiddleware,
 			handler.SetupRouter,
 			NewHTTPServer,
 		),
@@ -37,6 +61,39 @@ func main() {
 	).Run()
 }
 
+// RepositoryOptions provides the repositories of the configured storage
+// backend. The in-memory backend starts empty and loses its data on exit.
+func RepositoryOptions(cfg *config.Config) fx.Option {
+	if cfg.Storage == config.StorageMemory {
+		return fx.Provide(
+			memory.NewStore,
+			memory.NewWriterRepository,
+			memory.NewWorkRepository,
+			memory.NewOpinionRepository,
+			memory.NewGraphRepository,
+			memory.NewAuditRepository,
+			memory.NewOpinionRevisionRepository,
+			memory.NewSourceRepository,
+			memory.NewWriterAliasRepository,
+			memory.NewSearchRepository,
+			memory.NewTransactor,
+		)
+	}
+	return fx.Provide(
+		database.NewDatabase,
+		gorm.NewWriterRepository,
+		gorm.NewWorkRepository,
+		gorm.NewOpinionRepository,
+		gorm.NewGraphRepository,
+		gorm.NewAuditRepository,
+		gorm.NewOpinionRevisionRepository,
+		gorm.NewSourceRepository,
+		gorm.NewWriterAliasRepository,
+		gorm.NewSearchRepository,
+		gorm.NewTransactor,
+	)
+}
+
 func NewHTTPServer(cfg *config.Config, router *gin.Engine) *http.Server {
 	return &http.Server{
 		Addr:              fmt.Sprintf(":%s", cfg.ServerPort),
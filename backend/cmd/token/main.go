@@ -0,0 +1,47 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/infrastructure/config"
+	"github.com/what-writers-like/backend/internal/service"
+)
+
+// token signs a bearer token with AUTH_SIGNING_KEY. It is how the first
+// admin token is minted; further tokens can be issued over the API.
+func main() {
+	subject := flag.String("subject", "", "who the token is issued to")
+	role := flag.String("role", string(domain.RoleEditor), "editor or admin")
+	ttl := flag.Duration("ttl", service.DefaultTokenTTL, "token lifetime")
+	flag.Parse()
+
+	if err := run(*subject, *role, *ttl); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(subject, roleName string, ttl time.Duration) error {
+	role, err := domain.ParseRole(roleName)
+	if err != nil {
+		return err
+	}
+
+	authService, err := service.NewAuthService(&config.Config{AuthSigningKey: os.Getenv("AUTH_SIGNING_KEY")})
+	if err != nil {
+		return err
+	}
+
+	token, expiresAt, err := authService.IssueToken(subject, role, ttl)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "token for %s (%s) expires at %s\n", subject, role, expiresAt.UTC().Format(time.RFC3339))
+	fmt.Println(token)
+	return nil
+}
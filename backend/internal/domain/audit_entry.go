@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+type AuditEntityType string
+
+const (
+	AuditEntityWriter      AuditEntityType = "writer"
+	AuditEntityWork        AuditEntityType = "work"
+	AuditEntityOpinion     AuditEntityType = "opinion"
+	AuditEntitySource      AuditEntityType = "source"
+	AuditEntityWriterAlias AuditEntityType = "writer_alias"
+)
+
+// ParseAuditEntityType accepts the entity types that are audited.
+func ParseAuditEntityType(s string) (AuditEntityType, error) {
+	switch AuditEntityType(s) {
+	case AuditEntityWriter, AuditEntityWork, AuditEntityOpinion, AuditEntitySource, AuditEntityWriterAlias:
+		return AuditEntityType(s), nil
+	default:
+		return "", fmt.Errorf(
+			"invalid entity type %q: expected %s, %s, %s, %s or %s",
+			s, AuditEntityWriter, AuditEntityWork, AuditEntityOpinion, AuditEntitySource, AuditEntityWriterAlias,
+		)
+	}
+}
+
+type AuditAction string
+
+const (
+	AuditActionCreate AuditAction = "create"
+	AuditActionUpdate AuditAction = "update"
+	AuditActionDelete AuditAction = "delete"
+)
+
+// AuditEntry records one change to a writer, work, opinion, source or writer
+// alias. Before and after hold JSON snapshots of the entity; before is nil
+// for a create and after is nil for a delete.
+type AuditEntry struct {
+	id         uint64
+	entityType AuditEntityType
+	entityID   string
+	action     AuditAction
+	before     []byte
+	after      []byte
+	actor      string
+	requestID  string
+	createdAt  time.Time
+}
+
+func NewAuditEntry(
+	id uint64,
+	entityType AuditEntityType,
+	entityID string,
+	action AuditAction,
+	before, after []byte,
+	actor, requestID string,
+	createdAt time.Time,
+) *AuditEntry {
+	return &AuditEntry{
+		id:         id,
+		entityType: entityType,
+		entityID:   entityID,
+		action:     action,
+		before:     before,
+		after:      after,
+		actor:      actor,
+		requestID:  requestID,
+		createdAt:  createdAt,
+	}
+}
+
+func (e *AuditEntry) ID() uint64 {
+	return e.id
+}
+
+// SetID records the identifier allocated by storage.
+func (e *AuditEntry) SetID(id uint64) {
+	e.id = id
+}
+
+func (e *AuditEntry) EntityType() AuditEntityType {
+	return e.entityType
+}
+
+func (e *AuditEntry) EntityID() string {
+	return e.entityID
+}
+
+func (e *AuditEntry) Action() AuditAction {
+	return e.action
+}
+
+func (e *AuditEntry) Before() []byte {
+	return e.before
+}
+
+func (e *AuditEntry) After() []byte {
+	return e.after
+}
+
+func (e *AuditEntry) Actor() string {
+	return e.actor
+}
+
+func (e *AuditEntry) RequestID() string {
+	return e.requestID
+}
+
+func (e *AuditEntry) CreatedAt() time.Time {
+	return e.createdAt
+}
@@ -1,23 +1,34 @@
 package domain
 
+// Opinion is one documented statement by a writer about either a work or
+// another writer as a whole. Exactly one of workID and targetWriterID is
+// set. A writer may have made several statements about the same target
+// over the years. source is the citation as written; sourceID, when set,
+// links it to a catalogued Source. quote is the original text, in the
+// language given by languages along with its translations.
 type Opinion struct {
-	writerID      uint64
-	workID        uint64
-	sentiment     bool
-	quote         string
-	source        string
-	page          *string
-	statementYear *int
+	id             uint64
+	writerID       uint64
+	workID         uint64
+	targetWriterID uint64
+	sentiment      Sentiment
+	quote          string
+	source         string
+	sourceID       uint64
+	page           *string
+	statementYear  *int
+	languages      QuoteLanguages
 }
 
 func NewOpinion(
-	writerID, workID uint64,
-	sentiment bool,
+	id, writerID, workID uint64,
+	sentiment Sentiment,
 	quote, source string,
 	page *string,
 	statementYear *int,
 ) *Opinion {
 	return &Opinion{
+		id:            id,
 		writerID:      writerID,
 		workID:        workID,
 		sentiment:     sentiment,
@@ -28,15 +39,61 @@ func NewOpinion(
 	}
 }
 
+// NewWriterOpinion creates an opinion about targetWriterID as a person
+// rather than about one of their works.
+func NewWriterOpinion(
+	id, writerID, targetWriterID uint64,
+	sentiment Sentiment,
+	quote, source string,
+	page *string,
+	statementYear *int,
+) *Opinion {
+	o := NewOpinion(id, writerID, 0, sentiment, quote, source, page, statementYear)
+	o.targetWriterID = targetWriterID
+	return o
+}
+
+// Revise returns a copy of the opinion with the same identity and target
+// but new content.
+func (o *Opinion) Revise(sentiment Sentiment, quote, source string, page *string, statementYear *int) *Opinion {
+	revised := *o
+	revised.sentiment = sentiment
+	revised.quote = quote
+	revised.source = source
+	revised.page = page
+	revised.statementYear = statementYear
+	return &revised
+}
+
+func (o *Opinion) ID() uint64 {
+	return o.id
+}
+
+// SetID records the identifier allocated by storage for an opinion created
+// with a zero ID.
+func (o *Opinion) SetID(id uint64) {
+	o.id = id
+}
+
 func (o *Opinion) WriterID() uint64 {
 	return o.writerID
 }
 
+// WorkID is zero for opinions about a writer.
 func (o *Opinion) WorkID() uint64 {
 	return o.workID
 }
 
-func (o *Opinion) Sentiment() bool {
+// TargetWriterID is zero for opinions about a work.
+func (o *Opinion) TargetWriterID() uint64 {
+	return o.targetWriterID
+}
+
+func (o *Opinion) IsAboutWriter() bool {
+	return o.targetWriterID != 0
+}
+
+func (o *Opinion) Sentiment() Sentiment {
 	return o.sentiment
 }
 
@@ -48,6 +105,17 @@ func (o *Opinion) Source() string {
 	return o.source
 }
 
+// SourceID is zero for an opinion not linked to a catalogued source.
+func (o *Opinion) SourceID() uint64 {
+	return o.sourceID
+}
+
+// SetSourceID links the opinion to a catalogued source, or unlinks it
+// given zero.
+func (o *Opinion) SetSourceID(id uint64) {
+	o.sourceID = id
+}
+
 func (o *Opinion) Page() *string {
 	return o.page
 }
@@ -55,3 +123,12 @@ func (o *Opinion) Page() *string {
 func (o *Opinion) StatementYear() *int {
 	return o.statementYear
 }
+
+func (o *Opinion) Languages() QuoteLanguages {
+	return o.languages
+}
+
+// SetLanguages records the language of the quote and its translations.
+func (o *Opinion) SetLanguages(languages QuoteLanguages) {
+	o.languages = languages
+}
@@ -0,0 +1,106 @@
+package domain
+
+import "time"
+
+// OpinionRevision is a stored version of an opinion. Revisions of the same
+// opinion are numbered from 1 in the order they were made; each holds the
+// full state of the opinion after that change.
+type OpinionRevision struct {
+	number    int
+	opinion   *Opinion
+	actor     string
+	createdAt time.Time
+}
+
+func NewOpinionRevision(number int, opinion *Opinion, actor string, createdAt time.Time) *OpinionRevision {
+	return &OpinionRevision{
+		number:    number,
+		opinion:   opinion,
+		actor:     actor,
+		createdAt: createdAt,
+	}
+}
+
+func (r *OpinionRevision) Number() int {
+	return r.number
+}
+
+// SetNumber records the revision number allocated by storage.
+func (r *OpinionRevision) SetNumber(number int) {
+	r.number = number
+}
+
+func (r *OpinionRevision) Opinion() *Opinion {
+	return r.opinion
+}
+
+func (r *OpinionRevision) Actor() string {
+	return r.actor
+}
+
+func (r *OpinionRevision) CreatedAt() time.Time {
+	return r.createdAt
+}
+
+// FieldChange describes a field whose value differs between two revisions.
+type FieldChange struct {
+	Field string
+	From  any
+	To    any
+}
+
+// DiffOpinions lists the fields that differ between from and to, in the
+// order they appear in the API. The ID, writer and work are not compared
+// since revisions are only diffed within one opinion.
+func DiffOpinions(from, to *Opinion) []FieldChange {
+	changes := []FieldChange{}
+	if from.sentiment != to.sentiment {
+		changes = append(changes, FieldChange{Field: "sentiment_grade", From: from.sentiment, To: to.sentiment})
+	}
+	if from.quote != to.quote {
+		changes = append(changes, FieldChange{Field: "quote", From: from.quote, To: to.quote})
+	}
+	if from.languages.Language != to.languages.Language {
+		changes = append(changes, FieldChange{
+			Field: "language", From: from.languages.Language, To: to.languages.Language,
+		})
+	}
+	if !equalTranslations(from.languages.Translations, to.languages.Translations) {
+		changes = append(changes, FieldChange{
+			Field: "translations", From: from.languages.Translations, To: to.languages.Translations,
+		})
+	}
+	if from.source != to.source {
+		changes = append(changes, FieldChange{Field: "source", From: from.source, To: to.source})
+	}
+	if from.sourceID != to.sourceID {
+		changes = append(changes, FieldChange{Field: "source_id", From: from.sourceID, To: to.sourceID})
+	}
+	if !equalPtr(from.page, to.page) {
+		changes = append(changes, FieldChange{Field: "page", From: from.page, To: to.page})
+	}
+	if !equalPtr(from.statementYear, to.statementYear) {
+		changes = append(changes, FieldChange{Field: "statement_year", From: from.statementYear, To: to.statementYear})
+	}
+	return changes
+}
+
+func equalTranslations(a, b []Translation) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Language != b[i].Language || a[i].Text != b[i].Text ||
+			!equalPtr(a[i].Translator, b[i].Translator) || !equalPtr(a[i].Source, b[i].Source) {
+			return false
+		}
+	}
+	return true
+}
+
+func equalPtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
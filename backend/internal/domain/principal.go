@@ -0,0 +1,64 @@
+package domain
+
+import "fmt"
+
+type Role string
+
+const (
+	RoleAnonymous Role = "anonymous"
+	RoleEditor    Role = "editor"
+	RoleAdmin     Role = "admin"
+)
+
+// ParseRole accepts the roles that can be granted to a token holder.
+func ParseRole(s string) (Role, error) {
+	switch Role(s) {
+	case RoleEditor, RoleAdmin:
+		return Role(s), nil
+	default:
+		return "", fmt.Errorf("invalid role %q: expected %s or %s", s, RoleEditor, RoleAdmin)
+	}
+}
+
+// Includes reports whether r grants everything required grants. Roles are
+// ordered anonymous < editor < admin.
+func (r Role) Includes(required Role) bool {
+	return r.rank() >= required.rank()
+}
+
+func (r Role) rank() int {
+	switch r {
+	case RoleAdmin:
+		return 2
+	case RoleEditor:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Principal is the caller a request is made on behalf of.
+type Principal struct {
+	subject string
+	role    Role
+}
+
+func NewPrincipal(subject string, role Role) *Principal {
+	return &Principal{
+		subject: subject,
+		role:    role,
+	}
+}
+
+// AnonymousPrincipal is the caller of a request without credentials.
+func AnonymousPrincipal() *Principal {
+	return NewPrincipal("", RoleAnonymous)
+}
+
+func (p *Principal) Subject() string {
+	return p.subject
+}
+
+func (p *Principal) Role() Role {
+	return p.role
+}
@@ -0,0 +1,53 @@
+package domain
+
+import "fmt"
+
+// SearchHitType names the kind of entity a search hit refers to.
+type SearchHitType string
+
+const (
+	SearchHitWriter  SearchHitType = "writer"
+	SearchHitWork    SearchHitType = "work"
+	SearchHitOpinion SearchHitType = "opinion"
+)
+
+// ParseSearchHitType accepts writer, work or opinion.
+func ParseSearchHitType(s string) (SearchHitType, error) {
+	switch t := SearchHitType(s); t {
+	case SearchHitWriter, SearchHitWork, SearchHitOpinion:
+		return t, nil
+	default:
+		return "", fmt.Errorf("invalid type %q: expected writer, work or opinion", s)
+	}
+}
+
+// SearchHit is a writer, work or opinion found by a search across all of
+// them. Label is what the hit is shown as: the writer's name, the work's
+// title or the opinion's quote. Field names the field that matched best,
+// Text holds its value and MatchStart and MatchEnd the byte offsets of the
+// words in Text that matched the query. Score runs from 0 to 1.
+type SearchHit struct {
+	Type       SearchHitType
+	ID         uint64
+	Label      string
+	Field      string
+	Text       string
+	MatchStart int
+	MatchEnd   int
+	Score      float64
+}
+
+// QuoteMatch is an opinion found by a full-text search of quotes. Snippet
+// is the part of the quote around the words that matched, and Rank grows
+// from 0 towards 1 with how well the quote matched.
+type QuoteMatch struct {
+	Opinion *Opinion
+	Snippet []TextSegment
+	Rank    float64
+}
+
+// TextSegment is a run of a snippet; Match marks the words a search found.
+type TextSegment struct {
+	Text  string
+	Match bool
+}
@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Sentiment grades how a writer felt about a work on a scale from -2
+// (scathing) to +2 (glowing). SentimentMixed records a statement that
+// praises and condemns at once and sits outside the scale.
+type Sentiment string
+
+const (
+	SentimentVeryNegative Sentiment = "-2"
+	SentimentNegative     Sentiment = "-1"
+	SentimentNeutral      Sentiment = "0"
+	SentimentPositive     Sentiment = "+1"
+	SentimentVeryPositive Sentiment = "+2"
+	SentimentMixed        Sentiment = "mixed"
+)
+
+// Sentiments lists every grade, from most negative to mixed.
+var Sentiments = []Sentiment{
+	SentimentVeryNegative, SentimentNegative, SentimentNeutral,
+	SentimentPositive, SentimentVeryPositive, SentimentMixed,
+}
+
+// ParseSentiment accepts a grade from the scale, with or without a plus
+// sign on the positive ones, or "mixed". Surrounding spaces are ignored
+// since a plus sign in a query string decodes to one.
+func ParseSentiment(s string) (Sentiment, error) {
+	s = strings.TrimSpace(s)
+	if s == "1" || s == "2" {
+		s = "+" + s
+	}
+	if sentiment := Sentiment(s); sentiment.IsValid() {
+		return sentiment, nil
+	}
+	return "", fmt.Errorf("invalid sentiment %q: expected -2, -1, 0, +1, +2 or mixed", s)
+}
+
+// ParseSentiments reads a comma-separated list of sentiment grades such as
+// "+2,mixed". "positive" and "negative" stand for both grades on that side
+// of the scale. An empty value means no constraint.
+func ParseSentiments(raw string) ([]Sentiment, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	var sentiments []Sentiment
+	for _, p := range strings.Split(raw, ",") {
+		switch strings.TrimSpace(p) {
+		case "positive":
+			sentiments = append(sentiments, SentimentPositive, SentimentVeryPositive)
+		case "negative":
+			sentiments = append(sentiments, SentimentNegative, SentimentVeryNegative)
+		default:
+			sentiment, err := ParseSentiment(p)
+			if err != nil {
+				return nil, err
+			}
+			sentiments = append(sentiments, sentiment)
+		}
+	}
+	return sentiments, nil
+}
+
+// IsValid reports whether s is one of the defined grades.
+func (s Sentiment) IsValid() bool {
+	for _, sentiment := range Sentiments {
+		if s == sentiment {
+			return true
+		}
+	}
+	return false
+}
+
+// SentimentFromBool maps the yes/no sentiment used before grades were
+// introduced onto the scale.
+func SentimentFromBool(positive bool) Sentiment {
+	if positive {
+		return SentimentPositive
+	}
+	return SentimentNegative
+}
+
+// Score places the sentiment on the -2 to +2 scale. It reports false for
+// a mixed sentiment, which has no place on it.
+func (s Sentiment) Score() (int, bool) {
+	switch s {
+	case SentimentVeryNegative:
+		return -2, true
+	case SentimentNegative:
+		return -1, true
+	case SentimentNeutral:
+		return 0, true
+	case SentimentPositive:
+		return 1, true
+	case SentimentVeryPositive:
+		return 2, true
+	default:
+		return 0, false
+	}
+}
+
+// IsPositive reports whether the sentiment is above the middle of the
+// scale. It backs the boolean sentiment still offered to older clients.
+func (s Sentiment) IsPositive() bool {
+	score, ok := s.Score()
+	return ok && score > 0
+}
@@ -0,0 +1,95 @@
+package domain
+
+import "fmt"
+
+// SourceType classifies the document an opinion is quoted from.
+type SourceType string
+
+const (
+	SourceTypeLetter    SourceType = "letter"
+	SourceTypeDiary     SourceType = "diary"
+	SourceTypeEssay     SourceType = "essay"
+	SourceTypeInterview SourceType = "interview"
+	SourceTypeReview    SourceType = "review"
+)
+
+var SourceTypes = []SourceType{
+	SourceTypeLetter, SourceTypeDiary, SourceTypeEssay, SourceTypeInterview, SourceTypeReview,
+}
+
+// ParseSourceType accepts one of the defined source types.
+func ParseSourceType(s string) (SourceType, error) {
+	if t := SourceType(s); t.IsValid() {
+		return t, nil
+	}
+	return "", fmt.Errorf("invalid source type %q: expected letter, diary, essay, interview or review", s)
+}
+
+func (t SourceType) IsValid() bool {
+	for _, sourceType := range SourceTypes {
+		if t == sourceType {
+			return true
+		}
+	}
+	return false
+}
+
+// SourceDetails holds the optional bibliographic metadata of a source.
+// Author may name the editor of a collection rather than its author.
+type SourceDetails struct {
+	Author          *string
+	Publisher       *string
+	Year            *int
+	ISBN            *string
+	DOI             *string
+	URL             *string
+	ArchiveLocation *string
+}
+
+// Source is a publication or archival document that opinions are quoted
+// from. Candidate sources grouped from free-text citations have no type
+// and stay unconfirmed until a curator reviews them.
+type Source struct {
+	id         uint64
+	sourceType SourceType
+	title      string
+	details    SourceDetails
+	confirmed  bool
+}
+
+func NewSource(id uint64, sourceType SourceType, title string, details SourceDetails, confirmed bool) *Source {
+	return &Source{
+		id:         id,
+		sourceType: sourceType,
+		title:      title,
+		details:    details,
+		confirmed:  confirmed,
+	}
+}
+
+func (s *Source) ID() uint64 {
+	return s.id
+}
+
+// SetID records the identifier allocated by storage for a source created
+// with a zero ID.
+func (s *Source) SetID(id uint64) {
+	s.id = id
+}
+
+// Type is empty for a candidate whose type has not been determined yet.
+func (s *Source) Type() SourceType {
+	return s.sourceType
+}
+
+func (s *Source) Title() string {
+	return s.title
+}
+
+func (s *Source) Details() SourceDetails {
+	return s.details
+}
+
+func (s *Source) Confirmed() bool {
+	return s.confirmed
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"fmt"
+
+	"golang.org/x/text/language"
+)
+
+// Translation renders an opinion's quote in another language. Translator
+// and Source credit the published translation it is taken from, if any.
+type Translation struct {
+	Language   string
+	Text       string
+	Translator *string
+	Source     *string
+}
+
+// QuoteLanguages gives the language of an opinion's quote, as a BCP 47 tag
+// such as "ru" or "fr", and its translations into other languages.
+// Language is empty for quotes recorded before their language was.
+type QuoteLanguages struct {
+	Language     string
+	Translations []Translation
+}
+
+// ParseLanguage accepts a BCP 47 language tag and returns it in canonical
+// form, so that "EN-gb" becomes "en-GB".
+func ParseLanguage(s string) (string, error) {
+	tag, err := language.Parse(s)
+	if err != nil || tag == language.Und {
+		return "", fmt.Errorf("invalid language %q: expected a BCP 47 tag such as \"ru\" or \"pt-BR\"", s)
+	}
+	return tag.String(), nil
+}
+
+// Localize picks the text of the quote for a reader who prefers the given
+// languages, best first. It returns the translation chosen, or nil for the
+// original, which is kept when it is in a preferred language or no
+// translation is.
+func (o *Opinion) Localize(preferred []language.Tag) (string, *Translation) {
+	translations := o.languages.Translations
+	if len(translations) == 0 || len(preferred) == 0 {
+		return o.quote, nil
+	}
+	// An original in an unknown language is Und, which matches nothing
+	supported := make([]language.Tag, 0, len(translations)+1)
+	supported = append(supported, language.Make(o.languages.Language))
+	for _, t := range translations {
+		supported = append(supported, language.Make(t.Language))
+	}
+	_, index, confidence := language.NewMatcher(supported).Match(preferred...)
+	if confidence == language.No || index == 0 {
+		return o.quote, nil
+	}
+	translation := translations[index-1]
+	return translation.Text, &translation
+}
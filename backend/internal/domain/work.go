@@ -1,16 +1,31 @@
 package domain
 
+// WorkDetails holds the optional bibliographic metadata of a work. Genre
+// is free text naming the form or genre, such as "novel" or "verse drama".
+// OriginalTitle and OriginalLanguage describe a work known by a translated
+// title.
+type WorkDetails struct {
+	PublicationYear  *int
+	Genre            *string
+	OriginalLanguage *string
+	OriginalTitle    *string
+}
+
+// Work is written by its authors in the order they are credited. An
+// anonymous work has no authors.
 type Work struct {
-	id       uint64
-	title    string
-	authorID uint64
+	id        uint64
+	title     string
+	authorIDs []uint64
+	details   WorkDetails
 }
 
-func NewWork(id uint64, title string, authorID uint64) *Work {
+func NewWork(id uint64, title string, authorIDs []uint64, details WorkDetails) *Work {
 	return &Work{
-		id:       id,
-		title:    title,
-		authorID: authorID,
+		id:        id,
+		title:     title,
+		authorIDs: authorIDs,
+		details:   details,
 	}
 }
 
@@ -18,10 +33,31 @@ func (w *Work) ID() uint64 {
 	return w.id
 }
 
+// SetID records the identifier allocated by storage for a work created
+// with a zero ID.
+func (w *Work) SetID(id uint64) {
+	w.id = id
+}
+
 func (w *Work) Title() string {
 	return w.title
 }
 
-func (w *Work) AuthorID() uint64 {
-	return w.authorID
+// AuthorIDs lists the authors in the order they are credited.
+func (w *Work) AuthorIDs() []uint64 {
+	return w.authorIDs
+}
+
+// HasAuthor reports whether the writer is one of the work's authors.
+func (w *Work) HasAuthor(writerID uint64) bool {
+	for _, id := range w.authorIDs {
+		if id == writerID {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *Work) Details() WorkDetails {
+	return w.details
 }
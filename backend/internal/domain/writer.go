@@ -22,6 +22,12 @@ func (w *Writer) ID() uint64 {
 	return w.id
 }
 
+// SetID records the identifier allocated by storage for a writer created
+// with a zero ID.
+func (w *Writer) SetID(id uint64) {
+	w.id = id
+}
+
 func (w *Writer) Name() string {
 	return w.name
 }
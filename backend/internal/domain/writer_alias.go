@@ -0,0 +1,84 @@
+package domain
+
+import "fmt"
+
+// AliasKind tells how an alias relates to the name a writer is filed under.
+type AliasKind string
+
+const (
+	AliasKindPseudonym       AliasKind = "pseudonym"
+	AliasKindBirthName       AliasKind = "birth_name"
+	AliasKindTransliteration AliasKind = "transliteration"
+	AliasKindNativeScript    AliasKind = "native_script"
+)
+
+var AliasKinds = []AliasKind{
+	AliasKindPseudonym, AliasKindBirthName, AliasKindTransliteration, AliasKindNativeScript,
+}
+
+// ParseAliasKind accepts one of the defined alias kinds.
+func ParseAliasKind(s string) (AliasKind, error) {
+	if k := AliasKind(s); k.IsValid() {
+		return k, nil
+	}
+	return "", fmt.Errorf(
+		"invalid alias kind %q: expected pseudonym, birth_name, transliteration or native_script", s,
+	)
+}
+
+func (k AliasKind) IsValid() bool {
+	for _, kind := range AliasKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// WriterAlias is another name a writer is known by, such as a pen name
+// (Gorky for Peshkov), a different transliteration (Dostoyevsky) or the
+// name in its original script (Достоевский).
+type WriterAlias struct {
+	id       uint64
+	writerID uint64
+	name     string
+	kind     AliasKind
+}
+
+func NewWriterAlias(id, writerID uint64, name string, kind AliasKind) *WriterAlias {
+	return &WriterAlias{
+		id:       id,
+		writerID: writerID,
+		name:     name,
+		kind:     kind,
+	}
+}
+
+func (a *WriterAlias) ID() uint64 {
+	return a.id
+}
+
+// SetID records the identifier allocated by storage for an alias created
+// with a zero ID.
+func (a *WriterAlias) SetID(id uint64) {
+	a.id = id
+}
+
+func (a *WriterAlias) WriterID() uint64 {
+	return a.writerID
+}
+
+func (a *WriterAlias) Name() string {
+	return a.name
+}
+
+func (a *WriterAlias) Kind() AliasKind {
+	return a.kind
+}
+
+// WriterMatch is a writer found by a search. Alias is the alias that
+// matched the query, or nil when the writer's own name or bio did.
+type WriterMatch struct {
+	Writer *Writer
+	Alias  *WriterAlias
+}
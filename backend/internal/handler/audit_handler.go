@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/repository"
+	"github.com/what-writers-like/backend/internal/service"
+)
+
+type AuditHandler struct {
+	auditService service.AuditService
+}
+
+func NewAuditHandler(auditService service.AuditService) *AuditHandler {
+	return &AuditHandler{auditService: auditService}
+}
+
+// List returns audit entries, newest first. Entries can be narrowed to an
+// entity type, a single entity and a time range given as RFC 3339
+// timestamps (since inclusive, until exclusive). Invalid parameters are a
+// 400 and a failure to read the log a 500.
+func (h *AuditHandler) List(c *gin.Context) {
+	var filter repository.AuditFilter
+	if raw := c.Query("entity_type"); raw != "" {
+		entityType, err := domain.ParseAuditEntityType(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		filter.EntityType = entityType
+	}
+	filter.EntityID = c.Query("entity_id")
+
+	var err error
+	if filter.Since, err = parseTimeParam(c, "since"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if filter.Until, err = parseTimeParam(c, "until"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	limitStr := c.DefaultQuery("limit", "50")
+	offsetStr := c.DefaultQuery("offset", "0")
+
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit <= 0 {
+		limit = 50
+	}
+
+	offset, err := strconv.Atoi(offsetStr)
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	entries, err := h.auditService.ListEntries(filter, limit, offset)
+	if errors.Is(err, service.ErrInvalidAuditRange) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	result := make([]gin.H, len(entries))
+	for i, e := range entries {
+		result[i] = auditEntryToResponse(e)
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func parseTimeParam(c *gin.Context, name string) (*time.Time, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: expected an RFC 3339 timestamp", name)
+	}
+	return &t, nil
+}
+
+func auditEntryToResponse(e *domain.AuditEntry) gin.H {
+	return gin.H{
+		"id":          e.ID(),
+		"entity_type": e.EntityType(),
+		"entity_id":   e.EntityID(),
+		"action":      e.Action(),
+		"before":      snapshotToResponse(e.Before()),
+		"after":       snapshotToResponse(e.After()),
+		"actor":       e.Actor(),
+		"request_id":  e.RequestID(),
+		"created_at":  e.CreatedAt().UTC().Format(time.RFC3339Nano),
+	}
+}
+
+// snapshotToResponse embeds a stored snapshot as JSON rather than as an
+// encoded string.
+func snapshotToResponse(snapshot []byte) json.RawMessage {
+	if snapshot == nil {
+		return json.RawMessage("null")
+	}
+	return json.RawMessage(snapshot)
+}
@@ -0,0 +1,79 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/handler"
+	"github.com/what-writers-like/backend/internal/repository/gorm"
+	"github.com/what-writers-like/backend/internal/service"
+	"github.com/what-writers-like/backend/internal/testutils"
+)
+
+func setupAuditHandlerRouter(t *testing.T) (*gin.Engine, func()) {
+	db, cleanup := testutils.SetupTestDB(t)
+
+	auditRepo := gorm.NewAuditRepository(db)
+	writerService := service.NewWriterService(
+		gorm.NewWriterRepository(db), gorm.NewWorkRepository(db), gorm.NewTransactor(db),
+	)
+	_, err := writerService.CreateWriter(context.Background(), "Jane Austen", 1775, nil, nil)
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	auditHandler := handler.NewAuditHandler(service.NewAuditService(auditRepo))
+	router.GET("/audit", auditHandler.List)
+	return router, cleanup
+}
+
+func TestAuditHandler_List(t *testing.T) {
+	t.Parallel()
+	router, cleanup := setupAuditHandlerRouter(t)
+	defer cleanup()
+
+	t.Run("entries", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/audit?entity_type=writer", http.NoBody)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var entries []map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &entries))
+		require.Len(t, entries, 1)
+		assert.Equal(t, string(domain.AuditActionCreate), entries[0]["action"])
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		for _, path := range []string{
+			"/audit?entity_type=planet",
+			"/audit?since=yesterday",
+			"/audit?since=2001-01-01T00:00:00Z&until=2000-01-01T00:00:00Z",
+		} {
+			req := httptest.NewRequest(http.MethodGet, path, http.NoBody)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusBadRequest, w.Code, path)
+		}
+	})
+}
+
+func TestAuditHandler_DatabaseError(t *testing.T) {
+	t.Parallel()
+	router, cleanup := setupAuditHandlerRouter(t)
+	// A failing read is not mistaken for invalid parameters
+	cleanup()
+
+	req := httptest.NewRequest(http.MethodGet, "/audit", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/service"
+)
+
+type AuthHandler struct {
+	authService service.AuthService
+}
+
+func NewAuthHandler(authService service.AuthService) *AuthHandler {
+	return &AuthHandler{authService: authService}
+}
+
+// IssueTokenRequest bounds ttl_hours by service.MaxTokenTTL before it is
+// turned into a duration, which a larger number would overflow.
+type IssueTokenRequest struct {
+	Subject  string `json:"subject"   binding:"required"`
+	Role     string `json:"role"      binding:"required"`
+	TTLHours *int   `json:"ttl_hours" binding:"omitempty,min=1,max=8760"`
+}
+
+func (h *AuthHandler) IssueToken(c *gin.Context) {
+	var req IssueTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	role, err := domain.ParseRole(req.Role)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ttl := service.DefaultTokenTTL
+	if req.TTLHours != nil {
+		ttl = time.Duration(*req.TTLHours) * time.Hour
+	}
+
+	token, expiresAt, err := h.authService.IssueToken(req.Subject, role, ttl)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"token":      token,
+		"token_type": "Bearer",
+		"subject":    req.Subject,
+		"role":       role,
+		"expires_at": expiresAt.UTC().Format(time.RFC3339),
+	})
+}
+
+// Me describes the caller, which lets clients check a token.
+func (h *AuthHandler) Me(c *gin.Context) {
+	principal := principalFromContext(c)
+	c.JSON(http.StatusOK, gin.H{
+		"subject": principal.Subject(),
+		"role":    principal.Role(),
+	})
+}
@@ -0,0 +1,162 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/handler"
+	"github.com/what-writers-like/backend/internal/infrastructure/config"
+	"github.com/what-writers-like/backend/internal/service"
+)
+
+func setupAuthHandlerRouter(t *testing.T) (*gin.Engine, service.AuthService) {
+	authService, err := service.NewAuthService(&config.Config{AuthSigningKey: testSigningKey})
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	authHandler := handler.NewAuthHandler(authService)
+	authMiddleware := handler.NewAuthMiddleware(authService)
+	router.Use(authMiddleware.Authenticate)
+	router.POST("/auth/token", authMiddleware.Require(domain.RoleAdmin), authHandler.IssueToken)
+	router.GET("/auth/me", authHandler.Me)
+	return router, authService
+}
+
+func issueTestToken(t *testing.T, authService service.AuthService, role domain.Role) string {
+	token, _, err := authService.IssueToken("tester", role, time.Hour)
+	require.NoError(t, err)
+	return token
+}
+
+func TestAuthHandler_IssueToken(t *testing.T) {
+	t.Parallel()
+	body := []byte(`{"subject":"alice","role":"editor","ttl_hours":2}`)
+
+	t.Run("admin", func(t *testing.T) {
+		t.Parallel()
+		router, authService := setupAuthHandlerRouter(t)
+
+		req := httptest.NewRequest(http.MethodPost, "/auth/token", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+issueTestToken(t, authService, domain.RoleAdmin))
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		var response map[string]interface{}
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, "editor", response["role"])
+
+		token, ok := response["token"].(string)
+		require.True(t, ok)
+		principal, err := authService.Authenticate(token)
+		require.NoError(t, err)
+		assert.Equal(t, "alice", principal.Subject())
+	})
+
+	t.Run("editor", func(t *testing.T) {
+		t.Parallel()
+		router, authService := setupAuthHandlerRouter(t)
+
+		req := httptest.NewRequest(http.MethodPost, "/auth/token", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+issueTestToken(t, authService, domain.RoleEditor))
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("anonymous", func(t *testing.T) {
+		t.Parallel()
+		router, _ := setupAuthHandlerRouter(t)
+
+		req := httptest.NewRequest(http.MethodPost, "/auth/token", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("invalid role", func(t *testing.T) {
+		t.Parallel()
+		router, authService := setupAuthHandlerRouter(t)
+
+		req := httptest.NewRequest(
+			http.MethodPost, "/auth/token", bytes.NewBufferString(`{"subject":"alice","role":"anonymous"}`),
+		)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+issueTestToken(t, authService, domain.RoleAdmin))
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid lifetime", func(t *testing.T) {
+		t.Parallel()
+		router, authService := setupAuthHandlerRouter(t)
+
+		// 5124096 hours overflows a duration into about 25 minutes
+		for _, hours := range []string{"0", "-1", "8761", "5124096"} {
+			req := httptest.NewRequest(
+				http.MethodPost, "/auth/token",
+				bytes.NewBufferString(`{"subject":"alice","role":"editor","ttl_hours":`+hours+`}`),
+			)
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+issueTestToken(t, authService, domain.RoleAdmin))
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, hours)
+		}
+	})
+}
+
+func TestAuthHandler_Me(t *testing.T) {
+	t.Parallel()
+	t.Run("anonymous", func(t *testing.T) {
+		t.Parallel()
+		router, _ := setupAuthHandlerRouter(t)
+
+		req := httptest.NewRequest(http.MethodGet, "/auth/me", http.NoBody)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response map[string]interface{}
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, "anonymous", response["role"])
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		t.Parallel()
+		router, _ := setupAuthHandlerRouter(t)
+
+		req := httptest.NewRequest(http.MethodGet, "/auth/me", http.NoBody)
+		req.Header.Set("Authorization", "Bearer not-a-token")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/service"
+)
+
+const principalContextKey = "principal"
+
+type AuthMiddleware struct {
+	authService service.AuthService
+}
+
+func NewAuthMiddleware(authService service.AuthService) *AuthMiddleware {
+	return &AuthMiddleware{authService: authService}
+}
+
+// Authenticate resolves the bearer token of a request into a principal.
+// Requests without an Authorization header proceed anonymously; a header
+// carrying an invalid or expired token is rejected.
+func (m *AuthMiddleware) Authenticate(c *gin.Context) {
+	header := c.GetHeader("Authorization")
+	if header == "" {
+		c.Set(principalContextKey, domain.AnonymousPrincipal())
+		c.Next()
+		return
+	}
+
+	token, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "expected a bearer token"})
+		return
+	}
+
+	principal, err := m.authService.Authenticate(strings.TrimSpace(token))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	c.Set(principalContextKey, principal)
+	c.Request = c.Request.WithContext(service.WithActor(c.Request.Context(), principal.Subject()))
+	c.Next()
+}
+
+// Require only lets through callers whose role includes the given one.
+func (m *AuthMiddleware) Require(role domain.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal := principalFromContext(c)
+		if principal.Role() == domain.RoleAnonymous {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+			return
+		}
+		if !principal.Role().Includes(role) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "insufficient role"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// principalFromContext returns the caller set by Authenticate, or an
+// anonymous principal when the middleware did not run.
+func principalFromContext(c *gin.Context) *domain.Principal {
+	if value, ok := c.Get(principalContextKey); ok {
+		if principal, ok := value.(*domain.Principal); ok {
+			return principal
+		}
+	}
+	return domain.AnonymousPrincipal()
+}
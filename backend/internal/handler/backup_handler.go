@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/what-writers-like/backend/internal/infrastructure/config"
+	"github.com/what-writers-like/backend/internal/service"
+)
+
+// BackupHandler downloads snapshot bundles of every record and restores
+// them. A bundle to restore is the request body, or the "file" field of a
+// multipart form; mode is replace or merge, and with dry_run=true nothing
+// is written and the report says what would have been. A bundle larger
+// than the configured upload limit is refused.
+type BackupHandler struct {
+	backupService  service.BackupService
+	maxUploadBytes int64
+}
+
+func NewBackupHandler(backupService service.BackupService, cfg *config.Config) *BackupHandler {
+	return &BackupHandler{backupService: backupService, maxUploadBytes: cfg.MaxUploadBytes}
+}
+
+// Backup builds the bundle before answering, so that a failure is a 500
+// rather than a truncated download.
+func (h *BackupHandler) Backup(c *gin.Context) {
+	var bundle bytes.Buffer
+	manifest, err := h.backupService.Backup(&bundle)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	filename := fmt.Sprintf("what-writers-like-backup-%s.zip", manifest.CreatedAt.Format("20060102T150405Z"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, "application/zip", bundle.Bytes())
+}
+
+// Restore answers 200 with the report of a dry run or an applied restore,
+// 400 with the report when any row breaks a rule, 400 when the bundle
+// cannot be read, and 413 when it is over the upload limit.
+func (h *BackupHandler) Restore(c *gin.Context) {
+	mode, err := service.ParseRestoreMode(c.Query("mode"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	dryRun, err := parseBoolParam(c, "dry_run")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	file, err := importFile(c, h.maxUploadBytes)
+	if err != nil {
+		c.JSON(uploadErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+	// Reading a zip archive takes random access to it
+	bundle, err := io.ReadAll(file)
+	if err != nil {
+		c.JSON(uploadErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	options := service.RestoreOptions{Mode: mode, DryRun: dryRun != nil && *dryRun}
+	report, err := h.backupService.Restore(c.Request.Context(), bytes.NewReader(bundle), int64(len(bundle)), options)
+	if errors.Is(err, service.ErrInvalidSnapshot) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := restoreReportToResponse(report)
+	if len(report.Errors) > 0 && !report.DryRun {
+		response["error"] = "some rows break the rules; nothing was restored"
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func restoreReportToResponse(r *service.RestoreReport) gin.H {
+	tables := make([]gin.H, len(r.Tables))
+	for i, t := range r.Tables {
+		tables[i] = gin.H{
+			"name":      t.Name,
+			"rows":      t.Rows,
+			"created":   t.Created,
+			"updated":   t.Updated,
+			"unchanged": t.Unchanged,
+			"deleted":   t.Deleted,
+		}
+	}
+	errs := make([]gin.H, len(r.Errors))
+	for i, e := range r.Errors {
+		errs[i] = gin.H{"table": e.Table, "id": e.ID, "message": e.Message}
+	}
+	return gin.H{
+		"dry_run":  r.DryRun,
+		"applied":  r.Applied(),
+		"mode":     r.Mode,
+		"snapshot": r.Snapshot,
+		"tables":   tables,
+		"errors":   errs,
+	}
+}
@@ -0,0 +1,225 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/handler"
+	"github.com/what-writers-like/backend/internal/infrastructure/config"
+	"github.com/what-writers-like/backend/internal/repository"
+	"github.com/what-writers-like/backend/internal/repository/gorm"
+	"github.com/what-writers-like/backend/internal/service"
+	"github.com/what-writers-like/backend/internal/testutils"
+)
+
+func setupBackupHandlerRouter(t *testing.T, maxUploadBytes int64) (*gin.Engine, *repository.Repositories, func()) {
+	db, cleanup := testutils.SetupTestDB(t)
+
+	repos := &repository.Repositories{
+		Writers:  gorm.NewWriterRepository(db),
+		Works:    gorm.NewWorkRepository(db),
+		Opinions: gorm.NewOpinionRepository(db),
+	}
+	backupHandler := handler.NewBackupHandler(
+		service.NewBackupService(gorm.NewTransactor(db)), &config.Config{MaxUploadBytes: maxUploadBytes},
+	)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/backup", backupHandler.Backup)
+	router.POST("/restore", backupHandler.Restore)
+	return router, repos, cleanup
+}
+
+type restoreResponse struct {
+	DryRun   bool   `json:"dry_run"`
+	Applied  bool   `json:"applied"`
+	Mode     string `json:"mode"`
+	Error    string `json:"error"`
+	Snapshot struct {
+		SchemaVersion int `json:"schema_version"`
+	} `json:"snapshot"`
+	Tables []struct {
+		Name    string `json:"name"`
+		Rows    int    `json:"rows"`
+		Created int    `json:"created"`
+		Updated int    `json:"updated"`
+		Deleted int    `json:"deleted"`
+	} `json:"tables"`
+	Errors []struct {
+		Table   string `json:"table"`
+		ID      uint64 `json:"id"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func postRestore(t *testing.T, router *gin.Engine, path string, bundle []byte) (int, restoreResponse) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(bundle))
+	req.Header.Set("Content-Type", "application/zip")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var response restoreResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return w.Code, response
+}
+
+func TestBackupHandler_BackupAndRestore(t *testing.T) {
+	t.Parallel()
+	source, sourceRepos, cleanupSource := setupBackupHandlerRouter(t, config.DefaultMaxUploadBytes)
+	defer cleanupSource()
+	target, targetRepos, cleanupTarget := setupBackupHandlerRouter(t, config.DefaultMaxUploadBytes)
+	defer cleanupTarget()
+
+	require.NoError(t, sourceRepos.Writers.Create(domain.NewWriter(0, "Leo Tolstoy", 1828, nil, nil)))
+	require.NoError(t, sourceRepos.Writers.Create(domain.NewWriter(0, "William Shakespeare", 1564, nil, nil)))
+	require.NoError(t, sourceRepos.Works.Create(domain.NewWork(0, "King Lear", []uint64{2}, domain.WorkDetails{})))
+	require.NoError(t, sourceRepos.Opinions.Create(domain.NewOpinion(
+		0, 1, 1, domain.SentimentVeryNegative, "Very poor", "Essay", nil, nil,
+	)))
+	require.NoError(t, targetRepos.Writers.Create(domain.NewWriter(0, "Anton Chekhov", 1860, nil, nil)))
+
+	w := httptest.NewRecorder()
+	source.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/backup", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/zip", w.Header().Get("Content-Type"))
+	assert.Regexp(t, `^attachment; filename="what-writers-like-backup-\d{8}T\d{6}Z\.zip"$`,
+		w.Header().Get("Content-Disposition"))
+	bundle := w.Body.Bytes()
+
+	t.Run("mode is required", func(t *testing.T) {
+		code, response := postRestore(t, target, "/restore", bundle)
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Contains(t, response.Error, "restore mode")
+	})
+
+	t.Run("not a bundle", func(t *testing.T) {
+		code, response := postRestore(t, target, "/restore?mode=merge", []byte("name\nJane Austen\n"))
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Contains(t, response.Error, "invalid snapshot")
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		code, response := postRestore(t, target, "/restore?mode=replace&dry_run=true", bundle)
+		require.Equal(t, http.StatusOK, code)
+		assert.True(t, response.DryRun)
+		assert.False(t, response.Applied)
+		assert.Equal(t, 1, response.Snapshot.SchemaVersion)
+		_, err := targetRepos.Works.GetByID(1)
+		require.Error(t, err)
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, err := form.CreateFormFile("file", "backup.zip")
+		require.NoError(t, err)
+		_, err = part.Write(bundle)
+		require.NoError(t, err)
+		require.NoError(t, form.Close())
+
+		req := httptest.NewRequest(http.MethodPost, "/restore?mode=replace", &body)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		w := httptest.NewRecorder()
+		target.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var response restoreResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.True(t, response.Applied)
+		assert.Equal(t, "replace", response.Mode)
+		require.Len(t, response.Tables, 5)
+		assert.Equal(t, "writers", response.Tables[1].Name)
+		assert.Equal(t, 1, response.Tables[1].Created)
+		assert.Equal(t, 1, response.Tables[1].Updated)
+
+		writer, err := targetRepos.Writers.GetByID(1)
+		require.NoError(t, err)
+		assert.Equal(t, "Leo Tolstoy", writer.Name())
+		opinion, err := targetRepos.Opinions.GetByID(1)
+		require.NoError(t, err)
+		assert.Equal(t, "Very poor", opinion.Quote())
+	})
+}
+
+func TestBackupHandler_RestoreRejectsBrokenRows(t *testing.T) {
+	t.Parallel()
+	source, sourceRepos, cleanupSource := setupBackupHandlerRouter(t, config.DefaultMaxUploadBytes)
+	defer cleanupSource()
+	target, targetRepos, cleanupTarget := setupBackupHandlerRouter(t, config.DefaultMaxUploadBytes)
+	defer cleanupTarget()
+
+	require.NoError(t, sourceRepos.Writers.Create(domain.NewWriter(0, "Leo Tolstoy", 1828, nil, nil)))
+	require.NoError(t, sourceRepos.Writers.Create(domain.NewWriter(0, "William Shakespeare", 1564, nil, nil)))
+	require.NoError(t, sourceRepos.Works.Create(domain.NewWork(0, "King Lear", []uint64{2, 1}, domain.WorkDetails{})))
+	w := httptest.NewRecorder()
+	source.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/backup", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// The bundle credits King Lear to Tolstoy as well, who has an opinion
+	// about it here
+	require.NoError(t, targetRepos.Writers.Create(domain.NewWriter(0, "Leo Tolstoy", 1828, nil, nil)))
+	require.NoError(t, targetRepos.Writers.Create(domain.NewWriter(0, "W. Shakespeare", 1564, nil, nil)))
+	require.NoError(t, targetRepos.Works.Create(domain.NewWork(0, "King Lear", []uint64{2}, domain.WorkDetails{})))
+	require.NoError(t, targetRepos.Opinions.Create(domain.NewOpinion(
+		0, 1, 1, domain.SentimentVeryNegative, "Very poor", "Essay", nil, nil,
+	)))
+
+	code, response := postRestore(t, target, "/restore?mode=merge", w.Body.Bytes())
+	require.Equal(t, http.StatusBadRequest, code)
+	assert.False(t, response.Applied)
+	assert.NotEmpty(t, response.Error)
+	require.Len(t, response.Errors, 1)
+	assert.Equal(t, "opinions", response.Errors[0].Table)
+	assert.Equal(t, "writer cannot express opinion about their own work", response.Errors[0].Message)
+
+	writer, err := targetRepos.Writers.GetByID(2)
+	require.NoError(t, err)
+	assert.Equal(t, "W. Shakespeare", writer.Name())
+}
+
+func TestBackupHandler_RestoreRefusesLargeUploads(t *testing.T) {
+	t.Parallel()
+	source, sourceRepos, cleanupSource := setupBackupHandlerRouter(t, config.DefaultMaxUploadBytes)
+	defer cleanupSource()
+	target, _, cleanupTarget := setupBackupHandlerRouter(t, 64)
+	defer cleanupTarget()
+
+	require.NoError(t, sourceRepos.Writers.Create(domain.NewWriter(0, "Leo Tolstoy", 1828, nil, nil)))
+	w := httptest.NewRecorder()
+	source.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/backup", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	bundle := w.Body.Bytes()
+	require.Greater(t, len(bundle), 64)
+
+	t.Run("body", func(t *testing.T) {
+		code, response := postRestore(t, target, "/restore?mode=replace", bundle)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, code)
+		assert.NotEmpty(t, response.Error)
+	})
+
+	t.Run("multipart", func(t *testing.T) {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, err := form.CreateFormFile("file", "backup.zip")
+		require.NoError(t, err)
+		_, err = part.Write(bundle)
+		require.NoError(t, err)
+		require.NoError(t, form.Close())
+
+		req := httptest.NewRequest(http.MethodPost, "/restore?mode=replace", &body)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		w := httptest.NewRecorder()
+		target.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code, w.Body.String())
+	})
+}
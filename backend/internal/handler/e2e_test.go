@@ -6,40 +6,80 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/what-writers-like/backend/internal/domain"
 	"github.com/what-writers-like/backend/internal/handler"
+	"github.com/what-writers-like/backend/internal/infrastructure/config"
 	"github.com/what-writers-like/backend/internal/repository/gorm"
 	"github.com/what-writers-like/backend/internal/service"
 	"github.com/what-writers-like/backend/internal/testutils"
 )
 
-func setupE2ERouter(t *testing.T) (*gin.Engine, func()) {
+const testSigningKey = "test-signing-key-0123456789abcdef"
+
+// setupE2ERouter returns the full router along with an admin token for
+// requests that need one.
+func setupE2ERouter(t *testing.T) (*gin.Engine, string, func()) {
 	db, cleanup := testutils.SetupTestDB(t)
 
 	writerRepo := gorm.NewWriterRepository(db)
 	workRepo := gorm.NewWorkRepository(db)
 	opinionRepo := gorm.NewOpinionRepository(db)
-
-	writerService := service.NewWriterService(writerRepo, workRepo)
-	workService := service.NewWorkService(workRepo, writerRepo)
-	opinionService := service.NewOpinionService(opinionRepo, writerRepo, workRepo)
+	graphRepo := gorm.NewGraphRepository(db)
+	auditRepo := gorm.NewAuditRepository(db)
+	sourceRepo := gorm.NewSourceRepository(db)
+
+	transactor := gorm.NewTransactor(db)
+	writerService := service.NewWriterService(writerRepo, workRepo, transactor)
+	workService := service.NewWorkService(workRepo, writerRepo, transactor)
+	opinionService := service.NewOpinionService(
+		opinionRepo, writerRepo, workRepo, gorm.NewOpinionRevisionRepository(db), sourceRepo, transactor,
+	)
+	sourceService := service.NewSourceService(sourceRepo, opinionRepo, transactor)
+	writerAliasService := service.NewWriterAliasService(gorm.NewWriterAliasRepository(db), writerRepo, transactor)
+	graphService := service.NewGraphService(writerRepo, workRepo, opinionRepo, graphRepo)
+	searchService := service.NewSearchService(gorm.NewSearchRepository(db))
+	auditService := service.NewAuditService(auditRepo)
+	importService := service.NewImportService(transactor)
+	exportService := service.NewExportService(writerRepo, workRepo, opinionRepo, sourceRepo)
+	backupService := service.NewBackupService(transactor)
+	cfg := &config.Config{AuthSigningKey: testSigningKey, MaxUploadBytes: config.DefaultMaxUploadBytes}
+	authService, err := service.NewAuthService(cfg)
+	require.NoError(t, err)
 
 	writerHandler := handler.NewWriterHandler(writerService)
 	workHandler := handler.NewWorkHandler(workService)
 	opinionHandler := handler.NewOpinionHandler(opinionService)
+	sourceHandler := handler.NewSourceHandler(sourceService)
+	writerAliasHandler := handler.NewWriterAliasHandler(writerAliasService)
+	graphHandler := handler.NewGraphHandler(graphService, service.NewGraphExportService(graphService))
+	searchHandler := handler.NewSearchHandler(searchService)
+	auditHandler := handler.NewAuditHandler(auditService)
+	importHandler := handler.NewImportHandler(importService, cfg)
+	exportHandler := handler.NewExportHandler(exportService)
+	backupHandler := handler.NewBackupHandler(backupService, cfg)
+	authHandler := handler.NewAuthHandler(authService)
+	authMiddleware := handler.NewAuthMiddleware(authService)
 
 	gin.SetMode(gin.TestMode)
-	router := handler.SetupRouter(writerHandler, workHandler, opinionHandler)
+	router := handler.SetupRouter(
+		writerHandler, workHandler, opinionHandler, graphHandler, sourceHandler, writerAliasHandler, searchHandler,
+		auditHandler, importHandler, exportHandler, backupHandler, authHandler, authMiddleware,
+	)
 
-	return router, cleanup
+	token, _, err := authService.IssueToken("e2e", domain.RoleAdmin, time.Hour)
+	require.NoError(t, err)
+
+	return router, token, cleanup
 }
 
 func TestE2E_WriterWorkflow(t *testing.T) {
 	t.Parallel()
-	router, cleanup := setupE2ERouter(t)
+	router, token, cleanup := setupE2ERouter(t)
 	defer cleanup()
 
 	// Create writer
@@ -50,6 +90,7 @@ func TestE2E_WriterWorkflow(t *testing.T) {
 	body, _ := json.Marshal(createReq)
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/writers", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
@@ -76,10 +117,10 @@ func TestE2E_WriterWorkflow(t *testing.T) {
 	router.ServeHTTP(w, req)
 
 	require.Equal(t, http.StatusOK, w.Code)
-	var listResp []map[string]interface{}
+	var listResp listResponse
 	err = json.Unmarshal(w.Body.Bytes(), &listResp)
 	require.NoError(t, err)
-	assert.GreaterOrEqual(t, len(listResp), 1)
+	assert.GreaterOrEqual(t, len(listResp.Items), 1)
 
 	// Update writer
 	bio := "English novelist"
@@ -91,6 +132,7 @@ func TestE2E_WriterWorkflow(t *testing.T) {
 	body, _ = json.Marshal(updateReq)
 	req = httptest.NewRequest(http.MethodPut, "/api/v1/writers/1", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
 	w = httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
@@ -111,7 +153,7 @@ func TestE2E_WriterWorkflow(t *testing.T) {
 
 func TestE2E_WorkWorkflow(t *testing.T) {
 	t.Parallel()
-	router, cleanup := setupE2ERouter(t)
+	router, token, cleanup := setupE2ERouter(t)
 	defer cleanup()
 
 	// Create writer first
@@ -122,6 +164,7 @@ func TestE2E_WorkWorkflow(t *testing.T) {
 	body, _ := json.Marshal(createWriterReq)
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/writers", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 	require.Equal(t, http.StatusCreated, w.Code)
@@ -134,6 +177,7 @@ func TestE2E_WorkWorkflow(t *testing.T) {
 	body, _ = json.Marshal(createWorkReq)
 	req = httptest.NewRequest(http.MethodPost, "/api/v1/works", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
 	w = httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
@@ -157,7 +201,7 @@ func TestE2E_WorkWorkflow(t *testing.T) {
 
 func TestE2E_OpinionWorkflow(t *testing.T) {
 	t.Parallel()
-	router, cleanup := setupE2ERouter(t)
+	router, token, cleanup := setupE2ERouter(t)
 	defer cleanup()
 
 	// Create two writers
@@ -168,6 +212,7 @@ func TestE2E_OpinionWorkflow(t *testing.T) {
 	body, _ := json.Marshal(createWriter1Req)
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/writers", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 	require.Equal(t, http.StatusCreated, w.Code)
@@ -179,6 +224,7 @@ func TestE2E_OpinionWorkflow(t *testing.T) {
 	body, _ = json.Marshal(createWriter2Req)
 	req = httptest.NewRequest(http.MethodPost, "/api/v1/writers", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
 	w = httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 	require.Equal(t, http.StatusCreated, w.Code)
@@ -191,6 +237,7 @@ func TestE2E_OpinionWorkflow(t *testing.T) {
 	body, _ = json.Marshal(createWorkReq)
 	req = httptest.NewRequest(http.MethodPost, "/api/v1/works", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
 	w = httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 	require.Equal(t, http.StatusCreated, w.Code)
@@ -206,6 +253,7 @@ func TestE2E_OpinionWorkflow(t *testing.T) {
 	body, _ = json.Marshal(createOpinionReq)
 	req = httptest.NewRequest(http.MethodPost, "/api/v1/opinions", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
 	w = httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
@@ -238,8 +286,153 @@ func TestE2E_OpinionWorkflow(t *testing.T) {
 	body, _ = json.Marshal(createOwnOpinionReq)
 	req = httptest.NewRequest(http.MethodPost, "/api/v1/opinions", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestE2E_Authorization(t *testing.T) {
+	t.Parallel()
+	router, token, cleanup := setupE2ERouter(t)
+	defer cleanup()
+
+	body := []byte(`{"name":"Jane Austen","birth_year":1775}`)
+
+	// Anonymous callers can read but not write
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/writers", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/writers", http.NoBody)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// Editors can create but not delete
+	tokenBody := bytes.NewBufferString(`{"subject":"ed","role":"editor"}`)
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/auth/token", tokenBody)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var tokenResp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tokenResp))
+	editorToken := tokenResp["token"].(string)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/writers", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+editorToken)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/v1/writers/1", http.NoBody)
+	req.Header.Set("Authorization", "Bearer "+editorToken)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/v1/opinions/writer/1/work/1", http.NoBody)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// Imports write, so they too need an editor
+	csv := "name,birth_year\nCharlotte Bronte,1816\n"
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/import/writers", bytes.NewBufferString(csv))
+	req.Header.Set("Content-Type", "text/csv")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/import/writers", bytes.NewBufferString(csv))
+	req.Header.Set("Content-Type", "text/csv")
+	req.Header.Set("Authorization", "Bearer "+editorToken)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// Exports only read, so anyone may take one
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/export/writers", http.NoBody)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Charlotte Bronte")
+
+	// Admins can delete
+	req = httptest.NewRequest(http.MethodDelete, "/api/v1/writers/1", http.NoBody)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestE2E_AuditLog(t *testing.T) {
+	t.Parallel()
+	router, token, cleanup := setupE2ERouter(t)
+	defer cleanup()
+
+	body := []byte(`{"name":"Jane Austen","birth_year":1775}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/writers", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("X-Request-ID", "create-austen")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "create-austen", w.Header().Get("X-Request-ID"))
+
+	body = []byte(`{"name":"J. Austen","birth_year":1775}`)
+	req = httptest.NewRequest(http.MethodPut, "/api/v1/writers/1", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	updateRequestID := w.Header().Get("X-Request-ID")
+	assert.NotEmpty(t, updateRequestID)
+
+	// The audit log is not public
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/audit", http.NoBody)
 	w = httptest.NewRecorder()
 	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
 
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/audit?entity_type=writer&entity_id=1", http.NoBody)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var entries []map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &entries))
+	require.Len(t, entries, 2)
+	assert.Equal(t, "update", entries[0]["action"])
+	assert.Equal(t, "e2e", entries[0]["actor"])
+	assert.Equal(t, updateRequestID, entries[0]["request_id"])
+	assert.Equal(t, "Jane Austen", entries[0]["before"].(map[string]interface{})["name"])
+	assert.Equal(t, "J. Austen", entries[0]["after"].(map[string]interface{})["name"])
+	assert.Equal(t, "create", entries[1]["action"])
+	assert.Equal(t, "create-austen", entries[1]["request_id"])
+	assert.Nil(t, entries[1]["before"])
+
+	rangeURL := "/api/v1/audit?since=2000-01-01T00:00:00Z&until=2001-01-01T00:00:00Z"
+	req = httptest.NewRequest(http.MethodGet, rangeURL, http.NoBody)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &entries))
+	assert.Empty(t, entries)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/audit?since=yesterday", http.NoBody)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
@@ -0,0 +1,278 @@
+package handler
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/service"
+)
+
+// exportFormat is how the rows of an export are written: CSV with a header
+// row, a JSON array, or newline-delimited JSON with an object a line.
+type exportFormat string
+
+const (
+	exportCSV    exportFormat = "csv"
+	exportJSON   exportFormat = "json"
+	exportNDJSON exportFormat = "ndjson"
+)
+
+var exportContentTypes = map[exportFormat]string{
+	exportCSV:    "text/csv; charset=utf-8",
+	exportJSON:   "application/json; charset=utf-8",
+	exportNDJSON: "application/x-ndjson; charset=utf-8",
+}
+
+// exportAll names the export bundling every entity in a zip archive.
+const exportAll = "all"
+
+// exportStatusTrailer is sent after the last row of an export: "complete",
+// or "failed" when reading the rows broke off midway, which the status
+// code, sent before the first row, cannot tell.
+const exportStatusTrailer = "X-Export-Status"
+
+// ExportHandler streams every writer, work, opinion or source as CSV, JSON
+// or NDJSON, or all of them at once as a zip archive of one file each.
+// Rows are written out as they are read, so an export of any size takes
+// the same memory. The CSV columns are those the import reads, plus id;
+// translations of quotes are only in the JSON formats.
+type ExportHandler struct {
+	exportService service.ExportService
+	entities      []exportEntity
+}
+
+// exportEntity writes every row of one entity in a given format.
+type exportEntity struct {
+	name  string
+	write func(ctx context.Context, w io.Writer, format exportFormat) error
+}
+
+func NewExportHandler(exportService service.ExportService) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+		entities: []exportEntity{
+			{name: "writers", write: exportTable[*domain.Writer]{
+				columns: []string{"id", "name", "birth_year", "death_year", "bio"},
+				record:  writerToRecord,
+				object:  writerToResponse,
+				each:    exportService.ExportWriters,
+			}.write},
+			{name: "works", write: exportTable[*domain.Work]{
+				columns: []string{
+					"id", "title", "author_ids", "publication_year", "genre", "original_language", "original_title",
+				},
+				record: workToRecord,
+				object: workToResponse,
+				each:   exportService.ExportWorks,
+			}.write},
+			{name: "opinions", write: exportTable[*domain.Opinion]{
+				columns: []string{
+					"id", "writer_id", "work_id", "target_writer_id", "sentiment_grade", "quote", "source",
+					"source_id", "page", "statement_year", "language",
+				},
+				record: opinionToRecord,
+				object: func(o *domain.Opinion) gin.H { return opinionToResponse(o, nil) },
+				each:   exportService.ExportOpinions,
+			}.write},
+			{name: "sources", write: exportTable[*domain.Source]{
+				columns: []string{
+					"id", "type", "title", "author", "publisher", "year", "isbn", "doi", "url",
+					"archive_location", "confirmed",
+				},
+				record: sourceToRecord,
+				object: sourceToResponse,
+				each:   exportService.ExportSources,
+			}.write},
+		},
+	}
+}
+
+// Export answers GET /export/:entity, where entity is writers, works,
+// opinions, sources or all. format is csv, the default, json or ndjson.
+func (h *ExportHandler) Export(c *gin.Context) {
+	format := exportFormat(c.DefaultQuery("format", string(exportCSV)))
+	if _, ok := exportContentTypes[format]; !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("invalid format %q: expected csv, json or ndjson", format),
+		})
+		return
+	}
+	ctx := c.Request.Context()
+
+	name := c.Param("entity")
+	if name == exportAll {
+		filename := fmt.Sprintf("what-writers-like-%s.zip", time.Now().UTC().Format(time.DateOnly))
+		streamExport(c, filename, "application/zip", func(w io.Writer) error {
+			archive := zip.NewWriter(w)
+			for _, entity := range h.entities {
+				file, err := archive.Create(entity.name + "." + string(format))
+				if err != nil {
+					return err
+				}
+				if err := entity.write(ctx, file, format); err != nil {
+					return err
+				}
+			}
+			// An archive cut short has no central directory, so it does not open
+			return archive.Close()
+		})
+		return
+	}
+	for _, entity := range h.entities {
+		if entity.name == name {
+			streamExport(c, name+"."+string(format), exportContentTypes[format], func(w io.Writer) error {
+				return entity.write(ctx, w, format)
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusNotFound, gin.H{
+		"error": fmt.Sprintf("unknown export %q: expected writers, works, opinions, sources or all", name),
+	})
+}
+
+// streamExport sends what write writes as a downloaded file, followed by
+// the export status trailer.
+func streamExport(c *gin.Context, filename, contentType string, write func(w io.Writer) error) {
+	header := c.Writer.Header()
+	header.Set("Content-Type", contentType)
+	header.Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	header.Set("Trailer", exportStatusTrailer)
+	c.Status(http.StatusOK)
+
+	if err := write(c.Writer); err != nil {
+		_ = c.Error(err)
+		header.Set(exportStatusTrailer, "failed")
+		return
+	}
+	header.Set(exportStatusTrailer, "complete")
+}
+
+// exportTable is how the rows of one entity are exported: its CSV columns,
+// a row as a CSV record and as a JSON object, and the walk over its rows.
+type exportTable[T any] struct {
+	columns []string
+	record  func(T) []string
+	object  func(T) gin.H
+	each    func(ctx context.Context, fn func(T) error) error
+}
+
+// write writes every row in format. A JSON array is left unclosed when the
+// rows break off, so that a cut-short export does not parse.
+func (t exportTable[T]) write(ctx context.Context, w io.Writer, format exportFormat) error {
+	switch format {
+	case exportCSV:
+		out := csv.NewWriter(w)
+		if err := out.Write(t.columns); err != nil {
+			return err
+		}
+		if err := t.each(ctx, func(row T) error { return out.Write(t.record(row)) }); err != nil {
+			return err
+		}
+		out.Flush()
+		return out.Error()
+	case exportNDJSON:
+		out := json.NewEncoder(w)
+		return t.each(ctx, func(row T) error { return out.Encode(t.object(row)) })
+	default:
+		if _, err := io.WriteString(w, "["); err != nil {
+			return err
+		}
+		rows := 0
+		err := t.each(ctx, func(row T) error {
+			data, err := json.Marshal(t.object(row))
+			if err != nil {
+				return err
+			}
+			separator := ",\n"
+			if rows == 0 {
+				separator = "\n"
+			}
+			rows++
+			if _, err := io.WriteString(w, separator); err != nil {
+				return err
+			}
+			_, err = w.Write(data)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		closing := "]\n"
+		if rows > 0 {
+			closing = "\n]\n"
+		}
+		_, err = io.WriteString(w, closing)
+		return err
+	}
+}
+
+func writerToRecord(w *domain.Writer) []string {
+	return []string{
+		formatID(w.ID()), w.Name(), strconv.Itoa(w.BirthYear()), formatOptionalInt(w.DeathYear()),
+		formatOptional(w.Bio()),
+	}
+}
+
+// workToRecord separates author IDs with semicolons, as the import does.
+func workToRecord(w *domain.Work) []string {
+	details := w.Details()
+	authorIDs := make([]string, len(w.AuthorIDs()))
+	for i, id := range w.AuthorIDs() {
+		authorIDs[i] = formatID(id)
+	}
+	return []string{
+		formatID(w.ID()), w.Title(), strings.Join(authorIDs, ";"), formatOptionalInt(details.PublicationYear),
+		formatOptional(details.Genre), formatOptional(details.OriginalLanguage), formatOptional(details.OriginalTitle),
+	}
+}
+
+func opinionToRecord(o *domain.Opinion) []string {
+	return []string{
+		formatID(o.ID()), formatID(o.WriterID()), formatID(o.WorkID()), formatID(o.TargetWriterID()),
+		string(o.Sentiment()), o.Quote(), o.Source(), formatID(o.SourceID()), formatOptional(o.Page()),
+		formatOptionalInt(o.StatementYear()), o.Languages().Language,
+	}
+}
+
+func sourceToRecord(s *domain.Source) []string {
+	details := s.Details()
+	return []string{
+		formatID(s.ID()), string(s.Type()), s.Title(), formatOptional(details.Author),
+		formatOptional(details.Publisher), formatOptionalInt(details.Year), formatOptional(details.ISBN),
+		formatOptional(details.DOI), formatOptional(details.URL), formatOptional(details.ArchiveLocation),
+		strconv.FormatBool(s.Confirmed()),
+	}
+}
+
+// formatID leaves a cell empty for the zero ID of an unset reference.
+func formatID(id uint64) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.FormatUint(id, 10)
+}
+
+func formatOptionalInt(value *int) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.Itoa(*value)
+}
+
+func formatOptional(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,191 @@
+package handler_test
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/handler"
+	"github.com/what-writers-like/backend/internal/repository/gorm"
+	"github.com/what-writers-like/backend/internal/service"
+	"github.com/what-writers-like/backend/internal/testutils"
+)
+
+func setupExportHandlerRouter(t *testing.T) (*gin.Engine, func()) {
+	db, cleanup := testutils.SetupTestDB(t)
+
+	writerRepo := gorm.NewWriterRepository(db)
+	workRepo := gorm.NewWorkRepository(db)
+	opinionRepo := gorm.NewOpinionRepository(db)
+	sourceRepo := gorm.NewSourceRepository(db)
+
+	require.NoError(t, writerRepo.Create(domain.NewWriter(0, "Leo Tolstoy", 1828, nil, nil)))
+	died := 1616
+	require.NoError(t, writerRepo.Create(domain.NewWriter(0, "William Shakespeare", 1564, &died, nil)))
+	year := 1606
+	require.NoError(t, workRepo.Create(domain.NewWork(0, "King Lear", []uint64{2}, domain.WorkDetails{
+		PublicationYear: &year,
+	})))
+	source := domain.NewSource(0, domain.SourceTypeEssay, "Shakespeare and the Drama", domain.SourceDetails{}, true)
+	require.NoError(t, sourceRepo.Create(source))
+	opinion := domain.NewOpinion(0, 1, 1, domain.SentimentVeryNegative, "Крайне плохо", "Essay", nil, nil)
+	opinion.SetSourceID(source.ID())
+	opinion.SetLanguages(domain.QuoteLanguages{
+		Language:     "ru",
+		Translations: []domain.Translation{{Language: "en", Text: "Very poor"}},
+	})
+	require.NoError(t, opinionRepo.Create(opinion))
+
+	exportHandler := handler.NewExportHandler(service.NewExportService(writerRepo, workRepo, opinionRepo, sourceRepo))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/export/:entity", exportHandler.Export)
+	return router, cleanup
+}
+
+func getExport(router *gin.Engine, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestExportHandler_Export(t *testing.T) {
+	t.Parallel()
+	router, cleanup := setupExportHandlerRouter(t)
+	defer cleanup()
+
+	t.Run("csv", func(t *testing.T) {
+		w := getExport(router, "/export/works")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="works.csv"`, w.Header().Get("Content-Disposition"))
+		assert.Equal(t, "complete", w.Result().Trailer.Get("X-Export-Status"))
+
+		records, err := csv.NewReader(w.Body).ReadAll()
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{
+			{"id", "title", "author_ids", "publication_year", "genre", "original_language", "original_title"},
+			{"1", "King Lear", "2", "1606", "", "", ""},
+		}, records)
+
+		w = getExport(router, "/export/opinions?format=csv")
+		records, err = csv.NewReader(w.Body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		assert.Equal(t, []string{"1", "1", "1", "", "-2", "Крайне плохо", "Essay", "1", "", "", "ru"}, records[1])
+	})
+
+	t.Run("json", func(t *testing.T) {
+		w := getExport(router, "/export/writers?format=json")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+
+		var writers []struct {
+			ID        uint64 `json:"id"`
+			Name      string `json:"name"`
+			DeathYear *int   `json:"death_year"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &writers))
+		require.Len(t, writers, 2)
+		assert.Equal(t, "Leo Tolstoy", writers[0].Name)
+		assert.Nil(t, writers[0].DeathYear)
+		require.NotNil(t, writers[1].DeathYear)
+		assert.Equal(t, 1616, *writers[1].DeathYear)
+	})
+
+	t.Run("ndjson", func(t *testing.T) {
+		w := getExport(router, "/export/opinions?format=ndjson")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/x-ndjson; charset=utf-8", w.Header().Get("Content-Type"))
+
+		var lines []string
+		scanner := bufio.NewScanner(w.Body)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		require.Len(t, lines, 1)
+		var opinion struct {
+			Quote        string `json:"quote"`
+			Translations []struct {
+				Text string `json:"text"`
+			} `json:"translations"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &opinion))
+		assert.Equal(t, "Крайне плохо", opinion.Quote)
+		require.Len(t, opinion.Translations, 1)
+		assert.Equal(t, "Very poor", opinion.Translations[0].Text)
+	})
+
+	t.Run("all", func(t *testing.T) {
+		w := getExport(router, "/export/all?format=ndjson")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/zip", w.Header().Get("Content-Type"))
+		assert.Regexp(t, `^attachment; filename="what-writers-like-\d{4}-\d{2}-\d{2}\.zip"$`,
+			w.Header().Get("Content-Disposition"))
+
+		archive, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+		require.NoError(t, err)
+		var names []string
+		for _, file := range archive.File {
+			names = append(names, file.Name)
+		}
+		assert.Equal(t, []string{"writers.ndjson", "works.ndjson", "opinions.ndjson", "sources.ndjson"}, names)
+
+		file, err := archive.File[3].Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(file)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"title":"Shakespeare and the Drama"`)
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		w := getExport(router, "/export/writers?format=xml")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		w = getExport(router, "/export/audit")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+// failingExportService gives out one writer and then fails, as a database
+// might midway through an export.
+type failingExportService struct {
+	service.ExportService
+}
+
+func (failingExportService) ExportWriters(_ context.Context, fn func(*domain.Writer) error) error {
+	if err := fn(domain.NewWriter(1, "Jane Austen", 1775, nil, nil)); err != nil {
+		return err
+	}
+	return errors.New("connection lost")
+}
+
+func TestExportHandler_ExportFailsMidway(t *testing.T) {
+	t.Parallel()
+	exportHandler := handler.NewExportHandler(failingExportService{})
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/export/:entity", exportHandler.Export)
+
+	// The rows already sent cannot be taken back, but the trailer tells
+	// and a JSON array is left unclosed
+	w := getExport(router, "/export/writers?format=json")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "failed", w.Result().Trailer.Get("X-Export-Status"))
+	assert.Contains(t, w.Body.String(), "Jane Austen")
+	var writers []any
+	assert.Error(t, json.Unmarshal(w.Body.Bytes(), &writers))
+}
@@ -0,0 +1,314 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/service"
+	"golang.org/x/text/language"
+)
+
+type GraphHandler struct {
+	graphService       service.GraphService
+	graphExportService service.GraphExportService
+}
+
+func NewGraphHandler(graphService service.GraphService, graphExportService service.GraphExportService) *GraphHandler {
+	return &GraphHandler{graphService: graphService, graphExportService: graphExportService}
+}
+
+// graphContentTypes are the media types of the graph export formats, with
+// the file extensions downloads are named with.
+var graphContentTypes = map[service.GraphFormat]struct{ mediaType, extension string }{
+	service.GraphFormatGraphML: {"application/graphml+xml", "graphml"},
+	service.GraphFormatGEXF:    {"application/gexf+xml", "gexf"},
+	service.GraphFormatDOT:     {"text/vnd.graphviz; charset=utf-8", "dot"},
+	service.GraphFormatCypher:  {"application/x-cypher-query; charset=utf-8", "cypher"},
+}
+
+func (h *GraphHandler) Get(c *gin.Context) {
+	filter, err := parseGraphFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	graph, err := h.graphService.GetGraph(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, graphToResponse(graph, preferredLanguages(c)))
+}
+
+// Export downloads the graph that Get returns, as format graphml, the
+// default, gexf, dot or cypher. The graph is built before anything is sent,
+// so a failure still answers with an error status.
+func (h *GraphHandler) Export(c *gin.Context) {
+	format, err := service.ParseGraphFormat(c.DefaultQuery("format", string(service.GraphFormatGraphML)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter, err := parseGraphFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var out bytes.Buffer
+	if err := h.graphExportService.ExportGraph(&out, filter, format); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	contentType := graphContentTypes[format]
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"graph.%s\"", contentType.extension))
+	c.Data(http.StatusOK, contentType.mediaType, out.Bytes())
+}
+
+// parseGraphFilter reads the writer_ids, work_ids and sentiment query
+// parameters that select the opinions of a graph.
+func parseGraphFilter(c *gin.Context) (service.GraphFilter, error) {
+	writerIDs, err := parseIDList(c.Query("writer_ids"))
+	if err != nil {
+		return service.GraphFilter{}, errors.New("invalid writer_ids")
+	}
+	workIDs, err := parseIDList(c.Query("work_ids"))
+	if err != nil {
+		return service.GraphFilter{}, errors.New("invalid work_ids")
+	}
+	sentiments, err := domain.ParseSentiments(c.Query("sentiment"))
+	if err != nil {
+		return service.GraphFilter{}, err
+	}
+	return service.GraphFilter{WriterIDs: writerIDs, WorkIDs: workIDs, Sentiments: sentiments}, nil
+}
+
+func (h *GraphHandler) GetWriterNeighborhood(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	depth, err := parseDepth(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	graph, err := h.graphService.GetWriterNeighborhood(id, depth)
+	if errors.Is(err, service.ErrNodeNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, graphToResponse(graph, preferredLanguages(c)))
+}
+
+func (h *GraphHandler) GetWorkNeighborhood(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	depth, err := parseDepth(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	graph, err := h.graphService.GetWorkNeighborhood(id, depth)
+	if errors.Is(err, service.ErrNodeNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, graphToResponse(graph, preferredLanguages(c)))
+}
+
+func (h *GraphHandler) GetShortestPath(c *gin.Context) {
+	fromID, err := strconv.ParseUint(c.Query("from"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from"})
+		return
+	}
+
+	toID, err := strconv.ParseUint(c.Query("to"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to"})
+		return
+	}
+
+	sentiments, err := domain.ParseSentiments(c.Query("sentiment"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	path, err := h.graphService.FindShortestPath(fromID, toID, sentiments)
+	switch {
+	case errors.Is(err, service.ErrPathToSelf):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, service.ErrNoPath), errors.Is(err, service.ErrWriterNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	preferred := preferredLanguages(c)
+	hops := make([]gin.H, len(path.Hops))
+	for i, hop := range path.Hops {
+		// A hop over an opinion about a writer as a whole has no work
+		var work gin.H
+		if hop.Work != nil {
+			work = workToResponse(hop.Work)
+		}
+		hops[i] = gin.H{
+			"from":    writerToResponse(hop.From),
+			"opinion": opinionToResponse(hop.Opinion, preferred),
+			"work":    work,
+			"to":      writerToResponse(hop.To),
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"length": len(hops),
+		"hops":   hops,
+	})
+}
+
+// parseDepth reads the depth query parameter, falling back to the default
+// when it is missing. Values above the maximum are capped by the service.
+func parseDepth(c *gin.Context) (int, error) {
+	raw, ok := c.GetQuery("depth")
+	if !ok {
+		return service.DefaultNeighborhoodDepth, nil
+	}
+	depth, err := strconv.Atoi(raw)
+	if err != nil || depth <= 0 {
+		return 0, errors.New("depth must be a positive integer")
+	}
+	return depth, nil
+}
+
+// parseIDList parses a comma-separated list of IDs such as "1,2,3".
+func parseIDList(raw string) ([]uint64, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	parts := strings.Split(raw, ",")
+	ids := make([]uint64, 0, len(parts))
+	for _, p := range parts {
+		id, err := strconv.ParseUint(strings.TrimSpace(p), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func writerNodeID(id uint64) string {
+	return fmt.Sprintf("writer-%d", id)
+}
+
+func workNodeID(id uint64) string {
+	return fmt.Sprintf("work-%d", id)
+}
+
+func graphToResponse(graph *service.Graph, preferred []language.Tag) gin.H {
+	writerIDs := make(map[uint64]struct{}, len(graph.Writers))
+	nodes := make([]gin.H, 0, len(graph.Writers)+len(graph.Works))
+	for _, w := range graph.Writers {
+		writerIDs[w.ID()] = struct{}{}
+		nodes = append(nodes, gin.H{
+			"id":         writerNodeID(w.ID()),
+			"type":       "writer",
+			"label":      w.Name(),
+			"writer_id":  w.ID(),
+			"birth_year": w.BirthYear(),
+			"death_year": w.DeathYear(),
+		})
+	}
+	workIDs := make(map[uint64]struct{}, len(graph.Works))
+	for _, w := range graph.Works {
+		workIDs[w.ID()] = struct{}{}
+		work := workToResponse(w)
+		nodes = append(nodes, gin.H{
+			"id":         workNodeID(w.ID()),
+			"type":       "work",
+			"label":      w.Title(),
+			"work_id":    w.ID(),
+			"author_id":  work["author_id"],
+			"author_ids": work["author_ids"],
+		})
+	}
+
+	edges := make([]gin.H, 0, len(graph.Works)+len(graph.Opinions))
+	for _, w := range graph.Works {
+		for _, authorID := range w.AuthorIDs() {
+			// A traversal may stop at a work before reaching its author
+			if _, ok := writerIDs[authorID]; !ok {
+				continue
+			}
+			edges = append(edges, gin.H{
+				"id":     fmt.Sprintf("authored-%d-%d", w.ID(), authorID),
+				"type":   "authored",
+				"source": writerNodeID(authorID),
+				"target": workNodeID(w.ID()),
+			})
+		}
+	}
+	for _, o := range graph.Opinions {
+		// Nor may an edge point at a node the graph does not hold, such as
+		// one whose writer or work was deleted from under it
+		if _, ok := writerIDs[o.WriterID()]; !ok {
+			continue
+		}
+		target := workNodeID(o.WorkID())
+		_, ok := workIDs[o.WorkID()]
+		if o.IsAboutWriter() {
+			target = writerNodeID(o.TargetWriterID())
+			_, ok = writerIDs[o.TargetWriterID()]
+		}
+		if !ok {
+			continue
+		}
+		edges = append(edges, gin.H{
+			"id":      fmt.Sprintf("opinion-%d", o.ID()),
+			"type":    "opinion",
+			"source":  writerNodeID(o.WriterID()),
+			"target":  target,
+			"opinion": opinionToResponse(o, preferred),
+		})
+	}
+
+	return gin.H{
+		"nodes":     nodes,
+		"edges":     edges,
+		"truncated": graph.Truncated,
+	}
+}
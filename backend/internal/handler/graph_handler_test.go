@@ -0,0 +1,459 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/handler"
+	"github.com/what-writers-like/backend/internal/repository/gorm"
+	"github.com/what-writers-like/backend/internal/repository/memory"
+	"github.com/what-writers-like/backend/internal/service"
+	"github.com/what-writers-like/backend/internal/testutils"
+)
+
+func setupGraphHandlerRouter(t *testing.T) (*gin.Engine, func()) {
+	db, cleanup := testutils.SetupTestDB(t)
+
+	writerRepo := gorm.NewWriterRepository(db)
+	workRepo := gorm.NewWorkRepository(db)
+	opinionRepo := gorm.NewOpinionRepository(db)
+
+	require.NoError(t, writerRepo.Create(domain.NewWriter(1, "Jane Austen", 1775, nil, nil)))
+	require.NoError(t, writerRepo.Create(domain.NewWriter(2, "Charlotte Bronte", 1816, nil, nil)))
+	require.NoError(t, workRepo.Create(domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})))
+	require.NoError(t, opinionRepo.Create(domain.NewOpinion(
+		0, 2, 1, domain.SentimentNegative, "Quote", "Source", nil, nil,
+	)))
+
+	graphService := service.NewGraphService(writerRepo, workRepo, opinionRepo, gorm.NewGraphRepository(db))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	graphHandler := handler.NewGraphHandler(graphService, service.NewGraphExportService(graphService))
+	router.GET("/graph", graphHandler.Get)
+	router.GET("/graph/export", graphHandler.Export)
+	router.GET("/graph/writers/:id/neighborhood", graphHandler.GetWriterNeighborhood)
+	router.GET("/graph/works/:id/neighborhood", graphHandler.GetWorkNeighborhood)
+	router.GET("/graph/path", graphHandler.GetShortestPath)
+	return router, cleanup
+}
+
+func TestGraphHandler_Get(t *testing.T) {
+	t.Parallel()
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		router, cleanup := setupGraphHandlerRouter(t)
+		defer cleanup()
+
+		req := httptest.NewRequest(http.MethodGet, "/graph", http.NoBody)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			Nodes []map[string]interface{} `json:"nodes"`
+			Edges []map[string]interface{} `json:"edges"`
+		}
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Len(t, response.Nodes, 3)
+		require.Len(t, response.Edges, 2)
+		assert.Equal(t, "authored", response.Edges[0]["type"])
+		assert.Equal(t, "writer-1", response.Edges[0]["source"])
+		assert.Equal(t, "opinion", response.Edges[1]["type"])
+		assert.Equal(t, "writer-2", response.Edges[1]["source"])
+		assert.Equal(t, "work-1", response.Edges[1]["target"])
+	})
+
+	t.Run("sentiment filter", func(t *testing.T) {
+		t.Parallel()
+		router, cleanup := setupGraphHandlerRouter(t)
+		defer cleanup()
+
+		req := httptest.NewRequest(http.MethodGet, "/graph?work_ids=1&sentiment=positive", http.NoBody)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			Nodes []map[string]interface{} `json:"nodes"`
+			Edges []map[string]interface{} `json:"edges"`
+		}
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		// Only the requested work and its author remain
+		assert.Len(t, response.Nodes, 2)
+		assert.Len(t, response.Edges, 1)
+	})
+
+	t.Run("sentiment grades", func(t *testing.T) {
+		t.Parallel()
+		router, cleanup := setupGraphHandlerRouter(t)
+		defer cleanup()
+
+		req := httptest.NewRequest(http.MethodGet, "/graph?sentiment=mixed,-1", http.NoBody)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			Edges []map[string]interface{} `json:"edges"`
+		}
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		require.Len(t, response.Edges, 2)
+		opinion, ok := response.Edges[1]["opinion"].(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, "-1", opinion["sentiment_grade"])
+	})
+
+	t.Run("invalid writer_ids", func(t *testing.T) {
+		t.Parallel()
+		router, cleanup := setupGraphHandlerRouter(t)
+		defer cleanup()
+
+		req := httptest.NewRequest(http.MethodGet, "/graph?writer_ids=1,abc", http.NoBody)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid sentiment", func(t *testing.T) {
+		t.Parallel()
+		router, cleanup := setupGraphHandlerRouter(t)
+		defer cleanup()
+
+		req := httptest.NewRequest(http.MethodGet, "/graph?sentiment=lukewarm", http.NoBody)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestGraphHandler_WriterOpinionEdges(t *testing.T) {
+	t.Parallel()
+	db, cleanup := testutils.SetupTestDB(t)
+	defer cleanup()
+
+	writerRepo := gorm.NewWriterRepository(db)
+	workRepo := gorm.NewWorkRepository(db)
+	opinionRepo := gorm.NewOpinionRepository(db)
+	require.NoError(t, writerRepo.Create(domain.NewWriter(1, "Anton Chekhov", 1860, nil, nil)))
+	require.NoError(t, writerRepo.Create(domain.NewWriter(2, "Leo Tolstoy", 1828, nil, nil)))
+	require.NoError(t, opinionRepo.Create(domain.NewWriterOpinion(
+		0, 2, 1, domain.SentimentVeryPositive, "A genius", "Diary", nil, nil,
+	)))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	graphService := service.NewGraphService(writerRepo, workRepo, opinionRepo, gorm.NewGraphRepository(db))
+	graphHandler := handler.NewGraphHandler(graphService, service.NewGraphExportService(graphService))
+	router.GET("/graph", graphHandler.Get)
+	router.GET("/graph/path", graphHandler.GetShortestPath)
+
+	req := httptest.NewRequest(http.MethodGet, "/graph", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var graph struct {
+		Nodes []map[string]interface{} `json:"nodes"`
+		Edges []map[string]interface{} `json:"edges"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &graph))
+	assert.Len(t, graph.Nodes, 2)
+	require.Len(t, graph.Edges, 1)
+	assert.Equal(t, "writer-2", graph.Edges[0]["source"])
+	assert.Equal(t, "writer-1", graph.Edges[0]["target"])
+
+	// The hop has no work to pass through
+	req = httptest.NewRequest(http.MethodGet, "/graph/path?from=2&to=1", http.NoBody)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var path struct {
+		Hops []map[string]interface{} `json:"hops"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &path))
+	require.Len(t, path.Hops, 1)
+	assert.Nil(t, path.Hops[0]["work"])
+}
+
+func TestGraphHandler_DropsDanglingEdges(t *testing.T) {
+	t.Parallel()
+	// The in-memory store leaves opinions behind when their writer or work
+	// is deleted
+	store := memory.NewStore()
+	writerRepo := memory.NewWriterRepository(store)
+	workRepo := memory.NewWorkRepository(store)
+	opinionRepo := memory.NewOpinionRepository(store)
+	require.NoError(t, writerRepo.Create(domain.NewWriter(1, "Jane Austen", 1775, nil, nil)))
+	require.NoError(t, writerRepo.Create(domain.NewWriter(2, "Charlotte Bronte", 1816, nil, nil)))
+	require.NoError(t, writerRepo.Create(domain.NewWriter(3, "Mark Twain", 1835, nil, nil)))
+	require.NoError(t, workRepo.Create(domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})))
+	require.NoError(t, opinionRepo.Create(domain.NewOpinion(
+		0, 2, 1, domain.SentimentNegative, "Quote", "Letters", nil, nil,
+	)))
+	require.NoError(t, opinionRepo.Create(domain.NewWriterOpinion(
+		0, 3, 1, domain.SentimentVeryNegative, "Quote", "Letters", nil, nil,
+	)))
+	require.NoError(t, workRepo.Delete(1))
+	require.NoError(t, writerRepo.Delete(3))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	graphService := service.NewGraphService(writerRepo, workRepo, opinionRepo, memory.NewGraphRepository(store))
+	graphHandler := handler.NewGraphHandler(graphService, service.NewGraphExportService(graphService))
+	router.GET("/graph", graphHandler.Get)
+
+	req := httptest.NewRequest(http.MethodGet, "/graph", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var graph struct {
+		Nodes []map[string]interface{} `json:"nodes"`
+		Edges []map[string]interface{} `json:"edges"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &graph))
+	assert.Len(t, graph.Nodes, 2)
+	assert.Empty(t, graph.Edges)
+}
+
+func TestGraphHandler_Export(t *testing.T) {
+	t.Parallel()
+	router, cleanup := setupGraphHandlerRouter(t)
+	defer cleanup()
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, http.NoBody)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("/graph/export")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/graphml+xml", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="graph.graphml"`, w.Header().Get("Content-Disposition"))
+	assert.Contains(t, w.Body.String(), `<edge id="opinion-1" source="writer-2" target="work-1">`)
+
+	w = get("/graph/export?format=dot&sentiment=-1")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/vnd.graphviz; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="graph.dot"`, w.Header().Get("Content-Disposition"))
+	assert.Contains(t, w.Body.String(), `"writer-2" -> "work-1" [label="-1"`)
+
+	// The filters are those of the graph API
+	w = get("/graph/export?format=cypher&sentiment=%2B2")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "OPINION")
+
+	for _, path := range []string{
+		"/graph/export?format=svg",
+		"/graph/export?format=gexf&writer_ids=abc",
+		"/graph/export?format=gexf&sentiment=great",
+	} {
+		assert.Equal(t, http.StatusBadRequest, get(path).Code, path)
+	}
+}
+
+func TestGraphHandler_GetWriterNeighborhood(t *testing.T) {
+	t.Parallel()
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		router, cleanup := setupGraphHandlerRouter(t)
+		defer cleanup()
+
+		req := httptest.NewRequest(http.MethodGet, "/graph/writers/2/neighborhood?depth=1", http.NoBody)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			Nodes     []map[string]interface{} `json:"nodes"`
+			Edges     []map[string]interface{} `json:"edges"`
+			Truncated bool                     `json:"truncated"`
+		}
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		// The author of the work lies two hops away, so no authorship edge
+		assert.Len(t, response.Nodes, 2)
+		require.Len(t, response.Edges, 1)
+		assert.Equal(t, "opinion", response.Edges[0]["type"])
+		assert.False(t, response.Truncated)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+		router, cleanup := setupGraphHandlerRouter(t)
+		defer cleanup()
+
+		req := httptest.NewRequest(http.MethodGet, "/graph/writers/999/neighborhood", http.NoBody)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		t.Parallel()
+		router, cleanup := setupGraphHandlerRouter(t)
+		defer cleanup()
+
+		req := httptest.NewRequest(http.MethodGet, "/graph/writers/abc/neighborhood", http.NoBody)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid depth", func(t *testing.T) {
+		t.Parallel()
+		router, cleanup := setupGraphHandlerRouter(t)
+		defer cleanup()
+
+		for _, depth := range []string{"0", "-1", "abc"} {
+			req := httptest.NewRequest(http.MethodGet, "/graph/writers/2/neighborhood?depth="+depth, http.NoBody)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, "depth=%s", depth)
+		}
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		router, cleanup := setupGraphHandlerRouter(t)
+		// A failing lookup is not mistaken for a missing writer
+		cleanup()
+
+		req := httptest.NewRequest(http.MethodGet, "/graph/writers/2/neighborhood", http.NoBody)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestGraphHandler_GetWorkNeighborhood(t *testing.T) {
+	t.Parallel()
+	router, cleanup := setupGraphHandlerRouter(t)
+	defer cleanup()
+
+	req := httptest.NewRequest(http.MethodGet, "/graph/works/1/neighborhood?depth=2", http.NoBody)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Nodes []map[string]interface{} `json:"nodes"`
+		Edges []map[string]interface{} `json:"edges"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+	assert.Len(t, response.Nodes, 2)
+	require.Len(t, response.Edges, 1)
+	assert.Equal(t, "authored", response.Edges[0]["type"])
+}
+
+func TestGraphHandler_GetShortestPath(t *testing.T) {
+	t.Parallel()
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		router, cleanup := setupGraphHandlerRouter(t)
+		defer cleanup()
+
+		req := httptest.NewRequest(http.MethodGet, "/graph/path?from=2&to=1", http.NoBody)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			Length int                      `json:"length"`
+			Hops   []map[string]interface{} `json:"hops"`
+		}
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, 1, response.Length)
+		require.Len(t, response.Hops, 1)
+		opinion, ok := response.Hops[0]["opinion"].(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, "Quote", opinion["quote"])
+		assert.Equal(t, "Source", opinion["source"])
+	})
+
+	t.Run("no path with sentiment", func(t *testing.T) {
+		t.Parallel()
+		router, cleanup := setupGraphHandlerRouter(t)
+		defer cleanup()
+
+		req := httptest.NewRequest(http.MethodGet, "/graph/path?from=2&to=1&sentiment=positive", http.NoBody)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("missing to", func(t *testing.T) {
+		t.Parallel()
+		router, cleanup := setupGraphHandlerRouter(t)
+		defer cleanup()
+
+		req := httptest.NewRequest(http.MethodGet, "/graph/path?from=2", http.NoBody)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("unknown writer", func(t *testing.T) {
+		t.Parallel()
+		router, cleanup := setupGraphHandlerRouter(t)
+		defer cleanup()
+
+		req := httptest.NewRequest(http.MethodGet, "/graph/path?from=2&to=999", http.NoBody)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "writer not found")
+	})
+
+	t.Run("same writer", func(t *testing.T) {
+		t.Parallel()
+		router, cleanup := setupGraphHandlerRouter(t)
+		defer cleanup()
+
+		req := httptest.NewRequest(http.MethodGet, "/graph/path?from=2&to=2", http.NoBody)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
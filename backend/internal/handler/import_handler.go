@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/what-writers-like/backend/internal/infrastructure/config"
+	"github.com/what-writers-like/backend/internal/service"
+)
+
+// ImportHandler loads writers, works and opinions from CSV files. The file
+// is the request body, or the "file" field of a multipart form. With
+// dry_run=true nothing is written and the report says what would have
+// been; on_conflict is error, skip or update. A file larger than the
+// configured upload limit is refused.
+type ImportHandler struct {
+	importService  service.ImportService
+	maxUploadBytes int64
+}
+
+func NewImportHandler(importService service.ImportService, cfg *config.Config) *ImportHandler {
+	return &ImportHandler{importService: importService, maxUploadBytes: cfg.MaxUploadBytes}
+}
+
+type importFunc func(ctx context.Context, file io.Reader, options service.ImportOptions) (*service.ImportReport, error)
+
+func (h *ImportHandler) ImportWriters(c *gin.Context) {
+	h.handle(c, h.importService.ImportWriters)
+}
+
+func (h *ImportHandler) ImportWorks(c *gin.Context) {
+	h.handle(c, h.importService.ImportWorks)
+}
+
+func (h *ImportHandler) ImportOpinions(c *gin.Context) {
+	h.handle(c, h.importService.ImportOpinions)
+}
+
+// handle answers 200 with the report of a dry run or an applied import,
+// 400 with the report when any row is invalid, 400 when the file cannot be
+// read, 413 when it is over the upload limit, and 500 when the records
+// cannot be read or written.
+func (h *ImportHandler) handle(c *gin.Context, run importFunc) {
+	dryRun, err := parseBoolParam(c, "dry_run")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	onConflict, err := service.ParseConflictPolicy(c.Query("on_conflict"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	file, err := importFile(c, h.maxUploadBytes)
+	if err != nil {
+		c.JSON(uploadErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	options := service.ImportOptions{DryRun: dryRun != nil && *dryRun, OnConflict: onConflict}
+	report, err := run(c.Request.Context(), file, options)
+	if errors.Is(err, service.ErrInvalidImportFile) {
+		c.JSON(uploadErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := importReportToResponse(report)
+	if len(report.Errors) > 0 && !report.DryRun {
+		response["error"] = "some rows are invalid; nothing was imported"
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// importFile opens the uploaded file of a multipart request, or else the
+// request body, reading no more than limit bytes of the request.
+func importFile(c *gin.Context, limit int64) (io.ReadCloser, error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+	if !strings.HasPrefix(c.ContentType(), "multipart/") {
+		return c.Request.Body, nil
+	}
+	header, err := c.FormFile("file")
+	if tooLarge := new(http.MaxBytesError); errors.As(err, &tooLarge) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, errors.New("file is required")
+	}
+	return header.Open()
+}
+
+// uploadErrorStatus answers 413 when reading an upload stopped at the
+// limit, and 400 for any other unreadable upload.
+func uploadErrorStatus(err error) int {
+	if tooLarge := new(http.MaxBytesError); errors.As(err, &tooLarge) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
+
+func importReportToResponse(r *service.ImportReport) gin.H {
+	errs := make([]gin.H, len(r.Errors))
+	for i, e := range r.Errors {
+		errs[i] = gin.H{"row": e.Row, "field": e.Field, "message": e.Message}
+	}
+	return gin.H{
+		"dry_run": r.DryRun,
+		"applied": r.Applied(),
+		"rows":    r.Rows,
+		"created": r.Created,
+		"updated": r.Updated,
+		"skipped": r.Skipped,
+		"errors":  errs,
+	}
+}
@@ -0,0 +1,231 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/handler"
+	"github.com/what-writers-like/backend/internal/infrastructure/config"
+	"github.com/what-writers-like/backend/internal/repository"
+	"github.com/what-writers-like/backend/internal/repository/gorm"
+	"github.com/what-writers-like/backend/internal/service"
+	"github.com/what-writers-like/backend/internal/testutils"
+)
+
+func setupImportHandlerRouter(t *testing.T, maxUploadBytes int64) (*gin.Engine, *repository.Repositories, func()) {
+	db, cleanup := testutils.SetupTestDB(t)
+
+	repos := &repository.Repositories{
+		Writers:  gorm.NewWriterRepository(db),
+		Works:    gorm.NewWorkRepository(db),
+		Opinions: gorm.NewOpinionRepository(db),
+	}
+	importHandler := handler.NewImportHandler(
+		service.NewImportService(gorm.NewTransactor(db)), &config.Config{MaxUploadBytes: maxUploadBytes},
+	)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/import/writers", importHandler.ImportWriters)
+	router.POST("/import/works", importHandler.ImportWorks)
+	router.POST("/import/opinions", importHandler.ImportOpinions)
+	return router, repos, cleanup
+}
+
+type importResponse struct {
+	DryRun  bool   `json:"dry_run"`
+	Applied bool   `json:"applied"`
+	Rows    int    `json:"rows"`
+	Created int    `json:"created"`
+	Updated int    `json:"updated"`
+	Skipped int    `json:"skipped"`
+	Error   string `json:"error"`
+	Errors  []struct {
+		Row     int    `json:"row"`
+		Field   string `json:"field"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func postImport(t *testing.T, router *gin.Engine, path, file string) (int, importResponse) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(file))
+	req.Header.Set("Content-Type", "text/csv")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var response importResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return w.Code, response
+}
+
+func TestImportHandler_ImportWriters(t *testing.T) {
+	t.Parallel()
+	router, repos, cleanup := setupImportHandlerRouter(t, config.DefaultMaxUploadBytes)
+	defer cleanup()
+
+	const file = "name,birth_year,death_year\nJane Austen,1775,1817\nCharles Dickens,1812,1870\n"
+
+	t.Run("dry run", func(t *testing.T) {
+		code, response := postImport(t, router, "/import/writers?dry_run=true", file)
+		require.Equal(t, http.StatusOK, code)
+		assert.True(t, response.DryRun)
+		assert.False(t, response.Applied)
+		assert.Equal(t, 2, response.Created)
+
+		page, err := repos.Writers.List(repository.WriterFilter{}, repository.WriterSort{}, nil, 10)
+		require.NoError(t, err)
+		assert.Zero(t, page.Total)
+	})
+
+	t.Run("import", func(t *testing.T) {
+		code, response := postImport(t, router, "/import/writers", file)
+		require.Equal(t, http.StatusOK, code)
+		assert.True(t, response.Applied)
+		assert.Equal(t, 2, response.Rows)
+		assert.Equal(t, 2, response.Created)
+
+		page, err := repos.Writers.List(repository.WriterFilter{}, repository.WriterSort{}, nil, 10)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), page.Total)
+	})
+
+	t.Run("invalid rows import nothing", func(t *testing.T) {
+		const invalid = "name,birth_year\nJane Austen,1775\nCharlotte Bronte,1816\n"
+		code, response := postImport(t, router, "/import/writers", invalid)
+		require.Equal(t, http.StatusBadRequest, code)
+		assert.False(t, response.Applied)
+		assert.NotEmpty(t, response.Error)
+		require.Len(t, response.Errors, 1)
+		assert.Equal(t, 2, response.Errors[0].Row)
+		assert.Equal(t, "writer already exists", response.Errors[0].Message)
+
+		writers, err := repos.Writers.GetByNameAndBirthYear("Charlotte Bronte", 1816)
+		require.NoError(t, err)
+		assert.Empty(t, writers)
+	})
+
+	t.Run("skip conflicts", func(t *testing.T) {
+		const more = "name,birth_year\nJane Austen,1775\nCharlotte Bronte,1816\n"
+		code, response := postImport(t, router, "/import/writers?on_conflict=skip", more)
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, 1, response.Skipped)
+		assert.Equal(t, 1, response.Created)
+	})
+
+	t.Run("multipart upload", func(t *testing.T) {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, err := form.CreateFormFile("file", "writers.csv")
+		require.NoError(t, err)
+		_, err = part.Write([]byte("name,birth_year\nGeorge Eliot,1819\n"))
+		require.NoError(t, err)
+		require.NoError(t, form.Close())
+
+		req := httptest.NewRequest(http.MethodPost, "/import/writers", &body)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		writers, err := repos.Writers.GetByNameAndBirthYear("George Eliot", 1819)
+		require.NoError(t, err)
+		assert.Len(t, writers, 1)
+	})
+
+	t.Run("invalid parameters and files", func(t *testing.T) {
+		for _, path := range []string{"/import/writers?dry_run=maybe", "/import/writers?on_conflict=merge"} {
+			code, _ := postImport(t, router, path, file)
+			assert.Equal(t, http.StatusBadRequest, code, path)
+		}
+		code, response := postImport(t, router, "/import/writers", "name,bio\nJane Austen,Novelist\n")
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, `missing column "birth_year"`, response.Error)
+	})
+}
+
+func TestImportHandler_UploadLimit(t *testing.T) {
+	t.Parallel()
+	router, repos, cleanup := setupImportHandlerRouter(t, 64)
+	defer cleanup()
+
+	file := "name,birth_year\n" + strings.Repeat("Jane Austen,1775\n", 8)
+
+	t.Run("body", func(t *testing.T) {
+		code, response := postImport(t, router, "/import/writers", file)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, code)
+		assert.NotEmpty(t, response.Error)
+	})
+
+	t.Run("multipart", func(t *testing.T) {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, err := form.CreateFormFile("file", "writers.csv")
+		require.NoError(t, err)
+		_, err = part.Write([]byte(file))
+		require.NoError(t, err)
+		require.NoError(t, form.Close())
+
+		req := httptest.NewRequest(http.MethodPost, "/import/writers", &body)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code, w.Body.String())
+	})
+
+	page, err := repos.Writers.List(repository.WriterFilter{}, repository.WriterSort{}, nil, 10)
+	require.NoError(t, err)
+	assert.Zero(t, page.Total)
+}
+
+func TestImportHandler_DatabaseError(t *testing.T) {
+	t.Parallel()
+	router, _, cleanup := setupImportHandlerRouter(t, config.DefaultMaxUploadBytes)
+	// A failing write is not mistaken for an invalid file
+	cleanup()
+
+	code, response := postImport(t, router, "/import/writers", "name,birth_year\nJane Austen,1775\n")
+	assert.Equal(t, http.StatusInternalServerError, code)
+	assert.NotEmpty(t, response.Error)
+}
+
+func TestImportHandler_ImportOpinions(t *testing.T) {
+	t.Parallel()
+	router, repos, cleanup := setupImportHandlerRouter(t, config.DefaultMaxUploadBytes)
+	defer cleanup()
+
+	require.NoError(t, repos.Writers.Create(domain.NewWriter(0, "Leo Tolstoy", 1828, nil, nil)))
+	require.NoError(t, repos.Writers.Create(domain.NewWriter(0, "William Shakespeare", 1564, nil, nil)))
+	require.NoError(t, repos.Works.Create(domain.NewWork(0, "King Lear", []uint64{2}, domain.WorkDetails{})))
+
+	const file = "writer_id,work_id,target_writer_id,sentiment_grade,quote,source\n" +
+		"1,1,,-2,A very poor play,Essay\n" +
+		"1,,2,-1,No genius,Essay\n"
+	code, response := postImport(t, router, "/import/opinions", file)
+	require.Equal(t, http.StatusOK, code, response.Error)
+	assert.Equal(t, 2, response.Created)
+
+	const revised = "writer_id,work_id,sentiment_grade,quote,source\n" +
+		"1,1,mixed,A very poor play,Essay\n" +
+		"2,1,+2,Mine own,Folio\n"
+	code, response = postImport(t, router, "/import/opinions?on_conflict=update", revised)
+	require.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, 1, response.Updated)
+	require.Len(t, response.Errors, 1)
+	assert.Equal(t, 3, response.Errors[0].Row)
+
+	// The valid row was rolled back along with the invalid one
+	opinions, err := repos.Opinions.GetByWriterAndWork(1, 1)
+	require.NoError(t, err)
+	require.Len(t, opinions, 1)
+	assert.Equal(t, domain.SentimentVeryNegative, opinions[0].Sentiment())
+}
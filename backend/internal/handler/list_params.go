@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The list endpoints return limit items a page, 10 unless asked for more
+// and never more than 100.
+const (
+	defaultLimit = 10
+	maxLimit     = 100
+)
+
+// searchOrder stands for the ranked order of search results in cursors,
+// which page through them by offset.
+const searchOrder = "search"
+
+// parseLimit reads the page size, falling back to the default when it is
+// missing or malformed.
+func parseLimit(c *gin.Context) int {
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil || limit <= 0 {
+		return defaultLimit
+	}
+	return min(limit, maxLimit)
+}
+
+// cursorToken is what an opaque cursor encodes: the sort and order of the
+// listing it belongs to, and where its previous page ended.
+type cursorToken struct {
+	Sort       string          `json:"s,omitempty"`
+	Descending bool            `json:"d,omitempty"`
+	After      json.RawMessage `json:"a"`
+}
+
+// parseCursor reads the cursor query parameter, returning nil when there is
+// none. A cursor only continues the listing it was taken from, in the same
+// sort and order.
+func parseCursor[C any](c *gin.Context, sort string, descending bool) (*C, error) {
+	raw := c.Query("cursor")
+	if raw == "" {
+		return nil, nil
+	}
+	errInvalid := errors.New("invalid cursor")
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, errInvalid
+	}
+	var token cursorToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, errInvalid
+	}
+	if token.Sort != sort || token.Descending != descending {
+		return nil, errors.New("cursor does not match sort and order")
+	}
+	var after C
+	if err := json.Unmarshal(token.After, &after); err != nil {
+		return nil, errInvalid
+	}
+	return &after, nil
+}
+
+// parseSearchOffset reads the cursor of a page of search results, which
+// holds the offset the page starts at.
+func parseSearchOffset(c *gin.Context) (int, error) {
+	offset, err := parseCursor[int](c, searchOrder, false)
+	if err != nil || offset == nil {
+		return 0, err
+	}
+	if *offset < 0 {
+		return 0, errors.New("invalid cursor")
+	}
+	return *offset, nil
+}
+
+// pageResponse wraps a page of items in the envelope of the list
+// endpoints. next_cursor, null on the last page, fetches the next one.
+func pageResponse[C any](items []gin.H, total int64, sort string, descending bool, next *C) gin.H {
+	var cursor *string
+	if next != nil {
+		// Cursors hold IDs, names and years, which always marshal
+		after, _ := json.Marshal(next)
+		data, _ := json.Marshal(cursorToken{Sort: sort, Descending: descending, After: after})
+		encoded := base64.RawURLEncoding.EncodeToString(data)
+		cursor = &encoded
+	}
+	return gin.H{"items": items, "total": total, "next_cursor": cursor}
+}
+
+// parseYearParam reads an optional year from the query string.
+func parseYearParam(c *gin.Context, name string) (*int, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+	year, err := strconv.Atoi(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: expected a year", name)
+	}
+	return &year, nil
+}
+
+// parseBoolParam reads an optional true or false from the query string.
+func parseBoolParam(c *gin.Context, name string) (*bool, error) {
+	switch c.Query(name) {
+	case "":
+		return nil, nil
+	case "true":
+		value := true
+		return &value, nil
+	case "false":
+		value := false
+		return &value, nil
+	default:
+		return nil, fmt.Errorf("invalid %s: expected true or false", name)
+	}
+}
+
+// parseIDListParam reads an optional comma-separated list of IDs from the
+// query string.
+func parseIDListParam(c *gin.Context, name string) ([]uint64, error) {
+	ids, err := parseIDList(c.Query(name))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: expected a comma-separated list of IDs", name)
+	}
+	return ids, nil
+}
+
+// parseSort reads the sort and order query parameters. sort must be one of
+// keys, or empty for the default order; order is asc, the default, or desc.
+func parseSort(c *gin.Context, keys ...string) (key string, descending bool, err error) {
+	key = c.Query("sort")
+	if key != "" && !containsString(keys, key) {
+		return "", false, fmt.Errorf("invalid sort %q: expected %s", key, strings.Join(keys, " or "))
+	}
+	switch order := c.Query("order"); order {
+	case "", "asc":
+		return key, false, nil
+	case "desc":
+		return key, true, nil
+	default:
+		return "", false, fmt.Errorf("invalid order %q: expected asc or desc", order)
+	}
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -1,12 +1,18 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/repository"
 	"github.com/what-writers-like/backend/internal/service"
+	"golang.org/x/text/language"
 )
 
 type OpinionHandler struct {
@@ -17,22 +23,83 @@ func NewOpinionHandler(opinionService service.OpinionService) *OpinionHandler {
 	return &OpinionHandler{opinionService: opinionService}
 }
 
+// CreateOpinionRequest takes the sentiment as a grade from -2 to +2 or
+// "mixed". Older clients may send the boolean sentiment instead, which maps
+// to +1 or -1; the grade wins when both are present. The opinion is about
+// either a work or, given target_writer_id, a writer as a whole. The source
+// citation may be left out when source_id names a catalogued source.
+// Language is the BCP 47 tag of the quote as written, which translations
+// require.
 type CreateOpinionRequest struct {
-	WriterID      uint64  `json:"writer_id"                binding:"required"`
-	WorkID        uint64  `json:"work_id"                  binding:"required"`
-	Sentiment     bool    `json:"sentiment"                binding:"required"`
-	Quote         string  `json:"quote"                    binding:"required"`
-	Source        string  `json:"source"                   binding:"required"`
-	Page          *string `json:"page,omitempty"`
-	StatementYear *int    `json:"statement_year,omitempty"`
+	WriterID       uint64               `json:"writer_id"                  binding:"required"`
+	WorkID         uint64               `json:"work_id,omitempty"`
+	TargetWriterID uint64               `json:"target_writer_id,omitempty"`
+	SentimentGrade *string              `json:"sentiment_grade,omitempty"`
+	Sentiment      *bool                `json:"sentiment,omitempty"`
+	Quote          string               `json:"quote"                    binding:"required"`
+	Source         string               `json:"source"`
+	SourceID       uint64               `json:"source_id,omitempty"`
+	Page           *string              `json:"page,omitempty"`
+	StatementYear  *int                 `json:"statement_year,omitempty"`
+	Language       string               `json:"language,omitempty"`
+	Translations   []TranslationRequest `json:"translations,omitempty"`
 }
 
+// UpdateOpinionRequest leaves the catalogued source, the language of the
+// quote and its translations as they are when left out, so that clients
+// that predate them do not erase them. A source_id of null or zero
+// unlinks the source.
 type UpdateOpinionRequest struct {
-	Sentiment     bool    `json:"sentiment"                binding:"required"`
-	Quote         string  `json:"quote"                    binding:"required"`
-	Source        string  `json:"source"                   binding:"required"`
-	Page          *string `json:"page,omitempty"`
-	StatementYear *int    `json:"statement_year,omitempty"`
+	SentimentGrade *string               `json:"sentiment_grade,omitempty"`
+	Sentiment      *bool                 `json:"sentiment,omitempty"`
+	Quote          string                `json:"quote"                    binding:"required"`
+	Source         string                `json:"source"`
+	SourceID       optional[uint64]      `json:"source_id"`
+	Page           *string               `json:"page,omitempty"`
+	StatementYear  *int                  `json:"statement_year,omitempty"`
+	Language       *string               `json:"language,omitempty"`
+	Translations   *[]TranslationRequest `json:"translations,omitempty"`
+}
+
+// TranslationRequest is a translation of the quote into another language,
+// crediting the translator and publication it is taken from if known.
+type TranslationRequest struct {
+	Language   string  `json:"language"             binding:"required"`
+	Text       string  `json:"text"                 binding:"required"`
+	Translator *string `json:"translator,omitempty"`
+	Source     *string `json:"source,omitempty"`
+}
+
+// requestLanguages gathers the language of a create or update request's
+// quote and its translations.
+func requestLanguages(original string, translations []TranslationRequest) domain.QuoteLanguages {
+	return domain.QuoteLanguages{Language: original, Translations: requestTranslations(translations)}
+}
+
+func requestTranslations(translations []TranslationRequest) []domain.Translation {
+	var result []domain.Translation
+	for _, t := range translations {
+		result = append(result, domain.Translation{
+			Language:   t.Language,
+			Text:       t.Text,
+			Translator: t.Translator,
+			Source:     t.Source,
+		})
+	}
+	return result
+}
+
+// requestSentiment reads the graded sentiment of a create or update
+// request, falling back to the boolean one sent by older clients.
+func requestSentiment(grade *string, positive *bool) (domain.Sentiment, error) {
+	switch {
+	case grade != nil:
+		return domain.ParseSentiment(*grade)
+	case positive != nil:
+		return domain.SentimentFromBool(*positive), nil
+	default:
+		return "", errors.New("sentiment_grade is required")
+	}
 }
 
 func (h *OpinionHandler) Create(c *gin.Context) {
@@ -41,30 +108,50 @@ func (h *OpinionHandler) Create(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if (req.WorkID == 0) == (req.TargetWriterID == 0) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "exactly one of work_id and target_writer_id is required"})
+		return
+	}
+	sentiment, err := requestSentiment(req.SentimentGrade, req.Sentiment)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	opinion, err := h.opinionService.CreateOpinion(
-		req.WriterID,
-		req.WorkID,
-		req.Sentiment,
-		req.Quote,
-		req.Source,
-		req.Page,
-		req.StatementYear,
-	)
+	var opinion *domain.Opinion
+	if req.TargetWriterID != 0 {
+		opinion, err = h.opinionService.CreateWriterOpinion(
+			c.Request.Context(),
+			req.WriterID,
+			req.TargetWriterID,
+			sentiment,
+			req.Quote,
+			req.Source,
+			req.Page,
+			req.StatementYear,
+			req.SourceID,
+			requestLanguages(req.Language, req.Translations),
+		)
+	} else {
+		opinion, err = h.opinionService.CreateOpinion(
+			c.Request.Context(),
+			req.WriterID,
+			req.WorkID,
+			sentiment,
+			req.Quote,
+			req.Source,
+			req.Page,
+			req.StatementYear,
+			req.SourceID,
+			requestLanguages(req.Language, req.Translations),
+		)
+	}
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
-		"writer_id":      opinion.WriterID(),
-		"work_id":        opinion.WorkID(),
-		"sentiment":      opinion.Sentiment(),
-		"quote":          opinion.Quote(),
-		"source":         opinion.Source(),
-		"page":           opinion.Page(),
-		"statement_year": opinion.StatementYear(),
-	})
+	c.JSON(http.StatusCreated, opinionToResponse(opinion, preferredLanguages(c)))
 }
 
 func (h *OpinionHandler) GetByWriter(c *gin.Context) {
@@ -81,7 +168,7 @@ func (h *OpinionHandler) GetByWriter(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, h.opinionsToResponse(opinions))
+	c.JSON(http.StatusOK, h.opinionsToResponse(opinions, preferredLanguages(c)))
 }
 
 func (h *OpinionHandler) GetByWork(c *gin.Context) {
@@ -98,128 +185,530 @@ func (h *OpinionHandler) GetByWork(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, h.opinionsToResponse(opinions))
+	c.JSON(http.StatusOK, h.opinionsToResponse(opinions, preferredLanguages(c)))
 }
 
-func (h *OpinionHandler) GetByWriterAndWork(c *gin.Context) {
-	writerIDStr := c.Param("writer_id")
-	writerID, err := strconv.ParseUint(writerIDStr, 10, 64)
+// GetAboutWriter returns the opinions about the writer as a whole, leaving
+// out those about their works.
+func (h *OpinionHandler) GetAboutWriter(c *gin.Context) {
+	writerID, err := strconv.ParseUint(c.Param("writer_id"), 10, 64)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid writer_id"})
 		return
 	}
 
-	workIDStr := c.Param("work_id")
-	workID, err := strconv.ParseUint(workIDStr, 10, 64)
+	opinions, err := h.opinionService.GetOpinionsAboutWriter(writerID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid work_id"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	opinion, err := h.opinionService.GetOpinion(writerID, workID)
+	c.JSON(http.StatusOK, h.opinionsToResponse(opinions, preferredLanguages(c)))
+}
+
+// GetByWriterAboutWriter returns every statement one writer made about
+// another as a whole, dated ones first in chronological order.
+func (h *OpinionHandler) GetByWriterAboutWriter(c *gin.Context) {
+	writerID, err := strconv.ParseUint(c.Param("writer_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid writer_id"})
+		return
+	}
+	targetWriterID, err := strconv.ParseUint(c.Param("target_writer_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid target_writer_id"})
+		return
+	}
+
+	opinions, err := h.opinionService.GetOpinionsByWriterAboutWriter(writerID, targetWriterID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.opinionsToResponse(opinions, preferredLanguages(c)))
+}
+
+// GetByID returns a single statement.
+func (h *OpinionHandler) GetByID(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	opinion, err := h.opinionService.GetOpinion(id)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "opinion not found"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"writer_id":      opinion.WriterID(),
-		"work_id":        opinion.WorkID(),
-		"sentiment":      opinion.Sentiment(),
-		"quote":          opinion.Quote(),
-		"source":         opinion.Source(),
-		"page":           opinion.Page(),
-		"statement_year": opinion.StatementYear(),
-	})
+	c.JSON(http.StatusOK, opinionToResponse(opinion, preferredLanguages(c)))
+}
+
+// GetByWriterAndWork returns every statement the writer made about the
+// work, dated ones first in chronological order.
+func (h *OpinionHandler) GetByWriterAndWork(c *gin.Context) {
+	writerID, workID, ok := parseOpinionPair(c)
+	if !ok {
+		return
+	}
+
+	opinions, err := h.opinionService.GetOpinionsByWriterAndWork(writerID, workID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if len(opinions) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "opinion not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.opinionsToResponse(opinions, preferredLanguages(c)))
 }
 
+// List returns a page of opinions, optionally narrowed by sentiment (as
+// for the graph), statement year range (year_from and year_to,
+// inclusive), and by comma-separated lists of writer_id, work_author_id
+// and source_id. They are ordered by ID, or by sort=statement_year with
+// undated statements last; order is asc or desc.
 func (h *OpinionHandler) List(c *gin.Context) {
-	limitStr := c.DefaultQuery("limit", "10")
-	offsetStr := c.DefaultQuery("offset", "0")
+	limit := parseLimit(c)
 
-	limit, err := strconv.Atoi(limitStr)
+	filter, err := parseOpinionFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	key, descending, err := parseSort(c, string(repository.OpinionSortStatementYear))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	after, err := parseCursor[repository.OpinionCursor](c, key, descending)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	sort := repository.OpinionSort{Key: repository.OpinionSortKey(key), Descending: descending}
+
+	page, err := h.opinionService.ListOpinions(filter, sort, after, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	items := h.opinionsToResponse(page.Items, preferredLanguages(c))
+	c.JSON(http.StatusOK, pageResponse(items, page.Total, key, descending, page.Next))
+}
+
+func parseOpinionFilter(c *gin.Context) (repository.OpinionFilter, error) {
+	var filter repository.OpinionFilter
+	var err error
+	if filter.Sentiments, err = domain.ParseSentiments(c.Query("sentiment")); err != nil {
+		return filter, err
+	}
+	if filter.StatementYearFrom, err = parseYearParam(c, "year_from"); err != nil {
+		return filter, err
+	}
+	if filter.StatementYearTo, err = parseYearParam(c, "year_to"); err != nil {
+		return filter, err
+	}
+	if filter.WriterIDs, err = parseIDListParam(c, "writer_id"); err != nil {
+		return filter, err
+	}
+	if filter.WorkAuthorIDs, err = parseIDListParam(c, "work_author_id"); err != nil {
+		return filter, err
+	}
+	filter.SourceIDs, err = parseIDListParam(c, "source_id")
+	return filter, err
+}
+
+// SearchQuotes runs a full-text search of quotes for the q query parameter.
+// Each result is an opinion with the snippet of its quote that matched, as
+// highlight segments, and its rank. The optional language parameter keeps
+// quotes written in that language.
+func (h *OpinionHandler) SearchQuotes(c *gin.Context) {
+	query := c.Query("q")
+	if strings.TrimSpace(query) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+	quoteLanguage := c.Query("language")
+	if quoteLanguage != "" {
+		var err error
+		if quoteLanguage, err = domain.ParseLanguage(quoteLanguage); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
 	if err != nil || limit <= 0 {
 		limit = 10
 	}
-
-	offset, err := strconv.Atoi(offsetStr)
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
 	if err != nil || offset < 0 {
 		offset = 0
 	}
 
-	opinions, err := h.opinionService.ListOpinions(limit, offset)
+	matches, err := h.opinionService.SearchQuotes(query, quoteLanguage, limit, offset)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, h.opinionsToResponse(opinions))
+	preferred := preferredLanguages(c)
+	result := make([]gin.H, len(matches))
+	for i, m := range matches {
+		snippet := make([]gin.H, len(m.Snippet))
+		for j, segment := range m.Snippet {
+			snippet[j] = gin.H{"text": segment.Text, "match": segment.Match}
+		}
+		result[i] = opinionToResponse(m.Opinion, preferred)
+		result[i]["snippet"] = snippet
+		result[i]["rank"] = m.Rank
+	}
+	c.JSON(http.StatusOK, result)
 }
 
-func (h *OpinionHandler) opinionsToResponse(opinions []*domain.Opinion) []gin.H {
+func (h *OpinionHandler) opinionsToResponse(opinions []*domain.Opinion, preferred []language.Tag) []gin.H {
 	result := make([]gin.H, len(opinions))
 	for i, o := range opinions {
-		result[i] = gin.H{
-			"writer_id":      o.WriterID(),
-			"work_id":        o.WorkID(),
-			"sentiment":      o.Sentiment(),
-			"quote":          o.Quote(),
-			"source":         o.Source(),
-			"page":           o.Page(),
-			"statement_year": o.StatementYear(),
-		}
+		result[i] = opinionToResponse(o, preferred)
 	}
 	return result
 }
 
+// opinionToResponse reports the graded sentiment alongside a boolean one
+// for older clients, true for grades above the middle of the scale.
+// target_type tells which of work_id and target_writer_id is set; the other
+// is null, as is source_id for an opinion not linked to a catalogued source.
+// quote is always the original; localized holds the text in the language
+// the reader prefers, which is the original when nothing better matches.
+func opinionToResponse(o *domain.Opinion, preferred []language.Tag) gin.H {
+	languages := o.Languages()
+	translations := make([]gin.H, len(languages.Translations))
+	for i, t := range languages.Translations {
+		translations[i] = translationToResponse(t)
+	}
+	localized := gin.H{
+		"language":   nil,
+		"quote":      o.Quote(),
+		"translator": nil,
+		"source":     nil,
+	}
+	if languages.Language != "" {
+		localized["language"] = languages.Language
+	}
+	if text, t := o.Localize(preferred); t != nil {
+		localized = gin.H{
+			"language":   t.Language,
+			"quote":      text,
+			"translator": t.Translator,
+			"source":     t.Source,
+		}
+	}
+
+	response := gin.H{
+		"id":               o.ID(),
+		"writer_id":        o.WriterID(),
+		"target_type":      "work",
+		"work_id":          o.WorkID(),
+		"target_writer_id": nil,
+		"sentiment_grade":  o.Sentiment(),
+		"sentiment":        o.Sentiment().IsPositive(),
+		"quote":            o.Quote(),
+		"source":           o.Source(),
+		"source_id":        nil,
+		"page":             o.Page(),
+		"statement_year":   o.StatementYear(),
+		"language":         nil,
+		"translations":     translations,
+		"localized":        localized,
+	}
+	if languages.Language != "" {
+		response["language"] = languages.Language
+	}
+	if o.SourceID() != 0 {
+		response["source_id"] = o.SourceID()
+	}
+	if o.IsAboutWriter() {
+		response["target_type"] = "writer"
+		response["work_id"] = nil
+		response["target_writer_id"] = o.TargetWriterID()
+	}
+	return response
+}
+
 func (h *OpinionHandler) Update(c *gin.Context) {
-	writerIDStr := c.Param("writer_id")
-	writerID, err := strconv.ParseUint(writerIDStr, 10, 64)
+	var req UpdateOpinionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	sentiment, err := requestSentiment(req.SentimentGrade, req.Sentiment)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid writer_id"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	workIDStr := c.Param("work_id")
-	workID, err := strconv.ParseUint(workIDStr, 10, 64)
+	id, ok := h.opinionIDFromPath(c)
+	if !ok {
+		return
+	}
+	var translations *[]domain.Translation
+	if req.Translations != nil {
+		t := requestTranslations(*req.Translations)
+		translations = &t
+	}
+
+	err = h.opinionService.UpdateOpinion(
+		c.Request.Context(),
+		id,
+		sentiment,
+		req.Quote,
+		req.Source,
+		req.Page,
+		req.StatementYear,
+		req.SourceID.sent(),
+		req.Language,
+		translations,
+	)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid work_id"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	var req UpdateOpinionRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	c.JSON(http.StatusOK, gin.H{"message": "opinion updated"})
+}
+
+func (h *OpinionHandler) Delete(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	if err := h.opinionService.DeleteOpinion(c.Request.Context(), id); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	if err := h.opinionService.UpdateOpinion(writerID, workID, req.Sentiment, req.Quote, req.Source, req.Page, req.StatementYear); err != nil {
+	c.JSON(http.StatusOK, gin.H{"message": "opinion deleted"})
+}
+
+// DeleteByWriterAndWork deletes every statement the writer made about the
+// work.
+func (h *OpinionHandler) DeleteByWriterAndWork(c *gin.Context) {
+	writerID, workID, ok := parseOpinionPair(c)
+	if !ok {
+		return
+	}
+
+	if err := h.opinionService.DeleteOpinionsByWriterAndWork(c.Request.Context(), writerID, workID); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "opinion updated"})
+	c.JSON(http.StatusOK, gin.H{"message": "opinion deleted"})
 }
 
-func (h *OpinionHandler) Delete(c *gin.Context) {
-	writerIDStr := c.Param("writer_id")
-	writerID, err := strconv.ParseUint(writerIDStr, 10, 64)
+func (h *OpinionHandler) ListRevisions(c *gin.Context) {
+	id, ok := h.opinionIDFromPath(c)
+	if !ok {
+		return
+	}
+
+	revisions, err := h.opinionService.ListRevisions(id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid writer_id"})
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
 
-	workIDStr := c.Param("work_id")
-	workID, err := strconv.ParseUint(workIDStr, 10, 64)
+	result := make([]gin.H, len(revisions))
+	for i, r := range revisions {
+		result[i] = revisionToResponse(r, preferredLanguages(c))
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *OpinionHandler) GetRevision(c *gin.Context) {
+	id, ok := h.opinionIDFromPath(c)
+	if !ok {
+		return
+	}
+
+	number, err := parseRevisionNumber(c.Param("revision"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid work_id"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid revision"})
+		return
+	}
+
+	revision, err := h.opinionService.GetRevision(id, number)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, revisionToResponse(revision, preferredLanguages(c)))
+}
+
+// DiffRevisions compares the revisions given by the from and to query
+// parameters, listing the fields that changed between them.
+func (h *OpinionHandler) DiffRevisions(c *gin.Context) {
+	id, ok := h.opinionIDFromPath(c)
+	if !ok {
+		return
+	}
+
+	from, err := parseRevisionNumber(c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from"})
+		return
+	}
+	to, err := parseRevisionNumber(c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to"})
+		return
+	}
+
+	changes, err := h.opinionService.DiffRevisions(id, from, to)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
 
-	if err := h.opinionService.DeleteOpinion(writerID, workID); err != nil {
+	result := make([]gin.H, len(changes))
+	for i, change := range changes {
+		result[i] = gin.H{
+			"field": change.Field,
+			"from":  change.From,
+			"to":    change.To,
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"from":    from,
+		"to":      to,
+		"changes": result,
+	})
+}
+
+func (h *OpinionHandler) RestoreRevision(c *gin.Context) {
+	id, ok := h.opinionIDFromPath(c)
+	if !ok {
+		return
+	}
+
+	number, err := parseRevisionNumber(c.Param("revision"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid revision"})
+		return
+	}
+
+	revision, err := h.opinionService.RestoreRevision(c.Request.Context(), id, number)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "opinion deleted"})
+	c.JSON(http.StatusCreated, revisionToResponse(revision, preferredLanguages(c)))
+}
+
+// opinionIDFromPath resolves the opinion a request addresses, either by ID
+// or, on the older routes, by writer and work. A pair only identifies an
+// opinion while the writer has made a single statement about the work.
+func (h *OpinionHandler) opinionIDFromPath(c *gin.Context) (uint64, bool) {
+	if idStr := c.Param("id"); idStr != "" {
+		id, err := strconv.ParseUint(idStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return 0, false
+		}
+		return id, true
+	}
+
+	writerID, workID, ok := parseOpinionPair(c)
+	if !ok {
+		return 0, false
+	}
+	opinions, err := h.opinionService.GetOpinionsByWriterAndWork(writerID, workID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return 0, false
+	}
+	switch len(opinions) {
+	case 0:
+		c.JSON(http.StatusNotFound, gin.H{"error": "opinion not found"})
+		return 0, false
+	case 1:
+		return opinions[0].ID(), true
+	default:
+		c.JSON(http.StatusConflict, gin.H{
+			"error": fmt.Sprintf("writer made %d statements about this work; address one by id", len(opinions)),
+		})
+		return 0, false
+	}
+}
+
+// parseOpinionPair reads the writer_id and work_id path parameters,
+// responding with 400 when either is invalid.
+func parseOpinionPair(c *gin.Context) (writerID, workID uint64, ok bool) {
+	writerID, err := strconv.ParseUint(c.Param("writer_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid writer_id"})
+		return 0, 0, false
+	}
+	workID, err = strconv.ParseUint(c.Param("work_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid work_id"})
+		return 0, 0, false
+	}
+	return writerID, workID, true
+}
+
+func parseRevisionNumber(s string) (int, error) {
+	number, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, err
+	}
+	if number < 1 {
+		return 0, errors.New("revision numbers start at 1")
+	}
+	return number, nil
+}
+
+func revisionToResponse(r *domain.OpinionRevision, preferred []language.Tag) gin.H {
+	response := opinionToResponse(r.Opinion(), preferred)
+	response["revision"] = r.Number()
+	response["actor"] = r.Actor()
+	response["created_at"] = r.CreatedAt().UTC().Format(time.RFC3339Nano)
+	return response
+}
+
+func translationToResponse(t domain.Translation) gin.H {
+	return gin.H{
+		"language":   t.Language,
+		"text":       t.Text,
+		"translator": t.Translator,
+		"source":     t.Source,
+	}
+}
+
+// preferredLanguages reads the languages a reader wants quotes in: the
+// lang query parameter if it holds a valid tag, else the Accept-Language
+// header. Neither being usable leaves quotes in their original language.
+func preferredLanguages(c *gin.Context) []language.Tag {
+	if lang := c.Query("lang"); lang != "" {
+		if tag, err := language.Parse(lang); err == nil {
+			return []language.Tag{tag}
+		}
+	}
+	tags, _, err := language.ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+	if err != nil {
+		return nil
+	}
+	return tags
 }
@@ -3,8 +3,11 @@ package handler_test
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"sync"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -12,6 +15,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/what-writers-like/backend/internal/domain"
 	"github.com/what-writers-like/backend/internal/handler"
+	"github.com/what-writers-like/backend/internal/infrastructure/database"
 	"github.com/what-writers-like/backend/internal/repository"
 	"github.com/what-writers-like/backend/internal/repository/gorm"
 	"github.com/what-writers-like/backend/internal/service"
@@ -22,23 +26,44 @@ func setupOpinionHandlerRouter(
 	t *testing.T,
 ) (*gin.Engine, repository.OpinionRepository, repository.WriterRepository, repository.WorkRepository, func()) {
 	db, cleanup := testutils.SetupTestDB(t)
+	router := newOpinionHandlerRouter(db)
+	return router, gorm.NewOpinionRepository(db), gorm.NewWriterRepository(db), gorm.NewWorkRepository(db), cleanup
+}
 
+func newOpinionHandlerRouter(db *database.Database) *gin.Engine {
 	opinionRepo := gorm.NewOpinionRepository(db)
 	writerRepo := gorm.NewWriterRepository(db)
 	workRepo := gorm.NewWorkRepository(db)
-	opinionService := service.NewOpinionService(opinionRepo, writerRepo, workRepo)
+	opinionService := service.NewOpinionService(
+		opinionRepo, writerRepo, workRepo, gorm.NewOpinionRevisionRepository(db), gorm.NewSourceRepository(db),
+		gorm.NewTransactor(db),
+	)
 
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
 	opinionHandler := handler.NewOpinionHandler(opinionService)
 	router.POST("/opinions", opinionHandler.Create)
 	router.GET("/opinions", opinionHandler.List)
+	router.GET("/opinions/search", opinionHandler.SearchQuotes)
+	router.GET("/opinions/:id", opinionHandler.GetByID)
+	router.PUT("/opinions/:id", opinionHandler.Update)
+	router.DELETE("/opinions/:id", opinionHandler.Delete)
+	router.GET("/opinions/:id/revisions", opinionHandler.ListRevisions)
+	router.GET("/opinions/:id/revisions/diff", opinionHandler.DiffRevisions)
+	router.GET("/opinions/:id/revisions/:revision", opinionHandler.GetRevision)
+	router.POST("/opinions/:id/revisions/:revision/restore", opinionHandler.RestoreRevision)
 	router.GET("/opinions/writer/:writer_id", opinionHandler.GetByWriter)
 	router.GET("/opinions/work/:work_id", opinionHandler.GetByWork)
+	router.GET("/opinions/about-writer/:writer_id", opinionHandler.GetAboutWriter)
+	router.GET("/opinions/writer/:writer_id/about-writer/:target_writer_id", opinionHandler.GetByWriterAboutWriter)
 	router.GET("/opinions/writer/:writer_id/work/:work_id", opinionHandler.GetByWriterAndWork)
 	router.PUT("/opinions/writer/:writer_id/work/:work_id", opinionHandler.Update)
-	router.DELETE("/opinions/writer/:writer_id/work/:work_id", opinionHandler.Delete)
-	return router, opinionRepo, writerRepo, workRepo, cleanup
+	router.DELETE("/opinions/writer/:writer_id/work/:work_id", opinionHandler.DeleteByWriterAndWork)
+	router.GET("/opinions/writer/:writer_id/work/:work_id/revisions", opinionHandler.ListRevisions)
+	router.GET("/opinions/writer/:writer_id/work/:work_id/revisions/diff", opinionHandler.DiffRevisions)
+	router.GET("/opinions/writer/:writer_id/work/:work_id/revisions/:revision", opinionHandler.GetRevision)
+	router.POST("/opinions/writer/:writer_id/work/:work_id/revisions/:revision/restore", opinionHandler.RestoreRevision)
+	return router
 }
 
 func TestOpinionHandler_Create(t *testing.T) {
@@ -53,7 +78,7 @@ func TestOpinionHandler_Create(t *testing.T) {
 		writer2 := domain.NewWriter(2, "Charlotte Bronte", 1816, nil, nil)
 		require.NoError(t, writerRepo.Create(writer1))
 		require.NoError(t, writerRepo.Create(writer2))
-		work := domain.NewWork(1, "Pride and Prejudice", 1)
+		work := domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})
 		require.NoError(t, workRepo.Create(work))
 
 		reqBody := map[string]interface{}{
@@ -78,6 +103,92 @@ func TestOpinionHandler_Create(t *testing.T) {
 		assert.Equal(t, "A delightful novel", response["quote"])
 	})
 
+	t.Run("graded sentiment", func(t *testing.T) {
+		t.Parallel()
+		router, _, writerRepo, workRepo, cleanup := setupOpinionHandlerRouter(t)
+		defer cleanup()
+
+		require.NoError(t, writerRepo.Create(domain.NewWriter(1, "Jane Austen", 1775, nil, nil)))
+		require.NoError(t, writerRepo.Create(domain.NewWriter(2, "Charlotte Bronte", 1816, nil, nil)))
+		require.NoError(t, workRepo.Create(domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})))
+
+		create := func(body map[string]interface{}) *httptest.ResponseRecorder {
+			encoded, _ := json.Marshal(body)
+			req := httptest.NewRequest(http.MethodPost, "/opinions", bytes.NewBuffer(encoded))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w
+		}
+
+		w := create(map[string]interface{}{
+			"writer_id": 2, "work_id": 1, "sentiment_grade": "mixed", "quote": "Clever, but cold", "source": "Letters",
+		})
+		require.Equal(t, http.StatusCreated, w.Code)
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "mixed", response["sentiment_grade"])
+		assert.Equal(t, false, response["sentiment"])
+
+		// The boolean sent by older clients maps onto the scale
+		w = create(map[string]interface{}{
+			"writer_id": 2, "work_id": 1, "sentiment": false, "quote": "Dull", "source": "Letters",
+		})
+		require.Equal(t, http.StatusCreated, w.Code)
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "-1", response["sentiment_grade"])
+
+		w = create(map[string]interface{}{
+			"writer_id": 2, "work_id": 1, "sentiment_grade": "3", "quote": "Dull", "source": "Letters",
+		})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		w = create(map[string]interface{}{"writer_id": 2, "work_id": 1, "quote": "Dull", "source": "Letters"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("about a writer", func(t *testing.T) {
+		t.Parallel()
+		router, _, writerRepo, workRepo, cleanup := setupOpinionHandlerRouter(t)
+		defer cleanup()
+
+		require.NoError(t, writerRepo.Create(domain.NewWriter(1, "Anton Chekhov", 1860, nil, nil)))
+		require.NoError(t, writerRepo.Create(domain.NewWriter(2, "Leo Tolstoy", 1828, nil, nil)))
+		require.NoError(t, workRepo.Create(domain.NewWork(1, "The Seagull", []uint64{1}, domain.WorkDetails{})))
+
+		create := func(body map[string]interface{}) *httptest.ResponseRecorder {
+			encoded, _ := json.Marshal(body)
+			req := httptest.NewRequest(http.MethodPost, "/opinions", bytes.NewBuffer(encoded))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w
+		}
+
+		w := create(map[string]interface{}{
+			"writer_id": 2, "target_writer_id": 1, "sentiment_grade": "+2", "quote": "A genius", "source": "Diary",
+		})
+		require.Equal(t, http.StatusCreated, w.Code)
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "writer", response["target_type"])
+		assert.Equal(t, float64(1), response["target_writer_id"])
+		assert.Nil(t, response["work_id"])
+
+		// Exactly one target is required
+		w = create(map[string]interface{}{
+			"writer_id": 2, "work_id": 1, "target_writer_id": 1, "sentiment_grade": "+2", "quote": "Q", "source": "S",
+		})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		w = create(map[string]interface{}{"writer_id": 2, "sentiment_grade": "+2", "quote": "Q", "source": "S"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = create(map[string]interface{}{
+			"writer_id": 1, "target_writer_id": 1, "sentiment_grade": "+2", "quote": "Q", "source": "S",
+		})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "writer cannot express opinion about themselves")
+	})
+
 	t.Run("missing quote", func(t *testing.T) {
 		t.Parallel()
 		router, _, writerRepo, workRepo, cleanup := setupOpinionHandlerRouter(t)
@@ -88,7 +199,7 @@ func TestOpinionHandler_Create(t *testing.T) {
 		writer2 := domain.NewWriter(2, "Charlotte Bronte", 1816, nil, nil)
 		require.NoError(t, writerRepo.Create(writer1))
 		require.NoError(t, writerRepo.Create(writer2))
-		work := domain.NewWork(1, "Pride and Prejudice", 1)
+		work := domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})
 		require.NoError(t, workRepo.Create(work))
 
 		reqBody := map[string]interface{}{
@@ -140,9 +251,9 @@ func setupTestOpinionData(
 	writer2 := domain.NewWriter(2, "Charlotte Bronte", 1816, nil, nil)
 	require.NoError(t, writerRepo.Create(writer1))
 	require.NoError(t, writerRepo.Create(writer2))
-	work := domain.NewWork(1, "Pride and Prejudice", 1)
+	work := domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})
 	require.NoError(t, workRepo.Create(work))
-	opinion := domain.NewOpinion(2, 1, true, "Quote 1", "Source 1", nil, nil)
+	opinion := domain.NewOpinion(0, 2, 1, domain.SentimentPositive, "Quote 1", "Source 1", nil, nil)
 	require.NoError(t, opinionRepo.Create(opinion))
 }
 
@@ -177,7 +288,7 @@ func TestOpinionHandler_GetByWriter(t *testing.T) {
 		writer2 := domain.NewWriter(2, "Charlotte Bronte", 1816, nil, nil)
 		require.NoError(t, writerRepo.Create(writer1))
 		require.NoError(t, writerRepo.Create(writer2))
-		work := domain.NewWork(1, "Pride and Prejudice", 1)
+		work := domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})
 		require.NoError(t, workRepo.Create(work))
 
 		req := httptest.NewRequest(http.MethodGet, "/opinions/writer/invalid", http.NoBody)
@@ -189,6 +300,40 @@ func TestOpinionHandler_GetByWriter(t *testing.T) {
 	})
 }
 
+func TestOpinionHandler_GetAboutWriter(t *testing.T) {
+	t.Parallel()
+	router, opinionRepo, writerRepo, workRepo, cleanup := setupOpinionHandlerRouter(t)
+	defer cleanup()
+
+	setupTestOpinionData(t, writerRepo, workRepo, opinionRepo)
+	require.NoError(t, opinionRepo.Create(domain.NewWriterOpinion(
+		0, 2, 1, domain.SentimentMixed, "Quote 2", "Source 2", nil, nil,
+	)))
+
+	req := httptest.NewRequest(http.MethodGet, "/opinions/about-writer/1", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response []map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response, 1)
+	assert.Equal(t, "Quote 2", response[0]["quote"])
+
+	req = httptest.NewRequest(http.MethodGet, "/opinions/writer/2/about-writer/1", http.NoBody)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response, 1)
+
+	req = httptest.NewRequest(http.MethodGet, "/opinions/about-writer/abc", http.NoBody)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
 func TestOpinionHandler_GetByWork(t *testing.T) {
 	t.Parallel()
 	t.Run("success", func(t *testing.T) {
@@ -222,9 +367,9 @@ func TestOpinionHandler_GetByWriterAndWork(t *testing.T) {
 		writer2 := domain.NewWriter(2, "Charlotte Bronte", 1816, nil, nil)
 		require.NoError(t, writerRepo.Create(writer1))
 		require.NoError(t, writerRepo.Create(writer2))
-		work := domain.NewWork(1, "Pride and Prejudice", 1)
+		work := domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})
 		require.NoError(t, workRepo.Create(work))
-		opinion := domain.NewOpinion(2, 1, true, "Quote", "Source", nil, nil)
+		opinion := domain.NewOpinion(0, 2, 1, domain.SentimentPositive, "Quote", "Source", nil, nil)
 		require.NoError(t, opinionRepo.Create(opinion))
 
 		req := httptest.NewRequest(http.MethodGet, "/opinions/writer/2/work/1", http.NoBody)
@@ -233,11 +378,12 @@ func TestOpinionHandler_GetByWriterAndWork(t *testing.T) {
 		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusOK, w.Code)
-		var response map[string]interface{}
+		var response []map[string]interface{}
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		require.NoError(t, err)
-		assert.Equal(t, uint64(2), uint64(response["writer_id"].(float64)))
-		assert.Equal(t, uint64(1), uint64(response["work_id"].(float64)))
+		require.Len(t, response, 1)
+		assert.Equal(t, uint64(2), uint64(response[0]["writer_id"].(float64)))
+		assert.Equal(t, uint64(1), uint64(response[0]["work_id"].(float64)))
 	})
 
 	t.Run("not found", func(t *testing.T) {
@@ -254,6 +400,120 @@ func TestOpinionHandler_GetByWriterAndWork(t *testing.T) {
 	})
 }
 
+func TestOpinionHandler_GetByID(t *testing.T) {
+	t.Parallel()
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		router, opinionRepo, writerRepo, workRepo, cleanup := setupOpinionHandlerRouter(t)
+		defer cleanup()
+
+		setupTestOpinionData(t, writerRepo, workRepo, opinionRepo)
+
+		req := httptest.NewRequest(http.MethodGet, "/opinions/1", http.NoBody)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response map[string]interface{}
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.InDelta(t, 1, response["id"], 0)
+		assert.Equal(t, "Quote 1", response["quote"])
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+		router, _, _, _, cleanup := setupOpinionHandlerRouter(t)
+		defer cleanup()
+
+		req := httptest.NewRequest(http.MethodGet, "/opinions/1", http.NoBody)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestOpinionHandler_Translations(t *testing.T) {
+	t.Parallel()
+	router, _, writerRepo, workRepo, cleanup := setupOpinionHandlerRouter(t)
+	defer cleanup()
+
+	require.NoError(t, writerRepo.Create(domain.NewWriter(1, "Anton Chekhov", 1860, nil, nil)))
+	require.NoError(t, writerRepo.Create(domain.NewWriter(2, "Leo Tolstoy", 1828, nil, nil)))
+	require.NoError(t, workRepo.Create(domain.NewWork(1, "The Seagull", []uint64{1}, domain.WorkDetails{})))
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"writer_id":       2,
+		"work_id":         1,
+		"sentiment_grade": "-1",
+		"quote":           "Скверно",
+		"source":          "Diary",
+		"language":        "ru",
+		"translations": []map[string]interface{}{
+			{"language": "en", "text": "Nasty", "translator": "Constance Garnett"},
+			{"language": "fr", "text": "Mauvais"},
+		},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/opinions", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	get := func(path, acceptLanguage string) map[string]interface{} {
+		req := httptest.NewRequest(http.MethodGet, path, http.NoBody)
+		if acceptLanguage != "" {
+			req.Header.Set("Accept-Language", acceptLanguage)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	// The original is always given in full, whatever the reader prefers
+	response := get("/opinions/1", "")
+	assert.Equal(t, "Скверно", response["quote"])
+	assert.Equal(t, "ru", response["language"])
+	assert.Len(t, response["translations"], 2)
+	assert.Equal(t, map[string]interface{}{
+		"language": "ru", "quote": "Скверно", "translator": nil, "source": nil,
+	}, response["localized"])
+
+	response = get("/opinions/1", "en-US,en;q=0.9")
+	assert.Equal(t, "Скверно", response["quote"])
+	assert.Equal(t, map[string]interface{}{
+		"language": "en", "quote": "Nasty", "translator": "Constance Garnett", "source": nil,
+	}, response["localized"])
+
+	// The lang parameter wins over the header
+	response = get("/opinions/1?lang=fr", "en")
+	assert.Equal(t, "Mauvais", response["localized"].(map[string]interface{})["quote"])
+
+	// Without a translation in a preferred language, the original is kept
+	response = get("/opinions/1?lang=de", "")
+	assert.Equal(t, "Скверно", response["localized"].(map[string]interface{})["quote"])
+
+	body, _ = json.Marshal(map[string]interface{}{
+		"sentiment_grade": "-1",
+		"quote":           "Скверно",
+		"source":          "Diary",
+		"language":        "",
+		"translations":    []map[string]interface{}{{"language": "en", "text": "Nasty"}},
+	})
+	req = httptest.NewRequest(http.MethodPut, "/opinions/1", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "language is required")
+}
+
 func TestOpinionHandler_List(t *testing.T) {
 	t.Parallel()
 	t.Run("success", func(t *testing.T) {
@@ -267,12 +527,12 @@ func TestOpinionHandler_List(t *testing.T) {
 		require.NoError(t, writerRepo.Create(writer1))
 		require.NoError(t, writerRepo.Create(writer2))
 		require.NoError(t, writerRepo.Create(writer3))
-		work1 := domain.NewWork(1, "Pride and Prejudice", 1)
-		work2 := domain.NewWork(2, "Jane Eyre", 2)
+		work1 := domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})
+		work2 := domain.NewWork(2, "Jane Eyre", []uint64{2}, domain.WorkDetails{})
 		require.NoError(t, workRepo.Create(work1))
 		require.NoError(t, workRepo.Create(work2))
-		opinion1 := domain.NewOpinion(2, 1, true, "Quote 1", "Source 1", nil, nil)
-		opinion2 := domain.NewOpinion(3, 2, false, "Quote 2", "Source 2", nil, nil)
+		opinion1 := domain.NewOpinion(0, 2, 1, domain.SentimentPositive, "Quote 1", "Source 1", nil, nil)
+		opinion2 := domain.NewOpinion(0, 3, 2, domain.SentimentNegative, "Quote 2", "Source 2", nil, nil)
 		require.NoError(t, opinionRepo.Create(opinion1))
 		require.NoError(t, opinionRepo.Create(opinion2))
 
@@ -282,13 +542,100 @@ func TestOpinionHandler_List(t *testing.T) {
 		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusOK, w.Code)
-		var response []map[string]interface{}
+		var response listResponse
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		require.NoError(t, err)
-		assert.Len(t, response, 2)
+		assert.Len(t, response.Items, 2)
+	})
+
+	t.Run("filtered and sorted", func(t *testing.T) {
+		t.Parallel()
+		router, opinionRepo, writerRepo, workRepo, cleanup := setupOpinionHandlerRouter(t)
+		defer cleanup()
+
+		require.NoError(t, writerRepo.Create(domain.NewWriter(1, "Jane Austen", 1775, nil, nil)))
+		require.NoError(t, writerRepo.Create(domain.NewWriter(2, "Charlotte Bronte", 1816, nil, nil)))
+		require.NoError(t, workRepo.Create(domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})))
+		year := func(y int) *int { return &y }
+		for _, statementYear := range []*int{year(1850), nil, year(1848)} {
+			opinion := domain.NewOpinion(0, 2, 1, domain.SentimentNegative, "Quote", "Source", nil, statementYear)
+			require.NoError(t, opinionRepo.Create(opinion))
+		}
+
+		req := httptest.NewRequest(http.MethodGet,
+			"/opinions?work_author_id=1&sentiment=negative&sort=statement_year&order=desc", http.NoBody)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response listResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.Items, 3)
+		assert.InDelta(t, 1850, response.Items[0]["statement_year"], 0)
+		assert.InDelta(t, 1848, response.Items[1]["statement_year"], 0)
+		assert.Nil(t, response.Items[2]["statement_year"])
+
+		req = httptest.NewRequest(http.MethodGet, "/opinions?year_to=1849", http.NoBody)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response.Items, 1)
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		t.Parallel()
+		router, _, _, _, cleanup := setupOpinionHandlerRouter(t)
+		defer cleanup()
+
+		for _, query := range []string{"year_from=early", "writer_id=x", "source_id=1,", "sort=writer", "order=random"} {
+			req := httptest.NewRequest(http.MethodGet, "/opinions?"+query, http.NoBody)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		}
 	})
 }
 
+func TestOpinionHandler_SearchQuotes(t *testing.T) {
+	t.Parallel()
+	router, opinionRepo, writerRepo, workRepo, cleanup := setupOpinionHandlerRouter(t)
+	defer cleanup()
+	setupTestOpinionData(t, writerRepo, workRepo, opinionRepo)
+
+	russian := domain.NewOpinion(0, 2, 1, domain.SentimentNegative, "Какая пошлость", "Letters", nil, nil)
+	russian.SetLanguages(domain.QuoteLanguages{Language: "ru"})
+	require.NoError(t, opinionRepo.Create(russian))
+
+	search := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/opinions/search?"+query, http.NoBody)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := search("q=" + url.QueryEscape("пошлость") + "&language=RU")
+	require.Equal(t, http.StatusOK, w.Code)
+	var response []map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response, 1)
+	assert.InDelta(t, float64(russian.ID()), response[0]["id"], 0)
+	assert.Equal(t, "Какая пошлость", response[0]["quote"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"text": "Какая ", "match": false},
+		map[string]interface{}{"text": "пошлость", "match": true},
+	}, response[0]["snippet"])
+	assert.Greater(t, response[0]["rank"], 0.0)
+
+	w = search("q=" + url.QueryEscape("пошлость") + "&language=en")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, "[]", w.Body.String())
+
+	assert.Equal(t, http.StatusBadRequest, search("q=%20").Code)
+	assert.Equal(t, http.StatusBadRequest, search("q=quote&language=not+a+language").Code)
+}
+
 func TestOpinionHandler_Update(t *testing.T) {
 	t.Parallel()
 	t.Run("success", func(t *testing.T) {
@@ -300,9 +647,9 @@ func TestOpinionHandler_Update(t *testing.T) {
 		writer2 := domain.NewWriter(2, "Charlotte Bronte", 1816, nil, nil)
 		require.NoError(t, writerRepo.Create(writer1))
 		require.NoError(t, writerRepo.Create(writer2))
-		work := domain.NewWork(1, "Pride and Prejudice", 1)
+		work := domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})
 		require.NoError(t, workRepo.Create(work))
-		opinion := domain.NewOpinion(2, 1, true, "Quote", "Source", nil, nil)
+		opinion := domain.NewOpinion(0, 2, 1, domain.SentimentPositive, "Quote", "Source", nil, nil)
 		require.NoError(t, opinionRepo.Create(opinion))
 
 		reqBody := map[string]interface{}{
@@ -323,6 +670,65 @@ func TestOpinionHandler_Update(t *testing.T) {
 		assert.Equal(t, http.StatusOK, w.Code)
 	})
 
+	t.Run("fields left out are kept", func(t *testing.T) {
+		t.Parallel()
+		db, cleanup := testutils.SetupTestDB(t)
+		defer cleanup()
+		router := newOpinionHandlerRouter(db)
+		opinionRepo := gorm.NewOpinionRepository(db)
+		writerRepo := gorm.NewWriterRepository(db)
+		workRepo := gorm.NewWorkRepository(db)
+		sourceRepo := gorm.NewSourceRepository(db)
+
+		require.NoError(t, writerRepo.Create(domain.NewWriter(1, "Anton Chekhov", 1860, nil, nil)))
+		require.NoError(t, writerRepo.Create(domain.NewWriter(2, "Leo Tolstoy", 1828, nil, nil)))
+		require.NoError(t, workRepo.Create(domain.NewWork(1, "The Seagull", []uint64{1}, domain.WorkDetails{})))
+		require.NoError(t, sourceRepo.Create(
+			domain.NewSource(0, domain.SourceTypeDiary, "Diaries", domain.SourceDetails{}, true),
+		))
+		opinion := domain.NewOpinion(0, 2, 1, domain.SentimentNegative, "Скверно", "Diary", nil, nil)
+		opinion.SetSourceID(1)
+		opinion.SetLanguages(domain.QuoteLanguages{
+			Language:     "ru",
+			Translations: []domain.Translation{{Language: "en", Text: "Nasty"}},
+		})
+		require.NoError(t, opinionRepo.Create(opinion))
+
+		// As sent by a client that predates languages
+		body, _ := json.Marshal(map[string]interface{}{
+			"sentiment": false,
+			"quote":     "Скверно!",
+			"source":    "Diary",
+		})
+		req := httptest.NewRequest(http.MethodPut, "/opinions/1", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		updated, err := opinionRepo.GetByID(1)
+		require.NoError(t, err)
+		assert.Equal(t, "Скверно!", updated.Quote())
+		assert.Equal(t, opinion.Languages(), updated.Languages())
+		assert.Equal(t, uint64(1), updated.SourceID())
+
+		// Unlinking the source takes an explicit null
+		body, _ = json.Marshal(map[string]interface{}{
+			"sentiment": false,
+			"quote":     "Скверно!",
+			"source":    "Diary",
+			"source_id": nil,
+		})
+		req = httptest.NewRequest(http.MethodPut, "/opinions/1", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		updated, err = opinionRepo.GetByID(1)
+		require.NoError(t, err)
+		assert.Zero(t, updated.SourceID())
+	})
+
 	t.Run("missing quote", func(t *testing.T) {
 		t.Parallel()
 		router, _, writerRepo, workRepo, cleanup := setupOpinionHandlerRouter(t)
@@ -333,7 +739,7 @@ func TestOpinionHandler_Update(t *testing.T) {
 		writer2 := domain.NewWriter(2, "Charlotte Bronte", 1816, nil, nil)
 		require.NoError(t, writerRepo.Create(writer1))
 		require.NoError(t, writerRepo.Create(writer2))
-		work := domain.NewWork(1, "Pride and Prejudice", 1)
+		work := domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})
 		require.NoError(t, workRepo.Create(work))
 
 		reqBody := map[string]interface{}{
@@ -362,9 +768,9 @@ func TestOpinionHandler_Delete(t *testing.T) {
 		writer2 := domain.NewWriter(2, "Charlotte Bronte", 1816, nil, nil)
 		require.NoError(t, writerRepo.Create(writer1))
 		require.NoError(t, writerRepo.Create(writer2))
-		work := domain.NewWork(1, "Pride and Prejudice", 1)
+		work := domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})
 		require.NoError(t, workRepo.Create(work))
-		opinion := domain.NewOpinion(2, 1, true, "Quote", "Source", nil, nil)
+		opinion := domain.NewOpinion(0, 2, 1, domain.SentimentPositive, "Quote", "Source", nil, nil)
 		require.NoError(t, opinionRepo.Create(opinion))
 
 		req := httptest.NewRequest(http.MethodDelete, "/opinions/writer/2/work/1", http.NoBody)
@@ -375,3 +781,143 @@ func TestOpinionHandler_Delete(t *testing.T) {
 		assert.Equal(t, http.StatusOK, w.Code)
 	})
 }
+
+func TestOpinionHandler_Revisions(t *testing.T) {
+	t.Parallel()
+	router, _, writerRepo, workRepo, cleanup := setupOpinionHandlerRouter(t)
+	defer cleanup()
+
+	require.NoError(t, writerRepo.Create(domain.NewWriter(1, "Jane Austen", 1775, nil, nil)))
+	require.NoError(t, writerRepo.Create(domain.NewWriter(2, "Charlotte Bronte", 1816, nil, nil)))
+	require.NoError(t, workRepo.Create(domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})))
+
+	send := func(method, path string, body map[string]interface{}) *httptest.ResponseRecorder {
+		encoded, _ := json.Marshal(body)
+		req := httptest.NewRequest(method, path, bytes.NewBuffer(encoded))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send(http.MethodPost, "/opinions", map[string]interface{}{
+		"writer_id": 2, "work_id": 1, "sentiment": true, "quote": "Original quote", "source": "Letters",
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+	w = send(http.MethodPut, "/opinions/writer/2/work/1", map[string]interface{}{
+		"sentiment": true, "quote": "Mistaken quote", "source": "Letters", "page": "12",
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// Both states are kept
+	w = send(http.MethodGet, "/opinions/writer/2/work/1/revisions", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var revisions []map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &revisions))
+	require.Len(t, revisions, 2)
+	assert.InDelta(t, 1, revisions[0]["revision"], 0)
+	assert.Equal(t, "Original quote", revisions[0]["quote"])
+	assert.Equal(t, "Mistaken quote", revisions[1]["quote"])
+
+	// Only changed fields are reported
+	w = send(http.MethodGet, "/opinions/writer/2/work/1/revisions/diff?from=1&to=2", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var diff struct {
+		Changes []map[string]interface{} `json:"changes"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &diff))
+	require.Len(t, diff.Changes, 2)
+	assert.Equal(t, "quote", diff.Changes[0]["field"])
+	assert.Equal(t, "Original quote", diff.Changes[0]["from"])
+	assert.Equal(t, "Mistaken quote", diff.Changes[0]["to"])
+	assert.Equal(t, "page", diff.Changes[1]["field"])
+	assert.Nil(t, diff.Changes[1]["from"])
+
+	w = send(http.MethodGet, "/opinions/writer/2/work/1/revisions/diff?from=1&to=99", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = send(http.MethodGet, "/opinions/writer/2/work/1/revisions/diff?from=0&to=1", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Restoring the first revision undoes the update as a new revision
+	w = send(http.MethodPost, "/opinions/writer/2/work/1/revisions/1/restore", nil)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var revision map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &revision))
+	assert.InDelta(t, 3, revision["revision"], 0)
+	assert.Equal(t, "Original quote", revision["quote"])
+
+	w = send(http.MethodGet, "/opinions/writer/2/work/1", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var opinions []map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &opinions))
+	require.Len(t, opinions, 1)
+	assert.Equal(t, "Original quote", opinions[0]["quote"])
+	assert.Nil(t, opinions[0]["page"])
+
+	// Once the pair holds several statements, history is addressed by opinion ID
+	w = send(http.MethodPost, "/opinions", map[string]interface{}{
+		"writer_id": 2, "work_id": 1, "sentiment": true, "quote": "Second thoughts", "source": "Letters",
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+	w = send(http.MethodGet, "/opinions/writer/2/work/1/revisions", nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	w = send(http.MethodGet, fmt.Sprintf("/opinions/%v/revisions", opinions[0]["id"]), nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &revisions))
+	assert.Len(t, revisions, 3)
+
+	// Pairs without an opinion have no history
+	w = send(http.MethodGet, "/opinions/writer/1/work/1/revisions", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// Concurrent edits of one opinion each get their own revision number
+func TestOpinionHandler_ConcurrentUpdates(t *testing.T) {
+	t.Parallel()
+	router, _, writerRepo, workRepo, cleanup := setupOpinionHandlerRouter(t)
+	defer cleanup()
+
+	require.NoError(t, writerRepo.Create(domain.NewWriter(1, "Jane Austen", 1775, nil, nil)))
+	require.NoError(t, writerRepo.Create(domain.NewWriter(2, "Charlotte Bronte", 1816, nil, nil)))
+	require.NoError(t, workRepo.Create(domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})))
+	body := `{"writer_id":2,"work_id":1,"sentiment_grade":"-1","quote":"Quote","source":"Letters"}`
+	req := httptest.NewRequest(http.MethodPost, "/opinions", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var created map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	path := fmt.Sprintf("/opinions/%d", uint64(created["id"].(float64)))
+
+	const count = 10
+	codes := make([]int, count)
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			body := fmt.Sprintf(`{"sentiment_grade":"-1","quote":"Quote %d","source":"Letters"}`, i)
+			req := httptest.NewRequest(http.MethodPut, path, bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			codes[i] = w.Code
+		}(i)
+	}
+	wg.Wait()
+	for _, code := range codes {
+		assert.Equal(t, http.StatusOK, code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, path+"/revisions", http.NoBody)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var revisions []map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &revisions))
+	require.Len(t, revisions, count+1)
+	for i, revision := range revisions {
+		assert.InDelta(t, i+1, revision["revision"], 0)
+	}
+}
@@ -0,0 +1,46 @@
+package handler
+
+import "encoding/json"
+
+// optional is a field of an update request that tells one left out, which
+// keeps the stored value, from one sent as null, which clears it.
+type optional[T any] struct {
+	set   bool
+	value *T
+}
+
+func (o *optional[T]) UnmarshalJSON(data []byte) error {
+	o.set = true
+	if string(data) == "null" {
+		o.value = nil
+		return nil
+	}
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	o.value = &value
+	return nil
+}
+
+// update is the value to set, pointing to nil to clear it, or nil when the
+// field was left out.
+func (o optional[T]) update() **T {
+	if !o.set {
+		return nil
+	}
+	return &o.value
+}
+
+// sent is the value sent, with null read as the zero value, or nil when
+// the field was left out.
+func (o optional[T]) sent() *T {
+	if !o.set {
+		return nil
+	}
+	if o.value == nil {
+		var zero T
+		return &zero
+	}
+	return o.value
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+	"github.com/what-writers-like/backend/internal/service"
+)
+
+const (
+	requestIDHeader    = "X-Request-ID"
+	maxRequestIDLength = 64
+)
+
+// RequestID tags every request with an ID, reusing the one sent by the
+// client or a proxy when present. The ID is echoed in the response and
+// recorded with any changes the request makes.
+func RequestID(c *gin.Context) {
+	requestID := c.GetHeader(requestIDHeader)
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		requestID = newRequestID()
+	}
+	c.Header(requestIDHeader, requestID)
+	c.Request = c.Request.WithContext(service.WithRequestID(c.Request.Context(), requestID))
+	c.Next()
+}
+
+func newRequestID() string {
+	buf := make([]byte, 16)
+	// crypto/rand.Read never returns an error
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
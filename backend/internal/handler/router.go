@@ -5,45 +5,126 @@ import (
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/what-writers-like/backend/internal/domain"
 )
 
-func SetupRouter(writerHandler *WriterHandler, workHandler *WorkHandler, opinionHandler *OpinionHandler) *gin.Engine {
+func SetupRouter(
+	writerHandler *WriterHandler,
+	workHandler *WorkHandler,
+	opinionHandler *OpinionHandler,
+	graphHandler *GraphHandler,
+	sourceHandler *SourceHandler,
+	writerAliasHandler *WriterAliasHandler,
+	searchHandler *SearchHandler,
+	auditHandler *AuditHandler,
+	importHandler *ImportHandler,
+	exportHandler *ExportHandler,
+	backupHandler *BackupHandler,
+	authHandler *AuthHandler,
+	authMiddleware *AuthMiddleware,
+) *gin.Engine {
 	router := gin.Default()
+	router.Use(RequestID)
 
 	// Configure CORS middleware
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:3000"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", requestIDHeader},
+		ExposeHeaders:    []string{"Content-Length", requestIDHeader},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
 
+	// Reads are public; editors may create and update, only admins delete
 	api := router.Group("/api/v1")
+	api.Use(authMiddleware.Authenticate)
+	editor := authMiddleware.Require(domain.RoleEditor)
+	admin := authMiddleware.Require(domain.RoleAdmin)
+
+	auth := api.Group("/auth")
+	auth.POST("/token", admin, authHandler.IssueToken)
+	auth.GET("/me", authHandler.Me)
+
 	writers := api.Group("/writers")
-	writers.POST("", writerHandler.Create)
+	writers.POST("", editor, writerHandler.Create)
 	writers.GET("", writerHandler.List)
 	writers.GET("/:id", writerHandler.GetByID)
-	writers.PUT("/:id", writerHandler.Update)
-	writers.DELETE("/:id", writerHandler.Delete)
+	writers.PUT("/:id", editor, writerHandler.Update)
+	writers.DELETE("/:id", admin, writerHandler.Delete)
+	writers.POST("/:id/aliases", editor, writerAliasHandler.Create)
+	writers.GET("/:id/aliases", writerAliasHandler.List)
+	writers.PUT("/:id/aliases/:alias_id", editor, writerAliasHandler.Update)
+	writers.DELETE("/:id/aliases/:alias_id", admin, writerAliasHandler.Delete)
 
 	works := api.Group("/works")
-	works.POST("", workHandler.Create)
+	works.POST("", editor, workHandler.Create)
 	works.GET("", workHandler.List)
 	works.GET("/:id", workHandler.GetByID)
 	works.GET("/author/:author_id", workHandler.GetByAuthor)
-	works.PUT("/:id", workHandler.Update)
-	works.DELETE("/:id", workHandler.Delete)
+	works.PUT("/:id", editor, workHandler.Update)
+	works.DELETE("/:id", admin, workHandler.Delete)
 
 	opinions := api.Group("/opinions")
-	opinions.POST("", opinionHandler.Create)
+	opinions.POST("", editor, opinionHandler.Create)
 	opinions.GET("", opinionHandler.List)
+	opinions.GET("/search", opinionHandler.SearchQuotes)
+	opinions.GET("/:id", opinionHandler.GetByID)
+	opinions.PUT("/:id", editor, opinionHandler.Update)
+	opinions.DELETE("/:id", admin, opinionHandler.Delete)
+	opinions.GET("/:id/revisions", opinionHandler.ListRevisions)
+	opinions.GET("/:id/revisions/diff", opinionHandler.DiffRevisions)
+	opinions.GET("/:id/revisions/:revision", opinionHandler.GetRevision)
+	opinions.POST("/:id/revisions/:revision/restore", editor, opinionHandler.RestoreRevision)
 	opinions.GET("/writer/:writer_id", opinionHandler.GetByWriter)
 	opinions.GET("/work/:work_id", opinionHandler.GetByWork)
-	opinions.GET("/writer/:writer_id/work/:work_id", opinionHandler.GetByWriterAndWork)
-	opinions.PUT("/writer/:writer_id/work/:work_id", opinionHandler.Update)
-	opinions.DELETE("/writer/:writer_id/work/:work_id", opinionHandler.Delete)
+	opinions.GET("/about-writer/:writer_id", opinionHandler.GetAboutWriter)
+	opinions.GET("/writer/:writer_id/about-writer/:target_writer_id", opinionHandler.GetByWriterAboutWriter)
+
+	// Routes addressing opinions by writer and work predate opinion IDs.
+	// Reads and deletes cover every statement of the pair; the others need
+	// the pair to have a single statement.
+	pair := opinions.Group("/writer/:writer_id/work/:work_id")
+	pair.GET("", opinionHandler.GetByWriterAndWork)
+	pair.PUT("", editor, opinionHandler.Update)
+	pair.DELETE("", admin, opinionHandler.DeleteByWriterAndWork)
+	pair.GET("/revisions", opinionHandler.ListRevisions)
+	pair.GET("/revisions/diff", opinionHandler.DiffRevisions)
+	pair.GET("/revisions/:revision", opinionHandler.GetRevision)
+	pair.POST("/revisions/:revision/restore", editor, opinionHandler.RestoreRevision)
+
+	sources := api.Group("/sources")
+	sources.POST("", editor, sourceHandler.Create)
+	sources.GET("", sourceHandler.List)
+	sources.GET("/:id", sourceHandler.GetByID)
+	sources.PUT("/:id", editor, sourceHandler.Update)
+	sources.POST("/:id/confirm", editor, sourceHandler.Confirm)
+	sources.DELETE("/:id", admin, sourceHandler.Delete)
+
+	// An import applies every row of its file or none of them
+	imports := api.Group("/import")
+	imports.POST("/writers", editor, importHandler.ImportWriters)
+	imports.POST("/works", editor, importHandler.ImportWorks)
+	imports.POST("/opinions", editor, importHandler.ImportOpinions)
+
+	// Full dumps of every row, streamed as they are read
+	api.GET("/export/:entity", exportHandler.Export)
+
+	// A restore can replace every record, so snapshots are for admins only
+	api.GET("/backup", admin, backupHandler.Backup)
+	api.POST("/restore", admin, backupHandler.Restore)
+
+	api.GET("/search", searchHandler.Search)
+
+	graph := api.Group("/graph")
+	graph.GET("", graphHandler.Get)
+	graph.GET("/export", graphHandler.Export)
+	graph.GET("/writers/:id/neighborhood", graphHandler.GetWriterNeighborhood)
+	graph.GET("/works/:id/neighborhood", graphHandler.GetWorkNeighborhood)
+	graph.GET("/path", graphHandler.GetShortestPath)
+
+	// The audit log names the people behind changes, so it is not public
+	api.GET("/audit", editor, auditHandler.List)
 
 	return router
 }
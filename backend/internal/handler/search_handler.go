@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/service"
+)
+
+type SearchHandler struct {
+	searchService service.SearchService
+}
+
+func NewSearchHandler(searchService service.SearchService) *SearchHandler {
+	return &SearchHandler{searchService: searchService}
+}
+
+// Search looks for the q query parameter among writers, works and opinions
+// at once. The optional types parameter is a comma-separated list of the
+// kinds of hit wanted: writer, work or opinion.
+func (h *SearchHandler) Search(c *gin.Context) {
+	query := c.Query("q")
+	if strings.TrimSpace(query) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+
+	var types []domain.SearchHitType
+	if raw := c.Query("types"); raw != "" {
+		for _, p := range strings.Split(raw, ",") {
+			t, err := domain.ParseSearchHitType(strings.TrimSpace(p))
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			types = append(types, t)
+		}
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit <= 0 {
+		limit = 10
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	hits, err := h.searchService.Search(query, types, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	result := make([]gin.H, len(hits))
+	for i, hit := range hits {
+		result[i] = searchHitToResponse(hit)
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// searchHitToResponse splits the matched field into highlight segments,
+// marking the one the query was found in, so that clients can render it
+// without parsing markup.
+func searchHitToResponse(hit *domain.SearchHit) gin.H {
+	var highlight []gin.H
+	segment := func(text string, match bool) {
+		if text != "" {
+			highlight = append(highlight, gin.H{"text": text, "match": match})
+		}
+	}
+	segment(hit.Text[:hit.MatchStart], false)
+	segment(hit.Text[hit.MatchStart:hit.MatchEnd], true)
+	segment(hit.Text[hit.MatchEnd:], false)
+
+	return gin.H{
+		"type":      hit.Type,
+		"id":        hit.ID,
+		"label":     hit.Label,
+		"field":     hit.Field,
+		"highlight": highlight,
+		"score":     hit.Score,
+	}
+}
@@ -0,0 +1,80 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/handler"
+	"github.com/what-writers-like/backend/internal/repository/gorm"
+	"github.com/what-writers-like/backend/internal/service"
+	"github.com/what-writers-like/backend/internal/testutils"
+)
+
+func TestSearchHandler_Search(t *testing.T) {
+	t.Parallel()
+	db, cleanup := testutils.SetupTestDB(t)
+	defer cleanup()
+
+	writerRepo := gorm.NewWriterRepository(db)
+	workRepo := gorm.NewWorkRepository(db)
+	searchHandler := handler.NewSearchHandler(service.NewSearchService(gorm.NewSearchRepository(db)))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/search", searchHandler.Search)
+
+	require.NoError(t, writerRepo.Create(domain.NewWriter(1, "Leo Tolstoy", 1828, nil, nil)))
+	require.NoError(t, writerRepo.Create(domain.NewWriter(2, "Anton Chekhov", 1860, nil, nil)))
+	require.NoError(t, workRepo.Create(domain.NewWork(1, "The Seagull", []uint64{2}, domain.WorkDetails{})))
+	require.NoError(t, gorm.NewOpinionRepository(db).Create(
+		domain.NewOpinion(0, 1, 1, domain.SentimentNegative, "A seagull <b>is</b> a bird", "Diary", nil, nil),
+	))
+
+	search := func(query string) (int, []map[string]interface{}) {
+		req := httptest.NewRequest(http.MethodGet, "/search?"+query, http.NoBody)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var hits []map[string]interface{}
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &hits))
+		}
+		return w.Code, hits
+	}
+
+	code, hits := search("q=seagull")
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, hits, 2)
+	assert.Equal(t, "work", hits[0]["type"])
+	assert.Equal(t, "The Seagull", hits[0]["label"])
+	assert.Equal(t, "title", hits[0]["field"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"text": "The ", "match": false},
+		map[string]interface{}{"text": "Seagull", "match": true},
+	}, hits[0]["highlight"])
+	assert.InDelta(t, 1.0, hits[0]["score"], 0.0001)
+
+	// Highlights are plain text, so markup in the field is left as it is
+	assert.Equal(t, "opinion", hits[1]["type"])
+	assert.Equal(t, "quote", hits[1]["field"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"text": "A ", "match": false},
+		map[string]interface{}{"text": "seagull", "match": true},
+		map[string]interface{}{"text": " <b>is</b> a bird", "match": false},
+	}, hits[1]["highlight"])
+
+	code, hits = search("q=seagull&types=opinion")
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, hits, 1)
+	assert.Equal(t, "opinion", hits[0]["type"])
+
+	code, _ = search("q=seagull&types=poem")
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = search("q=")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/service"
+)
+
+type SourceHandler struct {
+	sourceService service.SourceService
+}
+
+func NewSourceHandler(sourceService service.SourceService) *SourceHandler {
+	return &SourceHandler{sourceService: sourceService}
+}
+
+// SourceRequest creates or updates a source. Author may name the editor of
+// a collection rather than its author.
+type SourceRequest struct {
+	Type            string  `json:"type"                       binding:"required"`
+	Title           string  `json:"title"                      binding:"required"`
+	Author          *string `json:"author,omitempty"`
+	Publisher       *string `json:"publisher,omitempty"`
+	Year            *int    `json:"year,omitempty"`
+	ISBN            *string `json:"isbn,omitempty"`
+	DOI             *string `json:"doi,omitempty"`
+	URL             *string `json:"url,omitempty"`
+	ArchiveLocation *string `json:"archive_location,omitempty"`
+}
+
+func (r *SourceRequest) details() domain.SourceDetails {
+	return domain.SourceDetails{
+		Author:          r.Author,
+		Publisher:       r.Publisher,
+		Year:            r.Year,
+		ISBN:            r.ISBN,
+		DOI:             r.DOI,
+		URL:             r.URL,
+		ArchiveLocation: r.ArchiveLocation,
+	}
+}
+
+type ConfirmSourceRequest struct {
+	Type string `json:"type" binding:"required"`
+}
+
+func (h *SourceHandler) Create(c *gin.Context) {
+	var req SourceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	sourceType, err := domain.ParseSourceType(req.Type)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	source, err := h.sourceService.CreateSource(c.Request.Context(), sourceType, req.Title, req.details())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, sourceToResponse(source))
+}
+
+func (h *SourceHandler) GetByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	source, err := h.sourceService.GetSource(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "source not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, sourceToResponse(source))
+}
+
+// List returns every source, or only the candidates awaiting a curator's
+// confirmation given candidates=true.
+func (h *SourceHandler) List(c *gin.Context) {
+	limitStr := c.DefaultQuery("limit", "10")
+	offsetStr := c.DefaultQuery("offset", "0")
+
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit <= 0 {
+		limit = 10
+	}
+
+	offset, err := strconv.Atoi(offsetStr)
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	var sources []*domain.Source
+	if c.Query("candidates") == "true" {
+		sources, err = h.sourceService.ListCandidates(limit, offset)
+	} else {
+		sources, err = h.sourceService.ListSources(limit, offset)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	result := make([]gin.H, len(sources))
+	for i, s := range sources {
+		result[i] = sourceToResponse(s)
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *SourceHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req SourceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	sourceType, err := domain.ParseSourceType(req.Type)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = h.sourceService.UpdateSource(c.Request.Context(), id, sourceType, req.Title, req.details())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "source updated"})
+}
+
+// Confirm accepts a candidate source grouped from free-text citations,
+// assigning it a type. Curators who also want to correct the title or add
+// metadata can update the source instead, which confirms it as well.
+func (h *SourceHandler) Confirm(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req ConfirmSourceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	sourceType, err := domain.ParseSourceType(req.Type)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	source, err := h.sourceService.ConfirmSource(c.Request.Context(), id, sourceType)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sourceToResponse(source))
+}
+
+func (h *SourceHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	if err := h.sourceService.DeleteSource(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "source deleted"})
+}
+
+// sourceToResponse reports a null type for candidates not yet typed.
+func sourceToResponse(s *domain.Source) gin.H {
+	details := s.Details()
+	response := gin.H{
+		"id":               s.ID(),
+		"type":             nil,
+		"title":            s.Title(),
+		"author":           details.Author,
+		"publisher":        details.Publisher,
+		"year":             details.Year,
+		"isbn":             details.ISBN,
+		"doi":              details.DOI,
+		"url":              details.URL,
+		"archive_location": details.ArchiveLocation,
+		"confirmed":        s.Confirmed(),
+	}
+	if s.Type() != "" {
+		response["type"] = s.Type()
+	}
+	return response
+}
@@ -0,0 +1,134 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/handler"
+	"github.com/what-writers-like/backend/internal/repository"
+	"github.com/what-writers-like/backend/internal/repository/gorm"
+	"github.com/what-writers-like/backend/internal/service"
+	"github.com/what-writers-like/backend/internal/testutils"
+)
+
+func setupSourceHandlerRouter(t *testing.T) (*gin.Engine, repository.SourceRepository, func()) {
+	db, cleanup := testutils.SetupTestDB(t)
+
+	sourceRepo := gorm.NewSourceRepository(db)
+	sourceService := service.NewSourceService(sourceRepo, gorm.NewOpinionRepository(db), gorm.NewTransactor(db))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	sourceHandler := handler.NewSourceHandler(sourceService)
+	router.POST("/sources", sourceHandler.Create)
+	router.GET("/sources", sourceHandler.List)
+	router.GET("/sources/:id", sourceHandler.GetByID)
+	router.PUT("/sources/:id", sourceHandler.Update)
+	router.POST("/sources/:id/confirm", sourceHandler.Confirm)
+	router.DELETE("/sources/:id", sourceHandler.Delete)
+	return router, sourceRepo, cleanup
+}
+
+func TestSourceHandler_CRUD(t *testing.T) {
+	t.Parallel()
+	router, _, cleanup := setupSourceHandlerRouter(t)
+	defer cleanup()
+
+	body := []byte(`{"type":"letter","title":"Jane Austen's Letters","author":"R. W. Chapman","year":1932}`)
+	req := httptest.NewRequest(http.MethodPost, "/sources", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var created map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, "letter", created["type"])
+	assert.Equal(t, "R. W. Chapman", created["author"])
+	assert.Equal(t, true, created["confirmed"])
+	assert.Nil(t, created["isbn"])
+	id := uint64(created["id"].(float64))
+
+	body = []byte(`{"type":"letter","title":"Jane Austen's Letters","isbn":"978-0-19-283530-4"}`)
+	req = httptest.NewRequest(http.MethodPut, fmt.Sprintf("/sources/%d", id), bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("/sources/%d", id), http.NoBody)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var found map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &found))
+	assert.Equal(t, "978-0-19-283530-4", found["isbn"])
+	assert.Nil(t, found["author"])
+
+	req = httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/sources/%d", id), http.NoBody)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("/sources/%d", id), http.NoBody)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestSourceHandler_Create_InvalidType(t *testing.T) {
+	t.Parallel()
+	router, _, cleanup := setupSourceHandlerRouter(t)
+	defer cleanup()
+
+	body := []byte(`{"type":"memoir","title":"Memoirs"}`)
+	req := httptest.NewRequest(http.MethodPost, "/sources", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestSourceHandler_ConfirmCandidate(t *testing.T) {
+	t.Parallel()
+	router, sourceRepo, cleanup := setupSourceHandlerRouter(t)
+	defer cleanup()
+
+	candidate := domain.NewSource(0, "", "Letters", domain.SourceDetails{}, false)
+	require.NoError(t, sourceRepo.Create(candidate))
+
+	req := httptest.NewRequest(http.MethodGet, "/sources?candidates=true", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var candidates []map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &candidates))
+	require.Len(t, candidates, 1)
+	assert.Nil(t, candidates[0]["type"])
+	assert.Equal(t, false, candidates[0]["confirmed"])
+
+	confirmURL := fmt.Sprintf("/sources/%d/confirm", candidate.ID())
+	req = httptest.NewRequest(http.MethodPost, confirmURL, bytes.NewBufferString(`{"type":"letter"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var confirmed map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &confirmed))
+	assert.Equal(t, "letter", confirmed["type"])
+	assert.Equal(t, true, confirmed["confirmed"])
+
+	req = httptest.NewRequest(http.MethodGet, "/sources?candidates=true", http.NoBody)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &candidates))
+	assert.Empty(t, candidates)
+}
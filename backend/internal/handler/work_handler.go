@@ -1,11 +1,13 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/repository"
 	"github.com/what-writers-like/backend/internal/service"
 )
 
@@ -17,34 +19,67 @@ func NewWorkHandler(workService service.WorkService) *WorkHandler {
 	return &WorkHandler{workService: workService}
 }
 
-type CreateWorkRequest struct {
-	Title    string `json:"title"     binding:"required"`
-	AuthorID uint64 `json:"author_id" binding:"required"`
+// WorkRequest creates or updates a work. AuthorIDs credits the work to its
+// authors in order and is left empty for an anonymous work; older clients
+// may send a single AuthorID instead. An update keeps the details it leaves
+// out, and clears those sent as null.
+type WorkRequest struct {
+	Title            string           `json:"title"                       binding:"required"`
+	AuthorIDs        []uint64         `json:"author_ids,omitempty"`
+	AuthorID         *uint64          `json:"author_id,omitempty"`
+	PublicationYear  optional[int]    `json:"publication_year"`
+	Genre            optional[string] `json:"genre"`
+	OriginalLanguage optional[string] `json:"original_language"`
+	OriginalTitle    optional[string] `json:"original_title"`
 }
 
-type UpdateWorkRequest struct {
-	Title    string `json:"title"     binding:"required"`
-	AuthorID uint64 `json:"author_id" binding:"required"`
+func (r *WorkRequest) authorIDs() ([]uint64, error) {
+	if r.AuthorID == nil {
+		return r.AuthorIDs, nil
+	}
+	if len(r.AuthorIDs) > 0 {
+		return nil, errors.New("send either author_ids or author_id, not both")
+	}
+	return []uint64{*r.AuthorID}, nil
+}
+
+func (r *WorkRequest) details() domain.WorkDetails {
+	return domain.WorkDetails{
+		PublicationYear:  r.PublicationYear.value,
+		Genre:            r.Genre.value,
+		OriginalLanguage: r.OriginalLanguage.value,
+		OriginalTitle:    r.OriginalTitle.value,
+	}
+}
+
+func (r *WorkRequest) detailsUpdate() service.WorkDetailsUpdate {
+	return service.WorkDetailsUpdate{
+		PublicationYear:  r.PublicationYear.update(),
+		Genre:            r.Genre.update(),
+		OriginalLanguage: r.OriginalLanguage.update(),
+		OriginalTitle:    r.OriginalTitle.update(),
+	}
 }
 
 func (h *WorkHandler) Create(c *gin.Context) {
-	var req CreateWorkRequest
+	var req WorkRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	authorIDs, err := req.authorIDs()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	work, err := h.workService.CreateWork(req.Title, req.AuthorID)
+	work, err := h.workService.CreateWork(c.Request.Context(), req.Title, authorIDs, req.details())
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
-		"id":        work.ID(),
-		"title":     work.Title(),
-		"author_id": work.AuthorID(),
-	})
+	c.JSON(http.StatusCreated, workToResponse(work))
 }
 
 func (h *WorkHandler) GetByID(c *gin.Context) {
@@ -61,11 +96,7 @@ func (h *WorkHandler) GetByID(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"id":        work.ID(),
-		"title":     work.Title(),
-		"author_id": work.AuthorID(),
-	})
+	c.JSON(http.StatusOK, workToResponse(work))
 }
 
 func (h *WorkHandler) GetByAuthor(c *gin.Context) {
@@ -84,53 +115,60 @@ func (h *WorkHandler) GetByAuthor(c *gin.Context) {
 
 	result := make([]gin.H, len(works))
 	for i, w := range works {
-		result[i] = gin.H{
-			"id":        w.ID(),
-			"title":     w.Title(),
-			"author_id": w.AuthorID(),
-		}
+		result[i] = workToResponse(w)
 	}
 
 	c.JSON(http.StatusOK, result)
 }
 
+// List returns a page of works, optionally narrowed to those by any of the
+// writers in author_id, a comma-separated list. Works are in ID order, and
+// search results best match first.
 func (h *WorkHandler) List(c *gin.Context) {
-	limitStr := c.DefaultQuery("limit", "10")
-	offsetStr := c.DefaultQuery("offset", "0")
 	searchQuery := c.Query("search")
+	limit := parseLimit(c)
 
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 {
-		limit = 10
+	authorIDs, err := parseIDListParam(c, "author_id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
+	filter := repository.WorkFilter{AuthorIDs: authorIDs}
 
-	offset, err := strconv.Atoi(offsetStr)
-	if err != nil || offset < 0 {
-		offset = 0
+	if searchQuery == "" {
+		after, err := parseCursor[repository.WorkCursor](c, "", false)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		page, err := h.workService.ListWorks(filter, after, limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, pageResponse(worksToResponse(page.Items), page.Total, "", false, page.Next))
+		return
 	}
 
-	var works []*domain.Work
-	if searchQuery != "" {
-		works, err = h.workService.SearchWorks(searchQuery, limit, offset)
-	} else {
-		works, err = h.workService.ListWorks(limit, offset)
+	offset, err := parseSearchOffset(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
-
+	page, err := h.workService.SearchWorks(searchQuery, filter, limit, offset)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	c.JSON(http.StatusOK, pageResponse(worksToResponse(page.Items), page.Total, searchOrder, false, page.Next))
+}
 
+func worksToResponse(works []*domain.Work) []gin.H {
 	result := make([]gin.H, len(works))
 	for i, w := range works {
-		result[i] = gin.H{
-			"id":        w.ID(),
-			"title":     w.Title(),
-			"author_id": w.AuthorID(),
-		}
+		result[i] = workToResponse(w)
 	}
-
-	c.JSON(http.StatusOK, result)
+	return result
 }
 
 func (h *WorkHandler) Update(c *gin.Context) {
@@ -141,13 +179,19 @@ func (h *WorkHandler) Update(c *gin.Context) {
 		return
 	}
 
-	var req UpdateWorkRequest
+	var req WorkRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	authorIDs, err := req.authorIDs()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	if err := h.workService.UpdateWork(id, req.Title, req.AuthorID); err != nil {
+	err = h.workService.UpdateWork(c.Request.Context(), id, req.Title, authorIDs, req.detailsUpdate())
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -163,10 +207,30 @@ func (h *WorkHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.workService.DeleteWork(id); err != nil {
+	if err := h.workService.DeleteWork(c.Request.Context(), id); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "work deleted"})
 }
+
+// workToResponse also reports the first author as author_id, null for an
+// anonymous work, for clients that predate co-authors.
+func workToResponse(w *domain.Work) gin.H {
+	details := w.Details()
+	var authorID *uint64
+	if len(w.AuthorIDs()) > 0 {
+		authorID = &w.AuthorIDs()[0]
+	}
+	return gin.H{
+		"id":                w.ID(),
+		"title":             w.Title(),
+		"author_ids":        append([]uint64{}, w.AuthorIDs()...),
+		"author_id":         authorID,
+		"publication_year":  details.PublicationYear,
+		"genre":             details.Genre,
+		"original_language": details.OriginalLanguage,
+		"original_title":    details.OriginalTitle,
+	}
+}
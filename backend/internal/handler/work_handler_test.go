@@ -25,7 +25,7 @@ func setupWorkHandlerRouter(
 
 	workRepo := gorm.NewWorkRepository(db)
 	writerRepo := gorm.NewWriterRepository(db)
-	workService := service.NewWorkService(workRepo, writerRepo)
+	workService := service.NewWorkService(workRepo, writerRepo, gorm.NewTransactor(db))
 
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
@@ -66,6 +66,60 @@ func TestWorkHandler_Create(t *testing.T) {
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		require.NoError(t, err)
 		assert.Equal(t, "Pride and Prejudice", response["title"])
+		assert.Equal(t, []interface{}{float64(1)}, response["author_ids"])
+	})
+
+	t.Run("co-authors and details", func(t *testing.T) {
+		t.Parallel()
+		router, _, writerRepo, cleanup := setupWorkHandlerRouter(t)
+		defer cleanup()
+
+		require.NoError(t, writerRepo.Create(domain.NewWriter(1, "Jane Austen", 1775, nil, nil)))
+		require.NoError(t, writerRepo.Create(domain.NewWriter(2, "Charlotte Bronte", 1816, nil, nil)))
+
+		body := []byte(`{"title":"A Joint Novel","author_ids":[2,1],"publication_year":1850,` +
+			`"genre":"novel","original_language":"French","original_title":"Un roman"}`)
+		req := httptest.NewRequest(http.MethodPost, "/works", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, []interface{}{float64(2), float64(1)}, response["author_ids"])
+		assert.Equal(t, float64(2), response["author_id"])
+		assert.Equal(t, float64(1850), response["publication_year"])
+		assert.Equal(t, "novel", response["genre"])
+		assert.Equal(t, "French", response["original_language"])
+		assert.Equal(t, "Un roman", response["original_title"])
+
+		body = []byte(`{"title":"Beowulf"}`)
+		req = httptest.NewRequest(http.MethodPost, "/works", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, []interface{}{}, response["author_ids"])
+		assert.Nil(t, response["author_id"])
+	})
+
+	t.Run("author_ids and author_id together", func(t *testing.T) {
+		t.Parallel()
+		router, _, writerRepo, cleanup := setupWorkHandlerRouter(t)
+		defer cleanup()
+
+		require.NoError(t, writerRepo.Create(domain.NewWriter(1, "Jane Austen", 1775, nil, nil)))
+
+		body := []byte(`{"title":"Emma","author_ids":[1],"author_id":1}`)
+		req := httptest.NewRequest(http.MethodPost, "/works", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
 
 	t.Run("missing title", func(t *testing.T) {
@@ -116,7 +170,7 @@ func TestWorkHandler_GetByID(t *testing.T) {
 		// Create writer and work first
 		writer := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
 		require.NoError(t, writerRepo.Create(writer))
-		work := domain.NewWork(1, "Pride and Prejudice", 1)
+		work := domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})
 		require.NoError(t, workRepo.Create(work))
 
 		req := httptest.NewRequest(http.MethodGet, "/works/1", http.NoBody)
@@ -167,8 +221,8 @@ func TestWorkHandler_GetByAuthor(t *testing.T) {
 
 		writer := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
 		require.NoError(t, writerRepo.Create(writer))
-		work1 := domain.NewWork(1, "Pride and Prejudice", 1)
-		work2 := domain.NewWork(2, "Sense and Sensibility", 1)
+		work1 := domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})
+		work2 := domain.NewWork(2, "Sense and Sensibility", []uint64{1}, domain.WorkDetails{})
 		require.NoError(t, workRepo.Create(work1))
 		require.NoError(t, workRepo.Create(work2))
 
@@ -207,8 +261,8 @@ func TestWorkHandler_List(t *testing.T) {
 
 		writer := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
 		require.NoError(t, writerRepo.Create(writer))
-		work1 := domain.NewWork(1, "Pride and Prejudice", 1)
-		work2 := domain.NewWork(2, "Sense and Sensibility", 1)
+		work1 := domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})
+		work2 := domain.NewWork(2, "Sense and Sensibility", []uint64{1}, domain.WorkDetails{})
 		require.NoError(t, workRepo.Create(work1))
 		require.NoError(t, workRepo.Create(work2))
 
@@ -218,10 +272,74 @@ func TestWorkHandler_List(t *testing.T) {
 		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusOK, w.Code)
-		var response []map[string]interface{}
+		var response listResponse
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		require.NoError(t, err)
-		assert.Len(t, response, 2)
+		assert.Len(t, response.Items, 2)
+	})
+
+	t.Run("by author", func(t *testing.T) {
+		t.Parallel()
+		router, workRepo, writerRepo, cleanup := setupWorkHandlerRouter(t)
+		defer cleanup()
+
+		require.NoError(t, writerRepo.Create(domain.NewWriter(1, "Jane Austen", 1775, nil, nil)))
+		require.NoError(t, writerRepo.Create(domain.NewWriter(2, "Charles Dickens", 1812, nil, nil)))
+		require.NoError(t, workRepo.Create(domain.NewWork(1, "Emma", []uint64{1}, domain.WorkDetails{})))
+		require.NoError(t, workRepo.Create(domain.NewWork(2, "Bleak House", []uint64{2}, domain.WorkDetails{})))
+
+		req := httptest.NewRequest(http.MethodGet, "/works?author_id=2", http.NoBody)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response listResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.Items, 1)
+		assert.Equal(t, "Bleak House", response.Items[0]["title"])
+
+		req = httptest.NewRequest(http.MethodGet, "/works?author_id=two", http.NoBody)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("search pages", func(t *testing.T) {
+		t.Parallel()
+		router, workRepo, writerRepo, cleanup := setupWorkHandlerRouter(t)
+		defer cleanup()
+
+		require.NoError(t, writerRepo.Create(domain.NewWriter(1, "Jane Austen", 1775, nil, nil)))
+		require.NoError(t, workRepo.Create(domain.NewWork(1, "Prejudice", []uint64{1}, domain.WorkDetails{})))
+		require.NoError(t, workRepo.Create(domain.NewWork(2, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})))
+
+		req := httptest.NewRequest(http.MethodGet, "/works?search=prejudice&limit=1", http.NoBody)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		var response listResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, int64(2), response.Total)
+		require.Len(t, response.Items, 1)
+		assert.Equal(t, "Prejudice", response.Items[0]["title"])
+		require.NotNil(t, response.NextCursor)
+		cursor := *response.NextCursor
+
+		req = httptest.NewRequest(http.MethodGet, "/works?search=prejudice&limit=1&cursor="+cursor, http.NoBody)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		response = listResponse{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.Items, 1)
+		assert.Equal(t, "Pride and Prejudice", response.Items[0]["title"])
+		assert.Nil(t, response.NextCursor)
+
+		// Search cursors hold an offset, not a position in the listing
+		req = httptest.NewRequest(http.MethodGet, "/works?cursor="+cursor, http.NoBody)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
 }
 
@@ -234,7 +352,7 @@ func TestWorkHandler_Update(t *testing.T) {
 
 		writer := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
 		require.NoError(t, writerRepo.Create(writer))
-		work := domain.NewWork(1, "Pride and Prejudice", 1)
+		work := domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})
 		require.NoError(t, workRepo.Create(work))
 
 		reqBody := map[string]interface{}{
@@ -251,6 +369,32 @@ func TestWorkHandler_Update(t *testing.T) {
 		assert.Equal(t, http.StatusOK, w.Code)
 	})
 
+	t.Run("details left out are kept", func(t *testing.T) {
+		t.Parallel()
+		router, workRepo, writerRepo, cleanup := setupWorkHandlerRouter(t)
+		defer cleanup()
+
+		require.NoError(t, writerRepo.Create(domain.NewWriter(1, "Anton Chekhov", 1860, nil, nil)))
+		year, genre := 1896, "play"
+		require.NoError(t, workRepo.Create(domain.NewWork(1, "The Seagull", []uint64{1}, domain.WorkDetails{
+			PublicationYear: &year,
+			Genre:           &genre,
+		})))
+
+		body, _ := json.Marshal(map[string]interface{}{"title": "Чайка", "author_ids": []uint64{1}, "genre": nil})
+		req := httptest.NewRequest(http.MethodPut, "/works/1", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		updated, err := workRepo.GetByID(1)
+		require.NoError(t, err)
+		assert.Equal(t, "Чайка", updated.Title())
+		assert.Equal(t, &year, updated.Details().PublicationYear)
+		assert.Nil(t, updated.Details().Genre)
+	})
+
 	t.Run("missing title", func(t *testing.T) {
 		t.Parallel()
 		router, _, _, cleanup := setupWorkHandlerRouter(t)
@@ -279,7 +423,7 @@ func TestWorkHandler_Delete(t *testing.T) {
 
 		writer := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
 		require.NoError(t, writerRepo.Create(writer))
-		work := domain.NewWork(1, "Pride and Prejudice", 1)
+		work := domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})
 		require.NoError(t, workRepo.Create(work))
 
 		req := httptest.NewRequest(http.MethodDelete, "/works/1", http.NoBody)
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/service"
+)
+
+type WriterAliasHandler struct {
+	aliasService service.WriterAliasService
+}
+
+func NewWriterAliasHandler(aliasService service.WriterAliasService) *WriterAliasHandler {
+	return &WriterAliasHandler{aliasService: aliasService}
+}
+
+// WriterAliasRequest creates or updates an alias. Kind is one of
+// pseudonym, birth_name, transliteration or native_script.
+type WriterAliasRequest struct {
+	Name string `json:"name" binding:"required"`
+	Kind string `json:"kind" binding:"required"`
+}
+
+func (h *WriterAliasHandler) Create(c *gin.Context) {
+	writerID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req WriterAliasRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	kind, err := domain.ParseAliasKind(req.Kind)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	alias, err := h.aliasService.AddAlias(c.Request.Context(), writerID, req.Name, kind)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, writerAliasToResponse(alias))
+}
+
+func (h *WriterAliasHandler) List(c *gin.Context) {
+	writerID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	aliases, err := h.aliasService.ListAliases(writerID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	result := make([]gin.H, len(aliases))
+	for i, a := range aliases {
+		result[i] = writerAliasToResponse(a)
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *WriterAliasHandler) Update(c *gin.Context) {
+	writerID, aliasID, ok := parseAliasIDs(c)
+	if !ok {
+		return
+	}
+
+	var req WriterAliasRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	kind, err := domain.ParseAliasKind(req.Kind)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	alias, err := h.aliasService.UpdateAlias(c.Request.Context(), writerID, aliasID, req.Name, kind)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, writerAliasToResponse(alias))
+}
+
+func (h *WriterAliasHandler) Delete(c *gin.Context) {
+	writerID, aliasID, ok := parseAliasIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.aliasService.DeleteAlias(c.Request.Context(), writerID, aliasID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "alias deleted"})
+}
+
+// parseAliasIDs reads the writer and alias IDs from the path, answering
+// 400 when either is malformed.
+func parseAliasIDs(c *gin.Context) (writerID, aliasID uint64, ok bool) {
+	writerID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return 0, 0, false
+	}
+	aliasID, err = strconv.ParseUint(c.Param("alias_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid alias id"})
+		return 0, 0, false
+	}
+	return writerID, aliasID, true
+}
+
+func writerAliasToResponse(a *domain.WriterAlias) gin.H {
+	return gin.H{
+		"id":        a.ID(),
+		"writer_id": a.WriterID(),
+		"name":      a.Name(),
+		"kind":      a.Kind(),
+	}
+}
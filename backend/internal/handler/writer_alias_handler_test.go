@@ -0,0 +1,99 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/handler"
+	"github.com/what-writers-like/backend/internal/repository/gorm"
+	"github.com/what-writers-like/backend/internal/service"
+	"github.com/what-writers-like/backend/internal/testutils"
+)
+
+func TestWriterAliasHandler_CRUD(t *testing.T) {
+	t.Parallel()
+	db, cleanup := testutils.SetupTestDB(t)
+	defer cleanup()
+
+	writerRepo := gorm.NewWriterRepository(db)
+	transactor := gorm.NewTransactor(db)
+	aliasService := service.NewWriterAliasService(gorm.NewWriterAliasRepository(db), writerRepo, transactor)
+	writerService := service.NewWriterService(writerRepo, gorm.NewWorkRepository(db), transactor)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	aliasHandler := handler.NewWriterAliasHandler(aliasService)
+	router.GET("/writers", handler.NewWriterHandler(writerService).List)
+	router.POST("/writers/:id/aliases", aliasHandler.Create)
+	router.GET("/writers/:id/aliases", aliasHandler.List)
+	router.PUT("/writers/:id/aliases/:alias_id", aliasHandler.Update)
+	router.DELETE("/writers/:id/aliases/:alias_id", aliasHandler.Delete)
+
+	require.NoError(t, writerRepo.Create(domain.NewWriter(1, "Maxim Gorky", 1868, nil, nil)))
+
+	body := []byte(`{"name":"Alexei Peshkov","kind":"birth_name"}`)
+	req := httptest.NewRequest(http.MethodPost, "/writers/1/aliases", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var created map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, "birth_name", created["kind"])
+	aliasID := uint64(created["id"].(float64))
+
+	body = []byte(`{"name":"Gorky","kind":"pen_name"}`)
+	req = httptest.NewRequest(http.MethodPost, "/writers/1/aliases", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Searches report the alias a writer was found by
+	req = httptest.NewRequest(http.MethodGet, "/writers?search=Peshkov", http.NoBody)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var found listResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &found))
+	require.Len(t, found.Items, 1)
+	assert.Equal(t, "Maxim Gorky", found.Items[0]["name"])
+	matched, ok := found.Items[0]["matched_alias"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "Alexei Peshkov", matched["name"])
+
+	aliasURL := fmt.Sprintf("/writers/1/aliases/%d", aliasID)
+	body = []byte(`{"name":"Aleksei Peshkov","kind":"birth_name"}`)
+	req = httptest.NewRequest(http.MethodPut, aliasURL, bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/writers/1/aliases", http.NoBody)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var aliases []map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &aliases))
+	require.Len(t, aliases, 1)
+	assert.Equal(t, "Aleksei Peshkov", aliases[0]["name"])
+
+	req = httptest.NewRequest(http.MethodDelete, aliasURL, http.NoBody)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/writers/999/aliases", http.NoBody)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
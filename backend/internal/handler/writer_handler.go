@@ -6,6 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/repository"
 	"github.com/what-writers-like/backend/internal/service"
 )
 
@@ -38,19 +39,13 @@ func (h *WriterHandler) Create(c *gin.Context) {
 		return
 	}
 
-	writer, err := h.writerService.CreateWriter(req.Name, req.BirthYear, req.DeathYear, req.Bio)
+	writer, err := h.writerService.CreateWriter(c.Request.Context(), req.Name, req.BirthYear, req.DeathYear, req.Bio)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
-		"id":         writer.ID(),
-		"name":       writer.Name(),
-		"birth_year": writer.BirthYear(),
-		"death_year": writer.DeathYear(),
-		"bio":        writer.Bio(),
-	})
+	c.JSON(http.StatusCreated, writerToResponse(writer))
 }
 
 func (h *WriterHandler) GetByID(c *gin.Context) {
@@ -67,54 +62,92 @@ func (h *WriterHandler) GetByID(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"id":         writer.ID(),
-		"name":       writer.Name(),
-		"birth_year": writer.BirthYear(),
-		"death_year": writer.DeathYear(),
-		"bio":        writer.Bio(),
-	})
+	c.JSON(http.StatusOK, writerToResponse(writer))
 }
 
+// List returns a page of writers, optionally narrowed to birth and death
+// year ranges (born_from, born_to, died_from, died_to, all inclusive) and
+// to those alive or not (alive=true or false). Without a search they are
+// ordered by sort, name or birth_year, and order, asc or desc; search
+// results come best match first.
 func (h *WriterHandler) List(c *gin.Context) {
-	limitStr := c.DefaultQuery("limit", "10")
-	offsetStr := c.DefaultQuery("offset", "0")
 	searchQuery := c.Query("search")
+	limit := parseLimit(c)
 
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 {
-		limit = 10
+	filter, err := parseWriterFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
-
-	offset, err := strconv.Atoi(offsetStr)
-	if err != nil || offset < 0 {
-		offset = 0
+	key, descending, err := parseSort(c, string(repository.WriterSortName), string(repository.WriterSortBirthYear))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	var writers []*domain.Writer
-	if searchQuery != "" {
-		writers, err = h.writerService.SearchWriters(searchQuery, limit, offset)
-	} else {
-		writers, err = h.writerService.ListWriters(limit, offset)
+	if searchQuery == "" {
+		after, err := parseCursor[repository.WriterCursor](c, key, descending)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		sort := repository.WriterSort{Key: repository.WriterSortKey(key), Descending: descending}
+		page, err := h.writerService.ListWriters(filter, sort, after, limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		result := make([]gin.H, len(page.Items))
+		for i, w := range page.Items {
+			result[i] = writerToResponse(w)
+		}
+		c.JSON(http.StatusOK, pageResponse(result, page.Total, key, descending, page.Next))
+		return
+	}
+	if key != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sort cannot be combined with search"})
+		return
+	}
+	offset, err := parseSearchOffset(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
+	// Search results name the alias a writer was found by, if any
+	page, err := h.writerService.SearchWriters(searchQuery, filter, limit, offset)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-
-	result := make([]gin.H, len(writers))
-	for i, w := range writers {
-		result[i] = gin.H{
-			"id":         w.ID(),
-			"name":       w.Name(),
-			"birth_year": w.BirthYear(),
-			"death_year": w.DeathYear(),
-			"bio":        w.Bio(),
+	result := make([]gin.H, len(page.Items))
+	for i, m := range page.Items {
+		result[i] = writerToResponse(m.Writer)
+		result[i]["matched_alias"] = nil
+		if m.Alias != nil {
+			result[i]["matched_alias"] = writerAliasToResponse(m.Alias)
 		}
 	}
+	c.JSON(http.StatusOK, pageResponse(result, page.Total, searchOrder, false, page.Next))
+}
 
-	c.JSON(http.StatusOK, result)
+func parseWriterFilter(c *gin.Context) (repository.WriterFilter, error) {
+	var filter repository.WriterFilter
+	var err error
+	if filter.BirthYearFrom, err = parseYearParam(c, "born_from"); err != nil {
+		return filter, err
+	}
+	if filter.BirthYearTo, err = parseYearParam(c, "born_to"); err != nil {
+		return filter, err
+	}
+	if filter.DeathYearFrom, err = parseYearParam(c, "died_from"); err != nil {
+		return filter, err
+	}
+	if filter.DeathYearTo, err = parseYearParam(c, "died_to"); err != nil {
+		return filter, err
+	}
+	filter.Alive, err = parseBoolParam(c, "alive")
+	return filter, err
 }
 
 func (h *WriterHandler) Update(c *gin.Context) {
@@ -131,7 +164,8 @@ func (h *WriterHandler) Update(c *gin.Context) {
 		return
 	}
 
-	if err := h.writerService.UpdateWriter(id, req.Name, req.BirthYear, req.DeathYear, req.Bio); err != nil {
+	err = h.writerService.UpdateWriter(c.Request.Context(), id, req.Name, req.BirthYear, req.DeathYear, req.Bio)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -147,10 +181,20 @@ func (h *WriterHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.writerService.DeleteWriter(id); err != nil {
+	if err := h.writerService.DeleteWriter(c.Request.Context(), id); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "writer deleted"})
 }
+
+func writerToResponse(w *domain.Writer) gin.H {
+	return gin.H{
+		"id":         w.ID(),
+		"name":       w.Name(),
+		"birth_year": w.BirthYear(),
+		"death_year": w.DeathYear(),
+		"bio":        w.Bio(),
+	}
+}
@@ -25,7 +25,7 @@ func setupWriterHandlerRouter(
 
 	writerRepo := gorm.NewWriterRepository(db)
 	workRepo := gorm.NewWorkRepository(db)
-	writerService := service.NewWriterService(writerRepo, workRepo)
+	writerService := service.NewWriterService(writerRepo, workRepo, gorm.NewTransactor(db))
 
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
@@ -38,6 +38,13 @@ func setupWriterHandlerRouter(
 	return router, writerRepo, workRepo, cleanup
 }
 
+// listResponse is the envelope the list endpoints return a page in.
+type listResponse struct {
+	Items      []map[string]interface{} `json:"items"`
+	Total      int64                    `json:"total"`
+	NextCursor *string                  `json:"next_cursor"`
+}
+
 func TestWriterHandler_Create(t *testing.T) {
 	t.Parallel()
 	t.Run("success", func(t *testing.T) {
@@ -168,10 +175,101 @@ func TestWriterHandler_List(t *testing.T) {
 		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusOK, w.Code)
-		var response []map[string]interface{}
+		var response listResponse
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		require.NoError(t, err)
-		assert.Len(t, response, 2)
+		assert.Len(t, response.Items, 2)
+	})
+
+	t.Run("filtered and sorted", func(t *testing.T) {
+		t.Parallel()
+		router, writerRepo, _, cleanup := setupWriterHandlerRouter(t)
+		defer cleanup()
+
+		deathYear := 1817
+		require.NoError(t, writerRepo.Create(domain.NewWriter(1, "Jane Austen", 1775, &deathYear, nil)))
+		require.NoError(t, writerRepo.Create(domain.NewWriter(2, "Salman Rushdie", 1947, nil, nil)))
+		require.NoError(t, writerRepo.Create(domain.NewWriter(3, "Kazuo Ishiguro", 1954, nil, nil)))
+
+		req := httptest.NewRequest(http.MethodGet,
+			"/writers?alive=true&born_from=1900&sort=birth_year&order=desc", http.NoBody)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response listResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.Items, 2)
+		assert.Equal(t, "Kazuo Ishiguro", response.Items[0]["name"])
+		assert.Equal(t, "Salman Rushdie", response.Items[1]["name"])
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		t.Parallel()
+		router, _, _, cleanup := setupWriterHandlerRouter(t)
+		defer cleanup()
+
+		for query, message := range map[string]string{
+			"born_from=soon":          "invalid born_from: expected a year",
+			"alive=yes":               "invalid alive: expected true or false",
+			"sort=death_year":         `invalid sort \"death_year\": expected name or birth_year`,
+			"order=up":                `invalid order \"up\": expected asc or desc`,
+			"search=Austen&sort=name": "sort cannot be combined with search",
+		} {
+			req := httptest.NewRequest(http.MethodGet, "/writers?"+query, http.NoBody)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, query)
+			assert.Contains(t, w.Body.String(), message, query)
+		}
+	})
+
+	t.Run("pages", func(t *testing.T) {
+		t.Parallel()
+		router, writerRepo, _, cleanup := setupWriterHandlerRouter(t)
+		defer cleanup()
+
+		for i, name := range []string{"Leo Tolstoy", "Jane Austen", "Anton Chekhov"} {
+			require.NoError(t, writerRepo.Create(domain.NewWriter(uint64(i+1), name, 1800, nil, nil)))
+		}
+
+		get := func(url string) listResponse {
+			req := httptest.NewRequest(http.MethodGet, url, http.NoBody)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			var response listResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			return response
+		}
+
+		first := get("/writers?sort=name&limit=2")
+		assert.Equal(t, int64(3), first.Total)
+		require.Len(t, first.Items, 2)
+		assert.Equal(t, "Anton Chekhov", first.Items[0]["name"])
+		assert.Equal(t, "Jane Austen", first.Items[1]["name"])
+		require.NotNil(t, first.NextCursor)
+
+		second := get("/writers?sort=name&limit=2&cursor=" + *first.NextCursor)
+		assert.Equal(t, int64(3), second.Total)
+		require.Len(t, second.Items, 1)
+		assert.Equal(t, "Leo Tolstoy", second.Items[0]["name"])
+		assert.Nil(t, second.NextCursor)
+
+		// A cursor only continues the order it was taken from
+		for _, query := range []string{"sort=name&order=desc", "sort=birth_year", ""} {
+			req := httptest.NewRequest(http.MethodGet, "/writers?cursor="+*first.NextCursor+"&"+query, http.NoBody)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusBadRequest, w.Code, query)
+			assert.Contains(t, w.Body.String(), "cursor does not match sort and order", query)
+		}
+		req := httptest.NewRequest(http.MethodGet, "/writers?cursor=not-a-cursor", http.NoBody)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "invalid cursor")
 	})
 }
 
@@ -3,16 +3,46 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+)
+
+// Storage backends selectable with the STORAGE environment variable.
+const (
+	StoragePostgres = "postgres"
+	StorageSQLite   = "sqlite"
+	StorageMemory   = "memory"
 )
 
 type Config struct {
-	DatabaseDSN string
-	ServerPort  string
+	Storage        string
+	DatabaseDSN    string
+	ServerPort     string
+	MigrateOnStart bool
+	AuthSigningKey string
+	MaxUploadBytes int64
 }
 
+// DefaultMaxUploadBytes bounds an uploaded import file or snapshot unless
+// MAX_UPLOAD_BYTES says otherwise.
+const DefaultMaxUploadBytes = 32 << 20
+
 func NewConfig() (*Config, error) {
+	storage := os.Getenv("STORAGE")
+	switch storage {
+	case "":
+		storage = StoragePostgres
+	case StoragePostgres, StorageSQLite, StorageMemory:
+	default:
+		return nil, fmt.Errorf(
+			"invalid STORAGE value %q: expected %s, %s or %s",
+			storage, StoragePostgres, StorageSQLite, StorageMemory,
+		)
+	}
+
+	// For SQLite the DSN is the path of the database file. The in-memory
+	// backend needs no database.
 	dsn := os.Getenv("DATABASE_DSN")
-	if dsn == "" {
+	if dsn == "" && storage != StorageMemory {
 		return nil, fmt.Errorf("DATABASE_DSN environment variable is required")
 	}
 
@@ -21,8 +51,32 @@ func NewConfig() (*Config, error) {
 		port = "8080"
 	}
 
+	// Pending migrations are applied at startup unless disabled, in which
+	// case they have to be run with the migrate command first
+	migrateOnStart := true
+	if raw := os.Getenv("MIGRATE_ON_START"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MIGRATE_ON_START value %q: %w", raw, err)
+		}
+		migrateOnStart = parsed
+	}
+
+	maxUploadBytes := int64(DefaultMaxUploadBytes)
+	if raw := os.Getenv("MAX_UPLOAD_BYTES"); raw != "" {
+		parsed, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || parsed <= 0 {
+			return nil, fmt.Errorf("invalid MAX_UPLOAD_BYTES value %q: expected a positive number of bytes", raw)
+		}
+		maxUploadBytes = parsed
+	}
+
 	return &Config{
-		DatabaseDSN: dsn,
-		ServerPort:  port,
+		Storage:        storage,
+		DatabaseDSN:    dsn,
+		ServerPort:     port,
+		MigrateOnStart: migrateOnStart,
+		AuthSigningKey: os.Getenv("AUTH_SIGNING_KEY"),
+		MaxUploadBytes: maxUploadBytes,
 	}, nil
 }
@@ -9,88 +9,106 @@ import (
 	"github.com/what-writers-like/backend/internal/infrastructure/config"
 )
 
+// Dialect names as reported by gorm.Dialector.Name.
+const (
+	DialectPostgres = "postgres"
+	DialectSQLite   = "sqlite"
+)
+
 type Database struct {
 	db *gorm.DB
 }
 
+// NewDatabase connects to the database and makes sure its schema matches
+// this binary. Pending migrations are applied when MigrateOnStart is set;
+// otherwise, and in any case when the schema was migrated by a newer
+// release, startup fails.
 func NewDatabase(cfg *config.Config) (*Database, error) {
-	dsn := cfg.DatabaseDSN
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	db, err := Open(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to database: %w", err)
-	}
-
-	if err := AutoMigrate(db); err != nil {
-		return nil, fmt.Errorf("failed to run migrations: %w", err)
+		return nil, err
 	}
 
-	if err := enableExtensions(db); err != nil {
-		return nil, fmt.Errorf("failed to enable extensions: %w", err)
+	migrator, err := NewMigrator(db.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load migrations: %w", err)
 	}
 
-	if err := addConstraints(db); err != nil {
-		return nil, fmt.Errorf("failed to add constraints: %w", err)
+	if cfg.MigrateOnStart {
+		if _, err := migrator.Up(); err != nil {
+			return nil, fmt.Errorf("failed to run migrations: %w", err)
+		}
 	}
 
-	if err := createSearchIndexes(db); err != nil {
-		return nil, fmt.Errorf("failed to create search indexes: %w", err)
+	if err := migrator.CheckSchema(); err != nil {
+		return nil, fmt.Errorf("database schema check failed: %w", err)
 	}
 
-	return &Database{db: db}, nil
+	return db, nil
 }
 
-func enableExtensions(db *gorm.DB) error {
-	// Enable pg_trgm extension for fuzzy search
-	if err := db.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm").Error; err != nil {
-		return fmt.Errorf("failed to enable pg_trgm extension: %w", err)
+// Open connects to the configured database without touching its schema.
+func Open(cfg *config.Config) (*Database, error) {
+	var db *gorm.DB
+	var err error
+	switch cfg.Storage {
+	case config.StoragePostgres:
+		db, err = gorm.Open(postgres.Open(cfg.DatabaseDSN), &gorm.Config{})
+	case config.StorageSQLite:
+		db, err = openSQLite(cfg.DatabaseDSN)
+	default:
+		return nil, fmt.Errorf("storage %q is not backed by a database", cfg.Storage)
 	}
-	return nil
-}
-
-func createSearchIndexes(db *gorm.DB) error {
-	// Create GIN indexes for fuzzy search on writers table
-	indexesSQL := `
-		CREATE INDEX IF NOT EXISTS idx_writers_name_trgm ON writers USING gin(name gin_trgm_ops);
-		CREATE INDEX IF NOT EXISTS idx_writers_bio_trgm ON writers USING gin(bio gin_trgm_ops) WHERE bio IS NOT NULL;
-		CREATE INDEX IF NOT EXISTS idx_works_title_trgm ON works USING gin(title gin_trgm_ops);
-	`
-
-	if err := db.Exec(indexesSQL).Error; err != nil {
-		return fmt.Errorf("failed to create search indexes: %w", err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
-
-	return nil
+	return &Database{db: db}, nil
 }
 
-func addConstraints(db *gorm.DB) error {
-	// Add CHECK constraint: writer_id ≠ Work.author_id
-	// PostgreSQL doesn't allow subqueries in CHECK constraints directly,
-	// so we use a trigger function approach
-	constraintSQL := `
-		CREATE OR REPLACE FUNCTION check_writer_not_author()
-		RETURNS TRIGGER AS $$
-		BEGIN
-			IF EXISTS (
-				SELECT 1 FROM works 
-				WHERE id = NEW.work_id AND author_id = NEW.writer_id
-			) THEN
-				RAISE EXCEPTION 'writer cannot express opinion about their own work';
-			END IF;
-			RETURN NEW;
-		END;
-		$$ LANGUAGE plpgsql;
-
-		DROP TRIGGER IF EXISTS trigger_check_writer_not_author ON opinions;
-		CREATE TRIGGER trigger_check_writer_not_author
-			BEFORE INSERT OR UPDATE ON opinions
-			FOR EACH ROW
-			EXECUTE FUNCTION check_writer_not_author();
-	`
+// SyncIDSequence advances the id sequence of table so that the next
+// generated ID is above every existing row. It never moves the sequence
+// backwards, so IDs handed out to concurrent transactions stay unique.
+// Call it after inserting rows with explicit IDs.
+func SyncIDSequence(db *gorm.DB, table string) error {
+	// SQLite derives the next rowid from the largest one in use
+	if db.Dialector.Name() == DialectSQLite {
+		return nil
+	}
 
-	if err := db.Exec(constraintSQL).Error; err != nil {
-		return fmt.Errorf("failed to create constraint trigger: %w", err)
+	var syncSQL string
+	switch table {
+	case WritersTable:
+		syncSQL = `
+			SELECT setval('writers_id_seq', GREATEST(COALESCE(m.max_id, 0), s.last_value), s.is_called OR m.max_id IS NOT NULL)
+			FROM (SELECT MAX(id) AS max_id FROM writers) m, writers_id_seq s
+		`
+	case WorksTable:
+		syncSQL = `
+			SELECT setval('works_id_seq', GREATEST(COALESCE(m.max_id, 0), s.last_value), s.is_called OR m.max_id IS NOT NULL)
+			FROM (SELECT MAX(id) AS max_id FROM works) m, works_id_seq s
+		`
+	case OpinionsTable:
+		syncSQL = `
+			SELECT setval('opinions_id_seq', GREATEST(COALESCE(m.max_id, 0), s.last_value), s.is_called OR m.max_id IS NOT NULL)
+			FROM (SELECT MAX(id) AS max_id FROM opinions) m, opinions_id_seq s
+		`
+	case SourcesTable:
+		syncSQL = `
+			SELECT setval('sources_id_seq', GREATEST(COALESCE(m.max_id, 0), s.last_value), s.is_called OR m.max_id IS NOT NULL)
+			FROM (SELECT MAX(id) AS max_id FROM sources) m, sources_id_seq s
+		`
+	case WriterAliasesTable:
+		syncSQL = `
+			SELECT setval('writer_aliases_id_seq', GREATEST(COALESCE(m.max_id, 0), s.last_value), s.is_called OR m.max_id IS NOT NULL)
+			FROM (SELECT MAX(id) AS max_id FROM writer_aliases) m, writer_aliases_id_seq s
+		`
+	default:
+		return fmt.Errorf("no id sequence for table %q", table)
 	}
 
+	if err := db.Exec(syncSQL).Error; err != nil {
+		return fmt.Errorf("failed to sync %s id sequence: %w", table, err)
+	}
 	return nil
 }
 
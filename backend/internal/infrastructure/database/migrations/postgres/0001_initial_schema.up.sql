@@ -0,0 +1,28 @@
+-- Tables may already exist on databases created before versioned
+-- migrations, so every statement tolerates being run against them.
+CREATE TABLE IF NOT EXISTS writers (
+    id         BIGSERIAL PRIMARY KEY,
+    name       VARCHAR(255) NOT NULL,
+    birth_year BIGINT NOT NULL,
+    death_year BIGINT,
+    bio        TEXT
+);
+
+CREATE TABLE IF NOT EXISTS works (
+    id        BIGSERIAL PRIMARY KEY,
+    title     VARCHAR(255) NOT NULL,
+    author_id BIGINT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_works_author_id ON works (author_id);
+
+CREATE TABLE IF NOT EXISTS opinions (
+    writer_id      BIGINT NOT NULL,
+    work_id        BIGINT NOT NULL,
+    sentiment      BOOLEAN NOT NULL,
+    quote          TEXT NOT NULL,
+    source         VARCHAR(255) NOT NULL,
+    page           VARCHAR(100),
+    statement_year BIGINT,
+    PRIMARY KEY (writer_id, work_id)
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_works_title_trgm;
+DROP INDEX IF EXISTS idx_writers_bio_trgm;
+DROP INDEX IF EXISTS idx_writers_name_trgm;
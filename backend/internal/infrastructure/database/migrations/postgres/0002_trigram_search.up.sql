@@ -0,0 +1,6 @@
+-- pg_trgm provides similarity() for fuzzy search on names and titles
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_writers_name_trgm ON writers USING gin (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_writers_bio_trgm ON writers USING gin (bio gin_trgm_ops) WHERE bio IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_works_title_trgm ON works USING gin (title gin_trgm_ops);
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS trigger_check_writer_not_author ON opinions;
+DROP FUNCTION IF EXISTS check_writer_not_author();
@@ -0,0 +1,20 @@
+-- A writer cannot express an opinion about their own work. PostgreSQL does
+-- not allow subqueries in CHECK constraints, so this is enforced by a trigger.
+CREATE OR REPLACE FUNCTION check_writer_not_author()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM works
+        WHERE id = NEW.work_id AND author_id = NEW.writer_id
+    ) THEN
+        RAISE EXCEPTION 'writer cannot express opinion about their own work';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trigger_check_writer_not_author ON opinions;
+CREATE TRIGGER trigger_check_writer_not_author
+    BEFORE INSERT OR UPDATE ON opinions
+    FOR EACH ROW
+    EXECUTE FUNCTION check_writer_not_author();
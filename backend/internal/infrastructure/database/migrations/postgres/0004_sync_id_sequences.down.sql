@@ -0,0 +1 @@
+-- Sequences are never moved backwards.
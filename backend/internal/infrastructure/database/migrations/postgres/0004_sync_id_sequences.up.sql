@@ -0,0 +1,7 @@
+-- Before IDs were allocated by the database, services inserted rows with
+-- explicit IDs and left the sequences behind. Move them past existing rows.
+SELECT setval('writers_id_seq', GREATEST(COALESCE(m.max_id, 0), s.last_value), s.is_called OR m.max_id IS NOT NULL)
+FROM (SELECT MAX(id) AS max_id FROM writers) m, writers_id_seq s;
+
+SELECT setval('works_id_seq', GREATEST(COALESCE(m.max_id, 0), s.last_value), s.is_called OR m.max_id IS NOT NULL)
+FROM (SELECT MAX(id) AS max_id FROM works) m, works_id_seq s;
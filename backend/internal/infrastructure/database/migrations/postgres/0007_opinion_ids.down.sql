@@ -0,0 +1,26 @@
+-- Only one statement per writer and work can be kept: the earliest one.
+-- Revisions and audit entries of the others are dropped with them.
+DELETE FROM opinions o
+USING opinions first
+WHERE o.writer_id = first.writer_id AND o.work_id = first.work_id AND o.id > first.id;
+
+DELETE FROM opinion_revisions r
+WHERE EXISTS (
+    SELECT 1 FROM opinion_revisions earlier
+    WHERE earlier.writer_id = r.writer_id AND earlier.work_id = r.work_id AND earlier.opinion_id < r.opinion_id
+);
+
+UPDATE audit_log a SET entity_id = o.writer_id || ':' || o.work_id
+FROM opinions o
+WHERE a.entity_type = 'opinion' AND a.entity_id = CAST(o.id AS TEXT);
+
+ALTER TABLE opinion_revisions DROP CONSTRAINT opinion_revisions_opinion_id_revision_key;
+ALTER TABLE opinion_revisions DROP COLUMN opinion_id;
+ALTER TABLE opinion_revisions ADD CONSTRAINT opinion_revisions_writer_id_work_id_revision_key
+    UNIQUE (writer_id, work_id, revision);
+
+DROP INDEX IF EXISTS idx_opinions_work_id;
+DROP INDEX IF EXISTS idx_opinions_writer_work;
+ALTER TABLE opinions DROP CONSTRAINT opinions_pkey;
+ALTER TABLE opinions DROP COLUMN id;
+ALTER TABLE opinions ADD PRIMARY KEY (writer_id, work_id);
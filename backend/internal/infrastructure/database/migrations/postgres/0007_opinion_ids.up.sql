@@ -0,0 +1,38 @@
+-- Opinions get their own identity so that a writer can have several dated
+-- statements about the same work. Existing rows are numbered in key order.
+ALTER TABLE opinions DROP CONSTRAINT opinions_pkey;
+ALTER TABLE opinions ADD COLUMN id BIGSERIAL;
+UPDATE opinions o SET id = n.id
+FROM (SELECT writer_id, work_id, ROW_NUMBER() OVER (ORDER BY writer_id, work_id) AS id FROM opinions) n
+WHERE o.writer_id = n.writer_id AND o.work_id = n.work_id;
+ALTER TABLE opinions ADD PRIMARY KEY (id);
+SELECT setval('opinions_id_seq', COALESCE(MAX(id), 0) + 1, false) FROM opinions;
+
+CREATE INDEX idx_opinions_writer_work ON opinions (writer_id, work_id);
+CREATE INDEX idx_opinions_work_id ON opinions (work_id);
+
+-- Revisions now belong to an opinion rather than to a writer and work
+ALTER TABLE opinion_revisions ADD COLUMN opinion_id BIGINT;
+UPDATE opinion_revisions r SET opinion_id = o.id
+FROM opinions o
+WHERE o.writer_id = r.writer_id AND o.work_id = r.work_id;
+
+-- Revisions of deleted opinions keep an ID of their own so that they can
+-- still be restored
+WITH orphans AS (
+    SELECT writer_id, work_id, nextval('opinions_id_seq') AS id
+    FROM (SELECT DISTINCT writer_id, work_id FROM opinion_revisions WHERE opinion_id IS NULL) pairs
+)
+UPDATE opinion_revisions r SET opinion_id = orphans.id
+FROM orphans
+WHERE r.opinion_id IS NULL AND r.writer_id = orphans.writer_id AND r.work_id = orphans.work_id;
+
+ALTER TABLE opinion_revisions ALTER COLUMN opinion_id SET NOT NULL;
+ALTER TABLE opinion_revisions DROP CONSTRAINT opinion_revisions_writer_id_work_id_revision_key;
+ALTER TABLE opinion_revisions ADD CONSTRAINT opinion_revisions_opinion_id_revision_key UNIQUE (opinion_id, revision);
+
+-- Audit entries named opinions by writer and work; point them at the
+-- opinion instead where it still exists
+UPDATE audit_log a SET entity_id = CAST(o.id AS TEXT)
+FROM opinions o
+WHERE a.entity_type = 'opinion' AND a.entity_id = o.writer_id || ':' || o.work_id;
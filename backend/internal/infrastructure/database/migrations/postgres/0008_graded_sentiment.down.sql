@@ -0,0 +1,9 @@
+-- Only grades above the middle of the scale count as positive; neutral and
+-- mixed opinions become negative
+ALTER TABLE opinion_revisions DROP CONSTRAINT opinion_revisions_sentiment_check;
+ALTER TABLE opinion_revisions ALTER COLUMN sentiment TYPE BOOLEAN
+    USING sentiment IN ('+1', '+2');
+
+ALTER TABLE opinions DROP CONSTRAINT opinions_sentiment_check;
+ALTER TABLE opinions ALTER COLUMN sentiment TYPE BOOLEAN
+    USING sentiment IN ('+1', '+2');
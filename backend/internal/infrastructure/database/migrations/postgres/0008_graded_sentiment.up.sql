@@ -0,0 +1,12 @@
+-- Sentiment moves from yes/no to a graded scale from -2 to +2, plus "mixed"
+-- for statements that praise and condemn at once. Existing opinions become
+-- +1 or -1.
+ALTER TABLE opinions ALTER COLUMN sentiment TYPE VARCHAR(5)
+    USING CASE WHEN sentiment THEN '+1' ELSE '-1' END;
+ALTER TABLE opinions ADD CONSTRAINT opinions_sentiment_check
+    CHECK (sentiment IN ('-2', '-1', '0', '+1', '+2', 'mixed'));
+
+ALTER TABLE opinion_revisions ALTER COLUMN sentiment TYPE VARCHAR(5)
+    USING CASE WHEN sentiment THEN '+1' ELSE '-1' END;
+ALTER TABLE opinion_revisions ADD CONSTRAINT opinion_revisions_sentiment_check
+    CHECK (sentiment IN ('-2', '-1', '0', '+1', '+2', 'mixed'));
@@ -0,0 +1,23 @@
+-- Opinions about writers have no place in the older schema and are dropped
+CREATE OR REPLACE FUNCTION check_writer_not_author()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM works
+        WHERE id = NEW.work_id AND author_id = NEW.writer_id
+    ) THEN
+        RAISE EXCEPTION 'writer cannot express opinion about their own work';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DELETE FROM opinion_revisions WHERE target_writer_id IS NOT NULL;
+ALTER TABLE opinion_revisions DROP COLUMN target_writer_id;
+ALTER TABLE opinion_revisions ALTER COLUMN work_id SET NOT NULL;
+
+DELETE FROM opinions WHERE target_writer_id IS NOT NULL;
+DROP INDEX idx_opinions_target_writer_id;
+ALTER TABLE opinions DROP CONSTRAINT opinions_target_check;
+ALTER TABLE opinions DROP COLUMN target_writer_id;
+ALTER TABLE opinions ALTER COLUMN work_id SET NOT NULL;
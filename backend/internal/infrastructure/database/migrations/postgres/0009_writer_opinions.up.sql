@@ -0,0 +1,27 @@
+-- Opinions may be about a writer as a whole rather than one of their works.
+-- Every opinion has exactly one target.
+ALTER TABLE opinions ALTER COLUMN work_id DROP NOT NULL;
+ALTER TABLE opinions ADD COLUMN target_writer_id BIGINT;
+ALTER TABLE opinions ADD CONSTRAINT opinions_target_check
+    CHECK ((work_id IS NULL) <> (target_writer_id IS NULL));
+CREATE INDEX idx_opinions_target_writer_id ON opinions (target_writer_id);
+
+ALTER TABLE opinion_revisions ALTER COLUMN work_id DROP NOT NULL;
+ALTER TABLE opinion_revisions ADD COLUMN target_writer_id BIGINT;
+
+-- Nor can a writer express an opinion about themselves
+CREATE OR REPLACE FUNCTION check_writer_not_author()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM works
+        WHERE id = NEW.work_id AND author_id = NEW.writer_id
+    ) THEN
+        RAISE EXCEPTION 'writer cannot express opinion about their own work';
+    END IF;
+    IF NEW.target_writer_id = NEW.writer_id THEN
+        RAISE EXCEPTION 'writer cannot express opinion about themselves';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
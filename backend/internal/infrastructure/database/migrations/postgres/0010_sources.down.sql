@@ -0,0 +1,4 @@
+ALTER TABLE opinion_revisions DROP COLUMN source_id;
+DROP INDEX idx_opinions_source_id;
+ALTER TABLE opinions DROP COLUMN source_id;
+DROP TABLE sources;
@@ -0,0 +1,42 @@
+-- The older schema credits every work to exactly one author. Co-authors
+-- after the first are dropped, and so are anonymous works along with the
+-- opinions about them
+DELETE FROM opinion_revisions WHERE work_id IN (
+    SELECT id FROM works WHERE id NOT IN (SELECT work_id FROM work_authors)
+);
+DELETE FROM opinions WHERE work_id IN (
+    SELECT id FROM works WHERE id NOT IN (SELECT work_id FROM work_authors)
+);
+DELETE FROM works WHERE id NOT IN (SELECT work_id FROM work_authors);
+
+ALTER TABLE works ADD COLUMN author_id BIGINT;
+UPDATE works SET author_id = (
+    SELECT writer_id FROM work_authors
+    WHERE work_id = works.id
+    ORDER BY position
+    LIMIT 1
+);
+ALTER TABLE works ALTER COLUMN author_id SET NOT NULL;
+CREATE INDEX idx_works_author_id ON works (author_id);
+
+CREATE OR REPLACE FUNCTION check_writer_not_author()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM works
+        WHERE id = NEW.work_id AND author_id = NEW.writer_id
+    ) THEN
+        RAISE EXCEPTION 'writer cannot express opinion about their own work';
+    END IF;
+    IF NEW.target_writer_id = NEW.writer_id THEN
+        RAISE EXCEPTION 'writer cannot express opinion about themselves';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TABLE work_authors;
+ALTER TABLE works DROP COLUMN original_title;
+ALTER TABLE works DROP COLUMN original_language;
+ALTER TABLE works DROP COLUMN genre;
+ALTER TABLE works DROP COLUMN publication_year;
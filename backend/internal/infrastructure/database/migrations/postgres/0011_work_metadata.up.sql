@@ -0,0 +1,38 @@
+-- Works record where they come from, and are credited to an ordered list
+-- of authors: several for co-written works, none for anonymous ones
+ALTER TABLE works ADD COLUMN publication_year INTEGER;
+ALTER TABLE works ADD COLUMN genre VARCHAR(100);
+ALTER TABLE works ADD COLUMN original_language VARCHAR(64);
+ALTER TABLE works ADD COLUMN original_title VARCHAR(255);
+
+CREATE TABLE work_authors (
+    work_id   BIGINT NOT NULL,
+    writer_id BIGINT NOT NULL,
+    position  INTEGER NOT NULL,
+    PRIMARY KEY (work_id, writer_id),
+    UNIQUE (work_id, position)
+);
+CREATE INDEX idx_work_authors_writer_id ON work_authors (writer_id);
+
+INSERT INTO work_authors (work_id, writer_id, position)
+SELECT id, author_id, 1 FROM works;
+
+-- A writer cannot express an opinion about a work they wrote or co-wrote
+CREATE OR REPLACE FUNCTION check_writer_not_author()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM work_authors
+        WHERE work_id = NEW.work_id AND writer_id = NEW.writer_id
+    ) THEN
+        RAISE EXCEPTION 'writer cannot express opinion about their own work';
+    END IF;
+    IF NEW.target_writer_id = NEW.writer_id THEN
+        RAISE EXCEPTION 'writer cannot express opinion about themselves';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP INDEX idx_works_author_id;
+ALTER TABLE works DROP COLUMN author_id;
@@ -0,0 +1,11 @@
+-- Writers are also known by pen names, birth names, other transliterations
+-- and their names in the original script
+CREATE TABLE writer_aliases (
+    id        BIGSERIAL PRIMARY KEY,
+    writer_id BIGINT NOT NULL,
+    name      VARCHAR(255) NOT NULL,
+    kind      VARCHAR(32) NOT NULL
+              CHECK (kind IN ('pseudonym', 'birth_name', 'transliteration', 'native_script')),
+    UNIQUE (writer_id, name)
+);
+CREATE INDEX idx_writer_aliases_name_trgm ON writer_aliases USING gin (name gin_trgm_ops);
@@ -0,0 +1,15 @@
+-- Quotes keep the language they were written in, and may be translated
+-- into others. Revisions store their translations as a JSON array.
+ALTER TABLE opinions ADD COLUMN language VARCHAR(35);
+ALTER TABLE opinion_revisions ADD COLUMN language VARCHAR(35);
+ALTER TABLE opinion_revisions ADD COLUMN translations TEXT;
+
+CREATE TABLE opinion_translations (
+    opinion_id BIGINT NOT NULL,
+    language   VARCHAR(35) NOT NULL,
+    text       TEXT NOT NULL,
+    translator VARCHAR(255),
+    source     VARCHAR(255),
+    position   INTEGER NOT NULL,
+    PRIMARY KEY (opinion_id, language)
+);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_opinions_quote_search;
+ALTER TABLE opinions DROP COLUMN quote_search;
+DROP FUNCTION quote_search_query(TEXT);
+DROP FUNCTION quote_search_config(TEXT);
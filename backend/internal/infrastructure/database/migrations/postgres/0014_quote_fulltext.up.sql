@@ -0,0 +1,54 @@
+-- Quotes are searched with the text search configuration of their
+-- language, so that a search stems its words the way the quote's were
+-- stemmed. Languages without a configuration, and quotes without a
+-- language, are matched word for word.
+CREATE FUNCTION quote_search_config(language TEXT) RETURNS regconfig AS $$
+    SELECT CASE lower(split_part(language, '-', 1))
+        WHEN 'da' THEN 'pg_catalog.danish'
+        WHEN 'de' THEN 'pg_catalog.german'
+        WHEN 'en' THEN 'pg_catalog.english'
+        WHEN 'es' THEN 'pg_catalog.spanish'
+        WHEN 'fi' THEN 'pg_catalog.finnish'
+        WHEN 'fr' THEN 'pg_catalog.french'
+        WHEN 'hu' THEN 'pg_catalog.hungarian'
+        WHEN 'it' THEN 'pg_catalog.italian'
+        WHEN 'nb' THEN 'pg_catalog.norwegian'
+        WHEN 'nl' THEN 'pg_catalog.dutch'
+        WHEN 'nn' THEN 'pg_catalog.norwegian'
+        WHEN 'no' THEN 'pg_catalog.norwegian'
+        WHEN 'pt' THEN 'pg_catalog.portuguese'
+        WHEN 'ro' THEN 'pg_catalog.romanian'
+        WHEN 'ru' THEN 'pg_catalog.russian'
+        WHEN 'sv' THEN 'pg_catalog.swedish'
+        WHEN 'tr' THEN 'pg_catalog.turkish'
+        ELSE 'pg_catalog.simple'
+    END::regconfig
+$$ LANGUAGE SQL IMMUTABLE;
+
+-- quote_search_query parses a search in every configuration above and
+-- accepts a quote matching any of them. The index can answer it for all
+-- languages at once; each quote is then checked against the search parsed
+-- in its own language.
+CREATE FUNCTION quote_search_query(query TEXT) RETURNS tsquery AS $$
+    SELECT websearch_to_tsquery('pg_catalog.simple', query)
+        || websearch_to_tsquery('pg_catalog.danish', query)
+        || websearch_to_tsquery('pg_catalog.german', query)
+        || websearch_to_tsquery('pg_catalog.english', query)
+        || websearch_to_tsquery('pg_catalog.spanish', query)
+        || websearch_to_tsquery('pg_catalog.finnish', query)
+        || websearch_to_tsquery('pg_catalog.french', query)
+        || websearch_to_tsquery('pg_catalog.hungarian', query)
+        || websearch_to_tsquery('pg_catalog.italian', query)
+        || websearch_to_tsquery('pg_catalog.norwegian', query)
+        || websearch_to_tsquery('pg_catalog.dutch', query)
+        || websearch_to_tsquery('pg_catalog.portuguese', query)
+        || websearch_to_tsquery('pg_catalog.romanian', query)
+        || websearch_to_tsquery('pg_catalog.russian', query)
+        || websearch_to_tsquery('pg_catalog.swedish', query)
+        || websearch_to_tsquery('pg_catalog.turkish', query)
+$$ LANGUAGE SQL IMMUTABLE;
+
+ALTER TABLE opinions ADD COLUMN quote_search tsvector
+    GENERATED ALWAYS AS (to_tsvector(quote_search_config(language), quote)) STORED;
+
+CREATE INDEX idx_opinions_quote_search ON opinions USING gin (quote_search);
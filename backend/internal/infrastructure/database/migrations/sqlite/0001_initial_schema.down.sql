@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS opinions;
+DROP TABLE IF EXISTS works;
+DROP TABLE IF EXISTS writers;
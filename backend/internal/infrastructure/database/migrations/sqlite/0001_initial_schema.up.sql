@@ -0,0 +1,28 @@
+-- AUTOINCREMENT keeps IDs of deleted rows from being reused, like the
+-- sequences behind the PostgreSQL schema
+CREATE TABLE IF NOT EXISTS writers (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    name       VARCHAR(255) NOT NULL,
+    birth_year INTEGER NOT NULL,
+    death_year INTEGER,
+    bio        TEXT
+);
+
+CREATE TABLE IF NOT EXISTS works (
+    id        INTEGER PRIMARY KEY AUTOINCREMENT,
+    title     VARCHAR(255) NOT NULL,
+    author_id INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_works_author_id ON works (author_id);
+
+CREATE TABLE IF NOT EXISTS opinions (
+    writer_id      INTEGER NOT NULL,
+    work_id        INTEGER NOT NULL,
+    sentiment      BOOLEAN NOT NULL,
+    quote          TEXT NOT NULL,
+    source         VARCHAR(255) NOT NULL,
+    page           VARCHAR(100),
+    statement_year INTEGER,
+    PRIMARY KEY (writer_id, work_id)
+);
@@ -0,0 +1 @@
+-- Nothing to drop.
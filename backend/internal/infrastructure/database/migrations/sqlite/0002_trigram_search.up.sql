@@ -0,0 +1,2 @@
+-- similarity() is provided by the application when it opens the database.
+-- SQLite cannot index it, so there is nothing to create here.
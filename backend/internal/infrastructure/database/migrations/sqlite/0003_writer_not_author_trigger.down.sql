@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS trigger_check_writer_not_author_update;
+DROP TRIGGER IF EXISTS trigger_check_writer_not_author_insert;
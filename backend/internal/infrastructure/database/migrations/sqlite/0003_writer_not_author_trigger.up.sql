@@ -0,0 +1,16 @@
+-- A writer cannot express an opinion about their own work
+CREATE TRIGGER IF NOT EXISTS trigger_check_writer_not_author_insert
+BEFORE INSERT ON opinions
+FOR EACH ROW
+WHEN EXISTS (SELECT 1 FROM works WHERE id = NEW.work_id AND author_id = NEW.writer_id)
+BEGIN
+    SELECT RAISE(ABORT, 'writer cannot express opinion about their own work');
+END;
+
+CREATE TRIGGER IF NOT EXISTS trigger_check_writer_not_author_update
+BEFORE UPDATE ON opinions
+FOR EACH ROW
+WHEN EXISTS (SELECT 1 FROM works WHERE id = NEW.work_id AND author_id = NEW.writer_id)
+BEGIN
+    SELECT RAISE(ABORT, 'writer cannot express opinion about their own work');
+END;
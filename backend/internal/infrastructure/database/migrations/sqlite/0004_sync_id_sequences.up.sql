@@ -0,0 +1,2 @@
+-- SQLite derives new IDs from the largest one in use, so rows inserted
+-- with explicit IDs never leave it behind.
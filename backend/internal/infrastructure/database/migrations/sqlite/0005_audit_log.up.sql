@@ -0,0 +1,16 @@
+-- Append-only record of every change made through the services. Entity IDs
+-- are text so that opinions, keyed by writer and work, fit the same column.
+CREATE TABLE audit_log (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    entity_type VARCHAR(32) NOT NULL,
+    entity_id   VARCHAR(64) NOT NULL,
+    action      VARCHAR(16) NOT NULL,
+    before      TEXT,
+    after       TEXT,
+    actor       VARCHAR(255) NOT NULL,
+    request_id  VARCHAR(64) NOT NULL,
+    created_at  DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_audit_log_entity ON audit_log (entity_type, entity_id, created_at);
+CREATE INDEX idx_audit_log_created_at ON audit_log (created_at);
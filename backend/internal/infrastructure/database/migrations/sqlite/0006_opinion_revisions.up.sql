@@ -0,0 +1,21 @@
+-- Every state an opinion has been in, so that edits can be reviewed and
+-- undone. Existing opinions start with their current state as revision 1.
+CREATE TABLE opinion_revisions (
+    id             INTEGER PRIMARY KEY AUTOINCREMENT,
+    writer_id      INTEGER NOT NULL,
+    work_id        INTEGER NOT NULL,
+    revision       INTEGER NOT NULL,
+    sentiment      BOOLEAN NOT NULL,
+    quote          TEXT NOT NULL,
+    source         VARCHAR(255) NOT NULL,
+    page           VARCHAR(100),
+    statement_year INTEGER,
+    actor          VARCHAR(255) NOT NULL,
+    created_at     DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (writer_id, work_id, revision)
+);
+
+INSERT INTO opinion_revisions
+    (writer_id, work_id, revision, sentiment, quote, source, page, statement_year, actor)
+SELECT writer_id, work_id, 1, sentiment, quote, source, page, statement_year, 'system'
+FROM opinions;
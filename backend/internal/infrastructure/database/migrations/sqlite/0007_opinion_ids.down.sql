@@ -0,0 +1,76 @@
+-- Only one statement per writer and work can be kept: the earliest one.
+-- Revisions and audit entries of the others are dropped with them.
+UPDATE audit_log
+SET entity_id = (
+    SELECT o.writer_id || ':' || o.work_id FROM opinions o
+    WHERE audit_log.entity_id = CAST(o.id AS TEXT)
+)
+WHERE entity_type = 'opinion'
+  AND EXISTS (SELECT 1 FROM opinions o WHERE audit_log.entity_id = CAST(o.id AS TEXT));
+
+CREATE TABLE opinions_old AS SELECT * FROM opinions;
+DROP TABLE opinions;
+
+CREATE TABLE opinions (
+    writer_id      INTEGER NOT NULL,
+    work_id        INTEGER NOT NULL,
+    sentiment      BOOLEAN NOT NULL,
+    quote          TEXT NOT NULL,
+    source         VARCHAR(255) NOT NULL,
+    page           VARCHAR(100),
+    statement_year INTEGER,
+    PRIMARY KEY (writer_id, work_id)
+);
+
+INSERT INTO opinions (writer_id, work_id, sentiment, quote, source, page, statement_year)
+SELECT writer_id, work_id, sentiment, quote, source, page, statement_year
+FROM opinions_old o
+WHERE NOT EXISTS (
+    SELECT 1 FROM opinions_old earlier
+    WHERE earlier.writer_id = o.writer_id AND earlier.work_id = o.work_id AND earlier.id < o.id
+);
+DROP TABLE opinions_old;
+
+CREATE TRIGGER trigger_check_writer_not_author_insert
+BEFORE INSERT ON opinions
+FOR EACH ROW
+WHEN EXISTS (SELECT 1 FROM works WHERE id = NEW.work_id AND author_id = NEW.writer_id)
+BEGIN
+    SELECT RAISE(ABORT, 'writer cannot express opinion about their own work');
+END;
+
+CREATE TRIGGER trigger_check_writer_not_author_update
+BEFORE UPDATE ON opinions
+FOR EACH ROW
+WHEN EXISTS (SELECT 1 FROM works WHERE id = NEW.work_id AND author_id = NEW.writer_id)
+BEGIN
+    SELECT RAISE(ABORT, 'writer cannot express opinion about their own work');
+END;
+
+CREATE TABLE opinion_revisions_old AS SELECT * FROM opinion_revisions;
+DROP TABLE opinion_revisions;
+
+CREATE TABLE opinion_revisions (
+    id             INTEGER PRIMARY KEY AUTOINCREMENT,
+    writer_id      INTEGER NOT NULL,
+    work_id        INTEGER NOT NULL,
+    revision       INTEGER NOT NULL,
+    sentiment      BOOLEAN NOT NULL,
+    quote          TEXT NOT NULL,
+    source         VARCHAR(255) NOT NULL,
+    page           VARCHAR(100),
+    statement_year INTEGER,
+    actor          VARCHAR(255) NOT NULL,
+    created_at     DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (writer_id, work_id, revision)
+);
+
+INSERT INTO opinion_revisions
+    (id, writer_id, work_id, revision, sentiment, quote, source, page, statement_year, actor, created_at)
+SELECT id, writer_id, work_id, revision, sentiment, quote, source, page, statement_year, actor, created_at
+FROM opinion_revisions_old r
+WHERE NOT EXISTS (
+    SELECT 1 FROM opinion_revisions_old earlier
+    WHERE earlier.writer_id = r.writer_id AND earlier.work_id = r.work_id AND earlier.opinion_id < r.opinion_id
+);
+DROP TABLE opinion_revisions_old;
@@ -0,0 +1,96 @@
+-- Opinions get their own identity so that a writer can have several dated
+-- statements about the same work. SQLite cannot change a primary key, so
+-- the table is rebuilt, numbering existing rows in key order.
+CREATE TABLE opinions_old AS SELECT * FROM opinions;
+DROP TABLE opinions;
+
+CREATE TABLE opinions (
+    id             INTEGER PRIMARY KEY AUTOINCREMENT,
+    writer_id      INTEGER NOT NULL,
+    work_id        INTEGER NOT NULL,
+    sentiment      BOOLEAN NOT NULL,
+    quote          TEXT NOT NULL,
+    source         VARCHAR(255) NOT NULL,
+    page           VARCHAR(100),
+    statement_year INTEGER
+);
+
+INSERT INTO opinions (writer_id, work_id, sentiment, quote, source, page, statement_year)
+SELECT writer_id, work_id, sentiment, quote, source, page, statement_year
+FROM opinions_old
+ORDER BY writer_id, work_id;
+DROP TABLE opinions_old;
+
+CREATE INDEX idx_opinions_writer_work ON opinions (writer_id, work_id);
+CREATE INDEX idx_opinions_work_id ON opinions (work_id);
+
+-- Dropping the table dropped its triggers
+CREATE TRIGGER trigger_check_writer_not_author_insert
+BEFORE INSERT ON opinions
+FOR EACH ROW
+WHEN EXISTS (SELECT 1 FROM works WHERE id = NEW.work_id AND author_id = NEW.writer_id)
+BEGIN
+    SELECT RAISE(ABORT, 'writer cannot express opinion about their own work');
+END;
+
+CREATE TRIGGER trigger_check_writer_not_author_update
+BEFORE UPDATE ON opinions
+FOR EACH ROW
+WHEN EXISTS (SELECT 1 FROM works WHERE id = NEW.work_id AND author_id = NEW.writer_id)
+BEGIN
+    SELECT RAISE(ABORT, 'writer cannot express opinion about their own work');
+END;
+
+-- Revisions now belong to an opinion rather than to a writer and work.
+-- Revisions of deleted opinions are given IDs after the existing opinions
+-- so that they can still be restored.
+CREATE TABLE opinion_revisions_old AS SELECT * FROM opinion_revisions;
+DROP TABLE opinion_revisions;
+
+CREATE TABLE opinion_revisions (
+    id             INTEGER PRIMARY KEY AUTOINCREMENT,
+    opinion_id     INTEGER NOT NULL,
+    writer_id      INTEGER NOT NULL,
+    work_id        INTEGER NOT NULL,
+    revision       INTEGER NOT NULL,
+    sentiment      BOOLEAN NOT NULL,
+    quote          TEXT NOT NULL,
+    source         VARCHAR(255) NOT NULL,
+    page           VARCHAR(100),
+    statement_year INTEGER,
+    actor          VARCHAR(255) NOT NULL,
+    created_at     DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (opinion_id, revision)
+);
+
+INSERT INTO opinion_revisions
+    (id, opinion_id, writer_id, work_id, revision, sentiment, quote, source, page, statement_year, actor, created_at)
+SELECT r.id,
+       COALESCE(
+           o.id,
+           (SELECT COALESCE(MAX(id), 0) FROM opinions)
+               + DENSE_RANK() OVER (PARTITION BY o.id IS NULL ORDER BY r.writer_id, r.work_id)
+       ),
+       r.writer_id, r.work_id, r.revision, r.sentiment, r.quote, r.source, r.page, r.statement_year,
+       r.actor, r.created_at
+FROM opinion_revisions_old r
+LEFT JOIN opinions o ON o.writer_id = r.writer_id AND o.work_id = r.work_id;
+DROP TABLE opinion_revisions_old;
+
+-- Keep new opinions from reusing the IDs given to deleted ones
+INSERT INTO sqlite_sequence (name, seq)
+SELECT 'opinions', 0
+WHERE NOT EXISTS (SELECT 1 FROM sqlite_sequence WHERE name = 'opinions');
+UPDATE sqlite_sequence
+SET seq = MAX(seq, (SELECT COALESCE(MAX(opinion_id), 0) FROM opinion_revisions))
+WHERE name = 'opinions';
+
+-- Audit entries named opinions by writer and work; point them at the
+-- opinion instead where it still exists
+UPDATE audit_log
+SET entity_id = (
+    SELECT CAST(o.id AS TEXT) FROM opinions o
+    WHERE audit_log.entity_id = o.writer_id || ':' || o.work_id
+)
+WHERE entity_type = 'opinion'
+  AND EXISTS (SELECT 1 FROM opinions o WHERE audit_log.entity_id = o.writer_id || ':' || o.work_id);
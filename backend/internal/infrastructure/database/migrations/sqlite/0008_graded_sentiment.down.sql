@@ -0,0 +1,11 @@
+-- Only grades above the middle of the scale count as positive; neutral and
+-- mixed opinions become negative
+ALTER TABLE opinion_revisions RENAME COLUMN sentiment TO grade;
+ALTER TABLE opinion_revisions ADD COLUMN sentiment BOOLEAN NOT NULL DEFAULT FALSE;
+UPDATE opinion_revisions SET sentiment = grade IN ('+1', '+2');
+ALTER TABLE opinion_revisions DROP COLUMN grade;
+
+ALTER TABLE opinions RENAME COLUMN sentiment TO grade;
+ALTER TABLE opinions ADD COLUMN sentiment BOOLEAN NOT NULL DEFAULT FALSE;
+UPDATE opinions SET sentiment = grade IN ('+1', '+2');
+ALTER TABLE opinions DROP COLUMN grade;
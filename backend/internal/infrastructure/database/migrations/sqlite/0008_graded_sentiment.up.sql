@@ -0,0 +1,15 @@
+-- Sentiment moves from yes/no to a graded scale from -2 to +2, plus "mixed"
+-- for statements that praise and condemn at once. Existing opinions become
+-- +1 or -1. SQLite cannot change a column type, so the graded column
+-- replaces the boolean one.
+ALTER TABLE opinions RENAME COLUMN sentiment TO positive;
+ALTER TABLE opinions ADD COLUMN sentiment VARCHAR(5) NOT NULL DEFAULT '0'
+    CHECK (sentiment IN ('-2', '-1', '0', '+1', '+2', 'mixed'));
+UPDATE opinions SET sentiment = CASE WHEN positive THEN '+1' ELSE '-1' END;
+ALTER TABLE opinions DROP COLUMN positive;
+
+ALTER TABLE opinion_revisions RENAME COLUMN sentiment TO positive;
+ALTER TABLE opinion_revisions ADD COLUMN sentiment VARCHAR(5) NOT NULL DEFAULT '0'
+    CHECK (sentiment IN ('-2', '-1', '0', '+1', '+2', 'mixed'));
+UPDATE opinion_revisions SET sentiment = CASE WHEN positive THEN '+1' ELSE '-1' END;
+ALTER TABLE opinion_revisions DROP COLUMN positive;
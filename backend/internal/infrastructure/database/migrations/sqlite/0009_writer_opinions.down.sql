@@ -0,0 +1,70 @@
+-- Opinions about writers have no place in the older schema and are dropped
+CREATE TABLE opinions_old AS SELECT * FROM opinions WHERE target_writer_id IS NULL;
+DROP TABLE opinions;
+
+CREATE TABLE opinions (
+    id             INTEGER PRIMARY KEY AUTOINCREMENT,
+    writer_id      INTEGER NOT NULL,
+    work_id        INTEGER NOT NULL,
+    sentiment      VARCHAR(5) NOT NULL CHECK (sentiment IN ('-2', '-1', '0', '+1', '+2', 'mixed')),
+    quote          TEXT NOT NULL,
+    source         VARCHAR(255) NOT NULL,
+    page           VARCHAR(100),
+    statement_year INTEGER
+);
+
+INSERT INTO opinions (id, writer_id, work_id, sentiment, quote, source, page, statement_year)
+SELECT id, writer_id, work_id, sentiment, quote, source, page, statement_year
+FROM opinions_old;
+DROP TABLE opinions_old;
+
+CREATE INDEX idx_opinions_writer_work ON opinions (writer_id, work_id);
+CREATE INDEX idx_opinions_work_id ON opinions (work_id);
+
+CREATE TRIGGER trigger_check_writer_not_author_insert
+BEFORE INSERT ON opinions
+FOR EACH ROW
+WHEN EXISTS (SELECT 1 FROM works WHERE id = NEW.work_id AND author_id = NEW.writer_id)
+BEGIN
+    SELECT RAISE(ABORT, 'writer cannot express opinion about their own work');
+END;
+
+CREATE TRIGGER trigger_check_writer_not_author_update
+BEFORE UPDATE ON opinions
+FOR EACH ROW
+WHEN EXISTS (SELECT 1 FROM works WHERE id = NEW.work_id AND author_id = NEW.writer_id)
+BEGIN
+    SELECT RAISE(ABORT, 'writer cannot express opinion about their own work');
+END;
+
+CREATE TABLE opinion_revisions_old AS SELECT * FROM opinion_revisions WHERE target_writer_id IS NULL;
+DROP TABLE opinion_revisions;
+
+CREATE TABLE opinion_revisions (
+    id             INTEGER PRIMARY KEY AUTOINCREMENT,
+    opinion_id     INTEGER NOT NULL,
+    writer_id      INTEGER NOT NULL,
+    work_id        INTEGER NOT NULL,
+    revision       INTEGER NOT NULL,
+    sentiment      VARCHAR(5) NOT NULL CHECK (sentiment IN ('-2', '-1', '0', '+1', '+2', 'mixed')),
+    quote          TEXT NOT NULL,
+    source         VARCHAR(255) NOT NULL,
+    page           VARCHAR(100),
+    statement_year INTEGER,
+    actor          VARCHAR(255) NOT NULL,
+    created_at     DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (opinion_id, revision)
+);
+
+INSERT INTO opinion_revisions
+    (id, opinion_id, writer_id, work_id, revision, sentiment, quote, source, page, statement_year, actor, created_at)
+SELECT id, opinion_id, writer_id, work_id, revision, sentiment, quote, source, page, statement_year, actor, created_at
+FROM opinion_revisions_old;
+DROP TABLE opinion_revisions_old;
+
+INSERT INTO sqlite_sequence (name, seq)
+SELECT 'opinions', 0
+WHERE NOT EXISTS (SELECT 1 FROM sqlite_sequence WHERE name = 'opinions');
+UPDATE sqlite_sequence
+SET seq = MAX(seq, (SELECT COALESCE(MAX(opinion_id), 0) FROM opinion_revisions))
+WHERE name = 'opinions';
@@ -0,0 +1,97 @@
+-- Opinions may be about a writer as a whole rather than one of their works.
+-- Every opinion has exactly one target. SQLite cannot relax NOT NULL, so
+-- both opinion tables are rebuilt.
+CREATE TABLE opinions_old AS SELECT * FROM opinions;
+DROP TABLE opinions;
+
+CREATE TABLE opinions (
+    id               INTEGER PRIMARY KEY AUTOINCREMENT,
+    writer_id        INTEGER NOT NULL,
+    work_id          INTEGER,
+    target_writer_id INTEGER,
+    sentiment        VARCHAR(5) NOT NULL CHECK (sentiment IN ('-2', '-1', '0', '+1', '+2', 'mixed')),
+    quote            TEXT NOT NULL,
+    source           VARCHAR(255) NOT NULL,
+    page             VARCHAR(100),
+    statement_year   INTEGER,
+    CHECK ((work_id IS NULL) <> (target_writer_id IS NULL))
+);
+
+INSERT INTO opinions (id, writer_id, work_id, sentiment, quote, source, page, statement_year)
+SELECT id, writer_id, work_id, sentiment, quote, source, page, statement_year
+FROM opinions_old;
+DROP TABLE opinions_old;
+
+CREATE INDEX idx_opinions_writer_work ON opinions (writer_id, work_id);
+CREATE INDEX idx_opinions_work_id ON opinions (work_id);
+CREATE INDEX idx_opinions_target_writer_id ON opinions (target_writer_id);
+
+-- Dropping the table dropped its triggers
+CREATE TRIGGER trigger_check_writer_not_author_insert
+BEFORE INSERT ON opinions
+FOR EACH ROW
+WHEN EXISTS (SELECT 1 FROM works WHERE id = NEW.work_id AND author_id = NEW.writer_id)
+BEGIN
+    SELECT RAISE(ABORT, 'writer cannot express opinion about their own work');
+END;
+
+CREATE TRIGGER trigger_check_writer_not_author_update
+BEFORE UPDATE ON opinions
+FOR EACH ROW
+WHEN EXISTS (SELECT 1 FROM works WHERE id = NEW.work_id AND author_id = NEW.writer_id)
+BEGIN
+    SELECT RAISE(ABORT, 'writer cannot express opinion about their own work');
+END;
+
+-- Nor can a writer express an opinion about themselves
+CREATE TRIGGER trigger_check_writer_not_target_insert
+BEFORE INSERT ON opinions
+FOR EACH ROW
+WHEN NEW.target_writer_id = NEW.writer_id
+BEGIN
+    SELECT RAISE(ABORT, 'writer cannot express opinion about themselves');
+END;
+
+CREATE TRIGGER trigger_check_writer_not_target_update
+BEFORE UPDATE ON opinions
+FOR EACH ROW
+WHEN NEW.target_writer_id = NEW.writer_id
+BEGIN
+    SELECT RAISE(ABORT, 'writer cannot express opinion about themselves');
+END;
+
+CREATE TABLE opinion_revisions_old AS SELECT * FROM opinion_revisions;
+DROP TABLE opinion_revisions;
+
+CREATE TABLE opinion_revisions (
+    id               INTEGER PRIMARY KEY AUTOINCREMENT,
+    opinion_id       INTEGER NOT NULL,
+    writer_id        INTEGER NOT NULL,
+    work_id          INTEGER,
+    target_writer_id INTEGER,
+    revision         INTEGER NOT NULL,
+    sentiment        VARCHAR(5) NOT NULL CHECK (sentiment IN ('-2', '-1', '0', '+1', '+2', 'mixed')),
+    quote            TEXT NOT NULL,
+    source           VARCHAR(255) NOT NULL,
+    page             VARCHAR(100),
+    statement_year   INTEGER,
+    actor            VARCHAR(255) NOT NULL,
+    created_at       DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (opinion_id, revision)
+);
+
+INSERT INTO opinion_revisions
+    (id, opinion_id, writer_id, work_id, revision, sentiment, quote, source, page, statement_year, actor, created_at)
+SELECT id, opinion_id, writer_id, work_id, revision, sentiment, quote, source, page, statement_year, actor, created_at
+FROM opinion_revisions_old;
+DROP TABLE opinion_revisions_old;
+
+-- Dropping the tables reset their ID sequences, and copying the rows back
+-- only advanced them to the largest ID copied. Deleted opinions live on in
+-- their revisions and keep their IDs
+INSERT INTO sqlite_sequence (name, seq)
+SELECT 'opinions', 0
+WHERE NOT EXISTS (SELECT 1 FROM sqlite_sequence WHERE name = 'opinions');
+UPDATE sqlite_sequence
+SET seq = MAX(seq, (SELECT COALESCE(MAX(opinion_id), 0) FROM opinion_revisions))
+WHERE name = 'opinions';
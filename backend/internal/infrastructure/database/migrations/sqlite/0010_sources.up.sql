@@ -0,0 +1,33 @@
+-- Sources are catalogued once and referenced by opinions, instead of being
+-- retyped as free text for every quote
+CREATE TABLE sources (
+    id               INTEGER PRIMARY KEY AUTOINCREMENT,
+    type             VARCHAR(16) CHECK (type IN ('letter', 'diary', 'essay', 'interview', 'review')),
+    title            VARCHAR(255) NOT NULL,
+    author           VARCHAR(255),
+    publisher        VARCHAR(255),
+    year             INTEGER,
+    isbn             VARCHAR(32),
+    doi              VARCHAR(255),
+    url              TEXT,
+    archive_location TEXT,
+    confirmed        BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+ALTER TABLE opinions ADD COLUMN source_id INTEGER;
+CREATE INDEX idx_opinions_source_id ON opinions (source_id);
+ALTER TABLE opinion_revisions ADD COLUMN source_id INTEGER;
+
+-- Citations that differ only in case or surrounding spaces become one
+-- unconfirmed candidate, left for curators to type, complete and confirm
+INSERT INTO sources (title, confirmed)
+SELECT MIN(TRIM(source)), FALSE
+FROM opinions
+GROUP BY LOWER(TRIM(source))
+ORDER BY MIN(id);
+
+UPDATE opinions
+SET source_id = (SELECT s.id FROM sources s WHERE LOWER(s.title) = LOWER(TRIM(opinions.source)));
+
+UPDATE opinion_revisions
+SET source_id = (SELECT s.id FROM sources s WHERE LOWER(s.title) = LOWER(TRIM(opinion_revisions.source)));
@@ -0,0 +1,64 @@
+-- The older schema credits every work to exactly one author. Co-authors
+-- after the first are dropped, and so are anonymous works along with the
+-- opinions about them
+DELETE FROM opinion_revisions WHERE work_id IN (
+    SELECT id FROM works WHERE id NOT IN (SELECT work_id FROM work_authors)
+);
+DELETE FROM opinions WHERE work_id IN (
+    SELECT id FROM works WHERE id NOT IN (SELECT work_id FROM work_authors)
+);
+DELETE FROM works WHERE id NOT IN (SELECT work_id FROM work_authors);
+
+DROP TRIGGER trigger_check_writer_not_author_insert;
+DROP TRIGGER trigger_check_writer_not_author_update;
+
+-- SQLite cannot add a NOT NULL column without a default, so the table is
+-- rebuilt
+CREATE TABLE works_old AS SELECT * FROM works;
+CREATE TABLE works_seq AS SELECT seq FROM sqlite_sequence WHERE name = 'works';
+DROP TABLE works;
+
+CREATE TABLE works (
+    id        INTEGER PRIMARY KEY AUTOINCREMENT,
+    title     VARCHAR(255) NOT NULL,
+    author_id INTEGER NOT NULL
+);
+
+INSERT INTO works (id, title, author_id)
+SELECT id, title, (
+    SELECT writer_id FROM work_authors
+    WHERE work_id = works_old.id
+    ORDER BY position
+    LIMIT 1
+)
+FROM works_old;
+DROP TABLE works_old;
+
+CREATE INDEX idx_works_author_id ON works (author_id);
+DROP TABLE work_authors;
+
+CREATE TRIGGER trigger_check_writer_not_author_insert
+BEFORE INSERT ON opinions
+FOR EACH ROW
+WHEN EXISTS (SELECT 1 FROM works WHERE id = NEW.work_id AND author_id = NEW.writer_id)
+BEGIN
+    SELECT RAISE(ABORT, 'writer cannot express opinion about their own work');
+END;
+
+CREATE TRIGGER trigger_check_writer_not_author_update
+BEFORE UPDATE ON opinions
+FOR EACH ROW
+WHEN EXISTS (SELECT 1 FROM works WHERE id = NEW.work_id AND author_id = NEW.writer_id)
+BEGIN
+    SELECT RAISE(ABORT, 'writer cannot express opinion about their own work');
+END;
+
+-- Dropping the table reset its ID sequence, and copying the rows back only
+-- advanced it to the largest ID copied
+INSERT INTO sqlite_sequence (name, seq)
+SELECT 'works', 0
+WHERE NOT EXISTS (SELECT 1 FROM sqlite_sequence WHERE name = 'works');
+UPDATE sqlite_sequence
+SET seq = MAX(seq, (SELECT COALESCE(MAX(seq), 0) FROM works_seq))
+WHERE name = 'works';
+DROP TABLE works_seq;
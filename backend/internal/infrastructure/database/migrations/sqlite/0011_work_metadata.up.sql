@@ -0,0 +1,42 @@
+-- Works record where they come from, and are credited to an ordered list
+-- of authors: several for co-written works, none for anonymous ones
+ALTER TABLE works ADD COLUMN publication_year INTEGER;
+ALTER TABLE works ADD COLUMN genre VARCHAR(100);
+ALTER TABLE works ADD COLUMN original_language VARCHAR(64);
+ALTER TABLE works ADD COLUMN original_title VARCHAR(255);
+
+CREATE TABLE work_authors (
+    work_id   INTEGER NOT NULL,
+    writer_id INTEGER NOT NULL,
+    position  INTEGER NOT NULL,
+    PRIMARY KEY (work_id, writer_id),
+    UNIQUE (work_id, position)
+);
+CREATE INDEX idx_work_authors_writer_id ON work_authors (writer_id);
+
+INSERT INTO work_authors (work_id, writer_id, position)
+SELECT id, author_id, 1 FROM works;
+
+-- A writer cannot express an opinion about a work they wrote or co-wrote.
+-- The triggers refer to the column being dropped, so they go first.
+DROP TRIGGER trigger_check_writer_not_author_insert;
+DROP TRIGGER trigger_check_writer_not_author_update;
+
+DROP INDEX idx_works_author_id;
+ALTER TABLE works DROP COLUMN author_id;
+
+CREATE TRIGGER trigger_check_writer_not_author_insert
+BEFORE INSERT ON opinions
+FOR EACH ROW
+WHEN EXISTS (SELECT 1 FROM work_authors WHERE work_id = NEW.work_id AND writer_id = NEW.writer_id)
+BEGIN
+    SELECT RAISE(ABORT, 'writer cannot express opinion about their own work');
+END;
+
+CREATE TRIGGER trigger_check_writer_not_author_update
+BEFORE UPDATE ON opinions
+FOR EACH ROW
+WHEN EXISTS (SELECT 1 FROM work_authors WHERE work_id = NEW.work_id AND writer_id = NEW.writer_id)
+BEGIN
+    SELECT RAISE(ABORT, 'writer cannot express opinion about their own work');
+END;
@@ -0,0 +1,10 @@
+-- Writers are also known by pen names, birth names, other transliterations
+-- and their names in the original script
+CREATE TABLE writer_aliases (
+    id        INTEGER PRIMARY KEY AUTOINCREMENT,
+    writer_id INTEGER NOT NULL,
+    name      VARCHAR(255) NOT NULL,
+    kind      VARCHAR(32) NOT NULL
+              CHECK (kind IN ('pseudonym', 'birth_name', 'transliteration', 'native_script')),
+    UNIQUE (writer_id, name)
+);
@@ -0,0 +1,4 @@
+DROP TABLE opinion_translations;
+ALTER TABLE opinion_revisions DROP COLUMN translations;
+ALTER TABLE opinion_revisions DROP COLUMN language;
+ALTER TABLE opinions DROP COLUMN language;
@@ -0,0 +1,3 @@
+-- Quotes are searched with functions provided by the application when it
+-- opens the database. They match whole words without stemming, and SQLite
+-- cannot index them, so there is nothing to create here.
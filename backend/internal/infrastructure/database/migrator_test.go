@@ -0,0 +1,358 @@
+package database_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/what-writers-like/backend/internal/infrastructure/database"
+	"github.com/what-writers-like/backend/internal/testutils"
+)
+
+// forEachDatabase runs test against a freshly migrated database of every
+// supported dialect.
+func forEachDatabase(t *testing.T, test func(t *testing.T, db *database.Database)) {
+	t.Helper()
+
+	setups := map[string]func(t *testing.T) (*database.Database, func()){
+		"postgres": testutils.SetupTestDB,
+		"sqlite":   testutils.SetupSQLiteTestDB,
+	}
+	for name, setup := range setups {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			db, cleanup := setup(t)
+			defer cleanup()
+
+			test(t, db)
+		})
+	}
+}
+
+func TestMigrator_Up(t *testing.T) {
+	t.Parallel()
+	t.Run("already up to date", func(t *testing.T) {
+		t.Parallel()
+		forEachDatabase(t, func(t *testing.T, db *database.Database) {
+			migrator, err := database.NewMigrator(db.DB())
+			require.NoError(t, err)
+
+			applied, err := migrator.Up()
+			require.NoError(t, err)
+			assert.Empty(t, applied)
+			require.NoError(t, migrator.CheckSchema())
+		})
+	})
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		t.Parallel()
+		forEachDatabase(t, func(t *testing.T, db *database.Database) {
+			migrator, err := database.NewMigrator(db.DB())
+			require.NoError(t, err)
+
+			err = db.DB().Exec("UPDATE schema_migrations SET checksum = 'edited' WHERE version = 1").Error
+			require.NoError(t, err)
+
+			_, err = migrator.Up()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "checksum mismatch")
+		})
+	})
+}
+
+func TestMigrator_Down(t *testing.T) {
+	t.Parallel()
+	forEachDatabase(t, func(t *testing.T, db *database.Database) {
+		migrator, err := database.NewMigrator(db.DB())
+		require.NoError(t, err)
+
+		reverted, err := migrator.Down(1)
+		require.NoError(t, err)
+		require.Len(t, reverted, 1)
+		assert.Equal(t, migrator.LatestVersion(), reverted[0].Version)
+
+		err = migrator.CheckSchema()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "pending")
+
+		applied, err := migrator.Up()
+		require.NoError(t, err)
+		require.Len(t, applied, 1)
+		require.NoError(t, migrator.CheckSchema())
+
+		_, err = migrator.Down(0)
+		require.Error(t, err)
+	})
+}
+
+func TestMigrator_DownAll(t *testing.T) {
+	t.Parallel()
+	forEachDatabase(t, func(t *testing.T, db *database.Database) {
+		migrator, err := database.NewMigrator(db.DB())
+		require.NoError(t, err)
+
+		// Every down migration must undo its up migration cleanly
+		_, err = migrator.Down(migrator.LatestVersion())
+		require.NoError(t, err)
+		assert.False(t, db.DB().Migrator().HasTable(database.WritersTable))
+
+		_, err = migrator.Up()
+		require.NoError(t, err)
+		assert.True(t, db.DB().Migrator().HasTable(database.WritersTable))
+	})
+}
+
+func TestMigrator_Status(t *testing.T) {
+	t.Parallel()
+	forEachDatabase(t, func(t *testing.T, db *database.Database) {
+		migrator, err := database.NewMigrator(db.DB())
+		require.NoError(t, err)
+
+		statuses, err := migrator.Status()
+		require.NoError(t, err)
+		require.NotEmpty(t, statuses)
+		for _, s := range statuses {
+			assert.True(t, s.Applied, "migration %d", s.Version)
+			assert.NotNil(t, s.AppliedAt)
+		}
+		assert.Equal(t, migrator.LatestVersion(), statuses[len(statuses)-1].Version)
+	})
+}
+
+func TestMigrator_CheckSchema(t *testing.T) {
+	t.Parallel()
+	t.Run("newer schema", func(t *testing.T) {
+		t.Parallel()
+		forEachDatabase(t, func(t *testing.T, db *database.Database) {
+			migrator, err := database.NewMigrator(db.DB())
+			require.NoError(t, err)
+
+			// Simulate a migration applied by a newer release
+			err = db.DB().Exec(
+				"INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES (?, ?, ?, CURRENT_TIMESTAMP)",
+				migrator.LatestVersion()+1, "from_the_future", "unknown",
+			).Error
+			require.NoError(t, err)
+
+			err = migrator.CheckSchema()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "newer than this binary supports")
+
+			_, err = migrator.Up()
+			require.Error(t, err)
+		})
+	})
+}
+
+func TestMigrator_GradedSentiment(t *testing.T) {
+	t.Parallel()
+	forEachDatabase(t, func(t *testing.T, db *database.Database) {
+		migrator, err := database.NewMigrator(db.DB())
+		require.NoError(t, err)
+
+		// Go back to boolean sentiments and record one of each
+		_, err = migrator.Down(migrator.LatestVersion() - 7)
+		require.NoError(t, err)
+		require.NoError(t, db.DB().Exec(`
+			INSERT INTO writers (id, name, birth_year) VALUES (1, 'Jane Austen', 1775), (2, 'Charlotte Bronte', 1816)
+		`).Error)
+		require.NoError(t, db.DB().Exec(`INSERT INTO works (id, title, author_id) VALUES (1, 'Emma', 1)`).Error)
+		require.NoError(t, db.DB().Exec(`
+			INSERT INTO opinions (writer_id, work_id, sentiment, quote, source)
+			VALUES (2, 1, TRUE, 'Praise', 'Letters'), (2, 1, FALSE, 'Scorn', 'Letters')
+		`).Error)
+
+		_, err = migrator.Up()
+		require.NoError(t, err)
+
+		var grades []string
+		require.NoError(t, db.DB().Raw("SELECT sentiment FROM opinions ORDER BY id").Scan(&grades).Error)
+		assert.Equal(t, []string{"+1", "-1"}, grades)
+
+		err = db.DB().Exec("UPDATE opinions SET sentiment = 'great' WHERE id = 1").Error
+		require.Error(t, err)
+	})
+}
+
+func TestMigrator_WriterOpinions(t *testing.T) {
+	t.Parallel()
+	forEachDatabase(t, func(t *testing.T, db *database.Database) {
+		require.NoError(t, db.DB().Exec(`
+			INSERT INTO writers (id, name, birth_year) VALUES (1, 'Jane Austen', 1775), (2, 'Charlotte Bronte', 1816)
+		`).Error)
+		require.NoError(t, db.DB().Exec(`INSERT INTO works (id, title) VALUES (1, 'Emma')`).Error)
+		require.NoError(t, db.DB().Exec(`INSERT INTO work_authors (work_id, writer_id, position) VALUES (1, 1, 1)`).Error)
+		require.NoError(t, db.DB().Exec(`
+			INSERT INTO opinions (id, writer_id, work_id, target_writer_id, sentiment, quote, source)
+			VALUES (1, 2, 1, NULL, '+1', 'Praise', 'Letters'), (2, 2, NULL, 1, '-1', 'Scorn', 'Letters')
+		`).Error)
+
+		// An opinion has exactly one target, and it is never its holder
+		err := db.DB().Exec(`
+			INSERT INTO opinions (writer_id, work_id, target_writer_id, sentiment, quote, source)
+			VALUES (2, 1, 1, '+1', 'Both', 'Letters')
+		`).Error
+		require.Error(t, err)
+		err = db.DB().Exec(`
+			INSERT INTO opinions (writer_id, sentiment, quote, source) VALUES (2, '+1', 'Neither', 'Letters')
+		`).Error
+		require.Error(t, err)
+		err = db.DB().Exec(`
+			INSERT INTO opinions (writer_id, target_writer_id, sentiment, quote, source)
+			VALUES (2, 2, '+1', 'Myself', 'Letters')
+		`).Error
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "writer cannot express opinion about themselves")
+
+		// Going back drops the opinions the older schema cannot hold
+		migrator, err := database.NewMigrator(db.DB())
+		require.NoError(t, err)
+		_, err = migrator.Down(migrator.LatestVersion() - 8)
+		require.NoError(t, err)
+		var ids []uint64
+		require.NoError(t, db.DB().Raw("SELECT id FROM opinions ORDER BY id").Scan(&ids).Error)
+		assert.Equal(t, []uint64{1}, ids)
+
+		_, err = migrator.Up()
+		require.NoError(t, err)
+		require.NoError(t, migrator.CheckSchema())
+
+		// The own-work rule survives the round trip
+		err = db.DB().Exec(`
+			INSERT INTO opinions (writer_id, work_id, sentiment, quote, source) VALUES (1, 1, '+1', 'Mine', 'Letters')
+		`).Error
+		require.Error(t, err)
+	})
+}
+
+func TestMigrator_WriterOpinionsKeepsDeletedIDs(t *testing.T) {
+	t.Parallel()
+	forEachDatabase(t, func(t *testing.T, db *database.Database) {
+		migrator, err := database.NewMigrator(db.DB())
+		require.NoError(t, err)
+
+		// Before opinions about writers, delete the last opinion but keep its history
+		_, err = migrator.Down(migrator.LatestVersion() - 8)
+		require.NoError(t, err)
+		require.NoError(t, db.DB().Exec(`
+			INSERT INTO writers (id, name, birth_year) VALUES (1, 'Jane Austen', 1775), (2, 'Charlotte Bronte', 1816)
+		`).Error)
+		require.NoError(t, db.DB().Exec(`INSERT INTO works (id, title, author_id) VALUES (1, 'Emma', 1)`).Error)
+		require.NoError(t, db.DB().Exec(`
+			INSERT INTO opinions (writer_id, work_id, sentiment, quote, source)
+			VALUES (2, 1, '+1', 'First', 'Letters'), (2, 1, '-1', 'Second', 'Letters'), (2, 1, '0', 'Third', 'Letters')
+		`).Error)
+		require.NoError(t, db.DB().Exec(`
+			INSERT INTO opinion_revisions (opinion_id, writer_id, work_id, revision, sentiment, quote, source, actor)
+			VALUES (3, 2, 1, 1, '0', 'Third', 'Letters', 'alice')
+		`).Error)
+		require.NoError(t, db.DB().Exec("DELETE FROM opinions WHERE id = 3").Error)
+
+		_, err = migrator.Up()
+		require.NoError(t, err)
+
+		if db.DB().Dialector.Name() == database.DialectSQLite {
+			var rows int
+			require.NoError(t, db.DB().Raw("SELECT COUNT(*) FROM sqlite_sequence WHERE name = 'opinions'").
+				Scan(&rows).Error)
+			assert.Equal(t, 1, rows)
+		}
+
+		// A new opinion does not take the ID of the deleted one
+		var id uint64
+		require.NoError(t, db.DB().Raw(`
+			INSERT INTO opinions (writer_id, work_id, sentiment, quote, source)
+			VALUES (2, 1, '+1', 'Fourth', 'Letters') RETURNING id
+		`).Scan(&id).Error)
+		assert.Equal(t, uint64(4), id)
+	})
+}
+
+func TestMigrator_SourceCandidates(t *testing.T) {
+	t.Parallel()
+	forEachDatabase(t, func(t *testing.T, db *database.Database) {
+		migrator, err := database.NewMigrator(db.DB())
+		require.NoError(t, err)
+
+		// Go back to free-text sources, spelled inconsistently
+		_, err = migrator.Down(migrator.LatestVersion() - 9)
+		require.NoError(t, err)
+		require.NoError(t, db.DB().Exec(`
+			INSERT INTO writers (id, name, birth_year) VALUES (1, 'Jane Austen', 1775), (2, 'Charlotte Bronte', 1816)
+		`).Error)
+		require.NoError(t, db.DB().Exec(`INSERT INTO works (id, title, author_id) VALUES (1, 'Emma', 1)`).Error)
+		require.NoError(t, db.DB().Exec(`
+			INSERT INTO opinions (writer_id, work_id, sentiment, quote, source)
+			VALUES (2, 1, '+1', 'One', 'Letters'), (2, 1, '-1', 'Two', ' letters '), (2, 1, '0', 'Three', 'Diary')
+		`).Error)
+
+		_, err = migrator.Up()
+		require.NoError(t, err)
+
+		var sources []database.SourceModel
+		require.NoError(t, db.DB().Order("id").Find(&sources).Error)
+		require.Len(t, sources, 2)
+		assert.Equal(t, "Letters", sources[0].Title)
+		assert.Equal(t, "Diary", sources[1].Title)
+		for _, s := range sources {
+			assert.False(t, s.Confirmed)
+			assert.Nil(t, s.Type)
+		}
+
+		var sourceIDs []uint64
+		require.NoError(t, db.DB().Raw("SELECT source_id FROM opinions ORDER BY id").Scan(&sourceIDs).Error)
+		assert.Equal(t, []uint64{sources[0].ID, sources[0].ID, sources[1].ID}, sourceIDs)
+	})
+}
+
+func TestMigrator_WorkMetadata(t *testing.T) {
+	t.Parallel()
+	forEachDatabase(t, func(t *testing.T, db *database.Database) {
+		migrator, err := database.NewMigrator(db.DB())
+		require.NoError(t, err)
+
+		// Works keep their single author through the upgrade
+		_, err = migrator.Down(migrator.LatestVersion() - 10)
+		require.NoError(t, err)
+		require.NoError(t, db.DB().Exec(`
+			INSERT INTO writers (id, name, birth_year) VALUES (1, 'Jane Austen', 1775), (2, 'Charlotte Bronte', 1816)
+		`).Error)
+		require.NoError(t, db.DB().Exec(`INSERT INTO works (id, title, author_id) VALUES (1, 'Emma', 1)`).Error)
+
+		_, err = migrator.Up()
+		require.NoError(t, err)
+
+		var authors []database.WorkAuthorModel
+		require.NoError(t, db.DB().Find(&authors).Error)
+		assert.Equal(t, []database.WorkAuthorModel{{WorkID: 1, WriterID: 1, Position: 1}}, authors)
+
+		// Co-authors are reduced to the first credited one, anonymous works
+		// and the opinions about them cannot be kept
+		require.NoError(t, db.DB().Exec(`INSERT INTO works (id, title) VALUES (2, 'A Joint Novel'), (3, 'Beowulf')`).Error)
+		require.NoError(t, db.DB().Exec(`
+			INSERT INTO work_authors (work_id, writer_id, position) VALUES (2, 2, 1), (2, 1, 2)
+		`).Error)
+		require.NoError(t, db.DB().Exec(`
+			INSERT INTO opinions (writer_id, work_id, sentiment, quote, source)
+			VALUES (1, 3, '+1', 'Old', 'Letters'), (2, 1, '+1', 'Fine', 'Letters')
+		`).Error)
+
+		_, err = migrator.Down(migrator.LatestVersion() - 10)
+		require.NoError(t, err)
+
+		var rows []struct {
+			ID       uint64
+			AuthorID uint64
+		}
+		require.NoError(t, db.DB().Raw("SELECT id, author_id FROM works ORDER BY id").Scan(&rows).Error)
+		require.Len(t, rows, 2)
+		assert.Equal(t, uint64(1), rows[0].AuthorID)
+		assert.Equal(t, uint64(2), rows[1].AuthorID)
+		var opinions int64
+		require.NoError(t, db.DB().Raw("SELECT COUNT(*) FROM opinions").Scan(&opinions).Error)
+		assert.Equal(t, int64(1), opinions)
+
+		_, err = migrator.Up()
+		require.NoError(t, err)
+	})
+}
@@ -1,11 +1,21 @@
 package database
 
-import (
-	"gorm.io/gorm"
+import "time"
+
+const (
+	WritersTable             = "writers"
+	WorksTable               = "works"
+	OpinionsTable            = "opinions"
+	AuditLogTable            = "audit_log"
+	OpinionRevisionsTable    = "opinion_revisions"
+	SourcesTable             = "sources"
+	WorkAuthorsTable         = "work_authors"
+	WriterAliasesTable       = "writer_aliases"
+	OpinionTranslationsTable = "opinion_translations"
 )
 
 type WriterModel struct {
-	ID        uint64 `gorm:"primaryKey"`
+	ID        uint64 `gorm:"primaryKey;autoIncrement"`
 	Name      string `gorm:"type:varchar(255);not null"`
 	BirthYear int    `gorm:"not null"`
 	DeathYear *int
@@ -13,37 +23,135 @@ type WriterModel struct {
 }
 
 func (WriterModel) TableName() string {
-	return "writers"
+	return WritersTable
+}
+
+type WriterAliasModel struct {
+	ID       uint64 `gorm:"primaryKey;autoIncrement"`
+	WriterID uint64 `gorm:"not null;uniqueIndex:idx_writer_alias"`
+	Name     string `gorm:"type:varchar(255);not null;uniqueIndex:idx_writer_alias"`
+	Kind     string `gorm:"type:varchar(32);not null"`
+}
+
+func (WriterAliasModel) TableName() string {
+	return WriterAliasesTable
 }
 
 type WorkModel struct {
-	ID       uint64 `gorm:"primaryKey"`
-	Title    string `gorm:"type:varchar(255);not null"`
-	AuthorID uint64 `gorm:"not null;index"`
+	ID               uint64 `gorm:"primaryKey;autoIncrement"`
+	Title            string `gorm:"type:varchar(255);not null"`
+	PublicationYear  *int
+	Genre            *string `gorm:"type:varchar(100)"`
+	OriginalLanguage *string `gorm:"type:varchar(64)"`
+	OriginalTitle    *string `gorm:"type:varchar(255)"`
 }
 
 func (WorkModel) TableName() string {
-	return "works"
+	return WorksTable
+}
+
+// WorkAuthorModel credits a writer as an author of a work. Position orders
+// the authors of a work from 1.
+type WorkAuthorModel struct {
+	WorkID   uint64 `gorm:"primaryKey"`
+	WriterID uint64 `gorm:"primaryKey;index"`
+	Position int    `gorm:"not null"`
+}
+
+func (WorkAuthorModel) TableName() string {
+	return WorkAuthorsTable
 }
 
+// OpinionModel has exactly one of WorkID and TargetWriterID set.
 type OpinionModel struct {
-	WriterID      uint64  `gorm:"primaryKey"`
-	WorkID        uint64  `gorm:"primaryKey"`
-	Sentiment     bool    `gorm:"not null"`
-	Quote         string  `gorm:"type:text;not null"`
-	Source        string  `gorm:"type:varchar(255);not null"`
-	Page          *string `gorm:"type:varchar(100)"`
-	StatementYear *int
+	ID             uint64  `gorm:"primaryKey;autoIncrement"`
+	WriterID       uint64  `gorm:"not null;index"`
+	WorkID         *uint64 `gorm:"index"`
+	TargetWriterID *uint64 `gorm:"index"`
+	Sentiment      string  `gorm:"type:varchar(5);not null"`
+	Quote          string  `gorm:"type:text;not null"`
+	Source         string  `gorm:"type:varchar(255);not null"`
+	SourceID       *uint64 `gorm:"index"`
+	Page           *string `gorm:"type:varchar(100)"`
+	StatementYear  *int
+	Language       *string `gorm:"type:varchar(35)"`
 }
 
 func (OpinionModel) TableName() string {
-	return "opinions"
+	return OpinionsTable
+}
+
+// OpinionTranslationModel is a translation of an opinion's quote. Position
+// orders the translations of an opinion from 1.
+type OpinionTranslationModel struct {
+	OpinionID  uint64  `gorm:"primaryKey"`
+	Language   string  `gorm:"primaryKey;type:varchar(35)"`
+	Text       string  `gorm:"type:text;not null"`
+	Translator *string `gorm:"type:varchar(255)"`
+	Source     *string `gorm:"type:varchar(255)"`
+	Position   int     `gorm:"not null"`
+}
+
+func (OpinionTranslationModel) TableName() string {
+	return OpinionTranslationsTable
+}
+
+type AuditEntryModel struct {
+	ID         uint64  `gorm:"primaryKey;autoIncrement"`
+	EntityType string  `gorm:"type:varchar(32);not null"`
+	EntityID   string  `gorm:"type:varchar(64);not null"`
+	Action     string  `gorm:"type:varchar(16);not null"`
+	Before     *string `gorm:"type:jsonb"`
+	After      *string `gorm:"type:jsonb"`
+	Actor      string  `gorm:"type:varchar(255);not null"`
+	RequestID  string  `gorm:"type:varchar(64);not null"`
+	CreatedAt  time.Time
+}
+
+func (AuditEntryModel) TableName() string {
+	return AuditLogTable
+}
+
+// OpinionRevisionModel keeps the translations of the revised opinion as a
+// JSON array.
+type OpinionRevisionModel struct {
+	ID             uint64 `gorm:"primaryKey;autoIncrement"`
+	OpinionID      uint64 `gorm:"not null"`
+	WriterID       uint64 `gorm:"not null"`
+	WorkID         *uint64
+	TargetWriterID *uint64
+	Revision       int    `gorm:"not null"`
+	Sentiment      string `gorm:"type:varchar(5);not null"`
+	Quote          string `gorm:"type:text;not null"`
+	Source         string `gorm:"type:varchar(255);not null"`
+	SourceID       *uint64
+	Page           *string `gorm:"type:varchar(100)"`
+	StatementYear  *int
+	Language       *string `gorm:"type:varchar(35)"`
+	Translations   *string `gorm:"type:text"`
+	Actor          string  `gorm:"type:varchar(255);not null"`
+	CreatedAt      time.Time
+}
+
+func (OpinionRevisionModel) TableName() string {
+	return OpinionRevisionsTable
+}
+
+// SourceModel leaves Type NULL for candidates whose type is not known yet.
+type SourceModel struct {
+	ID              uint64  `gorm:"primaryKey;autoIncrement"`
+	Type            *string `gorm:"type:varchar(16)"`
+	Title           string  `gorm:"type:varchar(255);not null"`
+	Author          *string `gorm:"type:varchar(255)"`
+	Publisher       *string `gorm:"type:varchar(255)"`
+	Year            *int
+	ISBN            *string `gorm:"column:isbn;type:varchar(32)"`
+	DOI             *string `gorm:"column:doi;type:varchar(255)"`
+	URL             *string `gorm:"column:url;type:text"`
+	ArchiveLocation *string `gorm:"type:text"`
+	Confirmed       bool    `gorm:"not null"`
 }
 
-func AutoMigrate(db *gorm.DB) error {
-	return db.AutoMigrate(
-		&WriterModel{},
-		&WorkModel{},
-		&OpinionModel{},
-	)
+func (SourceModel) TableName() string {
+	return SourcesTable
 }
@@ -0,0 +1,128 @@
+package database
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"sync"
+
+	"github.com/glebarez/go-sqlite"
+	gormsqlite "github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/what-writers-like/backend/internal/infrastructure/fulltext"
+	"github.com/what-writers-like/backend/internal/infrastructure/trigram"
+)
+
+// SQLite functions are registered with the driver once per process and are
+// then available on every connection.
+//
+//nolint:gochecknoglobals // the driver keeps a process-wide function registry
+var registerSQLiteFunctionsOnce sync.Once
+
+func openSQLite(dsn string) (*gorm.DB, error) {
+	var registerErr error
+	registerSQLiteFunctionsOnce.Do(func() {
+		registerErr = registerSQLiteFunctions()
+	})
+	if registerErr != nil {
+		return nil, fmt.Errorf("failed to register sqlite functions: %w", registerErr)
+	}
+
+	db, err := gorm.Open(gormsqlite.Open(dsn), &gorm.Config{})
+	if err != nil {
+		return nil, err
+	}
+
+	// SQLite allows a single writer at a time; sharing one connection
+	// queues writes instead of failing them with SQLITE_BUSY
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	return db, nil
+}
+
+// registerSQLiteFunctions provides the PostgreSQL functions the search
+// queries rely on, so that the same SQL runs on both databases.
+func registerSQLiteFunctions() error {
+	// similarity(text, text) from pg_trgm; NULL in, NULL out
+	err := sqlite.RegisterDeterministicScalarFunction("similarity", 2,
+		func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+			a, okA := args[0].(string)
+			b, okB := args[1].(string)
+			if !okA || !okB {
+				return nil, nil
+			}
+			return trigram.Similarity(a, b), nil
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	// strict_word_similarity(text, text) from pg_trgm; NULL in, NULL out
+	err = sqlite.RegisterDeterministicScalarFunction("strict_word_similarity", 2,
+		func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+			a, okA := args[0].(string)
+			b, okB := args[1].(string)
+			if !okA || !okB {
+				return nil, nil
+			}
+			return trigram.StrictWordSimilarity(a, b), nil
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	// Quote search has no SQLite equivalent of PostgreSQL's full-text
+	// search, so its SQLite query calls these instead. Each takes the
+	// search and a quote; NULL in, NULL out
+	quoteSearchFunctions := map[string]func(q fulltext.Query, quote string) driver.Value{
+		"quote_search_match":    func(q fulltext.Query, quote string) driver.Value { return q.Match(quote) },
+		"quote_search_rank":     func(q fulltext.Query, quote string) driver.Value { return q.Rank(quote) },
+		"quote_search_headline": func(q fulltext.Query, quote string) driver.Value { return q.Headline(quote) },
+	}
+	for name, fn := range quoteSearchFunctions {
+		err = sqlite.RegisterDeterministicScalarFunction(name, 2,
+			func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+				query, okQuery := args[0].(string)
+				quote, okQuote := args[1].(string)
+				if !okQuery || !okQuote {
+					return nil, nil
+				}
+				return fn(fulltext.Parse(query), quote), nil
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	// GREATEST(...) returns the largest non-NULL argument
+	return sqlite.RegisterDeterministicScalarFunction("greatest", -1,
+		func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+			var greatest driver.Value
+			var greatestNum float64
+			for _, arg := range args {
+				var num float64
+				switch v := arg.(type) {
+				case int64:
+					num = float64(v)
+				case float64:
+					num = v
+				case nil:
+					continue
+				default:
+					return nil, fmt.Errorf("greatest: unsupported argument %T", arg)
+				}
+				if greatest == nil || num > greatestNum {
+					greatest, greatestNum = arg, num
+				}
+			}
+			return greatest, nil
+		},
+	)
+}
@@ -0,0 +1,314 @@
+// Package fulltext matches web search style queries against text where
+// PostgreSQL's full-text search is not available. It follows the syntax of
+// websearch_to_tsquery() and the output of ts_headline(), but compares
+// whole words without stemming or stop words, like the simple
+// configuration.
+package fulltext
+
+import (
+	"strings"
+	"unicode"
+)
+
+// StartSel and StopSel enclose the matched words in a headline. Control
+// characters cannot occur in stored text, so the headline needs no
+// escaping; ts_headline() is given the same delimiters.
+const (
+	StartSel = "\x02"
+	StopSel  = "\x03"
+)
+
+// Headlines show up to maxWords words of a longer text, starting a few
+// words before the first match, like ts_headline()'s MaxWords option.
+const (
+	maxWords     = 35
+	leadingWords = 5
+)
+
+const (
+	orKeyword    = "or"
+	negationSign = '-'
+	phraseQuote  = '"'
+)
+
+// Query is a parsed search: every clause must be satisfied, and a clause
+// is satisfied by any one of its terms.
+type Query struct {
+	clauses [][]term
+}
+
+// term is a word, or a phrase of consecutive words, that must occur in the
+// text, or must not when negated.
+type term struct {
+	words   []string
+	negated bool
+}
+
+// Fragment is a run of a headline; Match marks the words the query matched.
+type Fragment struct {
+	Text  string
+	Match bool
+}
+
+// Parse reads a query the way websearch_to_tsquery() does: unquoted words
+// must all occur, "quoted text" must occur as a phrase, or between two
+// terms lets either match, and a leading - excludes texts containing the
+// term. It never fails; anything it cannot use is ignored.
+func Parse(s string) Query {
+	var q Query
+	pendingOr := false
+	add := func(t term) {
+		if pendingOr && len(q.clauses) > 0 {
+			last := len(q.clauses) - 1
+			q.clauses[last] = append(q.clauses[last], t)
+		} else {
+			q.clauses = append(q.clauses, []term{t})
+		}
+		pendingOr = false
+	}
+
+	for rest := strings.TrimSpace(s); rest != ""; rest = strings.TrimLeftFunc(rest, unicode.IsSpace) {
+		negated := false
+		if rest[0] == negationSign {
+			negated = true
+			rest = rest[1:]
+		}
+
+		var chunk string
+		if rest != "" && rest[0] == phraseQuote {
+			end := strings.IndexByte(rest[1:], phraseQuote)
+			if end < 0 {
+				chunk, rest = rest[1:], ""
+			} else {
+				chunk, rest = rest[1:end+1], rest[end+2:]
+			}
+		} else {
+			end := strings.IndexFunc(rest, func(r rune) bool { return unicode.IsSpace(r) || r == phraseQuote })
+			if end < 0 {
+				end = len(rest)
+			}
+			chunk, rest = rest[:end], rest[end:]
+			if !negated && strings.EqualFold(chunk, orKeyword) {
+				pendingOr = len(q.clauses) > 0
+				continue
+			}
+		}
+
+		var ws []string
+		for _, span := range words(chunk) {
+			ws = append(ws, strings.ToLower(chunk[span[0]:span[1]]))
+		}
+		if len(ws) > 0 {
+			add(term{words: ws, negated: negated})
+		}
+	}
+	return q
+}
+
+// IsEmpty reports a query with no words to look for, which matches nothing.
+func (q Query) IsEmpty() bool {
+	return len(q.clauses) == 0
+}
+
+// Match reports whether text satisfies the query.
+func (q Query) Match(text string) bool {
+	if q.IsEmpty() {
+		return false
+	}
+	doc := newDocument(text)
+	for _, clause := range q.clauses {
+		satisfied := false
+		for _, t := range clause {
+			if found := len(doc.find(t)) > 0; found != t.negated {
+				satisfied = true
+				break
+			}
+		}
+		if !satisfied {
+			return false
+		}
+	}
+	return true
+}
+
+// Rank grows from 0 towards 1 with the number of times the query's terms
+// occur in text, like ts_rank_cd() normalized by rank / (rank + 1).
+func (q Query) Rank(text string) float64 {
+	doc := newDocument(text)
+	occurrences := 0
+	for _, clause := range q.clauses {
+		for _, t := range clause {
+			if !t.negated {
+				occurrences += len(doc.find(t))
+			}
+		}
+	}
+	return float64(occurrences) / float64(occurrences+1)
+}
+
+// Snippet returns the part of text around the first match, split into
+// fragments with the matched words marked. A short text is returned whole.
+func (q Query) Snippet(text string) []Fragment {
+	doc := newDocument(text)
+	var matches [][2]int
+	for _, clause := range q.clauses {
+		for _, t := range clause {
+			if !t.negated {
+				matches = append(matches, doc.find(t)...)
+			}
+		}
+	}
+
+	// matches holds word indexes; pick the window of words to show
+	first, last := 0, len(doc.spans)
+	if len(doc.spans) > maxWords {
+		start := len(doc.spans)
+		for _, m := range matches {
+			start = min(start, m[0])
+		}
+		if start == len(doc.spans) {
+			start = 0
+		}
+		first = max(0, min(start-leadingWords, len(doc.spans)-maxWords))
+		last = first + maxWords
+	}
+	if first == last {
+		return nil
+	}
+
+	marked := make([]bool, len(doc.spans))
+	for _, m := range matches {
+		for i := m[0]; i < m[1]; i++ {
+			marked[i] = true
+		}
+	}
+
+	var fragments []Fragment
+	emit := func(from, to int, match bool) {
+		if from >= to {
+			return
+		}
+		if n := len(fragments); n > 0 && fragments[n-1].Match == match {
+			fragments[n-1].Text += text[from:to]
+			return
+		}
+		fragments = append(fragments, Fragment{Text: text[from:to], Match: match})
+	}
+	// A short text keeps whatever surrounds its words; a window starts and
+	// ends on a word
+	pos := doc.spans[first][0]
+	if last == len(doc.spans) && first == 0 {
+		pos = 0
+	}
+	for i := first; i < last; i++ {
+		span := doc.spans[i]
+		// Spaces between two matched words belong to the match
+		gap := text[pos:span[0]]
+		emit(pos, span[0], marked[i] && i > first && marked[i-1] && strings.TrimSpace(gap) == "")
+		emit(span[0], span[1], marked[i])
+		pos = span[1]
+	}
+	if last == len(doc.spans) && first == 0 {
+		emit(pos, len(text), false)
+	}
+	return fragments
+}
+
+// Headline returns Snippet as a single string with the matched words
+// enclosed in StartSel and StopSel, as ts_headline() does.
+func (q Query) Headline(text string) string {
+	var b strings.Builder
+	for _, f := range q.Snippet(text) {
+		if f.Match {
+			b.WriteString(StartSel + f.Text + StopSel)
+		} else {
+			b.WriteString(f.Text)
+		}
+	}
+	return b.String()
+}
+
+// SplitHeadline splits a headline from Headline or ts_headline() into
+// fragments.
+func SplitHeadline(headline string) []Fragment {
+	var fragments []Fragment
+	for headline != "" {
+		start := strings.Index(headline, StartSel)
+		if start < 0 {
+			fragments = append(fragments, Fragment{Text: headline})
+			break
+		}
+		if start > 0 {
+			fragments = append(fragments, Fragment{Text: headline[:start]})
+		}
+		headline = headline[start+len(StartSel):]
+		stop := strings.Index(headline, StopSel)
+		if stop < 0 {
+			stop = len(headline)
+		}
+		// ts_headline() marks each word of a phrase on its own
+		if n := len(fragments); n > 0 && fragments[n-1].Match {
+			fragments[n-1].Text += headline[:stop]
+		} else if n > 1 && fragments[n-2].Match && strings.TrimSpace(fragments[n-1].Text) == "" {
+			fragments[n-2].Text += fragments[n-1].Text + headline[:stop]
+			fragments = fragments[:n-1]
+		} else {
+			fragments = append(fragments, Fragment{Text: headline[:stop], Match: true})
+		}
+		headline = strings.TrimPrefix(headline[stop:], StopSel)
+	}
+	return fragments
+}
+
+// document is a text split into lowercased words.
+type document struct {
+	spans [][2]int
+	words []string
+}
+
+func newDocument(text string) document {
+	spans := words(text)
+	ws := make([]string, len(spans))
+	for i, span := range spans {
+		ws[i] = strings.ToLower(text[span[0]:span[1]])
+	}
+	return document{spans: spans, words: ws}
+}
+
+// find returns the word index ranges where t occurs.
+func (d document) find(t term) [][2]int {
+	var found [][2]int
+	for i := 0; i+len(t.words) <= len(d.words); i++ {
+		matched := true
+		for j, w := range t.words {
+			if d.words[i+j] != w {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			found = append(found, [2]int{i, i + len(t.words)})
+		}
+	}
+	return found
+}
+
+// words returns the byte offsets of the alphanumeric words in s.
+func words(s string) [][2]int {
+	var spans [][2]int
+	start := -1
+	for i, r := range s {
+		inWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case inWord && start < 0:
+			start = i
+		case !inWord && start >= 0:
+			spans = append(spans, [2]int{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, [2]int{start, len(s)})
+	}
+	return spans
+}
@@ -0,0 +1,80 @@
+package fulltext_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/what-writers-like/backend/internal/infrastructure/fulltext"
+)
+
+func TestQuery_Match(t *testing.T) {
+	t.Parallel()
+	text := "Dostoevsky is vulgar, vulgar beyond words."
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"VULGAR", true},
+		{"vulgar words", true},
+		{"vulgar tedious", false},
+		{"tedious or vulgar", true},
+		{"tedious OR dull", false},
+		{`"beyond words"`, true},
+		{`"words beyond"`, false},
+		{"vulgar -dostoevsky", false},
+		{"vulgar -tolstoy", true},
+		{`-"beyond words"`, false},
+		{"vulg", false},
+		{"", false},
+		{"or", false},
+		{"!!!", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, fulltext.Parse(tt.query).Match(text), "query %q", tt.query)
+	}
+}
+
+func TestQuery_Rank(t *testing.T) {
+	t.Parallel()
+	q := fulltext.Parse("vulgar")
+	assert.InDelta(t, 0.0, q.Rank("Tedious"), 0.0001)
+	assert.InDelta(t, 0.5, q.Rank("Vulgar"), 0.0001)
+	assert.Greater(t, q.Rank("Vulgar, vulgar"), q.Rank("Vulgar"))
+}
+
+func TestQuery_Snippet(t *testing.T) {
+	t.Parallel()
+	q := fulltext.Parse(`"beyond words" -tedious`)
+	assert.Equal(t, []fulltext.Fragment{
+		{Text: "Vulgar "},
+		{Text: "beyond words", Match: true},
+		{Text: "."},
+	}, q.Snippet("Vulgar beyond words."))
+
+	// A long text is cut to a window of words around the first match
+	long := strings.Repeat("dull ", 40) + "and vulgar, " + strings.Repeat("dull ", 40)
+	snippet := fulltext.Parse("vulgar").Snippet(long)
+	assert.Equal(t, []fulltext.Fragment{
+		{Text: "dull dull dull dull and "},
+		{Text: "vulgar", Match: true},
+		{Text: ", " + strings.TrimSpace(strings.Repeat("dull ", 29))},
+	}, snippet)
+}
+
+func TestSplitHeadline(t *testing.T) {
+	t.Parallel()
+	q := fulltext.Parse("vulgar")
+	text := "Vulgar, vulgar beyond words"
+	assert.Equal(t, q.Snippet(text), fulltext.SplitHeadline(q.Headline(text)))
+
+	// ts_headline() marks the words of a phrase one by one
+	headline := "Vulgar " + fulltext.StartSel + "beyond" + fulltext.StopSel + " " +
+		fulltext.StartSel + "words" + fulltext.StopSel + "."
+	assert.Equal(t, []fulltext.Fragment{
+		{Text: "Vulgar "},
+		{Text: "beyond words", Match: true},
+		{Text: "."},
+	}, fulltext.SplitHeadline(headline))
+	assert.Empty(t, fulltext.SplitHeadline(""))
+}
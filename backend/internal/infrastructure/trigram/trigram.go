@@ -0,0 +1,107 @@
+package trigram
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Similarity mirrors pg_trgm's similarity(): both strings are lowercased and
+// split into alphanumeric words, each word is padded with two leading and one
+// trailing space, and the result is the number of shared trigrams divided by
+// the number of distinct trigrams in either string.
+func Similarity(a, b string) float64 {
+	setA := trigrams(a)
+	setB := trigrams(b)
+	if len(setA) == 0 || len(setB) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for t := range setA {
+		if _, ok := setB[t]; ok {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(setA)+len(setB)-shared)
+}
+
+// StrictWordSimilarity mirrors pg_trgm's strict_word_similarity(): the
+// greatest similarity between a and any run of consecutive whole words in b.
+// It finds a short query anywhere in a long text.
+func StrictWordSimilarity(a, b string) float64 {
+	score, _, _ := bestExtent(a, b)
+	return score
+}
+
+// BestExtent returns the byte offsets in b of the run of words that
+// StrictWordSimilarity matched a against, or zeros when nothing matched.
+func BestExtent(a, b string) (start, end int) {
+	_, start, end = bestExtent(a, b)
+	return start, end
+}
+
+func bestExtent(a, b string) (score float64, start, end int) {
+	query := trigrams(a)
+	if len(query) == 0 {
+		return 0, 0, 0
+	}
+	spans := words(b)
+	for i := range spans {
+		extent := make(map[string]struct{})
+		shared := 0
+		for j := i; j < len(spans); j++ {
+			for t := range wordTrigrams(b[spans[j][0]:spans[j][1]]) {
+				if _, seen := extent[t]; seen {
+					continue
+				}
+				extent[t] = struct{}{}
+				if _, ok := query[t]; ok {
+					shared++
+				}
+			}
+			if s := float64(shared) / float64(len(query)+len(extent)-shared); s > score {
+				score, start, end = s, spans[i][0], spans[j][1]
+			}
+		}
+	}
+	return score, start, end
+}
+
+func trigrams(s string) map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, span := range words(s) {
+		for t := range wordTrigrams(s[span[0]:span[1]]) {
+			set[t] = struct{}{}
+		}
+	}
+	return set
+}
+
+// words returns the byte offsets of the alphanumeric words in s.
+func words(s string) [][2]int {
+	var spans [][2]int
+	start := -1
+	for i, r := range s {
+		inWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case inWord && start < 0:
+			start = i
+		case !inWord && start >= 0:
+			spans = append(spans, [2]int{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, [2]int{start, len(s)})
+	}
+	return spans
+}
+
+func wordTrigrams(word string) map[string]struct{} {
+	set := make(map[string]struct{})
+	padded := []rune("  " + strings.ToLower(word) + " ")
+	for i := 0; i+3 <= len(padded); i++ {
+		set[string(padded[i:i+3])] = struct{}{}
+	}
+	return set
+}
@@ -0,0 +1,42 @@
+package trigram_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/what-writers-like/backend/internal/infrastructure/trigram"
+)
+
+func TestSimilarity(t *testing.T) {
+	t.Parallel()
+	// Reference values computed by pg_trgm
+	assert.InDelta(t, 0.363636, trigram.Similarity("word", "two words"), 0.0001)
+	assert.InDelta(t, 1.0, trigram.Similarity("Jane Austen", "jane austen"), 0.0001)
+	assert.InDelta(t, 0.0, trigram.Similarity("", "Austen"), 0.0001)
+	assert.Greater(t, trigram.Similarity("Austen", "Jane Austen"), 0.3)
+	assert.Less(t, trigram.Similarity("Dickens", "Jane Austen"), 0.3)
+}
+
+func TestStrictWordSimilarity(t *testing.T) {
+	t.Parallel()
+	// Reference values computed by pg_trgm
+	assert.InDelta(t, 0.571429, trigram.StrictWordSimilarity("word", "two words"), 0.0001)
+	assert.InDelta(t, 1.0, trigram.StrictWordSimilarity("austen", "Novels by Jane Austen, mostly"), 0.0001)
+	assert.InDelta(t, 0.0, trigram.StrictWordSimilarity("", "Austen"), 0.0001)
+	assert.Less(t, trigram.StrictWordSimilarity("Dickens", "Novels by Jane Austen"), 0.3)
+}
+
+func TestBestExtent(t *testing.T) {
+	t.Parallel()
+	text := "Всё это пошлость, and vulgar too"
+	start, end := trigram.BestExtent("пошлость", text)
+	assert.Equal(t, "пошлость", text[start:end])
+
+	start, end = trigram.BestExtent("jane austen", "Novels by Jane Austen, mostly")
+	assert.Equal(t, 10, start)
+	assert.Equal(t, 21, end)
+
+	start, end = trigram.BestExtent("", "Austen")
+	assert.Zero(t, start)
+	assert.Zero(t, end)
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/what-writers-like/backend/internal/domain"
+)
+
+// AuditFilter narrows a Find query. Zero values leave the corresponding
+// column unconstrained; Since is inclusive and Until exclusive.
+type AuditFilter struct {
+	EntityType domain.AuditEntityType
+	EntityID   string
+	Since      *time.Time
+	Until      *time.Time
+}
+
+// AuditRepository is append-only: entries are never updated or deleted.
+type AuditRepository interface {
+	Create(entry *domain.AuditEntry) error
+	// Find returns matching entries, newest first.
+	Find(filter AuditFilter, limit, offset int) ([]*domain.AuditEntry, error)
+}
@@ -0,0 +1,64 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/repository"
+)
+
+func TestAuditRepository_CreateAndFind(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+		entries := []*domain.AuditEntry{
+			domain.NewAuditEntry(0, domain.AuditEntityWriter, "1", domain.AuditActionCreate,
+				nil, []byte(`{"name":"Jane Austen"}`), "alice", "req-1", base),
+			domain.NewAuditEntry(0, domain.AuditEntityWriter, "1", domain.AuditActionUpdate,
+				[]byte(`{"name":"Jane Austen"}`), []byte(`{"name":"J. Austen"}`), "bob", "req-2", base.Add(time.Hour)),
+			domain.NewAuditEntry(0, domain.AuditEntityWork, "1", domain.AuditActionCreate,
+				nil, []byte(`{"title":"Emma"}`), "alice", "req-3", base.Add(2*time.Hour)),
+			domain.NewAuditEntry(0, domain.AuditEntityOpinion, "2:1", domain.AuditActionDelete,
+				[]byte(`{"quote":"Quote"}`), nil, "bob", "req-4", base.Add(3*time.Hour)),
+		}
+		for _, e := range entries {
+			require.NoError(t, repos.auditRepo.Create(e))
+			assert.NotZero(t, e.ID())
+		}
+
+		all, err := repos.auditRepo.Find(repository.AuditFilter{}, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, all, 4)
+		assert.Equal(t, domain.AuditEntityOpinion, all[0].EntityType())
+		assert.Equal(t, "2:1", all[0].EntityID())
+		assert.Nil(t, all[0].After())
+		assert.Equal(t, "bob", all[0].Actor())
+		assert.Equal(t, "req-4", all[0].RequestID())
+		assert.True(t, base.Add(3*time.Hour).Equal(all[0].CreatedAt()))
+
+		writerFilter := repository.AuditFilter{EntityType: domain.AuditEntityWriter, EntityID: "1"}
+		writer, err := repos.auditRepo.Find(writerFilter, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, writer, 2)
+		assert.Equal(t, domain.AuditActionUpdate, writer[0].Action())
+		assert.JSONEq(t, `{"name":"Jane Austen"}`, string(writer[0].Before()))
+		assert.JSONEq(t, `{"name":"J. Austen"}`, string(writer[0].After()))
+		assert.Nil(t, writer[1].Before())
+
+		since := base.Add(time.Hour)
+		until := base.Add(3 * time.Hour)
+		window, err := repos.auditRepo.Find(repository.AuditFilter{Since: &since, Until: &until}, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, window, 2)
+		assert.Equal(t, "req-3", window[0].RequestID())
+		assert.Equal(t, "req-2", window[1].RequestID())
+
+		paged, err := repos.auditRepo.Find(repository.AuditFilter{}, 2, 1)
+		require.NoError(t, err)
+		require.Len(t, paged, 2)
+		assert.Equal(t, "req-3", paged[0].RequestID())
+	})
+}
@@ -0,0 +1,86 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/what-writers-like/backend/internal/repository"
+	"github.com/what-writers-like/backend/internal/repository/gorm"
+	"github.com/what-writers-like/backend/internal/repository/memory"
+	"github.com/what-writers-like/backend/internal/testutils"
+)
+
+type testRepos struct {
+	writerRepo          repository.WriterRepository
+	workRepo            repository.WorkRepository
+	opinionRepo         repository.OpinionRepository
+	graphRepo           repository.GraphRepository
+	auditRepo           repository.AuditRepository
+	opinionRevisionRepo repository.OpinionRevisionRepository
+	sourceRepo          repository.SourceRepository
+	writerAliasRepo     repository.WriterAliasRepository
+	searchRepo          repository.SearchRepository
+	transactor          repository.Transactor
+}
+
+// forEachBackend runs test against every repository implementation so that
+// the SQLite and in-memory backends are held to the same behaviour as
+// Postgres.
+func forEachBackend(t *testing.T, test func(t *testing.T, repos *testRepos)) {
+	t.Helper()
+
+	t.Run("postgres", func(t *testing.T) {
+		t.Parallel()
+		db, cleanup := testutils.SetupTestDB(t)
+		defer cleanup()
+
+		test(t, &testRepos{
+			writerRepo:          gorm.NewWriterRepository(db),
+			workRepo:            gorm.NewWorkRepository(db),
+			opinionRepo:         gorm.NewOpinionRepository(db),
+			graphRepo:           gorm.NewGraphRepository(db),
+			auditRepo:           gorm.NewAuditRepository(db),
+			opinionRevisionRepo: gorm.NewOpinionRevisionRepository(db),
+			sourceRepo:          gorm.NewSourceRepository(db),
+			writerAliasRepo:     gorm.NewWriterAliasRepository(db),
+			searchRepo:          gorm.NewSearchRepository(db),
+			transactor:          gorm.NewTransactor(db),
+		})
+	})
+
+	t.Run("sqlite", func(t *testing.T) {
+		t.Parallel()
+		db, cleanup := testutils.SetupSQLiteTestDB(t)
+		defer cleanup()
+
+		test(t, &testRepos{
+			writerRepo:          gorm.NewWriterRepository(db),
+			workRepo:            gorm.NewWorkRepository(db),
+			opinionRepo:         gorm.NewOpinionRepository(db),
+			graphRepo:           gorm.NewGraphRepository(db),
+			auditRepo:           gorm.NewAuditRepository(db),
+			opinionRevisionRepo: gorm.NewOpinionRevisionRepository(db),
+			sourceRepo:          gorm.NewSourceRepository(db),
+			writerAliasRepo:     gorm.NewWriterAliasRepository(db),
+			searchRepo:          gorm.NewSearchRepository(db),
+			transactor:          gorm.NewTransactor(db),
+		})
+	})
+
+	t.Run("memory", func(t *testing.T) {
+		t.Parallel()
+		store := memory.NewStore()
+
+		test(t, &testRepos{
+			writerRepo:          memory.NewWriterRepository(store),
+			workRepo:            memory.NewWorkRepository(store),
+			opinionRepo:         memory.NewOpinionRepository(store),
+			graphRepo:           memory.NewGraphRepository(store),
+			auditRepo:           memory.NewAuditRepository(store),
+			opinionRevisionRepo: memory.NewOpinionRevisionRepository(store),
+			sourceRepo:          memory.NewSourceRepository(store),
+			writerAliasRepo:     memory.NewWriterAliasRepository(store),
+			searchRepo:          memory.NewSearchRepository(store),
+			transactor:          memory.NewTransactor(store),
+		})
+	})
+}
@@ -0,0 +1,87 @@
+package gorm
+
+import (
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/infrastructure/database"
+	"github.com/what-writers-like/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *database.Database) repository.AuditRepository {
+	return &auditRepository{db: db.DB()}
+}
+
+func (r *auditRepository) Create(entry *domain.AuditEntry) error {
+	model := &database.AuditEntryModel{
+		EntityType: string(entry.EntityType()),
+		EntityID:   entry.EntityID(),
+		Action:     string(entry.Action()),
+		Before:     snapshotToColumn(entry.Before()),
+		After:      snapshotToColumn(entry.After()),
+		Actor:      entry.Actor(),
+		RequestID:  entry.RequestID(),
+		// Stored in UTC so that SQLite, which compares timestamps as text,
+		// orders and filters them correctly
+		CreatedAt: entry.CreatedAt().UTC(),
+	}
+	if err := r.db.Create(model).Error; err != nil {
+		return err
+	}
+	entry.SetID(model.ID)
+	return nil
+}
+
+func (r *auditRepository) Find(filter repository.AuditFilter, limit, offset int) ([]*domain.AuditEntry, error) {
+	query := r.db.Model(&database.AuditEntryModel{})
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", string(filter.EntityType))
+	}
+	if filter.EntityID != "" {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.Since != nil {
+		query = query.Where("created_at >= ?", filter.Since.UTC())
+	}
+	if filter.Until != nil {
+		query = query.Where("created_at < ?", filter.Until.UTC())
+	}
+
+	var models []database.AuditEntryModel
+	if err := query.Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&models).Error; err != nil {
+		return nil, err
+	}
+	entries := make([]*domain.AuditEntry, len(models))
+	for i, m := range models {
+		entries[i] = domain.NewAuditEntry(
+			m.ID,
+			domain.AuditEntityType(m.EntityType),
+			m.EntityID,
+			domain.AuditAction(m.Action),
+			columnToSnapshot(m.Before),
+			columnToSnapshot(m.After),
+			m.Actor,
+			m.RequestID,
+			m.CreatedAt,
+		)
+	}
+	return entries, nil
+}
+
+func snapshotToColumn(snapshot []byte) *string {
+	if snapshot == nil {
+		return nil
+	}
+	s := string(snapshot)
+	return &s
+}
+
+func columnToSnapshot(column *string) []byte {
+	if column == nil {
+		return nil
+	}
+	return []byte(*column)
+}
@@ -0,0 +1,17 @@
+package gorm
+
+import "gorm.io/gorm"
+
+// batchSize is how many rows ForEach reads at a time.
+const batchSize = 500
+
+// inBatches reads every row of the table behind M in order of ID, batchSize
+// rows at a time, and hands each batch to fn. Batches are read by keyset
+// and no connection is held while fn runs, so memory stays bounded however
+// large the table and fn may take its time.
+func inBatches[M any](db *gorm.DB, fn func(batch []M) error) error {
+	var models []M
+	return db.FindInBatches(&models, batchSize, func(*gorm.DB, int) error {
+		return fn(models)
+	}).Error
+}
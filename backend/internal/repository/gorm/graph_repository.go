@@ -0,0 +1,73 @@
+package gorm
+
+import (
+	"github.com/what-writers-like/backend/internal/infrastructure/database"
+	"github.com/what-writers-like/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+type graphRepository struct {
+	db *gorm.DB
+}
+
+func NewGraphRepository(db *database.Database) repository.GraphRepository {
+	return &graphRepository{db: db.DB()}
+}
+
+type nodeRow struct {
+	NodeType string
+	NodeID   uint64
+	Depth    int
+}
+
+func (r *graphRepository) Neighborhood(
+	startType repository.NodeType,
+	startID uint64,
+	depth, limit int,
+) ([]repository.NodeRef, error) {
+	// The walk is bounded by depth rather than by cycle detection, so a node
+	// may be visited several times; only its shortest distance is kept.
+	// Both databases walk breadth first and hand rows on as they find them,
+	// so capping the rows read from the walk stops it early. A node appears
+	// at most once per depth, so depth+1 rows per node keep enough distinct
+	// ones to fill the limit.
+	neighborhoodSQL := `
+		WITH RECURSIVE edges(src_type, src_id, dst_type, dst_id) AS (
+			SELECT 'writer', writer_id, 'work', work_id FROM opinions WHERE work_id IS NOT NULL
+			UNION ALL
+			SELECT 'writer', writer_id, 'writer', target_writer_id FROM opinions WHERE target_writer_id IS NOT NULL
+			UNION ALL
+			SELECT 'work', work_id, 'writer', writer_id FROM work_authors
+		),
+		walk(node_type, node_id, depth) AS (
+			SELECT CAST(? AS TEXT), CAST(? AS BIGINT), 0
+			UNION
+			SELECT e.dst_type, e.dst_id, w.depth + 1
+			FROM walk w
+			JOIN edges e ON e.src_type = w.node_type AND e.src_id = w.node_id
+			WHERE w.depth < ?
+		),
+		capped AS (
+			SELECT node_type, node_id, depth FROM walk LIMIT ?
+		)
+		SELECT node_type, node_id, MIN(depth) AS depth
+		FROM capped
+		GROUP BY node_type, node_id
+		ORDER BY MIN(depth), node_type, node_id
+		LIMIT ?
+	`
+	var rows []nodeRow
+	err := r.db.Raw(neighborhoodSQL, string(startType), startID, depth, limit*(depth+1), limit).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	refs := make([]repository.NodeRef, len(rows))
+	for i, row := range rows {
+		refs[i] = repository.NodeRef{
+			Type:  repository.NodeType(row.NodeType),
+			ID:    row.NodeID,
+			Depth: row.Depth,
+		}
+	}
+	return refs, nil
+}
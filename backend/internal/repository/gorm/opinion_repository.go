@@ -1,12 +1,20 @@
 package gorm
 
 import (
+	"database/sql"
+	"fmt"
+
 	"github.com/what-writers-like/backend/internal/domain"
 	"github.com/what-writers-like/backend/internal/infrastructure/database"
+	"github.com/what-writers-like/backend/internal/infrastructure/fulltext"
 	"github.com/what-writers-like/backend/internal/repository"
 	"gorm.io/gorm"
 )
 
+// statementOrder lists dated statements chronologically, then undated ones,
+// in the order they were recorded.
+const statementOrder = "statement_year IS NULL, statement_year, id"
+
 type opinionRepository struct {
 	db *gorm.DB
 }
@@ -16,83 +24,397 @@ func NewOpinionRepository(db *database.Database) repository.OpinionRepository {
 }
 
 func (r *opinionRepository) Create(opinion *domain.Opinion) error {
-	model := &database.OpinionModel{
-		WriterID:      opinion.WriterID(),
-		WorkID:        opinion.WorkID(),
-		Sentiment:     opinion.Sentiment(),
-		Quote:         opinion.Quote(),
-		Source:        opinion.Source(),
-		Page:          opinion.Page(),
-		StatementYear: opinion.StatementYear(),
-	}
-	return r.db.Create(model).Error
+	model := opinionToModel(opinion)
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(model).Error; err != nil {
+			return err
+		}
+		if err := advanceSequence(tx, database.OpinionsTable, opinion.ID()); err != nil {
+			return err
+		}
+		opinion.SetID(model.ID)
+		return saveTranslations(tx, opinion)
+	})
 }
 
-func (r *opinionRepository) GetByWriterID(writerID uint64) ([]*domain.Opinion, error) {
-	var models []database.OpinionModel
-	if err := r.db.Where("writer_id = ?", writerID).Find(&models).Error; err != nil {
+func (r *opinionRepository) GetByID(id uint64) (*domain.Opinion, error) {
+	var model database.OpinionModel
+	if err := r.db.First(&model, id).Error; err != nil {
 		return nil, err
 	}
-	opinions := make([]*domain.Opinion, len(models))
-	for i, m := range models {
-		opinions[i] = domain.NewOpinion(m.WriterID, m.WorkID, m.Sentiment, m.Quote, m.Source, m.Page, m.StatementYear)
+	opinions, err := r.toDomain([]database.OpinionModel{model})
+	if err != nil {
+		return nil, err
 	}
-	return opinions, nil
+	return opinions[0], nil
+}
+
+func (r *opinionRepository) GetByWriterID(writerID uint64) ([]*domain.Opinion, error) {
+	return r.find(r.db.Where("writer_id = ?", writerID).Order("id"))
 }
 
 func (r *opinionRepository) GetByWorkID(workID uint64) ([]*domain.Opinion, error) {
-	var models []database.OpinionModel
-	if err := r.db.Where("work_id = ?", workID).Find(&models).Error; err != nil {
+	return r.find(r.db.Where("work_id = ?", workID).Order("id"))
+}
+
+func (r *opinionRepository) GetByWriterAndWork(writerID, workID uint64) ([]*domain.Opinion, error) {
+	return r.find(r.db.Where("writer_id = ? AND work_id = ?", writerID, workID).Order(statementOrder))
+}
+
+func (r *opinionRepository) GetByTargetWriterID(targetWriterID uint64) ([]*domain.Opinion, error) {
+	return r.find(r.db.Where("target_writer_id = ?", targetWriterID).Order("id"))
+}
+
+func (r *opinionRepository) GetByWriterAndTargetWriter(writerID, targetWriterID uint64) ([]*domain.Opinion, error) {
+	return r.find(
+		r.db.Where("writer_id = ? AND target_writer_id = ?", writerID, targetWriterID).Order(statementOrder),
+	)
+}
+
+func (r *opinionRepository) List(
+	filter repository.OpinionFilter,
+	sort repository.OpinionSort,
+	after *repository.OpinionCursor,
+	limit int,
+) (*repository.Page[*domain.Opinion, repository.OpinionCursor], error) {
+	var total int64
+	if err := r.filterOpinions(r.db.Model(&database.OpinionModel{}), filter).Count(&total).Error; err != nil {
 		return nil, err
 	}
-	opinions := make([]*domain.Opinion, len(models))
-	for i, m := range models {
-		opinions[i] = domain.NewOpinion(m.WriterID, m.WorkID, m.Sentiment, m.Quote, m.Source, m.Page, m.StatementYear)
+
+	query := r.filterOpinions(r.db.Model(&database.OpinionModel{}), filter)
+	direction, beyond := "", ">"
+	if sort.Descending {
+		direction, beyond = " DESC", "<"
 	}
-	return opinions, nil
+	if sort.Key == repository.OpinionSortStatementYear {
+		// Undated statements come last either way, in ID order like ties
+		query = query.Order("statement_year IS NULL, statement_year" + direction + ", id")
+		switch {
+		case after == nil:
+		case after.StatementYear == nil:
+			query = query.Where("statement_year IS NULL AND id > ?", after.ID)
+		default:
+			year := *after.StatementYear
+			query = query.Where(
+				"(statement_year "+beyond+" ? OR statement_year = ? AND id > ? OR statement_year IS NULL)",
+				year, year, after.ID,
+			)
+		}
+	} else {
+		query = query.Order("id" + direction)
+		if after != nil {
+			query = query.Where("id "+beyond+" ?", after.ID)
+		}
+	}
+
+	opinions, err := r.find(query.Limit(limit + 1))
+	if err != nil {
+		return nil, err
+	}
+	return repository.NewPage(opinions, total, limit, repository.NewOpinionCursor), nil
 }
 
-func (r *opinionRepository) GetByWriterAndWork(writerID, workID uint64) (*domain.Opinion, error) {
-	var model database.OpinionModel
-	if err := r.db.Where("writer_id = ? AND work_id = ?", writerID, workID).First(&model).Error; err != nil {
+func (r *opinionRepository) Find(filter repository.OpinionFilter) ([]*domain.Opinion, error) {
+	query := r.filterOpinions(r.db.Model(&database.OpinionModel{}), filter)
+	// Opinions about works come before those about writers whichever way
+	// the database sorts NULLs
+	return r.find(query.Order("writer_id, work_id IS NULL, work_id, target_writer_id, " + statementOrder))
+}
+
+// filterOpinions narrows a query on opinions to those accepted by filter.
+func (r *opinionRepository) filterOpinions(query *gorm.DB, filter repository.OpinionFilter) *gorm.DB {
+	if len(filter.WriterIDs) > 0 {
+		query = query.Where("writer_id IN ?", filter.WriterIDs)
+	}
+	switch {
+	case len(filter.WorkIDs) > 0 && len(filter.TargetWriterIDs) > 0:
+		query = query.Where("(work_id IN ? OR target_writer_id IN ?)", filter.WorkIDs, filter.TargetWriterIDs)
+	case len(filter.WorkIDs) > 0:
+		query = query.Where("work_id IN ?", filter.WorkIDs)
+	case len(filter.TargetWriterIDs) > 0:
+		query = query.Where("target_writer_id IN ?", filter.TargetWriterIDs)
+	}
+	if len(filter.WorkAuthorIDs) > 0 {
+		authored := r.db.Model(&database.WorkAuthorModel{}).
+			Select("work_id").
+			Where("writer_id IN ?", filter.WorkAuthorIDs)
+		query = query.Where("work_id IN (?)", authored)
+	}
+	if len(filter.Sentiments) > 0 {
+		query = query.Where("sentiment IN ?", filter.Sentiments)
+	}
+	if len(filter.SourceIDs) > 0 {
+		query = query.Where("source_id IN ?", filter.SourceIDs)
+	}
+	if filter.StatementYearFrom != nil {
+		query = query.Where("statement_year >= ?", *filter.StatementYearFrom)
+	}
+	if filter.StatementYearTo != nil {
+		query = query.Where("statement_year <= ?", *filter.StatementYearTo)
+	}
+	return query
+}
+
+// quoteSearchPostgresSQL searches the quote_search column, which holds each
+// quote stemmed in its own language. quote_search_query() lets the index
+// narrow the candidates for every language at once; each is then matched
+// against the query parsed in its own language, which also ranks and
+// highlights it. Ranks are normalized to rank / (rank + 1)
+const quoteSearchPostgresSQL = `
+	SELECT o.id, ts_rank_cd(o.quote_search, q.query, 32) AS rank,
+		ts_headline(quote_search_config(o.language), o.quote, q.query, @options) AS headline
+	FROM opinions o,
+		LATERAL (SELECT websearch_to_tsquery(quote_search_config(o.language), @query) AS query) q
+	WHERE o.quote_search @@ quote_search_query(@query)
+		AND o.quote_search @@ q.query
+		AND (@language = '' OR o.language = @language)
+	ORDER BY rank DESC, o.id
+	LIMIT @limit OFFSET @offset
+`
+
+// quoteSearchSQLiteSQL calls the functions the application provides in
+// place of full-text search, see database.registerSQLiteFunctions
+const quoteSearchSQLiteSQL = `
+	SELECT id, quote_search_rank(@query, quote) AS rank, quote_search_headline(@query, quote) AS headline
+	FROM opinions
+	WHERE quote_search_match(@query, quote) AND (@language = '' OR language = @language)
+	ORDER BY rank DESC, id
+	LIMIT @limit OFFSET @offset
+`
+
+type quoteSearchRow struct {
+	ID       uint64
+	Rank     float64
+	Headline string
+}
+
+func (r *opinionRepository) SearchQuotes(query, language string, limit, offset int) ([]*domain.QuoteMatch, error) {
+	searchSQL := quoteSearchPostgresSQL
+	if r.db.Dialector.Name() == database.DialectSQLite {
+		searchSQL = quoteSearchSQLiteSQL
+	}
+	var rows []quoteSearchRow
+	err := r.db.Raw(
+		searchSQL,
+		sql.Named("query", query),
+		sql.Named("language", language),
+		sql.Named("options", fmt.Sprintf(
+			`StartSel="%s", StopSel="%s", MaxWords=35, MinWords=15`, fulltext.StartSel, fulltext.StopSel,
+		)),
+		sql.Named("limit", limit),
+		sql.Named("offset", offset),
+	).Scan(&rows).Error
+	if err != nil {
 		return nil, err
 	}
-	return domain.NewOpinion(
-		model.WriterID,
-		model.WorkID,
-		model.Sentiment,
-		model.Quote,
-		model.Source,
-		model.Page,
-		model.StatementYear,
-	), nil
+
+	if len(rows) == 0 {
+		return []*domain.QuoteMatch{}, nil
+	}
+	ids := make([]uint64, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+	opinions, err := r.find(r.db.Where("id IN ?", ids))
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint64]*domain.Opinion, len(opinions))
+	for _, o := range opinions {
+		byID[o.ID()] = o
+	}
+
+	matches := make([]*domain.QuoteMatch, 0, len(rows))
+	for _, row := range rows {
+		// Skip an opinion deleted since it was found
+		if opinion, ok := byID[row.ID]; ok {
+			matches = append(matches, &domain.QuoteMatch{
+				Opinion: opinion,
+				Snippet: snippetFromHeadline(row.Headline),
+				Rank:    row.Rank,
+			})
+		}
+	}
+	return matches, nil
+}
+
+func snippetFromHeadline(headline string) []domain.TextSegment {
+	fragments := fulltext.SplitHeadline(headline)
+	snippet := make([]domain.TextSegment, len(fragments))
+	for i, f := range fragments {
+		snippet[i] = domain.TextSegment{Text: f.Text, Match: f.Match}
+	}
+	return snippet
+}
+
+func (r *opinionRepository) ForEach(fn func(*domain.Opinion) error) error {
+	return inBatches(r.db, func(models []database.OpinionModel) error {
+		opinions, err := r.toDomain(models)
+		if err != nil {
+			return err
+		}
+		for _, opinion := range opinions {
+			if err := fn(opinion); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
-func (r *opinionRepository) List(limit, offset int) ([]*domain.Opinion, error) {
+func (r *opinionRepository) Update(opinion *domain.Opinion) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(opinionToModel(opinion)).Error; err != nil {
+			return err
+		}
+		return saveTranslations(tx, opinion)
+	})
+}
+
+func (r *opinionRepository) Delete(id uint64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("opinion_id = ?", id).Delete(&database.OpinionTranslationModel{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&database.OpinionModel{}, id).Error
+	})
+}
+
+func (r *opinionRepository) find(query *gorm.DB) ([]*domain.Opinion, error) {
 	var models []database.OpinionModel
-	if err := r.db.Limit(limit).Offset(offset).Find(&models).Error; err != nil {
+	if err := query.Find(&models).Error; err != nil {
 		return nil, err
 	}
+	return r.toDomain(models)
+}
+
+// toDomain loads the translations of the given opinions, keeping their
+// order.
+func (r *opinionRepository) toDomain(models []database.OpinionModel) ([]*domain.Opinion, error) {
 	opinions := make([]*domain.Opinion, len(models))
+	if len(models) == 0 {
+		return opinions, nil
+	}
+	ids := make([]uint64, len(models))
 	for i, m := range models {
-		opinions[i] = domain.NewOpinion(m.WriterID, m.WorkID, m.Sentiment, m.Quote, m.Source, m.Page, m.StatementYear)
+		ids[i] = m.ID
+	}
+	var rows []database.OpinionTranslationModel
+	err := r.db.Where("opinion_id IN ?", ids).Order("opinion_id, position").Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	translations := make(map[uint64][]domain.Translation, len(models))
+	for _, t := range rows {
+		translations[t.OpinionID] = append(translations[t.OpinionID], domain.Translation{
+			Language:   t.Language,
+			Text:       t.Text,
+			Translator: t.Translator,
+			Source:     t.Source,
+		})
+	}
+	for i := range models {
+		opinions[i] = opinionFromModel(&models[i])
+		opinions[i].SetLanguages(domain.QuoteLanguages{
+			Language:     opinions[i].Languages().Language,
+			Translations: translations[models[i].ID],
+		})
 	}
 	return opinions, nil
 }
 
-func (r *opinionRepository) Update(opinion *domain.Opinion) error {
-	model := &database.OpinionModel{
-		WriterID:      opinion.WriterID(),
-		WorkID:        opinion.WorkID(),
-		Sentiment:     opinion.Sentiment(),
-		Quote:         opinion.Quote(),
-		Source:        opinion.Source(),
-		Page:          opinion.Page(),
-		StatementYear: opinion.StatementYear(),
+// saveTranslations replaces the translations of an opinion's quote.
+func saveTranslations(tx *gorm.DB, opinion *domain.Opinion) error {
+	if err := tx.Where("opinion_id = ?", opinion.ID()).Delete(&database.OpinionTranslationModel{}).Error; err != nil {
+		return err
+	}
+	translations := opinion.Languages().Translations
+	if len(translations) == 0 {
+		return nil
+	}
+	rows := make([]database.OpinionTranslationModel, len(translations))
+	for i, t := range translations {
+		rows[i] = database.OpinionTranslationModel{
+			OpinionID:  opinion.ID(),
+			Language:   t.Language,
+			Text:       t.Text,
+			Translator: t.Translator,
+			Source:     t.Source,
+			Position:   i + 1,
+		}
+	}
+	return tx.Create(&rows).Error
+}
+
+func opinionToModel(o *domain.Opinion) *database.OpinionModel {
+	return &database.OpinionModel{
+		ID:             o.ID(),
+		WriterID:       o.WriterID(),
+		WorkID:         nullableID(o.WorkID()),
+		TargetWriterID: nullableID(o.TargetWriterID()),
+		Sentiment:      string(o.Sentiment()),
+		Quote:          o.Quote(),
+		Source:         o.Source(),
+		SourceID:       nullableID(o.SourceID()),
+		Page:           o.Page(),
+		StatementYear:  o.StatementYear(),
+		Language:       nullableString(o.Languages().Language),
 	}
-	return r.db.Save(model).Error
 }
 
-func (r *opinionRepository) Delete(writerID, workID uint64) error {
-	return r.db.Where("writer_id = ? AND work_id = ?", writerID, workID).Delete(&database.OpinionModel{}).Error
+// opinionFromModel leaves the translations to be loaded by the caller.
+func opinionFromModel(m *database.OpinionModel) *domain.Opinion {
+	return opinionFromColumns(
+		m.ID, m.WriterID, m.WorkID, m.TargetWriterID, m.Sentiment, m.Quote, m.Source, m.SourceID, m.Page,
+		m.StatementYear, m.Language,
+	)
+}
+
+// opinionFromColumns builds an opinion about whichever of work and target
+// writer is set, as read from the opinions or opinion_revisions table.
+func opinionFromColumns(
+	id, writerID uint64,
+	workID, targetWriterID *uint64,
+	sentiment, quote, source string,
+	sourceID *uint64,
+	page *string,
+	statementYear *int,
+	language *string,
+) *domain.Opinion {
+	var opinion *domain.Opinion
+	if targetWriterID != nil {
+		opinion = domain.NewWriterOpinion(
+			id, writerID, *targetWriterID, domain.Sentiment(sentiment), quote, source, page, statementYear,
+		)
+	} else {
+		var work uint64
+		if workID != nil {
+			work = *workID
+		}
+		opinion = domain.NewOpinion(id, writerID, work, domain.Sentiment(sentiment), quote, source, page, statementYear)
+	}
+	if sourceID != nil {
+		opinion.SetSourceID(*sourceID)
+	}
+	if language != nil {
+		opinion.SetLanguages(domain.QuoteLanguages{Language: *language})
+	}
+	return opinion
+}
+
+// nullableString stores an empty string as NULL.
+func nullableString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// nullableID stores an unset reference as NULL.
+func nullableID(id uint64) *uint64 {
+	if id == 0 {
+		return nil
+	}
+	return &id
 }
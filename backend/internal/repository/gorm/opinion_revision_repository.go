@@ -0,0 +1,143 @@
+package gorm
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/infrastructure/database"
+	"github.com/what-writers-like/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+type opinionRevisionRepository struct {
+	db *gorm.DB
+}
+
+func NewOpinionRevisionRepository(db *database.Database) repository.OpinionRevisionRepository {
+	return &opinionRevisionRepository{db: db.DB()}
+}
+
+// revisionTranslation is how a translation is written to the JSON array
+// kept with each revision.
+type revisionTranslation struct {
+	Language   string  `json:"language"`
+	Text       string  `json:"text"`
+	Translator *string `json:"translator,omitempty"`
+	Source     *string `json:"source,omitempty"`
+}
+
+func (r *opinionRevisionRepository) Create(revision *domain.OpinionRevision) error {
+	opinion := revision.Opinion()
+	translations, err := translationsToColumn(opinion.Languages().Translations)
+	if err != nil {
+		return err
+	}
+	model := &database.OpinionRevisionModel{
+		OpinionID:      opinion.ID(),
+		WriterID:       opinion.WriterID(),
+		WorkID:         nullableID(opinion.WorkID()),
+		TargetWriterID: nullableID(opinion.TargetWriterID()),
+		Sentiment:      string(opinion.Sentiment()),
+		Quote:          opinion.Quote(),
+		Source:         opinion.Source(),
+		SourceID:       nullableID(opinion.SourceID()),
+		Page:           opinion.Page(),
+		StatementYear:  opinion.StatementYear(),
+		Language:       nullableString(opinion.Languages().Language),
+		Translations:   translations,
+		Actor:          revision.Actor(),
+		CreatedAt:      revision.CreatedAt().UTC(),
+	}
+	// Services write the opinion earlier in the same transaction, and its row
+	// lock keeps concurrent edits from picking the same number. Should two
+	// still collide, the unique constraint rejects the second one rather than
+	// forking history
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&database.OpinionRevisionModel{}).
+			Select("COALESCE(MAX(revision), 0) + 1").
+			Where("opinion_id = ?", model.OpinionID).
+			Scan(&model.Revision).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(model).Error
+	})
+	if err != nil {
+		return err
+	}
+	revision.SetNumber(model.Revision)
+	return nil
+}
+
+func (r *opinionRevisionRepository) ListByOpinion(opinionID uint64) ([]*domain.OpinionRevision, error) {
+	var models []database.OpinionRevisionModel
+	if err := r.db.Where("opinion_id = ?", opinionID).Order("revision").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	revisions := make([]*domain.OpinionRevision, len(models))
+	for i := range models {
+		revision, err := revisionFromModel(&models[i])
+		if err != nil {
+			return nil, err
+		}
+		revisions[i] = revision
+	}
+	return revisions, nil
+}
+
+func (r *opinionRevisionRepository) Get(opinionID uint64, number int) (*domain.OpinionRevision, error) {
+	var model database.OpinionRevisionModel
+	if err := r.db.Where("opinion_id = ? AND revision = ?", opinionID, number).First(&model).Error; err != nil {
+		return nil, err
+	}
+	return revisionFromModel(&model)
+}
+
+func revisionFromModel(m *database.OpinionRevisionModel) (*domain.OpinionRevision, error) {
+	opinion := opinionFromColumns(
+		m.OpinionID, m.WriterID, m.WorkID, m.TargetWriterID, m.Sentiment,
+		m.Quote, m.Source, m.SourceID, m.Page, m.StatementYear, m.Language,
+	)
+	translations, err := columnToTranslations(m.Translations)
+	if err != nil {
+		return nil, fmt.Errorf("revision %d of opinion %d: %w", m.Revision, m.OpinionID, err)
+	}
+	opinion.SetLanguages(domain.QuoteLanguages{
+		Language:     opinion.Languages().Language,
+		Translations: translations,
+	})
+	return domain.NewOpinionRevision(m.Revision, opinion, m.Actor, m.CreatedAt), nil
+}
+
+// translationsToColumn stores an untranslated quote's translations as NULL.
+func translationsToColumn(translations []domain.Translation) (*string, error) {
+	if len(translations) == 0 {
+		return nil, nil
+	}
+	rows := make([]revisionTranslation, len(translations))
+	for i, t := range translations {
+		rows[i] = revisionTranslation(t)
+	}
+	data, err := json.Marshal(rows)
+	if err != nil {
+		return nil, err
+	}
+	column := string(data)
+	return &column, nil
+}
+
+func columnToTranslations(column *string) ([]domain.Translation, error) {
+	if column == nil {
+		return nil, nil
+	}
+	var rows []revisionTranslation
+	if err := json.Unmarshal([]byte(*column), &rows); err != nil {
+		return nil, fmt.Errorf("invalid translations: %w", err)
+	}
+	translations := make([]domain.Translation, len(rows))
+	for i, row := range rows {
+		translations[i] = domain.Translation(row)
+	}
+	return translations, nil
+}
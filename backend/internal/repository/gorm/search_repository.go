@@ -0,0 +1,107 @@
+package gorm
+
+import (
+	"database/sql"
+
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/infrastructure/database"
+	"github.com/what-writers-like/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+type searchRepository struct {
+	db *gorm.DB
+}
+
+func NewSearchRepository(db *database.Database) repository.SearchRepository {
+	return &searchRepository{db: db.DB()}
+}
+
+type searchRow struct {
+	HitType string
+	ID      uint64
+	Label   string
+	Field   string
+	Text    string
+	Score   float64
+}
+
+func (r *searchRepository) Search(
+	query string,
+	types []domain.SearchHitType,
+	limit, offset int,
+) ([]*domain.SearchHit, error) {
+	// strict_word_similarity() from pg_trgm finds the query among the words
+	// of a longer text such as a bio or quote; its usual cut-off is 0.5. On
+	// SQLite it is provided by the application, see
+	// database.registerSQLiteFunctions
+	// Every field that matches makes a row, and each entity keeps the row
+	// of its best field. Ties go to names and titles over longer texts,
+	// then to writers over works over opinions
+	searchSQL := `
+		SELECT hit_type, id, label, field, text, score FROM (
+			SELECT hits.*, ROW_NUMBER() OVER (
+				PARTITION BY hit_type, id ORDER BY score DESC, field_rank
+			) AS best
+			FROM (
+				SELECT 'writer' AS hit_type, 1 AS type_rank, id, name AS label,
+					'name' AS field, 1 AS field_rank, name AS text,
+					strict_word_similarity(@query, name) AS score
+				FROM writers
+				UNION ALL
+				SELECT 'writer', 1, w.id, w.name, 'alias', 2, a.name, strict_word_similarity(@query, a.name)
+				FROM writer_aliases a JOIN writers w ON w.id = a.writer_id
+				UNION ALL
+				SELECT 'writer', 1, id, name, 'bio', 3, bio, strict_word_similarity(@query, bio)
+				FROM writers WHERE bio IS NOT NULL
+				UNION ALL
+				SELECT 'work', 2, id, title, 'title', 1, title, strict_word_similarity(@query, title)
+				FROM works
+				UNION ALL
+				SELECT 'work', 2, id, title, 'original_title', 2, original_title,
+					strict_word_similarity(@query, original_title)
+				FROM works WHERE original_title IS NOT NULL
+				UNION ALL
+				SELECT 'opinion', 3, id, quote, 'quote', 1, quote, strict_word_similarity(@query, quote)
+				FROM opinions
+				UNION ALL
+				SELECT 'opinion', 3, id, quote, 'source', 2, source, strict_word_similarity(@query, source)
+				FROM opinions
+			) hits
+			WHERE score > 0.5
+		) ranked
+		WHERE best = 1 AND (@all_types OR hit_type IN @types)
+		ORDER BY score DESC, type_rank, field_rank, id
+		LIMIT @limit OFFSET @offset
+	`
+	// IN needs at least one value even when it is not consulted
+	typeNames := []string{""}
+	for _, t := range types {
+		typeNames = append(typeNames, string(t))
+	}
+	var rows []searchRow
+	err := r.db.Raw(
+		searchSQL,
+		sql.Named("query", query),
+		sql.Named("all_types", len(types) == 0),
+		sql.Named("types", typeNames),
+		sql.Named("limit", limit),
+		sql.Named("offset", offset),
+	).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]*domain.SearchHit, len(rows))
+	for i, row := range rows {
+		hits[i] = &domain.SearchHit{
+			Type:  domain.SearchHitType(row.HitType),
+			ID:    row.ID,
+			Label: row.Label,
+			Field: row.Field,
+			Text:  row.Text,
+			Score: row.Score,
+		}
+	}
+	return hits, nil
+}
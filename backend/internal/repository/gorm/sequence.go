@@ -0,0 +1,16 @@
+package gorm
+
+import (
+	"github.com/what-writers-like/backend/internal/infrastructure/database"
+	"gorm.io/gorm"
+)
+
+// advanceSequence moves the id sequence of table past a row just inserted
+// with the explicit ID id, which bypassed it. A generated ID, zero, needs
+// nothing.
+func advanceSequence(db *gorm.DB, table string, id uint64) error {
+	if id == 0 {
+		return nil
+	}
+	return database.SyncIDSequence(db, table)
+}
@@ -0,0 +1,113 @@
+package gorm
+
+import (
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/infrastructure/database"
+	"github.com/what-writers-like/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+type sourceRepository struct {
+	db *gorm.DB
+}
+
+func NewSourceRepository(db *database.Database) repository.SourceRepository {
+	return &sourceRepository{db: db.DB()}
+}
+
+func (r *sourceRepository) Create(source *domain.Source) error {
+	model := sourceToModel(source)
+	if err := r.db.Create(model).Error; err != nil {
+		return err
+	}
+	if err := advanceSequence(r.db, database.SourcesTable, source.ID()); err != nil {
+		return err
+	}
+	source.SetID(model.ID)
+	return nil
+}
+
+func (r *sourceRepository) GetByID(id uint64) (*domain.Source, error) {
+	var model database.SourceModel
+	if err := r.db.First(&model, id).Error; err != nil {
+		return nil, err
+	}
+	return sourceFromModel(&model), nil
+}
+
+func (r *sourceRepository) List(limit, offset int) ([]*domain.Source, error) {
+	return r.find(r.db.Order("id").Limit(limit).Offset(offset))
+}
+
+func (r *sourceRepository) ListCandidates(limit, offset int) ([]*domain.Source, error) {
+	return r.find(r.db.Where("confirmed = ?", false).Order("id").Limit(limit).Offset(offset))
+}
+
+func (r *sourceRepository) ForEach(fn func(*domain.Source) error) error {
+	return inBatches(r.db, func(models []database.SourceModel) error {
+		for i := range models {
+			if err := fn(sourceFromModel(&models[i])); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *sourceRepository) Update(source *domain.Source) error {
+	return r.db.Save(sourceToModel(source)).Error
+}
+
+func (r *sourceRepository) Delete(id uint64) error {
+	return r.db.Delete(&database.SourceModel{}, id).Error
+}
+
+func (r *sourceRepository) find(query *gorm.DB) ([]*domain.Source, error) {
+	var models []database.SourceModel
+	if err := query.Find(&models).Error; err != nil {
+		return nil, err
+	}
+	sources := make([]*domain.Source, len(models))
+	for i := range models {
+		sources[i] = sourceFromModel(&models[i])
+	}
+	return sources, nil
+}
+
+func sourceToModel(s *domain.Source) *database.SourceModel {
+	details := s.Details()
+	var sourceType *string
+	if s.Type() != "" {
+		t := string(s.Type())
+		sourceType = &t
+	}
+	return &database.SourceModel{
+		ID:              s.ID(),
+		Type:            sourceType,
+		Title:           s.Title(),
+		Author:          details.Author,
+		Publisher:       details.Publisher,
+		Year:            details.Year,
+		ISBN:            details.ISBN,
+		DOI:             details.DOI,
+		URL:             details.URL,
+		ArchiveLocation: details.ArchiveLocation,
+		Confirmed:       s.Confirmed(),
+	}
+}
+
+func sourceFromModel(m *database.SourceModel) *domain.Source {
+	var sourceType domain.SourceType
+	if m.Type != nil {
+		sourceType = domain.SourceType(*m.Type)
+	}
+	return domain.NewSource(m.ID, sourceType, m.Title, domain.SourceDetails{
+		Author:          m.Author,
+		Publisher:       m.Publisher,
+		Year:            m.Year,
+		ISBN:            m.ISBN,
+		DOI:             m.DOI,
+		URL:             m.URL,
+		ArchiveLocation: m.ArchiveLocation,
+	}, m.Confirmed)
+}
@@ -0,0 +1,48 @@
+package gorm
+
+import (
+	"database/sql"
+
+	"github.com/what-writers-like/backend/internal/infrastructure/database"
+	"github.com/what-writers-like/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+type transactor struct {
+	db *gorm.DB
+}
+
+func NewTransactor(db *database.Database) repository.Transactor {
+	return &transactor{db: db.DB()}
+}
+
+func (t *transactor) WithinTransaction(fn func(repos *repository.Repositories) error) error {
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		return fn(repositories(tx))
+	})
+}
+
+// WithinSnapshot asks Postgres for a repeatable read. SQLite needs no
+// options, and its driver takes none: with a single connection, nothing
+// commits while the transaction is open.
+func (t *transactor) WithinSnapshot(fn func(repos *repository.Repositories) error) error {
+	var options []*sql.TxOptions
+	if t.db.Dialector.Name() != database.DialectSQLite {
+		options = append(options, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	}
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		return fn(repositories(tx))
+	}, options...)
+}
+
+func repositories(tx *gorm.DB) *repository.Repositories {
+	return &repository.Repositories{
+		Writers:          &writerRepository{db: tx},
+		Works:            &workRepository{db: tx},
+		Opinions:         &opinionRepository{db: tx},
+		Audit:            &auditRepository{db: tx},
+		OpinionRevisions: &opinionRevisionRepository{db: tx},
+		Sources:          &sourceRepository{db: tx},
+		WriterAliases:    &writerAliasRepository{db: tx},
+	}
+}
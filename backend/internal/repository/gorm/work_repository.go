@@ -15,13 +15,74 @@ func NewWorkRepository(db *database.Database) repository.WorkRepository {
 	return &workRepository{db: db.DB()}
 }
 
-func (r *workRepository) Create(work *domain.Work) error {
-	model := &database.WorkModel{
-		ID:       work.ID(),
-		Title:    work.Title(),
-		AuthorID: work.AuthorID(),
+func workToModel(work *domain.Work) *database.WorkModel {
+	details := work.Details()
+	return &database.WorkModel{
+		ID:               work.ID(),
+		Title:            work.Title(),
+		PublicationYear:  details.PublicationYear,
+		Genre:            details.Genre,
+		OriginalLanguage: details.OriginalLanguage,
+		OriginalTitle:    details.OriginalTitle,
+	}
+}
+
+// toDomain loads the authors of the given works, keeping their order.
+func (r *workRepository) toDomain(models []database.WorkModel) ([]*domain.Work, error) {
+	works := make([]*domain.Work, len(models))
+	if len(models) == 0 {
+		return works, nil
+	}
+	ids := make([]uint64, len(models))
+	for i, m := range models {
+		ids[i] = m.ID
+	}
+	var authors []database.WorkAuthorModel
+	if err := r.db.Where("work_id IN ?", ids).Order("work_id, position").Find(&authors).Error; err != nil {
+		return nil, err
+	}
+	authorIDs := make(map[uint64][]uint64, len(models))
+	for _, a := range authors {
+		authorIDs[a.WorkID] = append(authorIDs[a.WorkID], a.WriterID)
+	}
+	for i, m := range models {
+		works[i] = domain.NewWork(m.ID, m.Title, authorIDs[m.ID], domain.WorkDetails{
+			PublicationYear:  m.PublicationYear,
+			Genre:            m.Genre,
+			OriginalLanguage: m.OriginalLanguage,
+			OriginalTitle:    m.OriginalTitle,
+		})
+	}
+	return works, nil
+}
+
+// saveAuthors replaces the authors credited for a work.
+func saveAuthors(tx *gorm.DB, work *domain.Work) error {
+	if err := tx.Where("work_id = ?", work.ID()).Delete(&database.WorkAuthorModel{}).Error; err != nil {
+		return err
+	}
+	if len(work.AuthorIDs()) == 0 {
+		return nil
 	}
-	return r.db.Create(model).Error
+	authors := make([]database.WorkAuthorModel, len(work.AuthorIDs()))
+	for i, authorID := range work.AuthorIDs() {
+		authors[i] = database.WorkAuthorModel{WorkID: work.ID(), WriterID: authorID, Position: i + 1}
+	}
+	return tx.Create(&authors).Error
+}
+
+func (r *workRepository) Create(work *domain.Work) error {
+	model := workToModel(work)
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(model).Error; err != nil {
+			return err
+		}
+		if err := advanceSequence(tx, database.WorksTable, work.ID()); err != nil {
+			return err
+		}
+		work.SetID(model.ID)
+		return saveAuthors(tx, work)
+	})
 }
 
 func (r *workRepository) GetByID(id uint64) (*domain.Work, error) {
@@ -29,62 +90,153 @@ func (r *workRepository) GetByID(id uint64) (*domain.Work, error) {
 	if err := r.db.First(&model, id).Error; err != nil {
 		return nil, err
 	}
-	return domain.NewWork(model.ID, model.Title, model.AuthorID), nil
+	works, err := r.toDomain([]database.WorkModel{model})
+	if err != nil {
+		return nil, err
+	}
+	return works[0], nil
+}
+
+func (r *workRepository) GetByIDs(ids []uint64) ([]*domain.Work, error) {
+	if len(ids) == 0 {
+		return []*domain.Work{}, nil
+	}
+	var models []database.WorkModel
+	if err := r.db.Where("id IN ?", ids).Order("id").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return r.toDomain(models)
 }
 
 func (r *workRepository) GetByAuthorID(authorID uint64) ([]*domain.Work, error) {
 	var models []database.WorkModel
-	if err := r.db.Where("author_id = ?", authorID).Find(&models).Error; err != nil {
+	authored := r.db.Model(&database.WorkAuthorModel{}).Select("work_id").Where("writer_id = ?", authorID)
+	if err := r.db.Where("id IN (?)", authored).Order("id").Find(&models).Error; err != nil {
 		return nil, err
 	}
-	works := make([]*domain.Work, len(models))
-	for i, m := range models {
-		works[i] = domain.NewWork(m.ID, m.Title, m.AuthorID)
+	return r.toDomain(models)
+}
+
+func (r *workRepository) GetByTitle(title string) ([]*domain.Work, error) {
+	var models []database.WorkModel
+	if err := r.db.Where("title = ?", title).Order("id").Find(&models).Error; err != nil {
+		return nil, err
 	}
-	return works, nil
+	return r.toDomain(models)
 }
 
-func (r *workRepository) List(limit, offset int) ([]*domain.Work, error) {
+func (r *workRepository) List(
+	filter repository.WorkFilter,
+	after *repository.WorkCursor,
+	limit int,
+) (*repository.Page[*domain.Work, repository.WorkCursor], error) {
+	var total int64
+	if err := r.filterWorks(r.db.Model(&database.WorkModel{}), filter).Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	query := r.filterWorks(r.db.Model(&database.WorkModel{}), filter)
+	if after != nil {
+		query = query.Where("id > ?", after.ID)
+	}
 	var models []database.WorkModel
-	if err := r.db.Limit(limit).Offset(offset).Find(&models).Error; err != nil {
+	if err := query.Order("id").Limit(limit + 1).Find(&models).Error; err != nil {
 		return nil, err
 	}
-	works := make([]*domain.Work, len(models))
-	for i, m := range models {
-		works[i] = domain.NewWork(m.ID, m.Title, m.AuthorID)
+	works, err := r.toDomain(models)
+	if err != nil {
+		return nil, err
 	}
-	return works, nil
+	return repository.NewPage(works, total, limit, repository.NewWorkCursor), nil
 }
 
-func (r *workRepository) Search(query string, limit, offset int) ([]*domain.Work, error) {
-	var models []database.WorkModel
+// workSearchRow is a work matched by a search, with the number of matches.
+type workSearchRow struct {
+	database.WorkModel `gorm:"embedded"`
+	Total              int64
+}
+
+func (r *workRepository) Search(
+	query string,
+	filter repository.WorkFilter,
+	limit, offset int,
+) (*repository.Page[*domain.Work, int], error) {
+	var rows []workSearchRow
 	// Use PostgreSQL fuzzy search with similarity threshold of 0.3
 	// similarity() function from pg_trgm returns a value between 0 and 1
+	// On SQLite similarity() is provided by the application, see
+	// database.registerSQLiteFunctions
+	// A work known by a translated title is also found by its original one.
+	// Every row carries the number of matches
 	searchSQL := `
-		SELECT * FROM works 
-		WHERE similarity(title, ?) > 0.3
-		ORDER BY similarity(title, ?) DESC
+		SELECT *, COUNT(*) OVER () AS total FROM (
+			SELECT works.*, CASE
+				WHEN COALESCE(similarity(original_title, ?), 0) > similarity(title, ?)
+				THEN similarity(original_title, ?)
+				ELSE similarity(title, ?)
+			END AS score
+			FROM (?) works
+		) scored
+		WHERE score > 0.3
+		ORDER BY score DESC, id
 		LIMIT ? OFFSET ?
 	`
-	if err := r.db.Raw(searchSQL, query, query, limit, offset).Scan(&models).Error; err != nil {
+	works := r.filterWorks(r.db.Model(&database.WorkModel{}), filter)
+	err := r.db.Raw(searchSQL, query, query, query, query, works, limit, offset).Scan(&rows).Error
+	if err != nil {
 		return nil, err
 	}
-	works := make([]*domain.Work, len(models))
-	for i, m := range models {
-		works[i] = domain.NewWork(m.ID, m.Title, m.AuthorID)
+
+	models := make([]database.WorkModel, len(rows))
+	var total int64
+	for i, row := range rows {
+		models[i], total = row.WorkModel, row.Total
 	}
-	return works, nil
+	matches, err := r.toDomain(models)
+	if err != nil {
+		return nil, err
+	}
+	return repository.NewOffsetPage(matches, total, offset), nil
 }
 
-func (r *workRepository) Update(work *domain.Work) error {
-	model := &database.WorkModel{
-		ID:       work.ID(),
-		Title:    work.Title(),
-		AuthorID: work.AuthorID(),
+// filterWorks narrows a query on works to those accepted by filter.
+func (r *workRepository) ForEach(fn func(*domain.Work) error) error {
+	return inBatches(r.db, func(models []database.WorkModel) error {
+		works, err := r.toDomain(models)
+		if err != nil {
+			return err
+		}
+		for _, work := range works {
+			if err := fn(work); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *workRepository) filterWorks(query *gorm.DB, filter repository.WorkFilter) *gorm.DB {
+	if len(filter.AuthorIDs) > 0 {
+		authored := r.db.Model(&database.WorkAuthorModel{}).Select("work_id").Where("writer_id IN ?", filter.AuthorIDs)
+		query = query.Where("id IN (?)", authored)
 	}
-	return r.db.Save(model).Error
+	return query
+}
+
+func (r *workRepository) Update(work *domain.Work) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(workToModel(work)).Error; err != nil {
+			return err
+		}
+		return saveAuthors(tx, work)
+	})
 }
 
 func (r *workRepository) Delete(id uint64) error {
-	return r.db.Delete(&database.WorkModel{}, id).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("work_id = ?", id).Delete(&database.WorkAuthorModel{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&database.WorkModel{}, id).Error
+	})
 }
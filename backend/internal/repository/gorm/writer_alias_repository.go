@@ -0,0 +1,80 @@
+package gorm
+
+import (
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/infrastructure/database"
+	"github.com/what-writers-like/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+type writerAliasRepository struct {
+	db *gorm.DB
+}
+
+func NewWriterAliasRepository(db *database.Database) repository.WriterAliasRepository {
+	return &writerAliasRepository{db: db.DB()}
+}
+
+func (r *writerAliasRepository) Create(alias *domain.WriterAlias) error {
+	model := writerAliasToModel(alias)
+	if err := r.db.Create(model).Error; err != nil {
+		return err
+	}
+	if err := advanceSequence(r.db, database.WriterAliasesTable, alias.ID()); err != nil {
+		return err
+	}
+	alias.SetID(model.ID)
+	return nil
+}
+
+func (r *writerAliasRepository) GetByID(id uint64) (*domain.WriterAlias, error) {
+	var model database.WriterAliasModel
+	if err := r.db.First(&model, id).Error; err != nil {
+		return nil, err
+	}
+	return writerAliasFromModel(&model), nil
+}
+
+func (r *writerAliasRepository) ListByWriter(writerID uint64) ([]*domain.WriterAlias, error) {
+	var models []database.WriterAliasModel
+	if err := r.db.Where("writer_id = ?", writerID).Order("id").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	aliases := make([]*domain.WriterAlias, len(models))
+	for i := range models {
+		aliases[i] = writerAliasFromModel(&models[i])
+	}
+	return aliases, nil
+}
+
+func (r *writerAliasRepository) ForEach(fn func(*domain.WriterAlias) error) error {
+	return inBatches(r.db, func(models []database.WriterAliasModel) error {
+		for i := range models {
+			if err := fn(writerAliasFromModel(&models[i])); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *writerAliasRepository) Update(alias *domain.WriterAlias) error {
+	return r.db.Save(writerAliasToModel(alias)).Error
+}
+
+func (r *writerAliasRepository) Delete(id uint64) error {
+	return r.db.Delete(&database.WriterAliasModel{}, id).Error
+}
+
+func writerAliasToModel(a *domain.WriterAlias) *database.WriterAliasModel {
+	return &database.WriterAliasModel{
+		ID:       a.ID(),
+		WriterID: a.WriterID(),
+		Name:     a.Name(),
+		Kind:     string(a.Kind()),
+	}
+}
+
+func writerAliasFromModel(m *database.WriterAliasModel) *domain.WriterAlias {
+	return domain.NewWriterAlias(m.ID, m.WriterID, m.Name, domain.AliasKind(m.Kind))
+}
@@ -1,6 +1,8 @@
 package gorm
 
 import (
+	"fmt"
+
 	"github.com/what-writers-like/backend/internal/domain"
 	"github.com/what-writers-like/backend/internal/infrastructure/database"
 	"github.com/what-writers-like/backend/internal/repository"
@@ -23,7 +25,14 @@ func (r *writerRepository) Create(writer *domain.Writer) error {
 		DeathYear: writer.DeathYear(),
 		Bio:       writer.Bio(),
 	}
-	return r.db.Create(model).Error
+	if err := r.db.Create(model).Error; err != nil {
+		return err
+	}
+	if err := advanceSequence(r.db, database.WritersTable, writer.ID()); err != nil {
+		return err
+	}
+	writer.SetID(model.ID)
+	return nil
 }
 
 func (r *writerRepository) GetByID(id uint64) (*domain.Writer, error) {
@@ -34,9 +43,25 @@ func (r *writerRepository) GetByID(id uint64) (*domain.Writer, error) {
 	return domain.NewWriter(model.ID, model.Name, model.BirthYear, model.DeathYear, model.Bio), nil
 }
 
-func (r *writerRepository) List(limit, offset int) ([]*domain.Writer, error) {
+func (r *writerRepository) GetByIDs(ids []uint64) ([]*domain.Writer, error) {
+	if len(ids) == 0 {
+		return []*domain.Writer{}, nil
+	}
+	var models []database.WriterModel
+	if err := r.db.Where("id IN ?", ids).Order("id").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	writers := make([]*domain.Writer, len(models))
+	for i, m := range models {
+		writers[i] = domain.NewWriter(m.ID, m.Name, m.BirthYear, m.DeathYear, m.Bio)
+	}
+	return writers, nil
+}
+
+func (r *writerRepository) GetByNameAndBirthYear(name string, birthYear int) ([]*domain.Writer, error) {
 	var models []database.WriterModel
-	if err := r.db.Limit(limit).Offset(offset).Find(&models).Error; err != nil {
+	err := r.db.Where("name = ? AND birth_year = ?", name, birthYear).Order("id").Find(&models).Error
+	if err != nil {
 		return nil, err
 	}
 	writers := make([]*domain.Writer, len(models))
@@ -46,26 +71,170 @@ func (r *writerRepository) List(limit, offset int) ([]*domain.Writer, error) {
 	return writers, nil
 }
 
-func (r *writerRepository) Search(query string, limit, offset int) ([]*domain.Writer, error) {
+func (r *writerRepository) List(
+	filter repository.WriterFilter,
+	sort repository.WriterSort,
+	after *repository.WriterCursor,
+	limit int,
+) (*repository.Page[*domain.Writer, repository.WriterCursor], error) {
+	var total int64
+	if err := filterWriters(r.db.Model(&database.WriterModel{}), filter).Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	query := filterWriters(r.db.Model(&database.WriterModel{}), filter)
+	direction, beyond := "", ">"
+	if sort.Descending {
+		direction, beyond = " DESC", "<"
+	}
+	switch sort.Key {
+	case repository.WriterSortName, repository.WriterSortBirthYear:
+		column := string(sort.Key)
+		query = query.Order(column + direction + ", id")
+		if after != nil {
+			var value any = after.Name
+			if sort.Key == repository.WriterSortBirthYear {
+				value = after.BirthYear
+			}
+			// Ties are in ID order whichever way the column goes
+			query = query.Where(
+				fmt.Sprintf("(%s %s ? OR %s = ? AND id > ?)", column, beyond, column),
+				value, value, after.ID,
+			)
+		}
+	default:
+		query = query.Order("id" + direction)
+		if after != nil {
+			query = query.Where("id "+beyond+" ?", after.ID)
+		}
+	}
+
 	var models []database.WriterModel
+	if err := query.Limit(limit + 1).Find(&models).Error; err != nil {
+		return nil, err
+	}
+	writers := make([]*domain.Writer, len(models))
+	for i, m := range models {
+		writers[i] = domain.NewWriter(m.ID, m.Name, m.BirthYear, m.DeathYear, m.Bio)
+	}
+	return repository.NewPage(writers, total, limit, repository.NewWriterCursor), nil
+}
+
+func (r *writerRepository) ForEach(fn func(*domain.Writer) error) error {
+	return inBatches(r.db, func(models []database.WriterModel) error {
+		for _, m := range models {
+			if err := fn(domain.NewWriter(m.ID, m.Name, m.BirthYear, m.DeathYear, m.Bio)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// writerSearchRow is a writer scored against a search query, with the
+// best matching of their aliases.
+type writerSearchRow struct {
+	database.WriterModel `gorm:"embedded"`
+	OwnScore             float64
+	AliasID              *uint64
+	AliasScore           float64
+	Total                int64
+}
+
+func (r *writerRepository) Search(
+	query string,
+	filter repository.WriterFilter,
+	limit, offset int,
+) (*repository.Page[*domain.WriterMatch, int], error) {
+	var rows []writerSearchRow
 	// Use PostgreSQL fuzzy search with similarity threshold of 0.3
 	// similarity() function from pg_trgm returns a value between 0 and 1
+	// On SQLite both similarity() and GREATEST() are provided by the
+	// application, see database.registerSQLiteFunctions
+	// A writer is scored by the name or bio, or by whichever of their
+	// aliases is closest to the query. Every row carries the number of
+	// matches
 	searchSQL := `
-		SELECT * FROM writers 
-		WHERE similarity(name, ?) > 0.3 
-		   OR (bio IS NOT NULL AND similarity(bio, ?) > 0.3)
-		ORDER BY 
-			GREATEST(similarity(name, ?), COALESCE(similarity(bio, ?), 0)) DESC
+		SELECT *, COUNT(*) OVER () AS total FROM (
+			SELECT writers.*,
+				GREATEST(similarity(name, ?), COALESCE(similarity(bio, ?), 0)) AS own_score,
+				(
+					SELECT a.id FROM writer_aliases a
+					WHERE a.writer_id = writers.id
+					ORDER BY similarity(a.name, ?) DESC, a.id
+					LIMIT 1
+				) AS alias_id,
+				COALESCE((
+					SELECT MAX(similarity(a.name, ?)) FROM writer_aliases a
+					WHERE a.writer_id = writers.id
+				), 0) AS alias_score
+			FROM (?) writers
+		) scored
+		WHERE own_score > 0.3 OR alias_score > 0.3
+		ORDER BY GREATEST(own_score, alias_score) DESC, id
 		LIMIT ? OFFSET ?
 	`
-	if err := r.db.Raw(searchSQL, query, query, query, query, limit, offset).Scan(&models).Error; err != nil {
+	writers := filterWriters(r.db.Model(&database.WriterModel{}), filter)
+	err := r.db.Raw(searchSQL, query, query, query, query, writers, limit, offset).Scan(&rows).Error
+	if err != nil {
 		return nil, err
 	}
-	writers := make([]*domain.Writer, len(models))
-	for i, m := range models {
-		writers[i] = domain.NewWriter(m.ID, m.Name, m.BirthYear, m.DeathYear, m.Bio)
+
+	// Only report an alias when it matched better than the name itself
+	var aliasIDs []uint64
+	for _, row := range rows {
+		if row.AliasID != nil && row.AliasScore > row.OwnScore {
+			aliasIDs = append(aliasIDs, *row.AliasID)
+		}
 	}
-	return writers, nil
+	aliases := make(map[uint64]*domain.WriterAlias, len(aliasIDs))
+	if len(aliasIDs) > 0 {
+		var models []database.WriterAliasModel
+		if err := r.db.Where("id IN ?", aliasIDs).Find(&models).Error; err != nil {
+			return nil, err
+		}
+		for i := range models {
+			aliases[models[i].ID] = writerAliasFromModel(&models[i])
+		}
+	}
+
+	matches := make([]*domain.WriterMatch, len(rows))
+	for i, row := range rows {
+		m := row.WriterModel
+		matches[i] = &domain.WriterMatch{Writer: domain.NewWriter(m.ID, m.Name, m.BirthYear, m.DeathYear, m.Bio)}
+		if row.AliasID != nil && row.AliasScore > row.OwnScore {
+			matches[i].Alias = aliases[*row.AliasID]
+		}
+	}
+	var total int64
+	if len(rows) > 0 {
+		total = rows[0].Total
+	}
+	return repository.NewOffsetPage(matches, total, offset), nil
+}
+
+// filterWriters narrows a query on writers to those accepted by filter.
+func filterWriters(query *gorm.DB, filter repository.WriterFilter) *gorm.DB {
+	if filter.BirthYearFrom != nil {
+		query = query.Where("birth_year >= ?", *filter.BirthYearFrom)
+	}
+	if filter.BirthYearTo != nil {
+		query = query.Where("birth_year <= ?", *filter.BirthYearTo)
+	}
+	if filter.DeathYearFrom != nil {
+		query = query.Where("death_year >= ?", *filter.DeathYearFrom)
+	}
+	if filter.DeathYearTo != nil {
+		query = query.Where("death_year <= ?", *filter.DeathYearTo)
+	}
+	if filter.Alive != nil {
+		if *filter.Alive {
+			query = query.Where("death_year IS NULL")
+		} else {
+			query = query.Where("death_year IS NOT NULL")
+		}
+	}
+	return query
 }
 
 func (r *writerRepository) Update(writer *domain.Writer) error {
@@ -80,5 +249,10 @@ func (r *writerRepository) Update(writer *domain.Writer) error {
 }
 
 func (r *writerRepository) Delete(id uint64) error {
-	return r.db.Delete(&database.WriterModel{}, id).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("writer_id = ?", id).Delete(&database.WriterAliasModel{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&database.WriterModel{}, id).Error
+	})
 }
@@ -0,0 +1,25 @@
+package repository
+
+type NodeType string
+
+const (
+	NodeTypeWriter NodeType = "writer"
+	NodeTypeWork   NodeType = "work"
+)
+
+// NodeRef identifies a writer or work reached during a traversal, along with
+// the number of hops needed to reach it from the starting node.
+type NodeRef struct {
+	Type  NodeType
+	ID    uint64
+	Depth int
+}
+
+type GraphRepository interface {
+	// Neighborhood walks opinion edges (writer -> work or writer -> writer)
+	// and authorship edges (work -> author) from the start node for at most depth hops. Nodes are
+	// returned closest first and at most limit of them. The walk stops once
+	// it has found limit nodes, so when it is cut short, which nodes at the
+	// farthest depth are kept is unspecified.
+	Neighborhood(startType NodeType, startID uint64, depth, limit int) ([]NodeRef, error)
+}
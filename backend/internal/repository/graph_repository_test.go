@@ -0,0 +1,102 @@
+package repository_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/repository"
+)
+
+func TestGraphRepository_Neighborhood(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		require.NoError(t, repos.writerRepo.Create(domain.NewWriter(1, "Jane Austen", 1775, nil, nil)))
+		require.NoError(t, repos.writerRepo.Create(domain.NewWriter(2, "Charlotte Bronte", 1816, nil, nil)))
+		require.NoError(t, repos.writerRepo.Create(domain.NewWriter(3, "Charles Dickens", 1812, nil, nil)))
+		require.NoError(t, repos.workRepo.Create(domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})))
+		require.NoError(t, repos.workRepo.Create(domain.NewWork(2, "Jane Eyre", []uint64{2}, domain.WorkDetails{})))
+		require.NoError(t, repos.opinionRepo.Create(domain.NewOpinion(
+			0, 2, 1, domain.SentimentNegative, "Quote 1", "Source 1", nil, nil,
+		)))
+		require.NoError(t, repos.opinionRepo.Create(domain.NewOpinion(
+			0, 3, 2, domain.SentimentPositive, "Quote 2", "Source 2", nil, nil,
+		)))
+
+		// Dickens -> Jane Eyre -> Bronte -> Pride and Prejudice -> Austen
+		refs, err := repos.graphRepo.Neighborhood(repository.NodeTypeWriter, 3, 2, 100)
+		require.NoError(t, err)
+		assert.Equal(t, []repository.NodeRef{
+			{Type: repository.NodeTypeWriter, ID: 3, Depth: 0},
+			{Type: repository.NodeTypeWork, ID: 2, Depth: 1},
+			{Type: repository.NodeTypeWriter, ID: 2, Depth: 2},
+		}, refs)
+
+		refs, err = repos.graphRepo.Neighborhood(repository.NodeTypeWriter, 3, 4, 100)
+		require.NoError(t, err)
+		assert.Len(t, refs, 5)
+		assert.Equal(t, repository.NodeRef{Type: repository.NodeTypeWriter, ID: 1, Depth: 4}, refs[4])
+
+		refs, err = repos.graphRepo.Neighborhood(repository.NodeTypeWriter, 3, 4, 2)
+		require.NoError(t, err)
+		assert.Len(t, refs, 2)
+
+		refs, err = repos.graphRepo.Neighborhood(repository.NodeTypeWork, 1, 3, 100)
+		require.NoError(t, err)
+		assert.Equal(t, []repository.NodeRef{
+			{Type: repository.NodeTypeWork, ID: 1, Depth: 0},
+			{Type: repository.NodeTypeWriter, ID: 1, Depth: 1},
+		}, refs)
+
+		// An opinion about Dickens himself leads straight to him
+		require.NoError(t, repos.opinionRepo.Create(domain.NewWriterOpinion(
+			0, 1, 3, domain.SentimentPositive, "Quote 3", "Source 3", nil, nil,
+		)))
+		refs, err = repos.graphRepo.Neighborhood(repository.NodeTypeWriter, 1, 1, 100)
+		require.NoError(t, err)
+		assert.Equal(t, []repository.NodeRef{
+			{Type: repository.NodeTypeWriter, ID: 1, Depth: 0},
+			{Type: repository.NodeTypeWriter, ID: 3, Depth: 1},
+		}, refs)
+	})
+}
+
+func TestGraphRepository_NeighborhoodLimit(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		// Every writer has an opinion of every other, so the walk keeps
+		// coming back to writers it has already reached
+		const writers = 6
+		for id := uint64(1); id <= writers; id++ {
+			require.NoError(t, repos.writerRepo.Create(domain.NewWriter(id, fmt.Sprintf("Writer %d", id), 1800, nil, nil)))
+		}
+		for from := uint64(1); from <= writers; from++ {
+			for to := uint64(1); to <= writers; to++ {
+				if from == to {
+					continue
+				}
+				require.NoError(t, repos.opinionRepo.Create(domain.NewWriterOpinion(
+					0, from, to, domain.SentimentPositive, "Quote", "Source", nil, nil,
+				)))
+			}
+		}
+
+		refs, err := repos.graphRepo.Neighborhood(repository.NodeTypeWriter, 1, 6, writers)
+		require.NoError(t, err)
+		require.Len(t, refs, writers)
+		assert.Equal(t, repository.NodeRef{Type: repository.NodeTypeWriter, ID: 1, Depth: 0}, refs[0])
+		for _, ref := range refs[1:] {
+			assert.Equal(t, 1, ref.Depth, "writer %d", ref.ID)
+		}
+
+		// Cut short, the walk still keeps the closest nodes
+		refs, err = repos.graphRepo.Neighborhood(repository.NodeTypeWriter, 1, 6, 3)
+		require.NoError(t, err)
+		require.Len(t, refs, 3)
+		assert.Equal(t, repository.NodeRef{Type: repository.NodeTypeWriter, ID: 1, Depth: 0}, refs[0])
+		assert.Equal(t, 1, refs[1].Depth)
+		assert.Equal(t, 1, refs[2].Depth)
+	})
+}
@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/repository"
+)
+
+type auditRepository struct {
+	store *Store
+}
+
+func NewAuditRepository(store *Store) repository.AuditRepository {
+	return &auditRepository{store: store}
+}
+
+func (r *auditRepository) Create(entry *domain.AuditEntry) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	entry.SetID(uint64(len(r.store.auditLog)) + 1)
+	r.store.auditLog = append(r.store.auditLog, *entry)
+	return nil
+}
+
+func (r *auditRepository) Find(filter repository.AuditFilter, limit, offset int) ([]*domain.AuditEntry, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	// Entries are appended in creation order, so walking backwards yields
+	// newest first
+	entries := make([]*domain.AuditEntry, 0)
+	for i := len(r.store.auditLog) - 1; i >= 0; i-- {
+		entry := r.store.auditLog[i]
+		if filter.EntityType != "" && entry.EntityType() != filter.EntityType {
+			continue
+		}
+		if filter.EntityID != "" && entry.EntityID() != filter.EntityID {
+			continue
+		}
+		if filter.Since != nil && entry.CreatedAt().Before(*filter.Since) {
+			continue
+		}
+		if filter.Until != nil && !entry.CreatedAt().Before(*filter.Until) {
+			continue
+		}
+		entries = append(entries, &entry)
+	}
+	start, end := page(len(entries), limit, offset)
+	return entries[start:end], nil
+}
@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"sort"
+
+	"github.com/what-writers-like/backend/internal/repository"
+)
+
+type graphRepository struct {
+	store *Store
+}
+
+func NewGraphRepository(store *Store) repository.GraphRepository {
+	return &graphRepository{store: store}
+}
+
+type nodeKey struct {
+	nodeType repository.NodeType
+	id       uint64
+}
+
+func (r *graphRepository) Neighborhood(
+	startType repository.NodeType,
+	startID uint64,
+	depth, limit int,
+) ([]repository.NodeRef, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	edges := make(map[nodeKey][]nodeKey)
+	for _, opinion := range r.store.opinions {
+		from := nodeKey{nodeType: repository.NodeTypeWriter, id: opinion.WriterID()}
+		to := nodeKey{nodeType: repository.NodeTypeWork, id: opinion.WorkID()}
+		if opinion.IsAboutWriter() {
+			to = nodeKey{nodeType: repository.NodeTypeWriter, id: opinion.TargetWriterID()}
+		}
+		edges[from] = append(edges[from], to)
+	}
+	for id, work := range r.store.works {
+		from := nodeKey{nodeType: repository.NodeTypeWork, id: id}
+		for _, authorID := range work.AuthorIDs() {
+			edges[from] = append(edges[from], nodeKey{nodeType: repository.NodeTypeWriter, id: authorID})
+		}
+	}
+
+	start := nodeKey{nodeType: startType, id: startID}
+	refs := []repository.NodeRef{{Type: startType, ID: startID, Depth: 0}}
+	seen := map[nodeKey]struct{}{start: {}}
+	frontier := []nodeKey{start}
+	// Like the SQL traversal, stop once enough nodes have been found
+	for d := 1; d <= depth && len(frontier) > 0 && len(refs) < limit; d++ {
+		var next []nodeKey
+		for _, node := range frontier {
+			for _, dst := range edges[node] {
+				if _, ok := seen[dst]; ok {
+					continue
+				}
+				seen[dst] = struct{}{}
+				next = append(next, dst)
+				refs = append(refs, repository.NodeRef{Type: dst.nodeType, ID: dst.id, Depth: d})
+			}
+		}
+		frontier = next
+	}
+
+	// Same order as the SQL traversal: closest first, then by type and ID
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].Depth != refs[j].Depth {
+			return refs[i].Depth < refs[j].Depth
+		}
+		if refs[i].Type != refs[j].Type {
+			return refs[i].Type < refs[j].Type
+		}
+		return refs[i].ID < refs[j].ID
+	})
+	if len(refs) > limit {
+		refs = refs[:limit]
+	}
+	return refs, nil
+}
@@ -0,0 +1,269 @@
+package memory
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/infrastructure/fulltext"
+	"github.com/what-writers-like/backend/internal/repository"
+)
+
+type opinionRepository struct {
+	store *Store
+}
+
+func NewOpinionRepository(store *Store) repository.OpinionRepository {
+	return &opinionRepository{store: store}
+}
+
+func (r *opinionRepository) Create(opinion *domain.Opinion) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.store.checkTarget(opinion); err != nil {
+		return err
+	}
+	if opinion.ID() == 0 {
+		r.store.opinionSeq++
+		opinion.SetID(r.store.opinionSeq)
+	} else if opinion.ID() > r.store.opinionSeq {
+		r.store.opinionSeq = opinion.ID()
+	}
+	if _, exists := r.store.opinions[opinion.ID()]; exists {
+		return ErrDuplicateKey
+	}
+	r.store.opinions[opinion.ID()] = *opinion
+	return nil
+}
+
+func (r *opinionRepository) GetByID(id uint64) (*domain.Opinion, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	opinion, ok := r.store.opinions[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &opinion, nil
+}
+
+func (r *opinionRepository) GetByWriterID(writerID uint64) ([]*domain.Opinion, error) {
+	opinions := r.filter(func(o *domain.Opinion) bool { return o.WriterID() == writerID })
+	sortByID(opinions)
+	return opinions, nil
+}
+
+func (r *opinionRepository) GetByWorkID(workID uint64) ([]*domain.Opinion, error) {
+	opinions := r.filter(func(o *domain.Opinion) bool { return !o.IsAboutWriter() && o.WorkID() == workID })
+	sortByID(opinions)
+	return opinions, nil
+}
+
+func (r *opinionRepository) GetByWriterAndWork(writerID, workID uint64) ([]*domain.Opinion, error) {
+	return r.filter(func(o *domain.Opinion) bool {
+		return o.WriterID() == writerID && !o.IsAboutWriter() && o.WorkID() == workID
+	}), nil
+}
+
+func (r *opinionRepository) GetByTargetWriterID(targetWriterID uint64) ([]*domain.Opinion, error) {
+	opinions := r.filter(func(o *domain.Opinion) bool {
+		return o.IsAboutWriter() && o.TargetWriterID() == targetWriterID
+	})
+	sortByID(opinions)
+	return opinions, nil
+}
+
+func (r *opinionRepository) GetByWriterAndTargetWriter(writerID, targetWriterID uint64) ([]*domain.Opinion, error) {
+	return r.filter(func(o *domain.Opinion) bool {
+		return o.WriterID() == writerID && o.IsAboutWriter() && o.TargetWriterID() == targetWriterID
+	}), nil
+}
+
+func (r *opinionRepository) List(
+	filter repository.OpinionFilter,
+	order repository.OpinionSort,
+	after *repository.OpinionCursor,
+	limit int,
+) (*repository.Page[*domain.Opinion, repository.OpinionCursor], error) {
+	opinions := r.filter(r.accepts(filter))
+	sort.Slice(opinions, func(i, j int) bool { return opinionBefore(order, opinions[i], opinions[j]) })
+
+	start := 0
+	if after != nil {
+		last := domain.NewOpinion(after.ID, 0, 0, "", "", "", nil, after.StatementYear)
+		start = sort.Search(len(opinions), func(i int) bool { return opinionBefore(order, last, opinions[i]) })
+	}
+	end := min(start+limit+1, len(opinions))
+	return repository.NewPage(opinions[start:end], int64(len(opinions)), limit, repository.NewOpinionCursor), nil
+}
+
+// opinionBefore reports whether a comes before b in a listing sorted by
+// order. Undated statements come last in either direction, and ID order
+// breaks ties.
+func opinionBefore(order repository.OpinionSort, a, b *domain.Opinion) bool {
+	if order.Key != repository.OpinionSortStatementYear {
+		if order.Descending {
+			return a.ID() > b.ID()
+		}
+		return a.ID() < b.ID()
+	}
+	x, y := a.StatementYear(), b.StatementYear()
+	switch {
+	case x == nil && y == nil, x != nil && y != nil && *x == *y:
+		return a.ID() < b.ID()
+	case x == nil || y == nil:
+		return y == nil
+	case order.Descending:
+		return *x > *y
+	default:
+		return *x < *y
+	}
+}
+
+func (r *opinionRepository) Find(filter repository.OpinionFilter) ([]*domain.Opinion, error) {
+	return r.filter(r.accepts(filter)), nil
+}
+
+// accepts returns a predicate for the opinions accepted by filter. It
+// reads works from the store, so call it only from within filter.
+func (r *opinionRepository) accepts(filter repository.OpinionFilter) func(o *domain.Opinion) bool {
+	writerIDs := idSet(filter.WriterIDs)
+	workIDs := idSet(filter.WorkIDs)
+	targetWriterIDs := idSet(filter.TargetWriterIDs)
+	sourceIDs := idSet(filter.SourceIDs)
+	sentiments := make(map[domain.Sentiment]struct{}, len(filter.Sentiments))
+	for _, s := range filter.Sentiments {
+		sentiments[s] = struct{}{}
+	}
+
+	return func(o *domain.Opinion) bool {
+		if _, ok := writerIDs[o.WriterID()]; len(writerIDs) > 0 && !ok {
+			return false
+		}
+		if len(workIDs) > 0 || len(targetWriterIDs) > 0 {
+			_, aboutWork := workIDs[o.WorkID()]
+			_, aboutWriter := targetWriterIDs[o.TargetWriterID()]
+			if o.IsAboutWriter() && !aboutWriter || !o.IsAboutWriter() && !aboutWork {
+				return false
+			}
+		}
+		if len(filter.WorkAuthorIDs) > 0 {
+			work, ok := r.store.works[o.WorkID()]
+			if o.IsAboutWriter() || !ok || !slices.ContainsFunc(filter.WorkAuthorIDs, work.HasAuthor) {
+				return false
+			}
+		}
+		if _, ok := sourceIDs[o.SourceID()]; len(sourceIDs) > 0 && !ok {
+			return false
+		}
+		year := o.StatementYear()
+		if filter.StatementYearFrom != nil && (year == nil || *year < *filter.StatementYearFrom) ||
+			filter.StatementYearTo != nil && (year == nil || *year > *filter.StatementYearTo) {
+			return false
+		}
+		_, ok := sentiments[o.Sentiment()]
+		return len(sentiments) == 0 || ok
+	}
+}
+
+func idSet(ids []uint64) map[uint64]struct{} {
+	set := make(map[uint64]struct{}, len(ids))
+	for _, id := range ids {
+		set[id] = struct{}{}
+	}
+	return set
+}
+
+// SearchQuotes matches whole words, without stemming, like the SQLite
+// backend.
+func (r *opinionRepository) SearchQuotes(query, language string, limit, offset int) ([]*domain.QuoteMatch, error) {
+	q := fulltext.Parse(query)
+	opinions := r.filter(func(o *domain.Opinion) bool {
+		return (language == "" || o.Languages().Language == language) && q.Match(o.Quote())
+	})
+
+	matches := make([]*domain.QuoteMatch, len(opinions))
+	for i, o := range opinions {
+		fragments := q.Snippet(o.Quote())
+		snippet := make([]domain.TextSegment, len(fragments))
+		for j, f := range fragments {
+			snippet[j] = domain.TextSegment{Text: f.Text, Match: f.Match}
+		}
+		matches[i] = &domain.QuoteMatch{Opinion: o, Snippet: snippet, Rank: q.Rank(o.Quote())}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Rank != matches[j].Rank {
+			return matches[i].Rank > matches[j].Rank
+		}
+		return matches[i].Opinion.ID() < matches[j].Opinion.ID()
+	})
+	start, end := page(len(matches), limit, offset)
+	return matches[start:end], nil
+}
+
+func (r *opinionRepository) ForEach(fn func(*domain.Opinion) error) error {
+	return each(r.store, func() map[uint64]domain.Opinion { return r.store.opinions }, fn)
+}
+
+func (r *opinionRepository) Update(opinion *domain.Opinion) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.store.checkTarget(opinion); err != nil {
+		return err
+	}
+	// Like gorm's Save, a missing row is inserted
+	r.store.opinions[opinion.ID()] = *opinion
+	return nil
+}
+
+func (r *opinionRepository) Delete(id uint64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.opinions, id)
+	return nil
+}
+
+// filter returns the opinions accepted by keep, ordered by writer and
+// target, works before writers, and then like statements of one pair: dated
+// ones chronologically, then undated ones, ties broken by ID.
+func (r *opinionRepository) filter(keep func(o *domain.Opinion) bool) []*domain.Opinion {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	opinions := []*domain.Opinion{}
+	for _, opinion := range r.store.opinions {
+		if keep(&opinion) {
+			opinions = append(opinions, &opinion)
+		}
+	}
+	sort.Slice(opinions, func(i, j int) bool {
+		a, b := opinions[i], opinions[j]
+		if a.WriterID() != b.WriterID() {
+			return a.WriterID() < b.WriterID()
+		}
+		if a.IsAboutWriter() != b.IsAboutWriter() {
+			return b.IsAboutWriter()
+		}
+		if a.WorkID() != b.WorkID() {
+			return a.WorkID() < b.WorkID()
+		}
+		if a.TargetWriterID() != b.TargetWriterID() {
+			return a.TargetWriterID() < b.TargetWriterID()
+		}
+		if (a.StatementYear() == nil) != (b.StatementYear() == nil) {
+			return b.StatementYear() == nil
+		}
+		if a.StatementYear() != nil && *a.StatementYear() != *b.StatementYear() {
+			return *a.StatementYear() < *b.StatementYear()
+		}
+		return a.ID() < b.ID()
+	})
+	return opinions
+}
+
+func sortByID(opinions []*domain.Opinion) {
+	sort.Slice(opinions, func(i, j int) bool { return opinions[i].ID() < opinions[j].ID() })
+}
@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/repository"
+)
+
+type opinionRevisionRepository struct {
+	store *Store
+}
+
+func NewOpinionRevisionRepository(store *Store) repository.OpinionRevisionRepository {
+	return &opinionRevisionRepository{store: store}
+}
+
+func (r *opinionRevisionRepository) Create(revision *domain.OpinionRevision) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	id := revision.Opinion().ID()
+	revision.SetNumber(len(r.store.opinionRevisions[id]) + 1)
+	r.store.opinionRevisions[id] = append(r.store.opinionRevisions[id], *revision)
+	return nil
+}
+
+func (r *opinionRevisionRepository) ListByOpinion(opinionID uint64) ([]*domain.OpinionRevision, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	stored := r.store.opinionRevisions[opinionID]
+	revisions := make([]*domain.OpinionRevision, len(stored))
+	for i := range stored {
+		revision := stored[i]
+		revisions[i] = &revision
+	}
+	return revisions, nil
+}
+
+func (r *opinionRevisionRepository) Get(opinionID uint64, number int) (*domain.OpinionRevision, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	// Revisions are numbered from 1 without gaps
+	stored := r.store.opinionRevisions[opinionID]
+	if number < 1 || number > len(stored) {
+		return nil, ErrNotFound
+	}
+	revision := stored[number-1]
+	return &revision, nil
+}
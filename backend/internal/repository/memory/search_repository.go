@@ -0,0 +1,120 @@
+package memory
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/infrastructure/trigram"
+	"github.com/what-writers-like/backend/internal/repository"
+)
+
+type searchRepository struct {
+	store *Store
+}
+
+func NewSearchRepository(store *Store) repository.SearchRepository {
+	return &searchRepository{store: store}
+}
+
+// searchField is a field of an entity that a search looks at. Ranks break
+// ties between hits of equal score as the Postgres query does: writers come
+// before works before opinions, and names and titles before longer texts.
+type searchField struct {
+	name string
+	rank int
+	text *string
+}
+
+func (r *searchRepository) Search(
+	query string,
+	types []domain.SearchHitType,
+	limit, offset int,
+) ([]*domain.SearchHit, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	wanted := func(hitType domain.SearchHitType) bool {
+		return len(types) == 0 || slices.Contains(types, hitType)
+	}
+
+	type ranked struct {
+		hit       *domain.SearchHit
+		typeRank  int
+		fieldRank int
+	}
+	var hits []ranked
+	add := func(hitType domain.SearchHitType, typeRank int, id uint64, label string, fields ...searchField) {
+		if !wanted(hitType) {
+			return
+		}
+		var best *ranked
+		for _, f := range fields {
+			if f.text == nil {
+				continue
+			}
+			score := trigram.StrictWordSimilarity(query, *f.text)
+			if score <= wordSearchThreshold {
+				continue
+			}
+			if best != nil && (score < best.hit.Score || score == best.hit.Score && f.rank >= best.fieldRank) {
+				continue
+			}
+			best = &ranked{
+				hit: &domain.SearchHit{
+					Type: hitType, ID: id, Label: label, Field: f.name, Text: *f.text, Score: score,
+				},
+				typeRank:  typeRank,
+				fieldRank: f.rank,
+			}
+		}
+		if best != nil {
+			hits = append(hits, *best)
+		}
+	}
+
+	for _, id := range sortedKeys(r.store.writers) {
+		writer := r.store.writers[id]
+		name := writer.Name()
+		fields := []searchField{{name: "name", rank: 1, text: &name}, {name: "bio", rank: 3, text: writer.Bio()}}
+		for _, alias := range r.store.aliasesOf(id) {
+			aliasName := alias.Name()
+			fields = append(fields, searchField{name: "alias", rank: 2, text: &aliasName})
+		}
+		add(domain.SearchHitWriter, 1, id, name, fields...)
+	}
+	for _, id := range sortedKeys(r.store.works) {
+		work := r.store.works[id]
+		title := work.Title()
+		add(domain.SearchHitWork, 2, id, title,
+			searchField{name: "title", rank: 1, text: &title},
+			searchField{name: "original_title", rank: 2, text: work.Details().OriginalTitle},
+		)
+	}
+	for _, id := range sortedKeys(r.store.opinions) {
+		opinion := r.store.opinions[id]
+		quote, source := opinion.Quote(), opinion.Source()
+		add(domain.SearchHitOpinion, 3, id, quote,
+			searchField{name: "quote", rank: 1, text: &quote},
+			searchField{name: "source", rank: 2, text: &source},
+		)
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		a, b := hits[i], hits[j]
+		if a.hit.Score != b.hit.Score {
+			return a.hit.Score > b.hit.Score
+		}
+		if a.typeRank != b.typeRank {
+			return a.typeRank < b.typeRank
+		}
+		return a.fieldRank < b.fieldRank
+	})
+
+	start, end := page(len(hits), limit, offset)
+	result := make([]*domain.SearchHit, 0, end-start)
+	for _, h := range hits[start:end] {
+		result = append(result, h.hit)
+	}
+	return result, nil
+}
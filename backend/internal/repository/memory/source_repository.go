@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/repository"
+)
+
+type sourceRepository struct {
+	store *Store
+}
+
+func NewSourceRepository(store *Store) repository.SourceRepository {
+	return &sourceRepository{store: store}
+}
+
+func (r *sourceRepository) Create(source *domain.Source) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if source.ID() == 0 {
+		r.store.sourceSeq++
+		source.SetID(r.store.sourceSeq)
+	} else if source.ID() > r.store.sourceSeq {
+		r.store.sourceSeq = source.ID()
+	}
+	if _, exists := r.store.sources[source.ID()]; exists {
+		return ErrDuplicateKey
+	}
+	r.store.sources[source.ID()] = *source
+	return nil
+}
+
+func (r *sourceRepository) GetByID(id uint64) (*domain.Source, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	source, ok := r.store.sources[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &source, nil
+}
+
+func (r *sourceRepository) List(limit, offset int) ([]*domain.Source, error) {
+	return r.list(func(*domain.Source) bool { return true }, limit, offset), nil
+}
+
+func (r *sourceRepository) ListCandidates(limit, offset int) ([]*domain.Source, error) {
+	return r.list(func(s *domain.Source) bool { return !s.Confirmed() }, limit, offset), nil
+}
+
+func (r *sourceRepository) ForEach(fn func(*domain.Source) error) error {
+	return each(r.store, func() map[uint64]domain.Source { return r.store.sources }, fn)
+}
+
+func (r *sourceRepository) Update(source *domain.Source) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// Like gorm's Save, a missing row is inserted
+	if source.ID() > r.store.sourceSeq {
+		r.store.sourceSeq = source.ID()
+	}
+	r.store.sources[source.ID()] = *source
+	return nil
+}
+
+func (r *sourceRepository) Delete(id uint64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.sources, id)
+	return nil
+}
+
+func (r *sourceRepository) list(keep func(s *domain.Source) bool, limit, offset int) []*domain.Source {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	sources := []*domain.Source{}
+	for _, id := range sortedKeys(r.store.sources) {
+		if source := r.store.sources[id]; keep(&source) {
+			sources = append(sources, &source)
+		}
+	}
+	start, end := page(len(sources), limit, offset)
+	return sources[start:end]
+}
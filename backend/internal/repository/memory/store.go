@@ -0,0 +1,115 @@
+package memory
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/what-writers-like/backend/internal/domain"
+)
+
+var (
+	ErrNotFound     = errors.New("record not found")
+	ErrDuplicateKey = errors.New("duplicate key")
+	ErrOwnWork      = errors.New("writer cannot express opinion about their own work")
+	ErrSelfTarget   = errors.New("writer cannot express opinion about themselves")
+)
+
+// searchThreshold matches the similarity cut-off used by the Postgres
+// search queries.
+const searchThreshold = 0.3
+
+// wordSearchThreshold is the cut-off the Postgres queries use for
+// strict_word_similarity().
+const wordSearchThreshold = 0.5
+
+// Store holds the rows behind the in-memory repositories. A single lock
+// guards every table so that rules spanning tables, such as a writer not
+// reviewing their own work, are checked atomically with the write.
+type Store struct {
+	mu               sync.RWMutex
+	txMu             sync.Mutex // held for the length of a transaction
+	writers          map[uint64]domain.Writer
+	works            map[uint64]domain.Work
+	opinions         map[uint64]domain.Opinion
+	auditLog         []domain.AuditEntry
+	opinionRevisions map[uint64][]domain.OpinionRevision
+	sources          map[uint64]domain.Source
+	writerAliases    map[uint64]domain.WriterAlias
+
+	// Last IDs handed out, advanced past explicit IDs like a sequence
+	writerSeq      uint64
+	workSeq        uint64
+	opinionSeq     uint64
+	sourceSeq      uint64
+	writerAliasSeq uint64
+}
+
+func NewStore() *Store {
+	return &Store{
+		writers:          make(map[uint64]domain.Writer),
+		works:            make(map[uint64]domain.Work),
+		opinions:         make(map[uint64]domain.Opinion),
+		opinionRevisions: make(map[uint64][]domain.OpinionRevision),
+		sources:          make(map[uint64]domain.Source),
+		writerAliases:    make(map[uint64]domain.WriterAlias),
+	}
+}
+
+// checkTarget mirrors the triggers that reject opinions about a work
+// written by the opinion holder or about the holder themselves. Callers
+// must hold the lock.
+func (s *Store) checkTarget(opinion *domain.Opinion) error {
+	if opinion.IsAboutWriter() {
+		if opinion.TargetWriterID() == opinion.WriterID() {
+			return ErrSelfTarget
+		}
+		return nil
+	}
+	if work, ok := s.works[opinion.WorkID()]; ok && work.HasAuthor(opinion.WriterID()) {
+		return ErrOwnWork
+	}
+	return nil
+}
+
+func sortedKeys[V any](rows map[uint64]V) []uint64 {
+	keys := make([]uint64, 0, len(rows))
+	for k := range rows {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+// each calls fn with the rows of a table in order of ID. The lock is held
+// only while a row is read, never while fn runs, so fn may take its time
+// and rows written meanwhile may or may not be seen. table returns the map
+// holding the rows, which a rolled back transaction replaces.
+func each[V any](s *Store, table func() map[uint64]V, fn func(*V) error) error {
+	s.mu.RLock()
+	ids := sortedKeys(table())
+	s.mu.RUnlock()
+	for _, id := range ids {
+		s.mu.RLock()
+		row, ok := table()[id]
+		s.mu.RUnlock()
+		if !ok {
+			continue
+		}
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// page applies limit and offset to a result of n rows, returning the bounds
+// of the selected slice. A negative limit means no limit.
+func page(n, limit, offset int) (start, end int) {
+	start = min(max(offset, 0), n)
+	end = n
+	if limit >= 0 {
+		end = min(start+limit, n)
+	}
+	return start, end
+}
@@ -0,0 +1,112 @@
+package memory
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/repository"
+)
+
+type transactor struct {
+	store *Store
+}
+
+func NewTransactor(store *Store) repository.Transactor {
+	return &transactor{store: store}
+}
+
+// WithinTransaction runs transactions one at a time and undoes the writes
+// of one that fails by putting back the tables as they were before it.
+// Unlike a database, readers may see the writes of a transaction before it
+// commits.
+func (t *transactor) WithinTransaction(fn func(repos *repository.Repositories) error) error {
+	t.store.txMu.Lock()
+	defer t.store.txMu.Unlock()
+
+	saved := t.store.snapshot()
+	err := fn(t.repositories())
+	if err != nil {
+		t.store.restore(saved)
+	}
+	return err
+}
+
+// WithinSnapshot holds off transactions while fn runs. Every write goes
+// through one, so fn sees the tables as they were when it began.
+func (t *transactor) WithinSnapshot(fn func(repos *repository.Repositories) error) error {
+	t.store.txMu.Lock()
+	defer t.store.txMu.Unlock()
+	return fn(t.repositories())
+}
+
+func (t *transactor) repositories() *repository.Repositories {
+	return &repository.Repositories{
+		Writers:          NewWriterRepository(t.store),
+		Works:            NewWorkRepository(t.store),
+		Opinions:         NewOpinionRepository(t.store),
+		Audit:            NewAuditRepository(t.store),
+		OpinionRevisions: NewOpinionRevisionRepository(t.store),
+		Sources:          NewSourceRepository(t.store),
+		WriterAliases:    NewWriterAliasRepository(t.store),
+	}
+}
+
+// tables is a copy of the rows and sequences of a Store.
+type tables struct {
+	writers          map[uint64]domain.Writer
+	works            map[uint64]domain.Work
+	opinions         map[uint64]domain.Opinion
+	auditLog         []domain.AuditEntry
+	opinionRevisions map[uint64][]domain.OpinionRevision
+	sources          map[uint64]domain.Source
+	writerAliases    map[uint64]domain.WriterAlias
+
+	writerSeq      uint64
+	workSeq        uint64
+	opinionSeq     uint64
+	sourceSeq      uint64
+	writerAliasSeq uint64
+}
+
+func (s *Store) snapshot() *tables {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	revisions := make(map[uint64][]domain.OpinionRevision, len(s.opinionRevisions))
+	for id, stored := range s.opinionRevisions {
+		revisions[id] = slices.Clone(stored)
+	}
+	return &tables{
+		writers:          maps.Clone(s.writers),
+		works:            maps.Clone(s.works),
+		opinions:         maps.Clone(s.opinions),
+		auditLog:         slices.Clone(s.auditLog),
+		opinionRevisions: revisions,
+		sources:          maps.Clone(s.sources),
+		writerAliases:    maps.Clone(s.writerAliases),
+		writerSeq:        s.writerSeq,
+		workSeq:          s.workSeq,
+		opinionSeq:       s.opinionSeq,
+		sourceSeq:        s.sourceSeq,
+		writerAliasSeq:   s.writerAliasSeq,
+	}
+}
+
+func (s *Store) restore(t *tables) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.writers = t.writers
+	s.works = t.works
+	s.opinions = t.opinions
+	s.auditLog = t.auditLog
+	s.opinionRevisions = t.opinionRevisions
+	s.sources = t.sources
+	s.writerAliases = t.writerAliases
+	s.writerSeq = t.writerSeq
+	s.workSeq = t.workSeq
+	s.opinionSeq = t.opinionSeq
+	s.sourceSeq = t.sourceSeq
+	s.writerAliasSeq = t.writerAliasSeq
+}
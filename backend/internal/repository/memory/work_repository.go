@@ -0,0 +1,184 @@
+package memory
+
+import (
+	"sort"
+
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/infrastructure/trigram"
+	"github.com/what-writers-like/backend/internal/repository"
+)
+
+type workRepository struct {
+	store *Store
+}
+
+func NewWorkRepository(store *Store) repository.WorkRepository {
+	return &workRepository{store: store}
+}
+
+func (r *workRepository) Create(work *domain.Work) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if work.ID() == 0 {
+		r.store.workSeq++
+		work.SetID(r.store.workSeq)
+	} else if work.ID() > r.store.workSeq {
+		r.store.workSeq = work.ID()
+	}
+	if _, exists := r.store.works[work.ID()]; exists {
+		return ErrDuplicateKey
+	}
+	r.store.works[work.ID()] = *work
+	return nil
+}
+
+func (r *workRepository) GetByID(id uint64) (*domain.Work, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	work, ok := r.store.works[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &work, nil
+}
+
+func (r *workRepository) GetByIDs(ids []uint64) ([]*domain.Work, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	sorted := append([]uint64(nil), ids...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	works := []*domain.Work{}
+	for i, id := range sorted {
+		if i > 0 && sorted[i-1] == id {
+			continue
+		}
+		if work, ok := r.store.works[id]; ok {
+			works = append(works, &work)
+		}
+	}
+	return works, nil
+}
+
+func (r *workRepository) GetByAuthorID(authorID uint64) ([]*domain.Work, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	works := []*domain.Work{}
+	for _, id := range sortedKeys(r.store.works) {
+		if work := r.store.works[id]; work.HasAuthor(authorID) {
+			works = append(works, &work)
+		}
+	}
+	return works, nil
+}
+
+func (r *workRepository) GetByTitle(title string) ([]*domain.Work, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	works := []*domain.Work{}
+	for _, id := range sortedKeys(r.store.works) {
+		if work := r.store.works[id]; work.Title() == title {
+			works = append(works, &work)
+		}
+	}
+	return works, nil
+}
+
+func (r *workRepository) List(
+	filter repository.WorkFilter,
+	after *repository.WorkCursor,
+	limit int,
+) (*repository.Page[*domain.Work, repository.WorkCursor], error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	works := []*domain.Work{}
+	for _, id := range sortedKeys(r.store.works) {
+		if work := r.store.works[id]; workAccepted(&work, filter) {
+			works = append(works, &work)
+		}
+	}
+	start := 0
+	if after != nil {
+		start = sort.Search(len(works), func(i int) bool { return works[i].ID() > after.ID })
+	}
+	end := min(start+limit+1, len(works))
+	return repository.NewPage(works[start:end], int64(len(works)), limit, repository.NewWorkCursor), nil
+}
+
+func (r *workRepository) Search(
+	query string,
+	filter repository.WorkFilter,
+	limit, offset int,
+) (*repository.Page[*domain.Work, int], error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	type match struct {
+		work  *domain.Work
+		score float64
+	}
+	var matches []match
+	for _, id := range sortedKeys(r.store.works) {
+		work := r.store.works[id]
+		if !workAccepted(&work, filter) {
+			continue
+		}
+		score := trigram.Similarity(work.Title(), query)
+		if original := work.Details().OriginalTitle; original != nil {
+			score = max(score, trigram.Similarity(*original, query))
+		}
+		if score > searchThreshold {
+			matches = append(matches, match{work: &work, score: score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+
+	start, end := page(len(matches), limit, offset)
+	works := make([]*domain.Work, 0, end-start)
+	for _, m := range matches[start:end] {
+		works = append(works, m.work)
+	}
+	return repository.NewOffsetPage(works, int64(len(matches)), offset), nil
+}
+
+func workAccepted(w *domain.Work, filter repository.WorkFilter) bool {
+	if len(filter.AuthorIDs) == 0 {
+		return true
+	}
+	for _, id := range filter.AuthorIDs {
+		if w.HasAuthor(id) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *workRepository) ForEach(fn func(*domain.Work) error) error {
+	return each(r.store, func() map[uint64]domain.Work { return r.store.works }, fn)
+}
+
+func (r *workRepository) Update(work *domain.Work) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// Like gorm's Save, a missing row is inserted
+	if work.ID() > r.store.workSeq {
+		r.store.workSeq = work.ID()
+	}
+	r.store.works[work.ID()] = *work
+	return nil
+}
+
+func (r *workRepository) Delete(id uint64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.works, id)
+	return nil
+}
@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/repository"
+)
+
+type writerAliasRepository struct {
+	store *Store
+}
+
+func NewWriterAliasRepository(store *Store) repository.WriterAliasRepository {
+	return &writerAliasRepository{store: store}
+}
+
+func (r *writerAliasRepository) Create(alias *domain.WriterAlias) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.store.hasAlias(alias) {
+		return ErrDuplicateKey
+	}
+	if alias.ID() == 0 {
+		r.store.writerAliasSeq++
+		alias.SetID(r.store.writerAliasSeq)
+	} else if alias.ID() > r.store.writerAliasSeq {
+		r.store.writerAliasSeq = alias.ID()
+	}
+	if _, exists := r.store.writerAliases[alias.ID()]; exists {
+		return ErrDuplicateKey
+	}
+	r.store.writerAliases[alias.ID()] = *alias
+	return nil
+}
+
+func (r *writerAliasRepository) GetByID(id uint64) (*domain.WriterAlias, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	alias, ok := r.store.writerAliases[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &alias, nil
+}
+
+func (r *writerAliasRepository) ListByWriter(writerID uint64) ([]*domain.WriterAlias, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.aliasesOf(writerID), nil
+}
+
+func (r *writerAliasRepository) ForEach(fn func(*domain.WriterAlias) error) error {
+	return each(r.store, func() map[uint64]domain.WriterAlias { return r.store.writerAliases }, fn)
+}
+
+func (r *writerAliasRepository) Update(alias *domain.WriterAlias) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.store.hasAlias(alias) {
+		return ErrDuplicateKey
+	}
+	// Like gorm's Save, a missing row is inserted
+	if alias.ID() > r.store.writerAliasSeq {
+		r.store.writerAliasSeq = alias.ID()
+	}
+	r.store.writerAliases[alias.ID()] = *alias
+	return nil
+}
+
+func (r *writerAliasRepository) Delete(id uint64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.writerAliases, id)
+	return nil
+}
+
+// hasAlias mirrors the unique constraint on a writer's alias names: it
+// reports whether another alias of the same writer has the same name.
+// Callers must hold the lock.
+func (s *Store) hasAlias(alias *domain.WriterAlias) bool {
+	for id, existing := range s.writerAliases {
+		if id != alias.ID() && existing.WriterID() == alias.WriterID() && existing.Name() == alias.Name() {
+			return true
+		}
+	}
+	return false
+}
+
+// aliasesOf returns the aliases of a writer by ID. Callers must hold the
+// lock.
+func (s *Store) aliasesOf(writerID uint64) []*domain.WriterAlias {
+	aliases := []*domain.WriterAlias{}
+	for _, id := range sortedKeys(s.writerAliases) {
+		if alias := s.writerAliases[id]; alias.WriterID() == writerID {
+			aliases = append(aliases, &alias)
+		}
+	}
+	return aliases
+}
@@ -0,0 +1,211 @@
+package memory
+
+import (
+	"sort"
+
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/infrastructure/trigram"
+	"github.com/what-writers-like/backend/internal/repository"
+)
+
+type writerRepository struct {
+	store *Store
+}
+
+func NewWriterRepository(store *Store) repository.WriterRepository {
+	return &writerRepository{store: store}
+}
+
+func (r *writerRepository) Create(writer *domain.Writer) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if writer.ID() == 0 {
+		r.store.writerSeq++
+		writer.SetID(r.store.writerSeq)
+	} else if writer.ID() > r.store.writerSeq {
+		r.store.writerSeq = writer.ID()
+	}
+	if _, exists := r.store.writers[writer.ID()]; exists {
+		return ErrDuplicateKey
+	}
+	r.store.writers[writer.ID()] = *writer
+	return nil
+}
+
+func (r *writerRepository) GetByID(id uint64) (*domain.Writer, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	writer, ok := r.store.writers[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &writer, nil
+}
+
+func (r *writerRepository) GetByIDs(ids []uint64) ([]*domain.Writer, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	sorted := append([]uint64(nil), ids...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	writers := []*domain.Writer{}
+	for i, id := range sorted {
+		if i > 0 && sorted[i-1] == id {
+			continue
+		}
+		if writer, ok := r.store.writers[id]; ok {
+			writers = append(writers, &writer)
+		}
+	}
+	return writers, nil
+}
+
+func (r *writerRepository) GetByNameAndBirthYear(name string, birthYear int) ([]*domain.Writer, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	writers := []*domain.Writer{}
+	for _, id := range sortedKeys(r.store.writers) {
+		if writer := r.store.writers[id]; writer.Name() == name && writer.BirthYear() == birthYear {
+			writers = append(writers, &writer)
+		}
+	}
+	return writers, nil
+}
+
+func (r *writerRepository) List(
+	filter repository.WriterFilter,
+	order repository.WriterSort,
+	after *repository.WriterCursor,
+	limit int,
+) (*repository.Page[*domain.Writer, repository.WriterCursor], error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	writers := []*domain.Writer{}
+	for _, id := range sortedKeys(r.store.writers) {
+		if writer := r.store.writers[id]; writerAccepted(&writer, filter) {
+			writers = append(writers, &writer)
+		}
+	}
+	sort.Slice(writers, func(i, j int) bool { return writerBefore(order, writers[i], writers[j]) })
+
+	start := 0
+	if after != nil {
+		last := domain.NewWriter(after.ID, after.Name, after.BirthYear, nil, nil)
+		start = sort.Search(len(writers), func(i int) bool { return writerBefore(order, last, writers[i]) })
+	}
+	end := min(start+limit+1, len(writers))
+	return repository.NewPage(writers[start:end], int64(len(writers)), limit, repository.NewWriterCursor), nil
+}
+
+// writerBefore reports whether a comes before b in a listing sorted by
+// order, ties broken by ID.
+func writerBefore(order repository.WriterSort, a, b *domain.Writer) bool {
+	x, y := a, b
+	if order.Descending {
+		x, y = b, a
+	}
+	switch {
+	case order.Key == repository.WriterSortName && a.Name() != b.Name():
+		return x.Name() < y.Name()
+	case order.Key == repository.WriterSortBirthYear && a.BirthYear() != b.BirthYear():
+		return x.BirthYear() < y.BirthYear()
+	case order.Key == repository.WriterSortName, order.Key == repository.WriterSortBirthYear:
+		return a.ID() < b.ID()
+	default:
+		return x.ID() < y.ID()
+	}
+}
+
+func (r *writerRepository) Search(
+	query string,
+	filter repository.WriterFilter,
+	limit, offset int,
+) (*repository.Page[*domain.WriterMatch, int], error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	type scored struct {
+		match *domain.WriterMatch
+		score float64
+	}
+	var matches []scored
+	for _, id := range sortedKeys(r.store.writers) {
+		writer := r.store.writers[id]
+		if !writerAccepted(&writer, filter) {
+			continue
+		}
+		match := &domain.WriterMatch{Writer: &writer}
+		score := trigram.Similarity(writer.Name(), query)
+		if writer.Bio() != nil {
+			score = max(score, trigram.Similarity(*writer.Bio(), query))
+		}
+		// Only report an alias when it matches better than the name itself
+		for _, alias := range r.store.aliasesOf(id) {
+			if aliasScore := trigram.Similarity(alias.Name(), query); aliasScore > score {
+				score = aliasScore
+				match.Alias = alias
+			}
+		}
+		if score > searchThreshold {
+			matches = append(matches, scored{match: match, score: score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+
+	start, end := page(len(matches), limit, offset)
+	writers := make([]*domain.WriterMatch, 0, end-start)
+	for _, m := range matches[start:end] {
+		writers = append(writers, m.match)
+	}
+	return repository.NewOffsetPage(writers, int64(len(matches)), offset), nil
+}
+
+func writerAccepted(w *domain.Writer, filter repository.WriterFilter) bool {
+	if filter.BirthYearFrom != nil && w.BirthYear() < *filter.BirthYearFrom ||
+		filter.BirthYearTo != nil && w.BirthYear() > *filter.BirthYearTo {
+		return false
+	}
+	death := w.DeathYear()
+	if (filter.DeathYearFrom != nil || filter.DeathYearTo != nil) && death == nil {
+		return false
+	}
+	if filter.DeathYearFrom != nil && *death < *filter.DeathYearFrom ||
+		filter.DeathYearTo != nil && *death > *filter.DeathYearTo {
+		return false
+	}
+	return filter.Alive == nil || *filter.Alive == (death == nil)
+}
+
+func (r *writerRepository) ForEach(fn func(*domain.Writer) error) error {
+	return each(r.store, func() map[uint64]domain.Writer { return r.store.writers }, fn)
+}
+
+func (r *writerRepository) Update(writer *domain.Writer) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// Like gorm's Save, a missing row is inserted
+	if writer.ID() > r.store.writerSeq {
+		r.store.writerSeq = writer.ID()
+	}
+	r.store.writers[writer.ID()] = *writer
+	return nil
+}
+
+func (r *writerRepository) Delete(id uint64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.writers, id)
+	for aliasID, alias := range r.store.writerAliases {
+		if alias.WriterID() == id {
+			delete(r.store.writerAliases, aliasID)
+		}
+	}
+	return nil
+}
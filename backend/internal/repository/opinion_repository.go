@@ -2,12 +2,85 @@ package repository
 
 import "github.com/what-writers-like/backend/internal/domain"
 
+// OpinionFilter narrows a Find or List query. Empty lists and nil bounds
+// leave the corresponding column unconstrained. WorkIDs and
+// TargetWriterIDs both constrain the target, so when both are given an
+// opinion about any of them matches. WorkAuthorIDs keeps opinions about
+// works by any of the given writers. The statement year range is
+// inclusive and leaves out undated statements.
+type OpinionFilter struct {
+	WriterIDs         []uint64
+	WorkIDs           []uint64
+	TargetWriterIDs   []uint64
+	WorkAuthorIDs     []uint64
+	Sentiments        []domain.Sentiment
+	SourceIDs         []uint64
+	StatementYearFrom *int
+	StatementYearTo   *int
+}
+
+// OpinionSortKey names what opinions can be listed by.
+type OpinionSortKey string
+
+const (
+	OpinionSortID            OpinionSortKey = "id"
+	OpinionSortStatementYear OpinionSortKey = "statement_year"
+)
+
+// OpinionSort orders an opinion listing, ties broken by ID. Undated
+// statements come last in either direction. The zero value lists opinions
+// by ID.
+type OpinionSort struct {
+	Key        OpinionSortKey
+	Descending bool
+}
+
+// OpinionCursor marks the last opinion of a page. A listing goes on after
+// the opinion with this ID and, when sorted by statement year, this year;
+// nil for an undated statement.
+type OpinionCursor struct {
+	ID            uint64
+	StatementYear *int
+}
+
+// NewOpinionCursor marks o as the last opinion of a page.
+func NewOpinionCursor(o *domain.Opinion) OpinionCursor {
+	return OpinionCursor{ID: o.ID(), StatementYear: o.StatementYear()}
+}
+
 type OpinionRepository interface {
 	Create(opinion *domain.Opinion) error
+	GetByID(id uint64) (*domain.Opinion, error)
 	GetByWriterID(writerID uint64) ([]*domain.Opinion, error)
 	GetByWorkID(workID uint64) ([]*domain.Opinion, error)
-	GetByWriterAndWork(writerID, workID uint64) (*domain.Opinion, error)
-	List(limit, offset int) ([]*domain.Opinion, error)
+	// GetByWriterAndWork returns every statement the writer made about the
+	// work, dated ones first in chronological order.
+	GetByWriterAndWork(writerID, workID uint64) ([]*domain.Opinion, error)
+	// GetByTargetWriterID returns the opinions about the writer as a whole,
+	// not those about their works.
+	GetByTargetWriterID(targetWriterID uint64) ([]*domain.Opinion, error)
+	// GetByWriterAndTargetWriter returns every statement the writer made
+	// about the other writer, in the same order as GetByWriterAndWork.
+	GetByWriterAndTargetWriter(writerID, targetWriterID uint64) ([]*domain.Opinion, error)
+	// List returns a page of up to limit opinions accepted by filter, after
+	// the opinion marked by after, or from the start when it is nil.
+	List(
+		filter OpinionFilter,
+		sort OpinionSort,
+		after *OpinionCursor,
+		limit int,
+	) (*Page[*domain.Opinion, OpinionCursor], error)
+	// Find returns every opinion accepted by filter, grouped by writer and
+	// target.
+	Find(filter OpinionFilter) ([]*domain.Opinion, error)
+	// SearchQuotes runs a full-text search of quotes, best matches first.
+	// The query takes words, "quoted phrases", or between alternatives and
+	// -excluded words. A non-empty language keeps only quotes written in
+	// it.
+	SearchQuotes(query, language string, limit, offset int) ([]*domain.QuoteMatch, error)
+	// ForEach calls fn with every opinion in order of ID, as
+	// WriterRepository's ForEach does with writers.
+	ForEach(fn func(*domain.Opinion) error) error
 	Update(opinion *domain.Opinion) error
-	Delete(writerID, workID uint64) error
+	Delete(id uint64) error
 }
@@ -2,205 +2,505 @@ package repository_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/what-writers-like/backend/internal/domain"
-	"github.com/what-writers-like/backend/internal/infrastructure/database"
 	"github.com/what-writers-like/backend/internal/repository"
 	"github.com/what-writers-like/backend/internal/repository/gorm"
 	"github.com/what-writers-like/backend/internal/testutils"
 )
 
-type testRepos struct {
-	writerRepo  repository.WriterRepository
-	workRepo    repository.WorkRepository
-	opinionRepo repository.OpinionRepository
-}
-
-func setupTestData(
-	t *testing.T,
-	db *database.Database,
-) (*testRepos, *domain.Writer, *domain.Writer, *domain.Work, *domain.Opinion) {
-	writerRepo := gorm.NewWriterRepository(db)
-	workRepo := gorm.NewWorkRepository(db)
-	opinionRepo := gorm.NewOpinionRepository(db)
-
+func setupTestData(t *testing.T, repos *testRepos) (*domain.Writer, *domain.Writer, *domain.Work, *domain.Opinion) {
 	writer1 := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
 	writer2 := domain.NewWriter(2, "Charlotte Bronte", 1816, nil, nil)
-	require.NoError(t, writerRepo.Create(writer1))
-	require.NoError(t, writerRepo.Create(writer2))
+	require.NoError(t, repos.writerRepo.Create(writer1))
+	require.NoError(t, repos.writerRepo.Create(writer2))
 
-	work := domain.NewWork(1, "Pride and Prejudice", 1)
-	require.NoError(t, workRepo.Create(work))
+	work := domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})
+	require.NoError(t, repos.workRepo.Create(work))
 
-	opinion := domain.NewOpinion(2, 1, true, "A delightful novel", "Personal Letters", nil, nil)
-	require.NoError(t, opinionRepo.Create(opinion))
+	opinion := domain.NewOpinion(0, 2, 1, domain.SentimentPositive, "A delightful novel", "Personal Letters", nil, nil)
+	require.NoError(t, repos.opinionRepo.Create(opinion))
 
-	return &testRepos{
-		writerRepo:  writerRepo,
-		workRepo:    workRepo,
-		opinionRepo: opinionRepo,
-	}, writer1, writer2, work, opinion
+	return writer1, writer2, work, opinion
 }
 
 func TestOpinionRepository_Create(t *testing.T) {
 	t.Parallel()
-	db, cleanup := testutils.SetupTestDB(t)
-	defer cleanup()
-
-	writerRepo := gorm.NewWriterRepository(db)
-	workRepo := gorm.NewWorkRepository(db)
-	opinionRepo := gorm.NewOpinionRepository(db)
-
-	writer1 := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
-	writer2 := domain.NewWriter(2, "Charlotte Bronte", 1816, nil, nil)
-	require.NoError(t, writerRepo.Create(writer1))
-	require.NoError(t, writerRepo.Create(writer2))
-
-	work := domain.NewWork(1, "Pride and Prejudice", 1)
-	require.NoError(t, workRepo.Create(work))
-
-	opinion := domain.NewOpinion(2, 1, true, "A delightful novel", "Personal Letters", nil, nil)
-	err := opinionRepo.Create(opinion)
-	assert.NoError(t, err)
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		writer1 := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
+		writer2 := domain.NewWriter(2, "Charlotte Bronte", 1816, nil, nil)
+		require.NoError(t, repos.writerRepo.Create(writer1))
+		require.NoError(t, repos.writerRepo.Create(writer2))
+
+		work := domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})
+		require.NoError(t, repos.workRepo.Create(work))
+
+		opinion := domain.NewOpinion(0, 2, 1, domain.SentimentPositive, "A delightful novel", "Personal Letters", nil, nil)
+		err := repos.opinionRepo.Create(opinion)
+		assert.NoError(t, err)
+		assert.NotZero(t, opinion.ID())
+
+		// A writer may make several statements about the same work
+		later := domain.NewOpinion(0, 2, 1, domain.SentimentNegative, "Overrated", "Another Source", nil, nil)
+		require.NoError(t, repos.opinionRepo.Create(later))
+		assert.Greater(t, later.ID(), opinion.ID())
+
+		// Explicit IDs are kept, and the next allocated one follows them
+		explicit := domain.NewOpinion(10, 2, 1, domain.SentimentPositive, "Reconsidered", "Diary", nil, nil)
+		require.NoError(t, repos.opinionRepo.Create(explicit))
+		next := domain.NewOpinion(0, 2, 1, domain.SentimentPositive, "Once more", "Diary", nil, nil)
+		require.NoError(t, repos.opinionRepo.Create(next))
+		assert.Equal(t, uint64(11), next.ID())
+
+		err = repos.opinionRepo.Create(domain.NewOpinion(10, 2, 1, domain.SentimentPositive, "Duplicate", "Diary", nil, nil))
+		assert.Error(t, err)
+	})
 }
 
 func TestOpinionRepository_GetByWriterID(t *testing.T) {
 	t.Parallel()
-	db, cleanup := testutils.SetupTestDB(t)
-	defer cleanup()
-
-	repos, _, _, _, opinion := setupTestData(t, db)
-
-	opinions, err := repos.opinionRepo.GetByWriterID(2)
-	require.NoError(t, err)
-	assert.Len(t, opinions, 1)
-	assert.Equal(t, opinion.WriterID(), opinions[0].WriterID())
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		_, _, _, opinion := setupTestData(t, repos)
+
+		opinions, err := repos.opinionRepo.GetByWriterID(2)
+		require.NoError(t, err)
+		assert.Len(t, opinions, 1)
+		assert.Equal(t, opinion.WriterID(), opinions[0].WriterID())
+	})
 }
 
 func TestOpinionRepository_GetByWorkID(t *testing.T) {
 	t.Parallel()
-	db, cleanup := testutils.SetupTestDB(t)
-	defer cleanup()
-
-	repos, _, _, _, opinion := setupTestData(t, db)
-
-	opinions, err := repos.opinionRepo.GetByWorkID(1)
-	require.NoError(t, err)
-	assert.Len(t, opinions, 1)
-	assert.Equal(t, opinion.WorkID(), opinions[0].WorkID())
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		_, _, _, opinion := setupTestData(t, repos)
+
+		opinions, err := repos.opinionRepo.GetByWorkID(1)
+		require.NoError(t, err)
+		assert.Len(t, opinions, 1)
+		assert.Equal(t, opinion.WorkID(), opinions[0].WorkID())
+	})
 }
 
 func TestOpinionRepository_GetByWriterAndWork(t *testing.T) {
 	t.Parallel()
-	db, cleanup := testutils.SetupTestDB(t)
-	defer cleanup()
-
-	writerRepo := gorm.NewWriterRepository(db)
-	workRepo := gorm.NewWorkRepository(db)
-	opinionRepo := gorm.NewOpinionRepository(db)
-
-	writer1 := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
-	writer2 := domain.NewWriter(2, "Charlotte Bronte", 1816, nil, nil)
-	require.NoError(t, writerRepo.Create(writer1))
-	require.NoError(t, writerRepo.Create(writer2))
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		_, _, _, opinion := setupTestData(t, repos)
+
+		year1849, year1855 := 1849, 1855
+		later := domain.NewOpinion(0, 2, 1, domain.SentimentNegative, "Later", "Letters", nil, &year1855)
+		earlier := domain.NewOpinion(0, 2, 1, domain.SentimentPositive, "Earlier", "Letters", nil, &year1849)
+		require.NoError(t, repos.opinionRepo.Create(later))
+		require.NoError(t, repos.opinionRepo.Create(earlier))
+
+		// Dated statements come first, in chronological order
+		found, err := repos.opinionRepo.GetByWriterAndWork(2, 1)
+		require.NoError(t, err)
+		require.Len(t, found, 3)
+		assert.Equal(t, earlier.ID(), found[0].ID())
+		assert.Equal(t, later.ID(), found[1].ID())
+		assert.Equal(t, opinion.ID(), found[2].ID())
+
+		found, err = repos.opinionRepo.GetByWriterAndWork(1, 1)
+		require.NoError(t, err)
+		assert.Empty(t, found)
+	})
+}
 
-	work := domain.NewWork(1, "Pride and Prejudice", 1)
-	require.NoError(t, workRepo.Create(work))
+func TestOpinionRepository_GetByID(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		_, _, _, opinion := setupTestData(t, repos)
 
-	opinion := domain.NewOpinion(2, 1, true, "A delightful novel", "Personal Letters", nil, nil)
-	require.NoError(t, opinionRepo.Create(opinion))
+		found, err := repos.opinionRepo.GetByID(opinion.ID())
+		require.NoError(t, err)
+		assert.Equal(t, opinion.WriterID(), found.WriterID())
+		assert.Equal(t, opinion.Quote(), found.Quote())
 
-	found, err := opinionRepo.GetByWriterAndWork(2, 1)
-	require.NoError(t, err)
-	assert.Equal(t, opinion.WriterID(), found.WriterID())
-	assert.Equal(t, opinion.WorkID(), found.WorkID())
+		_, err = repos.opinionRepo.GetByID(opinion.ID() + 1)
+		require.Error(t, err)
+	})
 }
 
 func TestOpinionRepository_List(t *testing.T) {
 	t.Parallel()
-	db, cleanup := testutils.SetupTestDB(t)
-	defer cleanup()
-
-	writerRepo := gorm.NewWriterRepository(db)
-	workRepo := gorm.NewWorkRepository(db)
-	opinionRepo := gorm.NewOpinionRepository(db)
-
-	writer1 := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
-	writer2 := domain.NewWriter(2, "Charlotte Bronte", 1816, nil, nil)
-	require.NoError(t, writerRepo.Create(writer1))
-	require.NoError(t, writerRepo.Create(writer2))
-
-	work := domain.NewWork(1, "Pride and Prejudice", 1)
-	require.NoError(t, workRepo.Create(work))
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		setupTestData(t, repos)
 
-	work2 := domain.NewWork(2, "Emma", 1)
-	require.NoError(t, workRepo.Create(work2))
+		work2 := domain.NewWork(2, "Emma", []uint64{1}, domain.WorkDetails{})
+		require.NoError(t, repos.workRepo.Create(work2))
 
-	opinion1 := domain.NewOpinion(2, 1, true, "A delightful novel", "Personal Letters", nil, nil)
-	opinion2 := domain.NewOpinion(2, 2, false, "Overrated", "Another Source", nil, nil)
-	require.NoError(t, opinionRepo.Create(opinion1))
-	require.NoError(t, opinionRepo.Create(opinion2))
+		opinion2 := domain.NewOpinion(0, 2, 2, domain.SentimentNegative, "Overrated", "Another Source", nil, nil)
+		require.NoError(t, repos.opinionRepo.Create(opinion2))
 
-	opinions, err := opinionRepo.List(10, 0)
-	require.NoError(t, err)
-	assert.Len(t, opinions, 2)
+		page, err := repos.opinionRepo.List(repository.OpinionFilter{}, repository.OpinionSort{}, nil, 10)
+		require.NoError(t, err)
+		assert.Len(t, page.Items, 2)
+	})
 }
 
 func TestOpinionRepository_Update(t *testing.T) {
 	t.Parallel()
-	db, cleanup := testutils.SetupTestDB(t)
-	defer cleanup()
-
-	writerRepo := gorm.NewWriterRepository(db)
-	workRepo := gorm.NewWorkRepository(db)
-	opinionRepo := gorm.NewOpinionRepository(db)
-
-	writer1 := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
-	writer2 := domain.NewWriter(2, "Charlotte Bronte", 1816, nil, nil)
-	require.NoError(t, writerRepo.Create(writer1))
-	require.NoError(t, writerRepo.Create(writer2))
-
-	work := domain.NewWork(1, "Pride and Prejudice", 1)
-	require.NoError(t, workRepo.Create(work))
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		_, _, _, opinion := setupTestData(t, repos)
+
+		updated := domain.NewOpinion(
+			opinion.ID(), 2, 1, domain.SentimentNegative, "Actually, it's overrated", "Personal Letters", nil, nil,
+		)
+		err := repos.opinionRepo.Update(updated)
+		require.NoError(t, err)
+
+		found, err := repos.opinionRepo.GetByID(opinion.ID())
+		require.NoError(t, err)
+		assert.Equal(t, domain.SentimentNegative, found.Sentiment())
+		assert.Equal(t, "Actually, it's overrated", found.Quote())
+	})
+}
 
-	opinion := domain.NewOpinion(2, 1, true, "A delightful novel", "Personal Letters", nil, nil)
-	require.NoError(t, opinionRepo.Create(opinion))
+func TestOpinionRepository_Delete(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		_, _, _, opinion := setupTestData(t, repos)
 
-	updated := domain.NewOpinion(2, 1, false, "Actually, it's overrated", "Personal Letters", nil, nil)
-	err := opinionRepo.Update(updated)
-	require.NoError(t, err)
+		err := repos.opinionRepo.Delete(opinion.ID())
+		require.NoError(t, err)
 
-	found, err := opinionRepo.GetByWriterAndWork(2, 1)
-	require.NoError(t, err)
-	assert.False(t, found.Sentiment())
-	assert.Equal(t, "Actually, it's overrated", found.Quote())
+		_, err = repos.opinionRepo.GetByID(opinion.ID())
+		require.Error(t, err)
+	})
 }
 
-func TestOpinionRepository_Delete(t *testing.T) {
+func TestOpinionRepository_Translations(t *testing.T) {
 	t.Parallel()
-	db, cleanup := testutils.SetupTestDB(t)
-	defer cleanup()
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		_, _, _, opinion := setupTestData(t, repos)
+
+		translator, source := "Constance Garnett", "Heinemann, 1912"
+		other := domain.NewOpinion(0, 2, 1, domain.SentimentNegative, "Скучно", "Letters", nil, nil)
+		other.SetLanguages(domain.QuoteLanguages{
+			Language: "ru",
+			Translations: []domain.Translation{
+				{Language: "fr", Text: "Ennuyeux"},
+				{Language: "en", Text: "Tedious", Translator: &translator, Source: &source},
+			},
+		})
+		require.NoError(t, repos.opinionRepo.Create(other))
+
+		// Translations keep the order they were given in
+		found, err := repos.opinionRepo.GetByID(other.ID())
+		require.NoError(t, err)
+		assert.Equal(t, other.Languages(), found.Languages())
+
+		opinions, err := repos.opinionRepo.GetByWriterAndWork(2, 1)
+		require.NoError(t, err)
+		require.Len(t, opinions, 2)
+		assert.Equal(t, domain.QuoteLanguages{}, opinions[0].Languages())
+		assert.Equal(t, other.Languages(), opinions[1].Languages())
+
+		// Updating replaces the translations
+		other.SetLanguages(domain.QuoteLanguages{
+			Language:     "ru",
+			Translations: []domain.Translation{{Language: "de", Text: "Langweilig"}},
+		})
+		require.NoError(t, repos.opinionRepo.Update(other))
+		found, err = repos.opinionRepo.GetByID(other.ID())
+		require.NoError(t, err)
+		assert.Equal(t, other.Languages(), found.Languages())
+
+		require.NoError(t, repos.opinionRepo.Delete(other.ID()))
+		found, err = repos.opinionRepo.GetByID(opinion.ID())
+		require.NoError(t, err)
+		assert.Empty(t, found.Languages().Translations)
+	})
+}
 
-	writerRepo := gorm.NewWriterRepository(db)
-	workRepo := gorm.NewWorkRepository(db)
-	opinionRepo := gorm.NewOpinionRepository(db)
+func TestOpinionRepository_ForEach(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		_, _, _, opinion := setupTestData(t, repos)
+		other := domain.NewOpinion(0, 2, 1, domain.SentimentNegative, "Скучно", "Letters", nil, nil)
+		other.SetLanguages(domain.QuoteLanguages{
+			Language:     "ru",
+			Translations: []domain.Translation{{Language: "en", Text: "Tedious"}},
+		})
+		require.NoError(t, repos.opinionRepo.Create(other))
+
+		var opinions []*domain.Opinion
+		require.NoError(t, repos.opinionRepo.ForEach(func(o *domain.Opinion) error {
+			opinions = append(opinions, o)
+			return nil
+		}))
+		require.Len(t, opinions, 2)
+		assert.Equal(t, opinion.ID(), opinions[0].ID())
+		assert.Equal(t, other.Languages(), opinions[1].Languages())
+	})
+}
 
-	writer1 := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
-	writer2 := domain.NewWriter(2, "Charlotte Bronte", 1816, nil, nil)
-	require.NoError(t, writerRepo.Create(writer1))
-	require.NoError(t, writerRepo.Create(writer2))
+func TestOpinionRepository_Find(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		setupTestData(t, repos)
+
+		require.NoError(t, repos.workRepo.Create(domain.NewWork(2, "Emma", []uint64{1}, domain.WorkDetails{})))
+		opinion2 := domain.NewOpinion(0, 2, 2, domain.SentimentNegative, "Overrated", "Another Source", nil, nil)
+		require.NoError(t, repos.opinionRepo.Create(opinion2))
+
+		opinions, err := repos.opinionRepo.Find(repository.OpinionFilter{})
+		require.NoError(t, err)
+		assert.Len(t, opinions, 2)
+
+		opinions, err = repos.opinionRepo.Find(repository.OpinionFilter{WorkIDs: []uint64{2}})
+		require.NoError(t, err)
+		require.Len(t, opinions, 1)
+		assert.Equal(t, "Overrated", opinions[0].Quote())
+
+		positive := []domain.Sentiment{domain.SentimentPositive, domain.SentimentVeryPositive}
+		opinions, err = repos.opinionRepo.Find(repository.OpinionFilter{WriterIDs: []uint64{2}, Sentiments: positive})
+		require.NoError(t, err)
+		require.Len(t, opinions, 1)
+		assert.Equal(t, uint64(1), opinions[0].WorkID())
+	})
+}
 
-	work := domain.NewWork(1, "Pride and Prejudice", 1)
-	require.NoError(t, workRepo.Create(work))
+func TestOpinionRepository_ListFiltered(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		setupTestData(t, repos)
+		year := func(y int) *int { return &y }
+
+		require.NoError(t, repos.writerRepo.Create(domain.NewWriter(3, "Charles Dickens", 1812, nil, nil)))
+		require.NoError(t, repos.workRepo.Create(domain.NewWork(2, "Bleak House", []uint64{3}, domain.WorkDetails{})))
+		source := domain.NewSource(0, domain.SourceTypeLetter, "Letters", domain.SourceDetails{}, true)
+		require.NoError(t, repos.sourceRepo.Create(source))
+
+		create := func(writerID, workID uint64, sentiment domain.Sentiment, statementYear *int) *domain.Opinion {
+			opinion := domain.NewOpinion(0, writerID, workID, sentiment, "Quote", "Source", nil, statementYear)
+			require.NoError(t, repos.opinionRepo.Create(opinion))
+			return opinion
+		}
+		aboutBleakHouse := create(1, 2, domain.SentimentVeryNegative, year(1853))
+		early := create(2, 2, domain.SentimentPositive, year(1850))
+		undated := create(2, 2, domain.SentimentMixed, nil)
+		aboutAusten := domain.NewWriterOpinion(0, 3, 1, domain.SentimentPositive, "Quote", "", nil, year(1840))
+		aboutAusten.SetSourceID(source.ID())
+		require.NoError(t, repos.opinionRepo.Create(aboutAusten))
+
+		// ids walks the listing two at a time, so every order is also
+		// checked across page boundaries
+		ids := func(filter repository.OpinionFilter, sort repository.OpinionSort) []uint64 {
+			var result []uint64
+			var after *repository.OpinionCursor
+			for {
+				page, err := repos.opinionRepo.List(filter, sort, after, 2)
+				require.NoError(t, err)
+				for _, o := range page.Items {
+					result = append(result, o.ID())
+				}
+				if page.Next == nil {
+					assert.Equal(t, int64(len(result)), page.Total)
+					return result
+				}
+				after = page.Next
+			}
+		}
+		none := repository.OpinionFilter{}
+		byYear := repository.OpinionSort{Key: repository.OpinionSortStatementYear}
+
+		// setupTestData's opinion is undated; undated statements come last
+		// in either direction
+		first := uint64(1)
+		assert.Equal(t, []uint64{aboutAusten.ID(), early.ID(), aboutBleakHouse.ID(), first, undated.ID()}, ids(none, byYear))
+		byYear.Descending = true
+		assert.Equal(t, []uint64{aboutBleakHouse.ID(), early.ID(), aboutAusten.ID(), first, undated.ID()}, ids(none, byYear))
+
+		dated := repository.OpinionFilter{StatementYearFrom: year(1845), StatementYearTo: year(1853)}
+		assert.Equal(t, []uint64{aboutBleakHouse.ID(), early.ID()}, ids(dated, repository.OpinionSort{}))
+
+		// Opinions about writers have no work, so no work author
+		byDickens := repository.OpinionFilter{WorkAuthorIDs: []uint64{3}}
+		assert.Equal(t, []uint64{aboutBleakHouse.ID(), early.ID(), undated.ID()}, ids(byDickens, repository.OpinionSort{}))
+
+		filter := repository.OpinionFilter{
+			WriterIDs:     []uint64{2},
+			WorkAuthorIDs: []uint64{3},
+			Sentiments:    []domain.Sentiment{domain.SentimentPositive, domain.SentimentMixed},
+		}
+		assert.Equal(t, []uint64{early.ID(), undated.ID()}, ids(filter, repository.OpinionSort{}))
+		assert.Equal(t, []uint64{aboutAusten.ID()}, ids(repository.OpinionFilter{SourceIDs: []uint64{source.ID()}}, byYear))
+	})
+}
 
-	opinion := domain.NewOpinion(2, 1, true, "A delightful novel", "Personal Letters", nil, nil)
-	require.NoError(t, opinionRepo.Create(opinion))
+func TestOpinionRepository_WriterOpinions(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		setupTestData(t, repos)
+		require.NoError(t, repos.writerRepo.Create(domain.NewWriter(3, "Charles Dickens", 1812, nil, nil)))
+
+		year := 1850
+		opinion := domain.NewWriterOpinion(0, 2, 1, domain.SentimentVeryPositive, "A genius", "Letters", nil, &year)
+		require.NoError(t, repos.opinionRepo.Create(opinion))
+		require.NoError(t, repos.opinionRepo.Create(
+			domain.NewWriterOpinion(0, 3, 1, domain.SentimentMixed, "Uneven", "Diary", nil, nil),
+		))
+
+		found, err := repos.opinionRepo.GetByID(opinion.ID())
+		require.NoError(t, err)
+		assert.True(t, found.IsAboutWriter())
+		assert.Equal(t, uint64(1), found.TargetWriterID())
+		assert.Zero(t, found.WorkID())
+
+		about, err := repos.opinionRepo.GetByTargetWriterID(1)
+		require.NoError(t, err)
+		require.Len(t, about, 2)
+		assert.Equal(t, opinion.ID(), about[0].ID())
+
+		pair, err := repos.opinionRepo.GetByWriterAndTargetWriter(2, 1)
+		require.NoError(t, err)
+		require.Len(t, pair, 1)
+		assert.Equal(t, "A genius", pair[0].Quote())
+
+		// Opinions about the writer are not about their works
+		byWork, err := repos.opinionRepo.GetByWorkID(1)
+		require.NoError(t, err)
+		assert.Len(t, byWork, 1)
+		byPair, err := repos.opinionRepo.GetByWriterAndWork(2, 1)
+		require.NoError(t, err)
+		assert.Len(t, byPair, 1)
+
+		// Work opinions come before writer opinions of the same writer
+		byWriter, err := repos.opinionRepo.Find(repository.OpinionFilter{WriterIDs: []uint64{2}})
+		require.NoError(t, err)
+		require.Len(t, byWriter, 2)
+		assert.False(t, byWriter[0].IsAboutWriter())
+		assert.True(t, byWriter[1].IsAboutWriter())
+
+		// Given both kinds of target, an opinion about either matches
+		either, err := repos.opinionRepo.Find(repository.OpinionFilter{
+			WriterIDs: []uint64{2}, WorkIDs: []uint64{1}, TargetWriterIDs: []uint64{1},
+		})
+		require.NoError(t, err)
+		assert.Len(t, either, 2)
+		targets, err := repos.opinionRepo.Find(repository.OpinionFilter{TargetWriterIDs: []uint64{1}})
+		require.NoError(t, err)
+		assert.Len(t, targets, 2)
+
+		// Updates keep the target
+		require.NoError(t, repos.opinionRepo.Update(opinion.Revise(domain.SentimentPositive, "Fine", "Letters", nil, nil)))
+		found, err = repos.opinionRepo.GetByID(opinion.ID())
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), found.TargetWriterID())
+		assert.Equal(t, "Fine", found.Quote())
+
+		// Revisions keep it as well
+		revision := domain.NewOpinionRevision(0, found, "alice", time.Now())
+		require.NoError(t, repos.opinionRevisionRepo.Create(revision))
+		stored, err := repos.opinionRevisionRepo.Get(opinion.ID(), revision.Number())
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), stored.Opinion().TargetWriterID())
+	})
+}
 
-	err := opinionRepo.Delete(2, 1)
-	require.NoError(t, err)
+func TestOpinionRepository_SearchQuotes(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		setupTestData(t, repos)
+
+		create := func(quote, language string) *domain.Opinion {
+			opinion := domain.NewOpinion(0, 2, 1, domain.SentimentNegative, quote, "Letters", nil, nil)
+			opinion.SetLanguages(domain.QuoteLanguages{Language: language})
+			require.NoError(t, repos.opinionRepo.Create(opinion))
+			return opinion
+		}
+		twice := create("Vulgar, vulgar beyond words", "")
+		once := create("A vulgar and tedious book", "")
+		russian := create("Какая пошлость", "ru")
+		tedious := create("Tedious beyond words", "")
+
+		ids := func(matches []*domain.QuoteMatch) []uint64 {
+			result := make([]uint64, len(matches))
+			for i, m := range matches {
+				result[i] = m.Opinion.ID()
+			}
+			return result
+		}
+
+		// More occurrences rank higher
+		matches, err := repos.opinionRepo.SearchQuotes("vulgar", "", 10, 0)
+		require.NoError(t, err)
+		require.Equal(t, []uint64{twice.ID(), once.ID()}, ids(matches))
+		assert.Greater(t, matches[0].Rank, matches[1].Rank)
+		assert.Equal(t, once.Quote(), matches[1].Opinion.Quote())
+		assert.Equal(t, []domain.TextSegment{
+			{Text: "A "},
+			{Text: "vulgar", Match: true},
+			{Text: " and tedious book"},
+		}, matches[1].Snippet)
+
+		paged, err := repos.opinionRepo.SearchQuotes("vulgar", "", 1, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{once.ID()}, ids(paged))
+
+		matches, err = repos.opinionRepo.SearchQuotes(`"beyond words"`, "", 10, 0)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []uint64{twice.ID(), tedious.ID()}, ids(matches))
+		matches, err = repos.opinionRepo.SearchQuotes(`"words beyond"`, "", 10, 0)
+		require.NoError(t, err)
+		assert.Empty(t, matches)
+
+		matches, err = repos.opinionRepo.SearchQuotes("vulgar -tedious", "", 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{twice.ID()}, ids(matches))
+
+		matches, err = repos.opinionRepo.SearchQuotes("vulgar or tedious", "", 10, 0)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []uint64{twice.ID(), once.ID(), tedious.ID()}, ids(matches))
+
+		matches, err = repos.opinionRepo.SearchQuotes("пошлость", "ru", 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{russian.ID()}, ids(matches))
+		matches, err = repos.opinionRepo.SearchQuotes("пошлость", "en", 10, 0)
+		require.NoError(t, err)
+		assert.Empty(t, matches)
+	})
+}
 
-	_, err = opinionRepo.GetByWriterAndWork(2, 1)
-	require.Error(t, err)
+// Stemming needs PostgreSQL's text search configurations, which the other
+// backends go without.
+func TestOpinionRepository_SearchQuotesStemming(t *testing.T) {
+	t.Parallel()
+	t.Run("postgres", func(t *testing.T) {
+		t.Parallel()
+		db, cleanup := testutils.SetupTestDB(t)
+		defer cleanup()
+		repos := &testRepos{
+			writerRepo:  gorm.NewWriterRepository(db),
+			workRepo:    gorm.NewWorkRepository(db),
+			opinionRepo: gorm.NewOpinionRepository(db),
+		}
+		setupTestData(t, repos)
+
+		english := domain.NewOpinion(0, 2, 1, domain.SentimentNegative, "Such vulgarity!", "Letters", nil, nil)
+		english.SetLanguages(domain.QuoteLanguages{Language: "en"})
+		require.NoError(t, repos.opinionRepo.Create(english))
+		russian := domain.NewOpinion(0, 2, 1, domain.SentimentNegative, "Сколько пошлости!", "Letters", nil, nil)
+		russian.SetLanguages(domain.QuoteLanguages{Language: "ru"})
+		require.NoError(t, repos.opinionRepo.Create(russian))
+
+		matches, err := repos.opinionRepo.SearchQuotes("vulgar", "", 10, 0)
+		require.NoError(t, err)
+		require.Len(t, matches, 1)
+		assert.Equal(t, english.ID(), matches[0].Opinion.ID())
+		assert.Equal(t, []domain.TextSegment{
+			{Text: "Such "},
+			{Text: "vulgarity", Match: true},
+			{Text: "!"},
+		}, matches[0].Snippet)
+
+		matches, err = repos.opinionRepo.SearchQuotes("пошлость", "", 10, 0)
+		require.NoError(t, err)
+		require.Len(t, matches, 1)
+		assert.Equal(t, russian.ID(), matches[0].Opinion.ID())
+	})
 }
@@ -0,0 +1,15 @@
+package repository
+
+import "github.com/what-writers-like/backend/internal/domain"
+
+// OpinionRevisionRepository stores the history of each opinion. Revisions
+// are never modified and outlive the opinion they belong to.
+type OpinionRevisionRepository interface {
+	// Create stores revision under the next free number for its opinion,
+	// and sets that number on revision. Call it in the transaction that
+	// writes the opinion, after the write.
+	Create(revision *domain.OpinionRevision) error
+	// ListByOpinion returns the revisions of an opinion, oldest first.
+	ListByOpinion(opinionID uint64) ([]*domain.OpinionRevision, error)
+	Get(opinionID uint64, number int) (*domain.OpinionRevision, error)
+}
@@ -0,0 +1,75 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/what-writers-like/backend/internal/domain"
+)
+
+func TestOpinionRevisionRepository(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+		page := "12"
+		first := domain.NewOpinionRevision(0,
+			domain.NewOpinion(7, 2, 1, domain.SentimentPositive, "Quote", "Letters", nil, nil), "alice", at)
+		second := domain.NewOpinionRevision(0,
+			domain.NewOpinion(7, 2, 1, domain.SentimentNegative, "Quote, corrected", "Letters", &page, nil),
+			"bob", at.Add(time.Minute))
+		other := domain.NewOpinionRevision(0,
+			domain.NewOpinion(8, 2, 1, domain.SentimentPositive, "Other", "Diary", nil, nil), "alice", at)
+
+		require.NoError(t, repos.opinionRevisionRepo.Create(first))
+		require.NoError(t, repos.opinionRevisionRepo.Create(second))
+		require.NoError(t, repos.opinionRevisionRepo.Create(other))
+		assert.Equal(t, 1, first.Number())
+		assert.Equal(t, 2, second.Number())
+		assert.Equal(t, 1, other.Number())
+
+		revisions, err := repos.opinionRevisionRepo.ListByOpinion(7)
+		require.NoError(t, err)
+		require.Len(t, revisions, 2)
+		assert.Equal(t, 1, revisions[0].Number())
+		assert.Equal(t, uint64(7), revisions[0].Opinion().ID())
+		assert.Equal(t, "Quote", revisions[0].Opinion().Quote())
+		assert.Equal(t, "alice", revisions[0].Actor())
+		assert.True(t, at.Equal(revisions[0].CreatedAt()))
+		assert.Equal(t, "Quote, corrected", revisions[1].Opinion().Quote())
+		assert.Equal(t, &page, revisions[1].Opinion().Page())
+
+		revision, err := repos.opinionRevisionRepo.Get(7, 2)
+		require.NoError(t, err)
+		assert.Equal(t, domain.SentimentNegative, revision.Opinion().Sentiment())
+		assert.Equal(t, "bob", revision.Actor())
+
+		_, err = repos.opinionRevisionRepo.Get(7, 3)
+		require.Error(t, err)
+
+		revisions, err = repos.opinionRevisionRepo.ListByOpinion(9)
+		require.NoError(t, err)
+		assert.Empty(t, revisions)
+	})
+}
+
+func TestOpinionRevisionRepository_Translations(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		translator := "Richard Pevear"
+		opinion := domain.NewOpinion(7, 2, 1, domain.SentimentPositive, "Гений", "Letters", nil, nil)
+		opinion.SetLanguages(domain.QuoteLanguages{
+			Language: "ru",
+			Translations: []domain.Translation{
+				{Language: "en", Text: "A genius", Translator: &translator},
+				{Language: "fr", Text: "Un génie"},
+			},
+		})
+		require.NoError(t, repos.opinionRevisionRepo.Create(domain.NewOpinionRevision(0, opinion, "alice", time.Now())))
+
+		revision, err := repos.opinionRevisionRepo.Get(7, 1)
+		require.NoError(t, err)
+		assert.Equal(t, opinion.Languages(), revision.Opinion().Languages())
+	})
+}
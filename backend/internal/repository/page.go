@@ -0,0 +1,34 @@
+package repository
+
+// Page is one page of a listing: its items, the number of items in the
+// whole listing, and where the next page starts, nil after the last page.
+// Sorted listings are paged by a cursor taken from the last item, so rows
+// added or removed meanwhile do not shift later pages; ranked search
+// results are paged by offset.
+type Page[T, C any] struct {
+	Items []T
+	Total int64
+	Next  *C
+}
+
+// NewPage makes a page of a sorted listing from up to limit+1 items, the
+// one past the limit showing that there is more: it is dropped, and Next
+// becomes the cursor of the last item kept. limit must be positive.
+func NewPage[T, C any](items []T, total int64, limit int, cursorOf func(T) C) *Page[T, C] {
+	page := &Page[T, C]{Items: items, Total: total}
+	if len(items) > limit {
+		next := cursorOf(items[limit-1])
+		page.Items, page.Next = items[:limit], &next
+	}
+	return page
+}
+
+// NewOffsetPage makes a page of ranked results that starts at offset in
+// a listing of total items.
+func NewOffsetPage[T any](items []T, total int64, offset int) *Page[T, int] {
+	page := &Page[T, int]{Items: items, Total: total}
+	if next := offset + len(items); len(items) > 0 && int64(next) < total {
+		page.Next = &next
+	}
+	return page
+}
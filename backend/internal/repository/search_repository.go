@@ -0,0 +1,13 @@
+package repository
+
+import "github.com/what-writers-like/backend/internal/domain"
+
+// SearchRepository searches writers (by name, alias and bio), works (by
+// title and original title) and opinions (by quote and source citation)
+// together.
+type SearchRepository interface {
+	// Search returns hits best first, each entity once under the field that
+	// matched it best. Non-empty types restricts the hits to those kinds of
+	// entity. Hits leave MatchStart and MatchEnd unset.
+	Search(query string, types []domain.SearchHitType, limit, offset int) ([]*domain.SearchHit, error)
+}
@@ -0,0 +1,83 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/what-writers-like/backend/internal/domain"
+)
+
+func TestSearchRepository_Search(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		bio := "Russian novelist who read Austen late in life"
+		original := "Война и мир"
+		require.NoError(t, repos.writerRepo.Create(domain.NewWriter(1, "Jane Austen", 1775, nil, nil)))
+		require.NoError(t, repos.writerRepo.Create(domain.NewWriter(2, "Leo Tolstoy", 1828, nil, &bio)))
+		require.NoError(t, repos.writerRepo.Create(domain.NewWriter(3, "Maxim Gorky", 1868, nil, nil)))
+		require.NoError(t, repos.writerAliasRepo.Create(
+			domain.NewWriterAlias(0, 3, "Alexei Peshkov", domain.AliasKindBirthName),
+		))
+		require.NoError(t, repos.workRepo.Create(
+			domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{}),
+		))
+		require.NoError(t, repos.workRepo.Create(
+			domain.NewWork(2, "War and Peace", []uint64{2}, domain.WorkDetails{OriginalTitle: &original}),
+		))
+		require.NoError(t, repos.opinionRepo.Create(
+			domain.NewOpinion(0, 2, 1, domain.SentimentNegative, "Austen is tedious", "Diary", nil, nil),
+		))
+
+		// Equal scores put writers before opinions, and names before bios
+		hits, err := repos.searchRepo.Search("austen", nil, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, hits, 3)
+		assert.Equal(t, domain.SearchHitWriter, hits[0].Type)
+		assert.Equal(t, uint64(1), hits[0].ID)
+		assert.Equal(t, "name", hits[0].Field)
+		assert.Equal(t, "Jane Austen", hits[0].Text)
+		assert.InDelta(t, 1.0, hits[0].Score, 0.0001)
+		assert.Equal(t, uint64(2), hits[1].ID)
+		assert.Equal(t, "Leo Tolstoy", hits[1].Label)
+		assert.Equal(t, "bio", hits[1].Field)
+		assert.Equal(t, bio, hits[1].Text)
+		assert.Equal(t, domain.SearchHitOpinion, hits[2].Type)
+		assert.Equal(t, "quote", hits[2].Field)
+		assert.Equal(t, "Austen is tedious", hits[2].Label)
+
+		types := []domain.SearchHitType{domain.SearchHitWork, domain.SearchHitOpinion}
+		filtered, err := repos.searchRepo.Search("austen", types, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, filtered, 1)
+		assert.Equal(t, hits[2], filtered[0])
+
+		paged, err := repos.searchRepo.Search("austen", nil, 1, 1)
+		require.NoError(t, err)
+		require.Len(t, paged, 1)
+		assert.Equal(t, hits[1], paged[0])
+
+		hits, err = repos.searchRepo.Search("Peshkov", nil, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, hits, 1)
+		assert.Equal(t, "Maxim Gorky", hits[0].Label)
+		assert.Equal(t, "alias", hits[0].Field)
+		assert.Equal(t, "Alexei Peshkov", hits[0].Text)
+
+		hits, err = repos.searchRepo.Search("война", nil, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, hits, 1)
+		assert.Equal(t, domain.SearchHitWork, hits[0].Type)
+		assert.Equal(t, "War and Peace", hits[0].Label)
+		assert.Equal(t, "original_title", hits[0].Field)
+
+		hits, err = repos.searchRepo.Search("diary", nil, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, hits, 1)
+		assert.Equal(t, "source", hits[0].Field)
+
+		hits, err = repos.searchRepo.Search("Dostoevsky", nil, 10, 0)
+		require.NoError(t, err)
+		assert.Empty(t, hits)
+	})
+}
@@ -0,0 +1,17 @@
+package repository
+
+import "github.com/what-writers-like/backend/internal/domain"
+
+type SourceRepository interface {
+	Create(source *domain.Source) error
+	GetByID(id uint64) (*domain.Source, error)
+	List(limit, offset int) ([]*domain.Source, error)
+	// ListCandidates returns the sources still awaiting a curator's
+	// confirmation, such as those grouped from free-text citations.
+	ListCandidates(limit, offset int) ([]*domain.Source, error)
+	// ForEach calls fn with every source in order of ID, as
+	// WriterRepository's ForEach does with writers.
+	ForEach(fn func(*domain.Source) error) error
+	Update(source *domain.Source) error
+	Delete(id uint64) error
+}
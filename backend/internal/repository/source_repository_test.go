@@ -0,0 +1,93 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/repository"
+)
+
+func TestSourceRepository_CRUD(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		editor, year := "R. W. Chapman", 1932
+		letters := domain.NewSource(0, domain.SourceTypeLetter, "Jane Austen's Letters", domain.SourceDetails{
+			Author: &editor,
+			Year:   &year,
+		}, true)
+		require.NoError(t, repos.sourceRepo.Create(letters))
+		assert.NotZero(t, letters.ID())
+
+		candidate := domain.NewSource(0, "", "Diary", domain.SourceDetails{}, false)
+		require.NoError(t, repos.sourceRepo.Create(candidate))
+
+		found, err := repos.sourceRepo.GetByID(letters.ID())
+		require.NoError(t, err)
+		assert.Equal(t, domain.SourceTypeLetter, found.Type())
+		assert.Equal(t, "Jane Austen's Letters", found.Title())
+		require.NotNil(t, found.Details().Author)
+		assert.Equal(t, editor, *found.Details().Author)
+		assert.Equal(t, &year, found.Details().Year)
+		assert.Nil(t, found.Details().ISBN)
+		assert.True(t, found.Confirmed())
+
+		// Candidates have no type until a curator confirms them
+		found, err = repos.sourceRepo.GetByID(candidate.ID())
+		require.NoError(t, err)
+		assert.Empty(t, found.Type())
+		assert.False(t, found.Confirmed())
+
+		all, err := repos.sourceRepo.List(10, 0)
+		require.NoError(t, err)
+		assert.Len(t, all, 2)
+		candidates, err := repos.sourceRepo.ListCandidates(10, 0)
+		require.NoError(t, err)
+		require.Len(t, candidates, 1)
+		assert.Equal(t, candidate.ID(), candidates[0].ID())
+
+		var titles []string
+		require.NoError(t, repos.sourceRepo.ForEach(func(s *domain.Source) error {
+			titles = append(titles, s.Title())
+			return nil
+		}))
+		assert.Equal(t, []string{"Jane Austen's Letters", "Diary"}, titles)
+
+		isbn := "978-0-19-283530-4"
+		confirmed := domain.NewSource(candidate.ID(), domain.SourceTypeDiary, "Diary", domain.SourceDetails{
+			ISBN: &isbn,
+		}, true)
+		require.NoError(t, repos.sourceRepo.Update(confirmed))
+		candidates, err = repos.sourceRepo.ListCandidates(10, 0)
+		require.NoError(t, err)
+		assert.Empty(t, candidates)
+
+		require.NoError(t, repos.sourceRepo.Delete(letters.ID()))
+		_, err = repos.sourceRepo.GetByID(letters.ID())
+		require.Error(t, err)
+	})
+}
+
+func TestOpinionRepository_SourceID(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		_, _, _, opinion := setupTestData(t, repos)
+
+		source := domain.NewSource(0, domain.SourceTypeLetter, "Personal Letters", domain.SourceDetails{}, true)
+		require.NoError(t, repos.sourceRepo.Create(source))
+		opinion.SetSourceID(source.ID())
+		require.NoError(t, repos.opinionRepo.Update(opinion))
+		unlinked := domain.NewOpinion(0, 2, 1, domain.SentimentNegative, "Overrated", "Hearsay", nil, nil)
+		require.NoError(t, repos.opinionRepo.Create(unlinked))
+
+		found, err := repos.opinionRepo.GetByID(opinion.ID())
+		require.NoError(t, err)
+		assert.Equal(t, source.ID(), found.SourceID())
+
+		cited, err := repos.opinionRepo.Find(repository.OpinionFilter{SourceIDs: []uint64{source.ID()}})
+		require.NoError(t, err)
+		require.Len(t, cited, 1)
+		assert.Equal(t, opinion.ID(), cited[0].ID())
+	})
+}
@@ -0,0 +1,25 @@
+package repository
+
+// Repositories bundles the repositories that take part in a transaction.
+type Repositories struct {
+	Writers          WriterRepository
+	Works            WorkRepository
+	Opinions         OpinionRepository
+	Audit            AuditRepository
+	OpinionRevisions OpinionRevisionRepository
+	Sources          SourceRepository
+	WriterAliases    WriterAliasRepository
+}
+
+// Transactor runs a unit of work so that the writes it makes through the
+// given repositories are committed together or not at all. Services use it
+// to keep a change and its audit entry in step.
+type Transactor interface {
+	// WithinTransaction commits when fn returns nil and rolls back when it
+	// returns an error, which is then returned.
+	WithinTransaction(fn func(repos *Repositories) error) error
+	// WithinSnapshot runs fn in a read-only transaction that sees the data
+	// as it was when the transaction began, unchanged by transactions that
+	// commit meanwhile. fn must not write.
+	WithinSnapshot(fn func(repos *Repositories) error) error
+}
@@ -0,0 +1,67 @@
+package repository_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/repository"
+)
+
+func TestTransactor_WithinTransaction(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		err := repos.transactor.WithinTransaction(func(tx *repository.Repositories) error {
+			return tx.Writers.Create(domain.NewWriter(0, "Jane Austen", 1775, nil, nil))
+		})
+		require.NoError(t, err)
+
+		failure := errors.New("audit failed")
+		err = repos.transactor.WithinTransaction(func(tx *repository.Repositories) error {
+			if err := tx.Writers.Create(domain.NewWriter(0, "Charlotte Bronte", 1816, nil, nil)); err != nil {
+				return err
+			}
+			entry := domain.NewAuditEntry(0, domain.AuditEntityWriter, "2", domain.AuditActionCreate,
+				nil, []byte(`{"name":"Charlotte Bronte"}`), "alice", "req-1", time.Now())
+			if err := tx.Audit.Create(entry); err != nil {
+				return err
+			}
+			return failure
+		})
+		require.ErrorIs(t, err, failure)
+
+		page, err := repos.writerRepo.List(repository.WriterFilter{}, repository.WriterSort{}, nil, 10)
+		require.NoError(t, err)
+		writers := page.Items
+		require.Len(t, writers, 1)
+		assert.Equal(t, "Jane Austen", writers[0].Name())
+
+		entries, err := repos.auditRepo.Find(repository.AuditFilter{}, 10, 0)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+}
+
+func TestTransactor_WithinSnapshot(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		require.NoError(t, repos.writerRepo.Create(domain.NewWriter(0, "Jane Austen", 1775, nil, nil)))
+
+		failure := errors.New("client gone")
+		var names []string
+		err := repos.transactor.WithinSnapshot(func(tx *repository.Repositories) error {
+			if err := tx.Writers.ForEach(func(w *domain.Writer) error {
+				names = append(names, w.Name())
+				return nil
+			}); err != nil {
+				return err
+			}
+			return failure
+		})
+		require.ErrorIs(t, err, failure)
+		assert.Equal(t, []string{"Jane Austen"}, names)
+	})
+}
@@ -2,12 +2,42 @@ package repository
 
 import "github.com/what-writers-like/backend/internal/domain"
 
+// WorkFilter narrows a work listing. An empty AuthorIDs leaves authorship
+// unconstrained; otherwise a work by any of them matches.
+type WorkFilter struct {
+	AuthorIDs []uint64
+}
+
+// WorkCursor marks the last work of a page; a listing goes on after the
+// work with this ID.
+type WorkCursor struct {
+	ID uint64
+}
+
+// NewWorkCursor marks w as the last work of a page.
+func NewWorkCursor(w *domain.Work) WorkCursor {
+	return WorkCursor{ID: w.ID()}
+}
+
 type WorkRepository interface {
 	Create(work *domain.Work) error
 	GetByID(id uint64) (*domain.Work, error)
+	GetByIDs(ids []uint64) ([]*domain.Work, error)
+	// GetByAuthorID returns the works the writer wrote alone or with others.
 	GetByAuthorID(authorID uint64) ([]*domain.Work, error)
-	List(limit, offset int) ([]*domain.Work, error)
-	Search(query string, limit, offset int) ([]*domain.Work, error)
+	// GetByTitle returns the works with exactly this title, in order of ID.
+	GetByTitle(title string) ([]*domain.Work, error)
+	// List returns a page of up to limit works accepted by filter in order
+	// of ID, after the work marked by after, or from the start when it is
+	// nil.
+	List(filter WorkFilter, after *WorkCursor, limit int) (*Page[*domain.Work, WorkCursor], error)
+	// Search matches the works accepted by filter by title or original
+	// title, best match first. The page's Next is the offset of the
+	// following page.
+	Search(query string, filter WorkFilter, limit, offset int) (*Page[*domain.Work, int], error)
+	// ForEach calls fn with every work in order of ID, as WriterRepository's
+	// ForEach does with writers.
+	ForEach(fn func(*domain.Work) error) error
 	Update(work *domain.Work) error
 	Delete(id uint64) error
 }
@@ -1,134 +1,354 @@
 package repository_test
 
 import (
+	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/what-writers-like/backend/internal/domain"
-	"github.com/what-writers-like/backend/internal/repository/gorm"
-	"github.com/what-writers-like/backend/internal/testutils"
+	"github.com/what-writers-like/backend/internal/repository"
 )
 
 func TestWorkRepository_Create(t *testing.T) {
 	t.Parallel()
-	db, cleanup := testutils.SetupTestDB(t)
-	defer cleanup()
-
-	writerRepo := gorm.NewWriterRepository(db)
-	workRepo := gorm.NewWorkRepository(db)
-
-	writer := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
-	require.NoError(t, writerRepo.Create(writer))
-
-	work := domain.NewWork(1, "Pride and Prejudice", 1)
-	err := workRepo.Create(work)
-	assert.NoError(t, err)
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		writer := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
+		require.NoError(t, repos.writerRepo.Create(writer))
+
+		work := domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})
+		err := repos.workRepo.Create(work)
+		assert.NoError(t, err)
+	})
 }
 
 func TestWorkRepository_GetByID(t *testing.T) {
 	t.Parallel()
-	db, cleanup := testutils.SetupTestDB(t)
-	defer cleanup()
-
-	writerRepo := gorm.NewWriterRepository(db)
-	workRepo := gorm.NewWorkRepository(db)
-
-	writer := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
-	require.NoError(t, writerRepo.Create(writer))
-
-	work := domain.NewWork(1, "Pride and Prejudice", 1)
-	require.NoError(t, workRepo.Create(work))
-
-	found, err := workRepo.GetByID(1)
-	require.NoError(t, err)
-	assert.Equal(t, work.ID(), found.ID())
-	assert.Equal(t, work.Title(), found.Title())
-	assert.Equal(t, work.AuthorID(), found.AuthorID())
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		writer := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
+		require.NoError(t, repos.writerRepo.Create(writer))
+
+		work := domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})
+		require.NoError(t, repos.workRepo.Create(work))
+
+		found, err := repos.workRepo.GetByID(1)
+		require.NoError(t, err)
+		assert.Equal(t, work.ID(), found.ID())
+		assert.Equal(t, work.Title(), found.Title())
+		assert.Equal(t, work.AuthorIDs(), found.AuthorIDs())
+	})
 }
 
 func TestWorkRepository_GetByAuthorID(t *testing.T) {
 	t.Parallel()
-	db, cleanup := testutils.SetupTestDB(t)
-	defer cleanup()
-
-	writerRepo := gorm.NewWriterRepository(db)
-	workRepo := gorm.NewWorkRepository(db)
-
-	writer := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
-	require.NoError(t, writerRepo.Create(writer))
-
-	work1 := domain.NewWork(1, "Pride and Prejudice", 1)
-	work2 := domain.NewWork(2, "Sense and Sensibility", 1)
-	require.NoError(t, workRepo.Create(work1))
-	require.NoError(t, workRepo.Create(work2))
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		writer := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
+		require.NoError(t, repos.writerRepo.Create(writer))
+
+		work1 := domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})
+		work2 := domain.NewWork(2, "Sense and Sensibility", []uint64{1}, domain.WorkDetails{})
+		require.NoError(t, repos.workRepo.Create(work1))
+		require.NoError(t, repos.workRepo.Create(work2))
+
+		works, err := repos.workRepo.GetByAuthorID(1)
+		require.NoError(t, err)
+		assert.Len(t, works, 2)
+	})
+}
 
-	works, err := workRepo.GetByAuthorID(1)
-	require.NoError(t, err)
-	assert.Len(t, works, 2)
+func TestWorkRepository_GetByTitle(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		require.NoError(t, repos.writerRepo.Create(domain.NewWriter(1, "Thomas Kyd", 1558, nil, nil)))
+		require.NoError(t, repos.workRepo.Create(domain.NewWork(1, "Hamlet", []uint64{1}, domain.WorkDetails{})))
+		require.NoError(t, repos.workRepo.Create(domain.NewWork(2, "The Spanish Tragedy", []uint64{1}, domain.WorkDetails{})))
+		require.NoError(t, repos.workRepo.Create(domain.NewWork(3, "Hamlet", nil, domain.WorkDetails{})))
+
+		works, err := repos.workRepo.GetByTitle("Hamlet")
+		require.NoError(t, err)
+		require.Len(t, works, 2)
+		assert.Equal(t, []uint64{1}, works[0].AuthorIDs())
+		assert.Empty(t, works[1].AuthorIDs())
+
+		works, err = repos.workRepo.GetByTitle("Ur-Hamlet")
+		require.NoError(t, err)
+		assert.Empty(t, works)
+	})
 }
 
 func TestWorkRepository_List(t *testing.T) {
 	t.Parallel()
-	db, cleanup := testutils.SetupTestDB(t)
-	defer cleanup()
-
-	writerRepo := gorm.NewWriterRepository(db)
-	workRepo := gorm.NewWorkRepository(db)
-
-	writer := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
-	require.NoError(t, writerRepo.Create(writer))
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		writer := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
+		require.NoError(t, repos.writerRepo.Create(writer))
+
+		work1 := domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})
+		work2 := domain.NewWork(2, "Sense and Sensibility", []uint64{1}, domain.WorkDetails{})
+		require.NoError(t, repos.workRepo.Create(work1))
+		require.NoError(t, repos.workRepo.Create(work2))
+
+		page, err := repos.workRepo.List(repository.WorkFilter{}, nil, 10)
+		require.NoError(t, err)
+		assert.Len(t, page.Items, 2)
+	})
+}
 
-	work1 := domain.NewWork(1, "Pride and Prejudice", 1)
-	work2 := domain.NewWork(2, "Sense and Sensibility", 1)
-	require.NoError(t, workRepo.Create(work1))
-	require.NoError(t, workRepo.Create(work2))
+func TestWorkRepository_ListByAuthor(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		require.NoError(t, repos.writerRepo.Create(domain.NewWriter(1, "Jane Austen", 1775, nil, nil)))
+		require.NoError(t, repos.writerRepo.Create(domain.NewWriter(2, "Charles Dickens", 1812, nil, nil)))
+		require.NoError(t, repos.writerRepo.Create(domain.NewWriter(3, "Wilkie Collins", 1824, nil, nil)))
+		require.NoError(t, repos.workRepo.Create(domain.NewWork(1, "Emma", []uint64{1}, domain.WorkDetails{})))
+		require.NoError(t, repos.workRepo.Create(
+			domain.NewWork(2, "No Thoroughfare", []uint64{2, 3}, domain.WorkDetails{}),
+		))
+		require.NoError(t, repos.workRepo.Create(domain.NewWork(3, "Bleak House", []uint64{2}, domain.WorkDetails{})))
+
+		titles := func(works []*domain.Work) []string {
+			result := make([]string, len(works))
+			for i, w := range works {
+				result[i] = w.Title()
+			}
+			return result
+		}
+
+		// Co-authors count, and works come in ID order
+		page, err := repos.workRepo.List(repository.WorkFilter{AuthorIDs: []uint64{3, 1}}, nil, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Emma", "No Thoroughfare"}, titles(page.Items))
+
+		byDickens := repository.WorkFilter{AuthorIDs: []uint64{2}}
+		page, err = repos.workRepo.List(byDickens, nil, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"No Thoroughfare"}, titles(page.Items))
+		assert.Equal(t, int64(2), page.Total)
+		require.NotNil(t, page.Next)
+		page, err = repos.workRepo.List(byDickens, page.Next, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Bleak House"}, titles(page.Items))
+		assert.Nil(t, page.Next)
+
+		matches, err := repos.workRepo.Search("Bleak House", repository.WorkFilter{AuthorIDs: []uint64{1}}, 10, 0)
+		require.NoError(t, err)
+		assert.Empty(t, matches.Items)
+	})
+}
 
-	works, err := workRepo.List(10, 0)
-	require.NoError(t, err)
-	assert.Len(t, works, 2)
+func TestWorkRepository_Search(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		writer := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
+		require.NoError(t, repos.writerRepo.Create(writer))
+
+		require.NoError(t, repos.workRepo.Create(domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})))
+		sense := domain.NewWork(2, "Sense and Sensibility", []uint64{1}, domain.WorkDetails{})
+		require.NoError(t, repos.workRepo.Create(sense))
+
+		page, err := repos.workRepo.Search("prejudice", repository.WorkFilter{}, 10, 0)
+		require.NoError(t, err)
+		works := page.Items
+		require.Len(t, works, 1)
+		assert.Equal(t, "Pride and Prejudice", works[0].Title())
+
+		// Ranked results are paged by offset
+		require.NoError(t, repos.workRepo.Create(domain.NewWork(3, "Prejudice", []uint64{1}, domain.WorkDetails{})))
+		page, err = repos.workRepo.Search("prejudice", repository.WorkFilter{}, 1, 0)
+		require.NoError(t, err)
+		require.Len(t, page.Items, 1)
+		assert.Equal(t, "Prejudice", page.Items[0].Title())
+		assert.Equal(t, int64(2), page.Total)
+		require.NotNil(t, page.Next)
+		page, err = repos.workRepo.Search("prejudice", repository.WorkFilter{}, 1, *page.Next)
+		require.NoError(t, err)
+		require.Len(t, page.Items, 1)
+		assert.Equal(t, "Pride and Prejudice", page.Items[0].Title())
+		assert.Equal(t, int64(2), page.Total)
+		assert.Nil(t, page.Next)
+
+		page, err = repos.workRepo.Search("Middlemarch", repository.WorkFilter{}, 10, 0)
+		require.NoError(t, err)
+		assert.Empty(t, page.Items)
+	})
 }
 
 func TestWorkRepository_Update(t *testing.T) {
 	t.Parallel()
-	db, cleanup := testutils.SetupTestDB(t)
-	defer cleanup()
-
-	writerRepo := gorm.NewWriterRepository(db)
-	workRepo := gorm.NewWorkRepository(db)
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		writer := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
+		require.NoError(t, repos.writerRepo.Create(writer))
 
-	writer := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
-	require.NoError(t, writerRepo.Create(writer))
+		work := domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})
+		require.NoError(t, repos.workRepo.Create(work))
 
-	work := domain.NewWork(1, "Pride and Prejudice", 1)
-	require.NoError(t, workRepo.Create(work))
+		updated := domain.NewWork(1, "Pride and Prejudice (Revised)", []uint64{1}, domain.WorkDetails{})
+		err := repos.workRepo.Update(updated)
+		require.NoError(t, err)
 
-	updated := domain.NewWork(1, "Pride and Prejudice (Revised)", 1)
-	err := workRepo.Update(updated)
-	require.NoError(t, err)
-
-	found, err := workRepo.GetByID(1)
-	require.NoError(t, err)
-	assert.Equal(t, "Pride and Prejudice (Revised)", found.Title())
+		found, err := repos.workRepo.GetByID(1)
+		require.NoError(t, err)
+		assert.Equal(t, "Pride and Prejudice (Revised)", found.Title())
+	})
 }
 
 func TestWorkRepository_Delete(t *testing.T) {
 	t.Parallel()
-	db, cleanup := testutils.SetupTestDB(t)
-	defer cleanup()
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		writer := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
+		require.NoError(t, repos.writerRepo.Create(writer))
+
+		work := domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})
+		require.NoError(t, repos.workRepo.Create(work))
 
-	writerRepo := gorm.NewWriterRepository(db)
-	workRepo := gorm.NewWorkRepository(db)
+		err := repos.workRepo.Delete(1)
+		require.NoError(t, err)
+
+		_, err = repos.workRepo.GetByID(1)
+		require.Error(t, err)
+	})
+}
+
+func TestWorkRepository_GetByIDs(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		writer := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
+		require.NoError(t, repos.writerRepo.Create(writer))
+
+		require.NoError(t, repos.workRepo.Create(domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})))
+		sense := domain.NewWork(2, "Sense and Sensibility", []uint64{1}, domain.WorkDetails{})
+		require.NoError(t, repos.workRepo.Create(sense))
+
+		works, err := repos.workRepo.GetByIDs([]uint64{2})
+		require.NoError(t, err)
+		require.Len(t, works, 1)
+		assert.Equal(t, "Sense and Sensibility", works[0].Title())
+	})
+}
 
-	writer := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
-	require.NoError(t, writerRepo.Create(writer))
+func TestWorkRepository_CreateAllocatesID(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		writer := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
+		require.NoError(t, repos.writerRepo.Create(writer))
+
+		first := domain.NewWork(0, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})
+		second := domain.NewWork(0, "Emma", []uint64{1}, domain.WorkDetails{})
+		require.NoError(t, repos.workRepo.Create(first))
+		require.NoError(t, repos.workRepo.Create(second))
+		assert.NotZero(t, first.ID())
+		assert.NotEqual(t, first.ID(), second.ID())
+	})
+}
 
-	work := domain.NewWork(1, "Pride and Prejudice", 1)
-	require.NoError(t, workRepo.Create(work))
+func TestWorkRepository_ConcurrentCreate(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		require.NoError(t, repos.writerRepo.Create(domain.NewWriter(1, "Jane Austen", 1775, nil, nil)))
+		require.NoError(t, repos.workRepo.Create(domain.NewWork(5, "Emma", []uint64{1}, domain.WorkDetails{})))
+
+		const count = 20
+		works := make([]*domain.Work, count)
+		errs := make([]error, count)
+		var wg sync.WaitGroup
+		for i := 0; i < count; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				works[i] = domain.NewWork(0, fmt.Sprintf("Work %d", i), []uint64{1}, domain.WorkDetails{})
+				errs[i] = repos.workRepo.Create(works[i])
+			}(i)
+		}
+		wg.Wait()
+
+		seen := map[uint64]bool{5: true}
+		for i, work := range works {
+			require.NoError(t, errs[i])
+			assert.Greater(t, work.ID(), uint64(5))
+			assert.False(t, seen[work.ID()], "duplicate id %d", work.ID())
+			seen[work.ID()] = true
+		}
+
+		page, err := repos.workRepo.List(repository.WorkFilter{}, nil, 100)
+		require.NoError(t, err)
+		assert.Len(t, page.Items, count+1)
+	})
+}
 
-	err := workRepo.Delete(1)
-	require.NoError(t, err)
+func TestWorkRepository_ForEach(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		require.NoError(t, repos.writerRepo.Create(domain.NewWriter(1, "Jane Austen", 1775, nil, nil)))
+		require.NoError(t, repos.writerRepo.Create(domain.NewWriter(2, "Charlotte Bronte", 1816, nil, nil)))
+		require.NoError(t, repos.workRepo.Create(domain.NewWork(2, "A Joint Novel", []uint64{2, 1}, domain.WorkDetails{})))
+		require.NoError(t, repos.workRepo.Create(domain.NewWork(1, "Emma", []uint64{1}, domain.WorkDetails{})))
+
+		var works []*domain.Work
+		require.NoError(t, repos.workRepo.ForEach(func(w *domain.Work) error {
+			works = append(works, w)
+			return nil
+		}))
+		require.Len(t, works, 2)
+		assert.Equal(t, "Emma", works[0].Title())
+		assert.Equal(t, []uint64{2, 1}, works[1].AuthorIDs())
+	})
+}
 
-	_, err = workRepo.GetByID(1)
-	require.Error(t, err)
+func TestWorkRepository_CoAuthorsAndDetails(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		require.NoError(t, repos.writerRepo.Create(domain.NewWriter(1, "Jane Austen", 1775, nil, nil)))
+		require.NoError(t, repos.writerRepo.Create(domain.NewWriter(2, "Charlotte Bronte", 1816, nil, nil)))
+		require.NoError(t, repos.writerRepo.Create(domain.NewWriter(3, "Leo Tolstoy", 1828, nil, nil)))
+
+		year, genre, language, original := 1869, "novel", "Russian", "Voyna i mir"
+		details := domain.WorkDetails{
+			PublicationYear:  &year,
+			Genre:            &genre,
+			OriginalLanguage: &language,
+			OriginalTitle:    &original,
+		}
+		require.NoError(t, repos.workRepo.Create(domain.NewWork(1, "War and Peace", []uint64{3}, details)))
+		require.NoError(t, repos.workRepo.Create(domain.NewWork(2, "A Joint Novel", []uint64{2, 1}, domain.WorkDetails{})))
+		require.NoError(t, repos.workRepo.Create(domain.NewWork(3, "Beowulf", nil, domain.WorkDetails{})))
+
+		found, err := repos.workRepo.GetByID(1)
+		require.NoError(t, err)
+		assert.Equal(t, details, found.Details())
+
+		found, err = repos.workRepo.GetByID(2)
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{2, 1}, found.AuthorIDs())
+
+		found, err = repos.workRepo.GetByID(3)
+		require.NoError(t, err)
+		assert.Empty(t, found.AuthorIDs())
+
+		works, err := repos.workRepo.GetByAuthorID(1)
+		require.NoError(t, err)
+		require.Len(t, works, 1)
+		assert.Equal(t, uint64(2), works[0].ID())
+
+		matches, err := repos.workRepo.Search("Voyna i mir", repository.WorkFilter{}, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, matches.Items, 1)
+		assert.Equal(t, uint64(1), matches.Items[0].ID())
+
+		// Every co-author counts as writing the work
+		opinion := domain.NewOpinion(0, 1, 2, domain.SentimentPositive, "Our book", "Personal", nil, nil)
+		err = repos.opinionRepo.Create(opinion)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "writer cannot express opinion about their own work")
+		opinion = domain.NewOpinion(0, 3, 2, domain.SentimentPositive, "Their book", "Personal", nil, nil)
+		require.NoError(t, repos.opinionRepo.Create(opinion))
+
+		// Reordering the authors replaces the credits
+		require.NoError(t, repos.workRepo.Update(domain.NewWork(2, "A Joint Novel", []uint64{1}, domain.WorkDetails{})))
+		found, err = repos.workRepo.GetByID(2)
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{1}, found.AuthorIDs())
+		works, err = repos.workRepo.GetByAuthorID(2)
+		require.NoError(t, err)
+		assert.Empty(t, works)
+	})
 }
@@ -0,0 +1,70 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/what-writers-like/backend/internal/domain"
+)
+
+func TestWriterAliasRepository_CRUD(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		require.NoError(t, repos.writerRepo.Create(domain.NewWriter(1, "Maxim Gorky", 1868, nil, nil)))
+		require.NoError(t, repos.writerRepo.Create(domain.NewWriter(2, "Leo Tolstoy", 1828, nil, nil)))
+
+		peshkov := domain.NewWriterAlias(0, 1, "Alexei Peshkov", domain.AliasKindBirthName)
+		require.NoError(t, repos.writerAliasRepo.Create(peshkov))
+		assert.NotZero(t, peshkov.ID())
+		native := domain.NewWriterAlias(0, 1, "Максим Горький", domain.AliasKindNativeScript)
+		require.NoError(t, repos.writerAliasRepo.Create(native))
+		tolstoi := domain.NewWriterAlias(0, 2, "Lev Tolstoi", domain.AliasKindTransliteration)
+		require.NoError(t, repos.writerAliasRepo.Create(tolstoi))
+
+		found, err := repos.writerAliasRepo.GetByID(peshkov.ID())
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), found.WriterID())
+		assert.Equal(t, "Alexei Peshkov", found.Name())
+		assert.Equal(t, domain.AliasKindBirthName, found.Kind())
+
+		aliases, err := repos.writerAliasRepo.ListByWriter(1)
+		require.NoError(t, err)
+		require.Len(t, aliases, 2)
+		assert.Equal(t, peshkov.ID(), aliases[0].ID())
+		assert.Equal(t, native.ID(), aliases[1].ID())
+
+		// A writer has each alias once, but two writers may share one
+		err = repos.writerAliasRepo.Create(domain.NewWriterAlias(0, 1, "Alexei Peshkov", domain.AliasKindPseudonym))
+		require.Error(t, err)
+		require.NoError(t, repos.writerAliasRepo.Create(
+			domain.NewWriterAlias(0, 2, "Alexei Peshkov", domain.AliasKindPseudonym),
+		))
+
+		updated := domain.NewWriterAlias(peshkov.ID(), 1, "Aleksei Peshkov", domain.AliasKindBirthName)
+		require.NoError(t, repos.writerAliasRepo.Update(updated))
+		found, err = repos.writerAliasRepo.GetByID(peshkov.ID())
+		require.NoError(t, err)
+		assert.Equal(t, "Aleksei Peshkov", found.Name())
+
+		require.NoError(t, repos.writerAliasRepo.Delete(native.ID()))
+		_, err = repos.writerAliasRepo.GetByID(native.ID())
+		require.Error(t, err)
+
+		// Deleting a writer deletes their aliases
+		require.NoError(t, repos.writerRepo.Delete(1))
+		aliases, err = repos.writerAliasRepo.ListByWriter(1)
+		require.NoError(t, err)
+		assert.Empty(t, aliases)
+		aliases, err = repos.writerAliasRepo.ListByWriter(2)
+		require.NoError(t, err)
+		assert.Len(t, aliases, 2)
+
+		var names []string
+		require.NoError(t, repos.writerAliasRepo.ForEach(func(a *domain.WriterAlias) error {
+			names = append(names, a.Name())
+			return nil
+		}))
+		assert.Equal(t, []string{"Lev Tolstoi", "Alexei Peshkov"}, names)
+	})
+}
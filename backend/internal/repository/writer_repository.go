@@ -2,11 +2,84 @@ package repository
 
 import "github.com/what-writers-like/backend/internal/domain"
 
+// WriterFilter narrows a writer listing. Nil fields leave the
+// corresponding column unconstrained; year ranges are inclusive, and a
+// writer with no death year is alive.
+type WriterFilter struct {
+	BirthYearFrom *int
+	BirthYearTo   *int
+	DeathYearFrom *int
+	DeathYearTo   *int
+	Alive         *bool
+}
+
+// WriterSortKey names what writers can be listed by.
+type WriterSortKey string
+
+const (
+	WriterSortID        WriterSortKey = "id"
+	WriterSortName      WriterSortKey = "name"
+	WriterSortBirthYear WriterSortKey = "birth_year"
+)
+
+// WriterSort orders a writer listing, ties broken by ID. The zero value
+// lists writers by ID.
+type WriterSort struct {
+	Key        WriterSortKey
+	Descending bool
+}
+
+// WriterCursor marks the last writer of a page. A listing goes on after
+// the writer with this ID and, when sorted by name or birth year, this
+// name or birth year.
+type WriterCursor struct {
+	ID        uint64
+	Name      string
+	BirthYear int
+}
+
+// NewWriterCursor marks w as the last writer of a page.
+func NewWriterCursor(w *domain.Writer) WriterCursor {
+	return WriterCursor{ID: w.ID(), Name: w.Name(), BirthYear: w.BirthYear()}
+}
+
 type WriterRepository interface {
 	Create(writer *domain.Writer) error
 	GetByID(id uint64) (*domain.Writer, error)
-	List(limit, offset int) ([]*domain.Writer, error)
-	Search(query string, limit, offset int) ([]*domain.Writer, error)
+	GetByIDs(ids []uint64) ([]*domain.Writer, error)
+	// GetByNameAndBirthYear returns the writers with exactly this name who
+	// were born in this year, in order of ID.
+	GetByNameAndBirthYear(name string, birthYear int) ([]*domain.Writer, error)
+	// List returns a page of up to limit writers accepted by filter, after
+	// the writer marked by after, or from the start when it is nil.
+	List(
+		filter WriterFilter,
+		sort WriterSort,
+		after *WriterCursor,
+		limit int,
+	) (*Page[*domain.Writer, WriterCursor], error)
+	// Search matches the writers accepted by filter by name, bio or any of
+	// their aliases, best match first, and reports the alias that matched.
+	// The page's Next is the offset of the following page.
+	Search(query string, filter WriterFilter, limit, offset int) (*Page[*domain.WriterMatch, int], error)
+	// ForEach calls fn with every writer in order of ID, stopping at the
+	// first error fn returns. Writers are read a batch at a time, so those
+	// written meanwhile may or may not be seen.
+	ForEach(fn func(*domain.Writer) error) error
 	Update(writer *domain.Writer) error
+	// Delete removes the writer along with their aliases.
+	Delete(id uint64) error
+}
+
+type WriterAliasRepository interface {
+	Create(alias *domain.WriterAlias) error
+	GetByID(id uint64) (*domain.WriterAlias, error)
+	// ListByWriter returns the aliases of a writer in the order they were
+	// added.
+	ListByWriter(writerID uint64) ([]*domain.WriterAlias, error)
+	// ForEach calls fn with every alias in order of ID, as
+	// WriterRepository's ForEach does with writers.
+	ForEach(fn func(*domain.WriterAlias) error) error
+	Update(alias *domain.WriterAlias) error
 	Delete(id uint64) error
 }
@@ -1,96 +1,364 @@
 package repository_test
 
 import (
+	"errors"
+	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/what-writers-like/backend/internal/domain"
-	"github.com/what-writers-like/backend/internal/repository/gorm"
-	"github.com/what-writers-like/backend/internal/testutils"
+	"github.com/what-writers-like/backend/internal/repository"
 )
 
 func TestWriterRepository_Create(t *testing.T) {
 	t.Parallel()
-	db, cleanup := testutils.SetupTestDB(t)
-	defer cleanup()
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		writer := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
+		err := repos.writerRepo.Create(writer)
+		assert.NoError(t, err)
 
-	repo := gorm.NewWriterRepository(db)
-
-	writer := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
-	err := repo.Create(writer)
-	assert.NoError(t, err)
+		// The primary key is unique
+		err = repos.writerRepo.Create(domain.NewWriter(1, "Charles Dickens", 1812, nil, nil))
+		assert.Error(t, err)
+	})
 }
 
 func TestWriterRepository_GetByID(t *testing.T) {
 	t.Parallel()
-	db, cleanup := testutils.SetupTestDB(t)
-	defer cleanup()
-
-	repo := gorm.NewWriterRepository(db)
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		writer := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
+		err := repos.writerRepo.Create(writer)
+		require.NoError(t, err)
 
-	writer := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
-	err := repo.Create(writer)
-	require.NoError(t, err)
+		found, err := repos.writerRepo.GetByID(1)
+		require.NoError(t, err)
+		assert.Equal(t, writer.ID(), found.ID())
+		assert.Equal(t, writer.Name(), found.Name())
+		assert.Equal(t, writer.BirthYear(), found.BirthYear())
 
-	found, err := repo.GetByID(1)
-	require.NoError(t, err)
-	assert.Equal(t, writer.ID(), found.ID())
-	assert.Equal(t, writer.Name(), found.Name())
-	assert.Equal(t, writer.BirthYear(), found.BirthYear())
+		_, err = repos.writerRepo.GetByID(999)
+		require.Error(t, err)
+	})
 }
 
 func TestWriterRepository_List(t *testing.T) {
 	t.Parallel()
-	db, cleanup := testutils.SetupTestDB(t)
-	defer cleanup()
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		writer1 := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
+		writer2 := domain.NewWriter(2, "Charles Dickens", 1812, nil, nil)
 
-	repo := gorm.NewWriterRepository(db)
+		require.NoError(t, repos.writerRepo.Create(writer1))
+		require.NoError(t, repos.writerRepo.Create(writer2))
 
-	writer1 := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
-	writer2 := domain.NewWriter(2, "Charles Dickens", 1812, nil, nil)
+		page, err := repos.writerRepo.List(repository.WriterFilter{}, repository.WriterSort{}, nil, 10)
+		require.NoError(t, err)
+		assert.Len(t, page.Items, 2)
+		assert.Equal(t, int64(2), page.Total)
+		assert.Nil(t, page.Next)
 
-	require.NoError(t, repo.Create(writer1))
-	require.NoError(t, repo.Create(writer2))
+		page, err = repos.writerRepo.List(repository.WriterFilter{}, repository.WriterSort{}, nil, 1)
+		require.NoError(t, err)
+		require.Len(t, page.Items, 1)
+		assert.Equal(t, int64(2), page.Total)
+		require.NotNil(t, page.Next)
+		assert.Equal(t, writer1.ID(), page.Next.ID)
 
-	writers, err := repo.List(10, 0)
-	require.NoError(t, err)
-	assert.Len(t, writers, 2)
+		page, err = repos.writerRepo.List(repository.WriterFilter{}, repository.WriterSort{}, page.Next, 1)
+		require.NoError(t, err)
+		require.Len(t, page.Items, 1)
+		assert.Equal(t, writer2.ID(), page.Items[0].ID())
+		assert.Nil(t, page.Next)
+	})
 }
 
-func TestWriterRepository_Update(t *testing.T) {
+func TestWriterRepository_ListFiltered(t *testing.T) {
 	t.Parallel()
-	db, cleanup := testutils.SetupTestDB(t)
-	defer cleanup()
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		year := func(y int) *int { return &y }
+		alive := true
+		require.NoError(t, repos.writerRepo.Create(domain.NewWriter(1, "Leo Tolstoy", 1828, year(1910), nil)))
+		require.NoError(t, repos.writerRepo.Create(domain.NewWriter(2, "Jane Austen", 1775, year(1817), nil)))
+		require.NoError(t, repos.writerRepo.Create(domain.NewWriter(3, "Salman Rushdie", 1947, nil, nil)))
+		require.NoError(t, repos.writerRepo.Create(domain.NewWriter(4, "Anton Chekhov", 1860, year(1904), nil)))
+		require.NoError(t, repos.writerRepo.Create(domain.NewWriter(5, "Ivan Turgenev", 1828, year(1883), nil)))
+
+		// names walks the listing two at a time, so every order is also
+		// checked across page boundaries
+		names := func(filter repository.WriterFilter, sort repository.WriterSort) []string {
+			var result []string
+			var after *repository.WriterCursor
+			for {
+				page, err := repos.writerRepo.List(filter, sort, after, 2)
+				require.NoError(t, err)
+				for _, w := range page.Items {
+					result = append(result, w.Name())
+				}
+				if page.Next == nil {
+					assert.Equal(t, int64(len(result)), page.Total)
+					return result
+				}
+				after = page.Next
+			}
+		}
+		none := repository.WriterFilter{}
+		byID := repository.WriterSort{}
+
+		assert.Equal(t,
+			[]string{"Anton Chekhov", "Ivan Turgenev", "Jane Austen", "Leo Tolstoy", "Salman Rushdie"},
+			names(none, repository.WriterSort{Key: repository.WriterSortName}),
+		)
+		assert.Equal(t,
+			[]string{"Salman Rushdie", "Leo Tolstoy", "Jane Austen", "Ivan Turgenev", "Anton Chekhov"},
+			names(none, repository.WriterSort{Key: repository.WriterSortName, Descending: true}),
+		)
+		// Ties are broken by ID in either direction
+		assert.Equal(t,
+			[]string{"Jane Austen", "Leo Tolstoy", "Ivan Turgenev", "Anton Chekhov", "Salman Rushdie"},
+			names(none, repository.WriterSort{Key: repository.WriterSortBirthYear}),
+		)
+		assert.Equal(t,
+			[]string{"Salman Rushdie", "Anton Chekhov", "Leo Tolstoy", "Ivan Turgenev", "Jane Austen"},
+			names(none, repository.WriterSort{Key: repository.WriterSortBirthYear, Descending: true}),
+		)
+		assert.Equal(t,
+			[]string{"Ivan Turgenev", "Anton Chekhov", "Salman Rushdie", "Jane Austen", "Leo Tolstoy"},
+			names(none, repository.WriterSort{Key: repository.WriterSortID, Descending: true}),
+		)
+
+		born := repository.WriterFilter{BirthYearFrom: year(1828), BirthYearTo: year(1860)}
+		assert.Equal(t, []string{"Leo Tolstoy", "Anton Chekhov", "Ivan Turgenev"}, names(born, byID))
+		died := repository.WriterFilter{DeathYearFrom: year(1900)}
+		assert.Equal(t, []string{"Leo Tolstoy", "Anton Chekhov"}, names(died, byID))
+		living := repository.WriterFilter{Alive: &alive}
+		assert.Equal(t, []string{"Salman Rushdie"}, names(living, byID))
 
-	repo := gorm.NewWriterRepository(db)
+		// Search honours the filter too
+		matches, err := repos.writerRepo.Search("Tolstoy", repository.WriterFilter{DeathYearTo: year(1900)}, 10, 0)
+		require.NoError(t, err)
+		assert.Empty(t, matches.Items)
+		matches, err = repos.writerRepo.Search("Tolstoy", born, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, matches.Items, 1)
+		assert.Equal(t, "Leo Tolstoy", matches.Items[0].Writer.Name())
+	})
+}
+
+func TestWriterRepository_Search(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		bio := "Victorian novelist"
+		require.NoError(t, repos.writerRepo.Create(domain.NewWriter(1, "Jane Austen", 1775, nil, nil)))
+		require.NoError(t, repos.writerRepo.Create(domain.NewWriter(2, "Charles Dickens", 1812, nil, &bio)))
 
-	writer := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
-	require.NoError(t, repo.Create(writer))
+		page, err := repos.writerRepo.Search("Austen", repository.WriterFilter{}, 10, 0)
+		require.NoError(t, err)
+		writers := page.Items
+		require.Len(t, writers, 1)
+		assert.Equal(t, "Jane Austen", writers[0].Writer.Name())
+		assert.Nil(t, writers[0].Alias)
 
-	bio := "English novelist"
-	updated := domain.NewWriter(1, "Jane Austen", 1775, nil, &bio)
-	err := repo.Update(updated)
-	require.NoError(t, err)
+		// The biography is searched as well
+		page, err = repos.writerRepo.Search("victorian", repository.WriterFilter{}, 10, 0)
+		require.NoError(t, err)
+		writers = page.Items
+		require.Len(t, writers, 1)
+		assert.Equal(t, "Charles Dickens", writers[0].Writer.Name())
 
-	found, err := repo.GetByID(1)
-	require.NoError(t, err)
-	assert.Equal(t, bio, *found.Bio())
+		page, err = repos.writerRepo.Search("Tolstoy", repository.WriterFilter{}, 10, 0)
+		require.NoError(t, err)
+		assert.Empty(t, page.Items)
+	})
+}
+
+func TestWriterRepository_SearchAliases(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		require.NoError(t, repos.writerRepo.Create(domain.NewWriter(1, "Maxim Gorky", 1868, nil, nil)))
+		require.NoError(t, repos.writerRepo.Create(domain.NewWriter(2, "Fyodor Dostoevsky", 1821, nil, nil)))
+		peshkov := domain.NewWriterAlias(0, 1, "Alexei Peshkov", domain.AliasKindBirthName)
+		require.NoError(t, repos.writerAliasRepo.Create(peshkov))
+		dostoyevsky := domain.NewWriterAlias(0, 2, "Fyodor Dostoyevsky", domain.AliasKindTransliteration)
+		require.NoError(t, repos.writerAliasRepo.Create(dostoyevsky))
+		native := domain.NewWriterAlias(0, 2, "Фёдор Достоевский", domain.AliasKindNativeScript)
+		require.NoError(t, repos.writerAliasRepo.Create(native))
+
+		page, err := repos.writerRepo.Search("Peshkov", repository.WriterFilter{}, 10, 0)
+		require.NoError(t, err)
+		writers := page.Items
+		require.Len(t, writers, 1)
+		assert.Equal(t, "Maxim Gorky", writers[0].Writer.Name())
+		require.NotNil(t, writers[0].Alias)
+		assert.Equal(t, peshkov.ID(), writers[0].Alias.ID())
+		assert.Equal(t, domain.AliasKindBirthName, writers[0].Alias.Kind())
+
+		page, err = repos.writerRepo.Search("Достоевский", repository.WriterFilter{}, 10, 0)
+		require.NoError(t, err)
+		writers = page.Items
+		require.Len(t, writers, 1)
+		require.NotNil(t, writers[0].Alias)
+		assert.Equal(t, native.ID(), writers[0].Alias.ID())
+
+		// The name itself wins over an alias that matches less well
+		page, err = repos.writerRepo.Search("Dostoevsky", repository.WriterFilter{}, 10, 0)
+		require.NoError(t, err)
+		writers = page.Items
+		require.Len(t, writers, 1)
+		assert.Equal(t, uint64(2), writers[0].Writer.ID())
+		assert.Nil(t, writers[0].Alias)
+	})
+}
+
+func TestWriterRepository_Update(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		writer := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
+		require.NoError(t, repos.writerRepo.Create(writer))
+
+		bio := "English novelist"
+		updated := domain.NewWriter(1, "Jane Austen", 1775, nil, &bio)
+		err := repos.writerRepo.Update(updated)
+		require.NoError(t, err)
+
+		found, err := repos.writerRepo.GetByID(1)
+		require.NoError(t, err)
+		assert.Equal(t, bio, *found.Bio())
+	})
 }
 
 func TestWriterRepository_Delete(t *testing.T) {
 	t.Parallel()
-	db, cleanup := testutils.SetupTestDB(t)
-	defer cleanup()
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		writer := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
+		require.NoError(t, repos.writerRepo.Create(writer))
 
-	repo := gorm.NewWriterRepository(db)
+		err := repos.writerRepo.Delete(1)
+		require.NoError(t, err)
+
+		_, err = repos.writerRepo.GetByID(1)
+		require.Error(t, err)
+	})
+}
+
+func TestWriterRepository_GetByIDs(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		require.NoError(t, repos.writerRepo.Create(domain.NewWriter(1, "Jane Austen", 1775, nil, nil)))
+		require.NoError(t, repos.writerRepo.Create(domain.NewWriter(2, "Charles Dickens", 1812, nil, nil)))
+		require.NoError(t, repos.writerRepo.Create(domain.NewWriter(3, "Charlotte Bronte", 1816, nil, nil)))
+
+		writers, err := repos.writerRepo.GetByIDs([]uint64{3, 1, 999})
+		require.NoError(t, err)
+		require.Len(t, writers, 2)
+		assert.Equal(t, uint64(1), writers[0].ID())
+		assert.Equal(t, uint64(3), writers[1].ID())
+
+		writers, err = repos.writerRepo.GetByIDs(nil)
+		require.NoError(t, err)
+		assert.Empty(t, writers)
+	})
+}
+
+func TestWriterRepository_GetByNameAndBirthYear(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		require.NoError(t, repos.writerRepo.Create(domain.NewWriter(1, "John Smith", 1580, nil, nil)))
+		require.NoError(t, repos.writerRepo.Create(domain.NewWriter(2, "John Smith", 1900, nil, nil)))
+		require.NoError(t, repos.writerRepo.Create(domain.NewWriter(3, "John Smith", 1580, nil, nil)))
+		require.NoError(t, repos.writerRepo.Create(domain.NewWriter(4, "john smith", 1580, nil, nil)))
+
+		writers, err := repos.writerRepo.GetByNameAndBirthYear("John Smith", 1580)
+		require.NoError(t, err)
+		require.Len(t, writers, 2)
+		assert.Equal(t, uint64(1), writers[0].ID())
+		assert.Equal(t, uint64(3), writers[1].ID())
+
+		writers, err = repos.writerRepo.GetByNameAndBirthYear("John Smith", 1700)
+		require.NoError(t, err)
+		assert.Empty(t, writers)
+	})
+}
+
+func TestWriterRepository_ForEach(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		// More writers than are read in one batch
+		const count = 1201
+		for i := count; i > 0; i-- {
+			require.NoError(t, repos.writerRepo.Create(domain.NewWriter(uint64(i), "Writer", 1800, nil, nil)))
+		}
+
+		var ids []uint64
+		require.NoError(t, repos.writerRepo.ForEach(func(w *domain.Writer) error {
+			ids = append(ids, w.ID())
+			return nil
+		}))
+		require.Len(t, ids, count)
+		for i, id := range ids {
+			assert.Equal(t, uint64(i+1), id)
+		}
+
+		// An error from fn stops the walk and is returned
+		stop := errors.New("stop")
+		seen := 0
+		err := repos.writerRepo.ForEach(func(*domain.Writer) error {
+			seen++
+			if seen == 3 {
+				return stop
+			}
+			return nil
+		})
+		assert.ErrorIs(t, err, stop)
+		assert.Equal(t, 3, seen)
+	})
+}
+
+func TestWriterRepository_CreateAllocatesID(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		// A row imported with an explicit ID must not collide with generated ones
+		require.NoError(t, repos.writerRepo.Create(domain.NewWriter(5, "Jane Austen", 1775, nil, nil)))
+
+		writer := domain.NewWriter(0, "Charles Dickens", 1812, nil, nil)
+		require.NoError(t, repos.writerRepo.Create(writer))
+		assert.Equal(t, uint64(6), writer.ID())
+
+		found, err := repos.writerRepo.GetByID(writer.ID())
+		require.NoError(t, err)
+		assert.Equal(t, "Charles Dickens", found.Name())
+	})
+}
+
+func TestWriterRepository_ConcurrentCreate(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, repos *testRepos) {
+		require.NoError(t, repos.writerRepo.Create(domain.NewWriter(5, "Jane Austen", 1775, nil, nil)))
 
-	writer := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
-	require.NoError(t, repo.Create(writer))
+		const count = 20
+		writers := make([]*domain.Writer, count)
+		errs := make([]error, count)
+		var wg sync.WaitGroup
+		for i := 0; i < count; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				writers[i] = domain.NewWriter(0, fmt.Sprintf("Writer %d", i), 1800+i, nil, nil)
+				errs[i] = repos.writerRepo.Create(writers[i])
+			}(i)
+		}
+		wg.Wait()
 
-	err := repo.Delete(1)
-	require.NoError(t, err)
+		seen := map[uint64]bool{5: true}
+		for i, writer := range writers {
+			require.NoError(t, errs[i])
+			assert.Greater(t, writer.ID(), uint64(5))
+			assert.False(t, seen[writer.ID()], "duplicate id %d", writer.ID())
+			seen[writer.ID()] = true
+		}
 
-	_, err = repo.GetByID(1)
-	require.Error(t, err)
+		page, err := repos.writerRepo.List(repository.WriterFilter{}, repository.WriterSort{}, nil, 100)
+		require.NoError(t, err)
+		assert.Len(t, page.Items, count+1)
+	})
 }
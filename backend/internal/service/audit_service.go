@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/repository"
+)
+
+// SystemActor is recorded for changes made without an authenticated caller,
+// such as those from command-line tools.
+const SystemActor = "system"
+
+// ErrInvalidAuditRange is returned for a time range that ends before it
+// starts.
+var ErrInvalidAuditRange = errors.New("since must be before until")
+
+type (
+	actorContextKey     struct{}
+	requestIDContextKey struct{}
+)
+
+// WithActor attaches the subject responsible for changes made with ctx.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+// WithRequestID attaches the ID of the request that changes made with ctx
+// belong to.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, requestID)
+}
+
+func actorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorContextKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return SystemActor
+}
+
+func requestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDContextKey{}).(string)
+	return requestID
+}
+
+type AuditService interface {
+	ListEntries(filter repository.AuditFilter, limit, offset int) ([]*domain.AuditEntry, error)
+}
+
+type auditService struct {
+	auditRepo repository.AuditRepository
+}
+
+func NewAuditService(auditRepo repository.AuditRepository) AuditService {
+	return &auditService{auditRepo: auditRepo}
+}
+
+func (s *auditService) ListEntries(filter repository.AuditFilter, limit, offset int) ([]*domain.AuditEntry, error) {
+	if filter.Since != nil && filter.Until != nil && !filter.Since.Before(*filter.Until) {
+		return nil, ErrInvalidAuditRange
+	}
+	return s.auditRepo.Find(filter, limit, offset)
+}
+
+// recordChange appends an audit entry for a change that has been written.
+// A nil before or after snapshot marks a create or delete respectively.
+func recordChange(
+	ctx context.Context,
+	auditRepo repository.AuditRepository,
+	entityType domain.AuditEntityType,
+	entityID string,
+	action domain.AuditAction,
+	before, after map[string]any,
+) error {
+	beforeJSON, err := marshalSnapshot(before)
+	if err != nil {
+		return err
+	}
+	afterJSON, err := marshalSnapshot(after)
+	if err != nil {
+		return err
+	}
+	entry := domain.NewAuditEntry(
+		0,
+		entityType,
+		entityID,
+		action,
+		beforeJSON,
+		afterJSON,
+		actorFromContext(ctx),
+		requestIDFromContext(ctx),
+		time.Now().UTC(),
+	)
+	if err := auditRepo.Create(entry); err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	return nil
+}
+
+func marshalSnapshot(snapshot map[string]any) ([]byte, error) {
+	if snapshot == nil {
+		return nil, nil
+	}
+	return json.Marshal(snapshot)
+}
+
+// Snapshots use the field names of the API so that audit entries read like
+// the resources they describe.
+
+func writerSnapshot(w *domain.Writer) map[string]any {
+	return map[string]any{
+		"id":         w.ID(),
+		"name":       w.Name(),
+		"birth_year": w.BirthYear(),
+		"death_year": w.DeathYear(),
+		"bio":        w.Bio(),
+	}
+}
+
+func writerAliasSnapshot(a *domain.WriterAlias) map[string]any {
+	return map[string]any{
+		"id":        a.ID(),
+		"writer_id": a.WriterID(),
+		"name":      a.Name(),
+		"kind":      a.Kind(),
+	}
+}
+
+func workSnapshot(w *domain.Work) map[string]any {
+	details := w.Details()
+	return map[string]any{
+		"id":                w.ID(),
+		"title":             w.Title(),
+		"author_ids":        append([]uint64{}, w.AuthorIDs()...),
+		"publication_year":  details.PublicationYear,
+		"genre":             details.Genre,
+		"original_language": details.OriginalLanguage,
+		"original_title":    details.OriginalTitle,
+	}
+}
+
+func opinionSnapshot(o *domain.Opinion) map[string]any {
+	languages := o.Languages()
+	var language *string
+	if languages.Language != "" {
+		language = &languages.Language
+	}
+	translations := make([]map[string]any, len(languages.Translations))
+	for i, t := range languages.Translations {
+		translations[i] = map[string]any{
+			"language":   t.Language,
+			"text":       t.Text,
+			"translator": t.Translator,
+			"source":     t.Source,
+		}
+	}
+	return map[string]any{
+		"id":               o.ID(),
+		"writer_id":        o.WriterID(),
+		"work_id":          optionalID(o.WorkID()),
+		"target_writer_id": optionalID(o.TargetWriterID()),
+		"sentiment_grade":  o.Sentiment(),
+		"quote":            o.Quote(),
+		"source":           o.Source(),
+		"source_id":        optionalID(o.SourceID()),
+		"page":             o.Page(),
+		"statement_year":   o.StatementYear(),
+		"language":         language,
+		"translations":     translations,
+	}
+}
+
+func sourceSnapshot(s *domain.Source) map[string]any {
+	details := s.Details()
+	var sourceType *domain.SourceType
+	if s.Type() != "" {
+		t := s.Type()
+		sourceType = &t
+	}
+	return map[string]any{
+		"id":               s.ID(),
+		"type":             sourceType,
+		"title":            s.Title(),
+		"author":           details.Author,
+		"publisher":        details.Publisher,
+		"year":             details.Year,
+		"isbn":             details.ISBN,
+		"doi":              details.DOI,
+		"url":              details.URL,
+		"archive_location": details.ArchiveLocation,
+		"confirmed":        s.Confirmed(),
+	}
+}
+
+func entityID(id uint64) string {
+	return strconv.FormatUint(id, 10)
+}
+
+// optionalID reports an unset reference, such as the work of an opinion
+// about a writer, as null.
+func optionalID(id uint64) *uint64 {
+	if id == 0 {
+		return nil
+	}
+	return &id
+}
@@ -0,0 +1,113 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/repository"
+	"github.com/what-writers-like/backend/internal/repository/memory"
+	"github.com/what-writers-like/backend/internal/service"
+)
+
+func TestAuditService_RecordsChanges(t *testing.T) {
+	t.Parallel()
+	store := memory.NewStore()
+
+	writerRepo := memory.NewWriterRepository(store)
+	workRepo := memory.NewWorkRepository(store)
+	opinionRepo := memory.NewOpinionRepository(store)
+	auditRepo := memory.NewAuditRepository(store)
+	transactor := memory.NewTransactor(store)
+	writerSvc := service.NewWriterService(writerRepo, workRepo, transactor)
+	workSvc := service.NewWorkService(workRepo, writerRepo, transactor)
+	opinionSvc := service.NewOpinionService(
+		opinionRepo, writerRepo, workRepo, memory.NewOpinionRevisionRepository(store),
+		memory.NewSourceRepository(store), transactor,
+	)
+	svc := service.NewAuditService(auditRepo)
+
+	ctx := service.WithRequestID(service.WithActor(context.Background(), "alice"), "req-1")
+
+	austen, err := writerSvc.CreateWriter(ctx, "Jane Austen", 1775, nil, nil)
+	require.NoError(t, err)
+	bronte, err := writerSvc.CreateWriter(ctx, "Charlotte Bronte", 1816, nil, nil)
+	require.NoError(t, err)
+	work, err := workSvc.CreateWork(ctx, "Pride and Prejudice", []uint64{austen.ID()}, domain.WorkDetails{})
+	require.NoError(t, err)
+	opinion, err := opinionSvc.CreateOpinion(
+		ctx, bronte.ID(), work.ID(), domain.SentimentNegative, "Quote", "Source", nil, nil, 0,
+		domain.QuoteLanguages{},
+	)
+	require.NoError(t, err)
+
+	err = opinionSvc.UpdateOpinion(
+		ctx, opinion.ID(), domain.SentimentPositive, "Updated quote", "Source", nil, nil, nil, nil, nil,
+	)
+	require.NoError(t, err)
+	require.NoError(t, opinionSvc.DeleteOpinion(context.Background(), opinion.ID()))
+
+	entries, err := svc.ListEntries(repository.AuditFilter{EntityType: domain.AuditEntityOpinion}, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	deleted, updated, created := entries[0], entries[1], entries[2]
+	assert.Equal(t, domain.AuditActionCreate, created.Action())
+	assert.Nil(t, created.Before())
+	assert.Equal(t, "alice", created.Actor())
+	assert.Equal(t, "req-1", created.RequestID())
+
+	assert.Equal(t, domain.AuditActionUpdate, updated.Action())
+	assert.Equal(t, "1", updated.EntityID())
+	assert.JSONEq(t, `{"id":1,"writer_id":2,"work_id":1,"target_writer_id":null,"sentiment_grade":"-1",
+		"quote":"Quote","source":"Source","source_id":null,"page":null,"statement_year":null,"language":null,
+		"translations":[]}`, string(updated.Before()))
+	assert.Contains(t, string(updated.After()), `"quote":"Updated quote"`)
+
+	// Changes made outside a request are attributed to the system
+	assert.Equal(t, domain.AuditActionDelete, deleted.Action())
+	assert.Nil(t, deleted.After())
+	assert.Equal(t, service.SystemActor, deleted.Actor())
+	assert.Empty(t, deleted.RequestID())
+
+	writers, err := svc.ListEntries(repository.AuditFilter{EntityType: domain.AuditEntityWriter, EntityID: "1"}, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, writers, 1)
+	assert.Contains(t, string(writers[0].After()), `"name":"Jane Austen"`)
+}
+
+func TestAuditService_FailedChangesAreNotRecorded(t *testing.T) {
+	t.Parallel()
+	store := memory.NewStore()
+
+	writerRepo := memory.NewWriterRepository(store)
+	workRepo := memory.NewWorkRepository(store)
+	auditRepo := memory.NewAuditRepository(store)
+	transactor := memory.NewTransactor(store)
+	writerSvc := service.NewWriterService(writerRepo, workRepo, transactor)
+	svc := service.NewAuditService(auditRepo)
+
+	_, err := writerSvc.CreateWriter(context.Background(), "", 1775, nil, nil)
+	require.Error(t, err)
+	require.Error(t, writerSvc.DeleteWriter(context.Background(), 42))
+
+	entries, err := svc.ListEntries(repository.AuditFilter{}, 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestAuditService_ListEntries(t *testing.T) {
+	t.Parallel()
+	t.Run("invalid time range", func(t *testing.T) {
+		t.Parallel()
+		svc := service.NewAuditService(memory.NewAuditRepository(memory.NewStore()))
+
+		now := time.Now()
+		earlier := now.Add(-time.Hour)
+		_, err := svc.ListEntries(repository.AuditFilter{Since: &now, Until: &earlier}, 10, 0)
+		require.ErrorIs(t, err, service.ErrInvalidAuditRange)
+	})
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/infrastructure/config"
+)
+
+const (
+	DefaultTokenTTL = 24 * time.Hour
+	MaxTokenTTL     = 365 * 24 * time.Hour
+
+	// MinSigningKeyLength is the shortest accepted HMAC key, in bytes
+	MinSigningKeyLength = 32
+)
+
+var ErrInvalidToken = errors.New("invalid token")
+
+type AuthService interface {
+	IssueToken(subject string, role domain.Role, ttl time.Duration) (string, time.Time, error)
+	Authenticate(token string) (*domain.Principal, error)
+}
+
+type authService struct {
+	signingKey []byte
+}
+
+// tokenClaims are carried by bearer tokens, signed with HS256.
+type tokenClaims struct {
+	Role domain.Role `json:"role"`
+	jwt.RegisteredClaims
+}
+
+func NewAuthService(cfg *config.Config) (AuthService, error) {
+	if len(cfg.AuthSigningKey) < MinSigningKeyLength {
+		return nil, fmt.Errorf("AUTH_SIGNING_KEY must be at least %d bytes", MinSigningKeyLength)
+	}
+	return &authService{
+		signingKey: []byte(cfg.AuthSigningKey),
+	}, nil
+}
+
+func (s *authService) IssueToken(subject string, role domain.Role, ttl time.Duration) (string, time.Time, error) {
+	if subject == "" {
+		return "", time.Time{}, errors.New("subject is required")
+	}
+	if _, err := domain.ParseRole(string(role)); err != nil {
+		return "", time.Time{}, err
+	}
+	if ttl <= 0 || ttl > MaxTokenTTL {
+		return "", time.Time{}, fmt.Errorf("token lifetime must be between 0 and %s", MaxTokenTTL)
+	}
+
+	issuedAt := time.Now()
+	expiresAt := issuedAt.Add(ttl)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, tokenClaims{
+		Role: role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   subject,
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	})
+	signed, err := token.SignedString(s.signingKey)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
+}
+
+// Authenticate verifies the signature and expiry of a bearer token and
+// returns the principal it was issued to.
+func (s *authService) Authenticate(token string) (*domain.Principal, error) {
+	var claims tokenClaims
+	_, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (interface{}, error) {
+		return s.signingKey, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	role, err := domain.ParseRole(string(claims.Role))
+	if err != nil || claims.Subject == "" {
+		return nil, ErrInvalidToken
+	}
+	return domain.NewPrincipal(claims.Subject, role), nil
+}
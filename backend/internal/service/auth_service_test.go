@@ -0,0 +1,109 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/infrastructure/config"
+	"github.com/what-writers-like/backend/internal/service"
+)
+
+const testSigningKey = "test-signing-key-0123456789abcdef"
+
+func TestAuthService_IssueToken(t *testing.T) {
+	t.Parallel()
+	t.Run("round trip", func(t *testing.T) {
+		t.Parallel()
+		svc, err := service.NewAuthService(&config.Config{AuthSigningKey: testSigningKey})
+		require.NoError(t, err)
+
+		token, expiresAt, err := svc.IssueToken("alice", domain.RoleEditor, time.Hour)
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), expiresAt, time.Minute)
+
+		principal, err := svc.Authenticate(token)
+		require.NoError(t, err)
+		assert.Equal(t, "alice", principal.Subject())
+		assert.Equal(t, domain.RoleEditor, principal.Role())
+	})
+
+	t.Run("anonymous role", func(t *testing.T) {
+		t.Parallel()
+		svc, err := service.NewAuthService(&config.Config{AuthSigningKey: testSigningKey})
+		require.NoError(t, err)
+
+		_, _, err = svc.IssueToken("alice", domain.RoleAnonymous, time.Hour)
+		require.Error(t, err)
+	})
+
+	t.Run("empty subject", func(t *testing.T) {
+		t.Parallel()
+		svc, err := service.NewAuthService(&config.Config{AuthSigningKey: testSigningKey})
+		require.NoError(t, err)
+
+		_, _, err = svc.IssueToken("", domain.RoleAdmin, time.Hour)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "subject is required")
+	})
+
+	t.Run("lifetime too long", func(t *testing.T) {
+		t.Parallel()
+		svc, err := service.NewAuthService(&config.Config{AuthSigningKey: testSigningKey})
+		require.NoError(t, err)
+
+		_, _, err = svc.IssueToken("alice", domain.RoleAdmin, service.MaxTokenTTL+time.Hour)
+		require.Error(t, err)
+	})
+}
+
+func TestAuthService_Authenticate(t *testing.T) {
+	t.Parallel()
+	t.Run("other key", func(t *testing.T) {
+		t.Parallel()
+		issuer, err := service.NewAuthService(&config.Config{AuthSigningKey: "another-signing-key-0123456789abc"})
+		require.NoError(t, err)
+		svc, err := service.NewAuthService(&config.Config{AuthSigningKey: testSigningKey})
+		require.NoError(t, err)
+
+		token, _, err := issuer.IssueToken("mallory", domain.RoleAdmin, time.Hour)
+		require.NoError(t, err)
+
+		_, err = svc.Authenticate(token)
+		require.ErrorIs(t, err, service.ErrInvalidToken)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		t.Parallel()
+		svc, err := service.NewAuthService(&config.Config{AuthSigningKey: testSigningKey})
+		require.NoError(t, err)
+
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"sub":  "alice",
+			"role": "admin",
+			"exp":  time.Now().Add(-time.Minute).Unix(),
+		}).SignedString([]byte(testSigningKey))
+		require.NoError(t, err)
+
+		_, err = svc.Authenticate(token)
+		require.ErrorIs(t, err, service.ErrInvalidToken)
+	})
+
+	t.Run("garbage", func(t *testing.T) {
+		t.Parallel()
+		svc, err := service.NewAuthService(&config.Config{AuthSigningKey: testSigningKey})
+		require.NoError(t, err)
+
+		_, err = svc.Authenticate("not-a-token")
+		require.ErrorIs(t, err, service.ErrInvalidToken)
+	})
+}
+
+func TestNewAuthService_ShortKey(t *testing.T) {
+	t.Parallel()
+	_, err := service.NewAuthService(&config.Config{AuthSigningKey: "short"})
+	require.Error(t, err)
+}
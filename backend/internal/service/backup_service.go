@@ -0,0 +1,550 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/repository"
+)
+
+// RestoreMode decides what a restore does with the records in storage.
+type RestoreMode string
+
+const (
+	// RestoreReplace leaves storage holding the rows of the bundle and
+	// nothing else.
+	RestoreReplace RestoreMode = "replace"
+	// RestoreMerge writes the rows of the bundle over the records with
+	// the same IDs and keeps the others.
+	RestoreMerge RestoreMode = "merge"
+)
+
+// ParseRestoreMode accepts replace or merge. There is no default, since
+// the two differ in what they delete.
+func ParseRestoreMode(s string) (RestoreMode, error) {
+	switch mode := RestoreMode(s); mode {
+	case RestoreReplace, RestoreMerge:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid restore mode %q: expected replace or merge", s)
+	}
+}
+
+// RestoreOptions control a restore. A dry run checks the bundle, and
+// reports what it would do, without writing anything.
+type RestoreOptions struct {
+	Mode   RestoreMode
+	DryRun bool
+}
+
+// RestoreReport accounts for the rows of a bundle, table by table. A
+// restore with any Errors writes nothing.
+type RestoreReport struct {
+	DryRun   bool
+	Mode     RestoreMode
+	Snapshot *SnapshotManifest
+	Tables   []RestoreTableReport
+	Errors   []RestoreError
+}
+
+// Applied reports whether the rows were written.
+func (r *RestoreReport) Applied() bool {
+	return !r.DryRun && len(r.Errors) == 0
+}
+
+// RestoreTableReport counts the rows of one table of a bundle by what was,
+// or in a dry run would have been, done with them, and the records in
+// storage that a replace deleted for not being in the bundle.
+type RestoreTableReport struct {
+	Name      string
+	Rows      int
+	Created   int
+	Updated   int
+	Unchanged int
+	Deleted   int
+}
+
+// RestoreError is a rule that a row of a bundle breaks. In a merge it may
+// be a record in storage that the bundle would leave broken, such as an
+// opinion about a work the bundle credits to the opinion's writer.
+type RestoreError struct {
+	Table   string
+	ID      uint64
+	Message string
+}
+
+// BackupService takes snapshots of every source, writer, writer alias,
+// work and opinion, with their IDs, and restores them, here or elsewhere.
+// The audit log and opinion revisions are not part of a snapshot.
+type BackupService interface {
+	// Backup writes a snapshot bundle of the records as they were at one
+	// moment and returns its manifest. Every record is read before w is
+	// written to.
+	Backup(w io.Writer) (*SnapshotManifest, error)
+	// Restore reads a bundle of size bytes and holds its rows to the rules
+	// of the other services: opinions must refer to writers, works and
+	// sources that exist, works to authors that exist, and no one may hold
+	// an opinion about their own work or themselves. It then writes them in
+	// a single transaction, all of them or, if any breaks a rule, none,
+	// with an audit entry for each change. A bundle that cannot be read is
+	// an error wrapping ErrInvalidSnapshot.
+	Restore(ctx context.Context, bundle io.ReaderAt, size int64, options RestoreOptions) (*RestoreReport, error)
+}
+
+type backupService struct {
+	transactor repository.Transactor
+}
+
+func NewBackupService(transactor repository.Transactor) BackupService {
+	return &backupService{transactor: transactor}
+}
+
+func (s *backupService) Backup(w io.Writer) (*SnapshotManifest, error) {
+	var data *snapshotData
+	var createdAt time.Time
+	err := s.transactor.WithinSnapshot(func(repos *repository.Repositories) error {
+		createdAt = time.Now().UTC().Truncate(time.Second)
+		var err error
+		data, err = loadSnapshotData(repos)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return writeSnapshotBundle(w, data, createdAt)
+}
+
+// errRestoreRolledBack undoes a dry run, or a restore of rows that break
+// the rules, once every row has been checked.
+var errRestoreRolledBack = errors.New("restore rolled back")
+
+func (s *backupService) Restore(
+	ctx context.Context,
+	bundle io.ReaderAt,
+	size int64,
+	options RestoreOptions,
+) (*RestoreReport, error) {
+	if _, err := ParseRestoreMode(string(options.Mode)); err != nil {
+		return nil, err
+	}
+	manifest, incoming, rowErrors, err := readSnapshotBundle(bundle, size)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &RestoreReport{DryRun: options.DryRun, Mode: options.Mode, Snapshot: manifest}
+	err = s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		stored, err := loadSnapshotData(repos)
+		if err != nil {
+			return err
+		}
+		report.Errors = append(rowErrors, checkSnapshot(incoming, stored, options.Mode)...)
+		slices.SortStableFunc(report.Errors, func(a, b RestoreError) int {
+			return slices.Index(snapshotTables, a.Table) - slices.Index(snapshotTables, b.Table)
+		})
+
+		r := &restore{ctx: ctx, repos: repos, mode: options.Mode, manifest: manifest}
+		sources := planTable(r, restoreTable[domain.Source]{
+			name:     snapshotSources,
+			entity:   domain.AuditEntitySource,
+			id:       (*domain.Source).ID,
+			snapshot: sourceSnapshot,
+			create:   repos.Sources.Create,
+			update:   repos.Sources.Update,
+			remove:   repos.Sources.Delete,
+		}, incoming.sources, stored.sources)
+		writers := planTable(r, restoreTable[domain.Writer]{
+			name:     snapshotWriters,
+			entity:   domain.AuditEntityWriter,
+			id:       (*domain.Writer).ID,
+			snapshot: writerSnapshot,
+			create:   repos.Writers.Create,
+			update:   repos.Writers.Update,
+			remove:   repos.Writers.Delete,
+		}, incoming.writers, stored.writers)
+		aliases := planTable(r, restoreTable[domain.WriterAlias]{
+			name:     snapshotWriterAliases,
+			entity:   domain.AuditEntityWriterAlias,
+			id:       (*domain.WriterAlias).ID,
+			snapshot: writerAliasSnapshot,
+			create:   repos.WriterAliases.Create,
+			update:   repos.WriterAliases.Update,
+			remove:   repos.WriterAliases.Delete,
+			vacate:   true,
+		}, incoming.aliases, stored.aliases)
+		works := planTable(r, restoreTable[domain.Work]{
+			name:     snapshotWorks,
+			entity:   domain.AuditEntityWork,
+			id:       (*domain.Work).ID,
+			snapshot: workSnapshot,
+			create:   repos.Works.Create,
+			update:   repos.Works.Update,
+			remove:   repos.Works.Delete,
+		}, incoming.works, stored.works)
+		opinions := planTable(r, restoreTable[domain.Opinion]{
+			name:     snapshotOpinions,
+			entity:   domain.AuditEntityOpinion,
+			id:       (*domain.Opinion).ID,
+			snapshot: opinionSnapshot,
+			create: func(o *domain.Opinion) error {
+				if err := repos.Opinions.Create(o); err != nil {
+					return err
+				}
+				_, err := recordRevision(ctx, repos.OpinionRevisions, o)
+				return err
+			},
+			update: func(o *domain.Opinion) error {
+				if err := repos.Opinions.Update(o); err != nil {
+					return err
+				}
+				_, err := recordRevision(ctx, repos.OpinionRevisions, o)
+				return err
+			},
+			remove: repos.Opinions.Delete,
+		}, incoming.opinions, stored.opinions)
+		report.Tables = r.tables
+		if r.err != nil {
+			return r.err
+		}
+		if !report.Applied() {
+			return errRestoreRolledBack
+		}
+
+		// Records that go are deleted before those that refer to them, and
+		// those that stay are written after those they refer to. Writers
+		// and sources go last, once no work or opinion refers to them.
+		for _, step := range []func() error{
+			opinions.remove, works.remove, aliases.remove,
+			sources.write, writers.write, aliases.write, works.write, opinions.write,
+			writers.remove, sources.remove,
+		} {
+			if err := step(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errRestoreRolledBack) {
+		return nil, err
+	}
+	return report, nil
+}
+
+// restore is a restore being planned, which collects the report of each
+// table and the first error.
+type restore struct {
+	ctx      context.Context
+	repos    *repository.Repositories
+	mode     RestoreMode
+	manifest *SnapshotManifest
+	tables   []RestoreTableReport
+	err      error
+}
+
+// restoreTable is how a restore writes the records of one table.
+type restoreTable[T any] struct {
+	name     string
+	entity   domain.AuditEntityType
+	id       func(*T) uint64
+	snapshot func(*T) map[string]any
+	create   func(*T) error
+	update   func(*T) error
+	remove   func(id uint64) error
+	// vacate removes the records that are to be updated along with those
+	// that are to be deleted, and writes them afresh, for a table with a
+	// unique key that rows of a bundle may trade, as aliases trade names.
+	vacate bool
+}
+
+// tableChanges are the writes that bring a table in line with a bundle.
+type tableChanges[T any] struct {
+	restore *restore
+	table   restoreTable[T]
+	created []*T
+	updated [][2]*T
+	removed []*T
+}
+
+// planTable compares the rows of a bundle with the records in storage by
+// ID. A row that is the same as its record, as the audit log would show
+// them, is left alone.
+func planTable[T any](r *restore, table restoreTable[T], incoming, stored []*T) *tableChanges[T] {
+	manifestTable, _ := r.manifest.table(table.name)
+	report := RestoreTableReport{Name: table.name, Rows: manifestTable.Rows}
+	changes := &tableChanges[T]{restore: r, table: table}
+
+	remaining := make(map[uint64]*T, len(stored))
+	for _, record := range stored {
+		remaining[table.id(record)] = record
+	}
+	for _, row := range incoming {
+		before, ok := remaining[table.id(row)]
+		if !ok {
+			changes.created = append(changes.created, row)
+			report.Created++
+			continue
+		}
+		delete(remaining, table.id(row))
+
+		same, err := sameSnapshot(table.snapshot(before), table.snapshot(row))
+		if err != nil && r.err == nil {
+			r.err = err
+		}
+		if same {
+			report.Unchanged++
+			continue
+		}
+		changes.updated = append(changes.updated, [2]*T{before, row})
+		report.Updated++
+	}
+	if r.mode == RestoreReplace {
+		for _, record := range stored {
+			if _, ok := remaining[table.id(record)]; ok {
+				changes.removed = append(changes.removed, record)
+				report.Deleted++
+			}
+		}
+	}
+	r.tables = append(r.tables, report)
+	return changes
+}
+
+// sameSnapshot reports whether two records would look the same in the
+// audit log.
+func sameSnapshot(a, b map[string]any) (bool, error) {
+	aJSON, err := marshalSnapshot(a)
+	if err != nil {
+		return false, err
+	}
+	bJSON, err := marshalSnapshot(b)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(aJSON, bJSON), nil
+}
+
+// write saves the updated rows before creating the new ones, which may take
+// up the values of a unique key that the updates give up.
+func (c *tableChanges[T]) write() error {
+	update := c.table.update
+	if c.table.vacate {
+		update = c.table.create
+	}
+	for _, pair := range c.updated {
+		before, row := pair[0], pair[1]
+		if err := update(row); err != nil {
+			return fmt.Errorf("%s %d: %w", c.table.name, c.table.id(row), err)
+		}
+		if err := c.record(row, domain.AuditActionUpdate, c.table.snapshot(before), c.table.snapshot(row)); err != nil {
+			return err
+		}
+	}
+	for _, row := range c.created {
+		if err := c.table.create(row); err != nil {
+			return fmt.Errorf("%s %d: %w", c.table.name, c.table.id(row), err)
+		}
+		if err := c.record(row, domain.AuditActionCreate, nil, c.table.snapshot(row)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *tableChanges[T]) remove() error {
+	if c.table.vacate {
+		for _, pair := range c.updated {
+			if err := c.table.remove(c.table.id(pair[0])); err != nil {
+				return fmt.Errorf("%s %d: %w", c.table.name, c.table.id(pair[0]), err)
+			}
+		}
+	}
+	for _, record := range c.removed {
+		if err := c.table.remove(c.table.id(record)); err != nil {
+			return fmt.Errorf("%s %d: %w", c.table.name, c.table.id(record), err)
+		}
+		if err := c.record(record, domain.AuditActionDelete, c.table.snapshot(record), nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *tableChanges[T]) record(row *T, action domain.AuditAction, before, after map[string]any) error {
+	r := c.restore
+	return recordChange(r.ctx, r.repos.Audit, c.table.entity, entityID(c.table.id(row)), action, before, after)
+}
+
+// checkSnapshot holds the rows of a bundle to the rules of the other
+// services and to each other. In a merge, rows may also refer to records
+// in storage, and the stored opinions about works in the bundle are
+// checked again, since the bundle may credit the work to other authors.
+// Translations of quotes are put in canonical form.
+func checkSnapshot(incoming, stored *snapshotData, mode RestoreMode) []RestoreError {
+	c := &snapshotCheck{}
+	sources := indexRecords(c, snapshotSources, incoming.sources, (*domain.Source).ID, stored.sources, mode)
+	writers := indexRecords(c, snapshotWriters, incoming.writers, (*domain.Writer).ID, stored.writers, mode)
+	indexRecords(c, snapshotWriterAliases, incoming.aliases, (*domain.WriterAlias).ID, stored.aliases, mode)
+	works := indexRecords(c, snapshotWorks, incoming.works, (*domain.Work).ID, stored.works, mode)
+	indexRecords(c, snapshotOpinions, incoming.opinions, (*domain.Opinion).ID, stored.opinions, mode)
+
+	for _, s := range incoming.sources {
+		if s.Title() == "" {
+			c.fail(snapshotSources, s.ID(), errors.New("title is required"))
+		}
+		// Candidate sources have no type until a curator confirms them
+		if s.Type() != "" && !s.Type().IsValid() {
+			c.fail(snapshotSources, s.ID(), errors.New("invalid source type"))
+		}
+	}
+
+	for _, w := range incoming.writers {
+		if err := validateWriter(w.Name(), w.BirthYear()); err != nil {
+			c.fail(snapshotWriters, w.ID(), err)
+		}
+	}
+
+	type aliasKey struct {
+		writerID uint64
+		name     string
+	}
+	aliases := make(map[aliasKey]bool)
+	if mode == RestoreMerge {
+		incomingIDs := make(map[uint64]bool, len(incoming.aliases))
+		for _, a := range incoming.aliases {
+			incomingIDs[a.ID()] = true
+		}
+		for _, a := range stored.aliases {
+			if !incomingIDs[a.ID()] {
+				aliases[aliasKey{a.WriterID(), a.Name()}] = true
+			}
+		}
+	}
+	for _, a := range incoming.aliases {
+		if err := validateAlias(a.Name(), a.Kind()); err != nil {
+			c.fail(snapshotWriterAliases, a.ID(), err)
+		}
+		if writers[a.WriterID()] == nil {
+			c.fail(snapshotWriterAliases, a.ID(), errors.New("writer not found"))
+		}
+		key := aliasKey{a.WriterID(), a.Name()}
+		if aliases[key] {
+			c.fail(snapshotWriterAliases, a.ID(),
+				fmt.Errorf("writer %d already has the alias %q", a.WriterID(), a.Name()))
+		}
+		aliases[key] = true
+	}
+
+	for _, w := range incoming.works {
+		if err := validateWork(w.Title()); err != nil {
+			c.fail(snapshotWorks, w.ID(), err)
+		}
+		seen := make(map[uint64]bool, len(w.AuthorIDs()))
+		for _, id := range w.AuthorIDs() {
+			if seen[id] {
+				c.fail(snapshotWorks, w.ID(), fmt.Errorf("author %d is listed more than once", id))
+			} else if writers[id] == nil {
+				c.fail(snapshotWorks, w.ID(), fmt.Errorf("author %d not found", id))
+			}
+			seen[id] = true
+		}
+	}
+
+	incomingOpinions := make(map[uint64]bool, len(incoming.opinions))
+	for _, o := range incoming.opinions {
+		incomingOpinions[o.ID()] = true
+		if err := validateOpinionContent(o.Sentiment(), o.Quote(), o.Source(), o.SourceID()); err != nil {
+			c.fail(snapshotOpinions, o.ID(), err)
+		}
+		languages, err := validateLanguages(o.Languages())
+		if err != nil {
+			c.fail(snapshotOpinions, o.ID(), err)
+		} else {
+			o.SetLanguages(languages)
+		}
+		if o.SourceID() != 0 && sources[o.SourceID()] == nil {
+			c.fail(snapshotOpinions, o.ID(), errors.New("source not found"))
+		}
+		c.checkTargets(o, writers, works)
+	}
+	if mode == RestoreMerge {
+		incomingWorks := make(map[uint64]bool, len(incoming.works))
+		for _, w := range incoming.works {
+			incomingWorks[w.ID()] = true
+		}
+		for _, o := range stored.opinions {
+			if !incomingOpinions[o.ID()] && incomingWorks[o.WorkID()] {
+				c.checkTargets(o, writers, works)
+			}
+		}
+	}
+	return c.errors
+}
+
+type snapshotCheck struct {
+	errors []RestoreError
+}
+
+func (c *snapshotCheck) fail(table string, id uint64, err error) {
+	c.errors = append(c.errors, RestoreError{Table: table, ID: id, Message: err.Error()})
+}
+
+// indexRecords maps the records a restore would leave by ID: the rows of
+// the bundle and, in a merge, the stored records it does not replace. Rows
+// must have an ID, and only one row each.
+func indexRecords[T any](
+	c *snapshotCheck,
+	table string,
+	incoming []*T,
+	id func(*T) uint64,
+	stored []*T,
+	mode RestoreMode,
+) map[uint64]*T {
+	records := make(map[uint64]*T, len(incoming)+len(stored))
+	for _, row := range incoming {
+		switch rowID := id(row); {
+		case rowID == 0:
+			c.fail(table, 0, errors.New("id is required"))
+		case records[rowID] != nil:
+			c.fail(table, rowID, errors.New("id is used by more than one row"))
+		default:
+			records[rowID] = row
+		}
+	}
+	if mode == RestoreMerge {
+		for _, record := range stored {
+			if records[id(record)] == nil {
+				records[id(record)] = record
+			}
+		}
+	}
+	return records
+}
+
+// checkTargets applies the rules of checkParticipants to the records a
+// restore would leave.
+func (c *snapshotCheck) checkTargets(
+	o *domain.Opinion,
+	writers map[uint64]*domain.Writer,
+	works map[uint64]*domain.Work,
+) {
+	if o.IsAboutWriter() {
+		if o.TargetWriterID() == o.WriterID() {
+			c.fail(snapshotOpinions, o.ID(), errors.New("writer cannot express opinion about themselves"))
+		} else if writers[o.TargetWriterID()] == nil {
+			c.fail(snapshotOpinions, o.ID(), errors.New("target writer not found"))
+		}
+	} else if work := works[o.WorkID()]; work == nil {
+		c.fail(snapshotOpinions, o.ID(), errors.New("work not found"))
+	} else if work.HasAuthor(o.WriterID()) {
+		c.fail(snapshotOpinions, o.ID(), errors.New("writer cannot express opinion about their own work"))
+	}
+	if writers[o.WriterID()] == nil {
+		c.fail(snapshotOpinions, o.ID(), errors.New("writer not found"))
+	}
+}
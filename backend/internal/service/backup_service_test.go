@@ -0,0 +1,366 @@
+package service_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/repository"
+	"github.com/what-writers-like/backend/internal/repository/memory"
+	"github.com/what-writers-like/backend/internal/service"
+)
+
+// seedBackupStore holds a writer with an alias, a work by another writer,
+// a cited source and a translated opinion about the work.
+func seedBackupStore(t *testing.T) *memory.Store {
+	t.Helper()
+	store := memory.NewStore()
+	died := 1910
+	require.NoError(t, memory.NewWriterRepository(store).Create(domain.NewWriter(1, "Leo Tolstoy", 1828, &died, nil)))
+	require.NoError(t, memory.NewWriterRepository(store).Create(newWriter(2, "William Shakespeare", 1564)))
+	require.NoError(t, memory.NewWriterAliasRepository(store).Create(
+		domain.NewWriterAlias(0, 1, "Лев Толстой", domain.AliasKindNativeScript),
+	))
+	require.NoError(t, memory.NewWorkRepository(store).Create(newWork(1, "King Lear", 2)))
+	source := domain.NewSource(0, domain.SourceTypeEssay, "Shakespeare and the Drama", domain.SourceDetails{}, true)
+	require.NoError(t, memory.NewSourceRepository(store).Create(source))
+	opinion := domain.NewOpinion(0, 1, 1, domain.SentimentVeryNegative, "Крайне плохо", "Essay", nil, nil)
+	opinion.SetSourceID(source.ID())
+	opinion.SetLanguages(domain.QuoteLanguages{
+		Language:     "ru",
+		Translations: []domain.Translation{{Language: "en", Text: "Very poor"}},
+	})
+	require.NoError(t, memory.NewOpinionRepository(store).Create(opinion))
+	return store
+}
+
+func newWriter(id uint64, name string, birthYear int) *domain.Writer {
+	return domain.NewWriter(id, name, birthYear, nil, nil)
+}
+
+func newWork(id uint64, title string, authorIDs ...uint64) *domain.Work {
+	return domain.NewWork(id, title, authorIDs, domain.WorkDetails{})
+}
+
+func backup(t *testing.T, store *memory.Store) ([]byte, *service.SnapshotManifest) {
+	t.Helper()
+	var bundle bytes.Buffer
+	manifest, err := service.NewBackupService(memory.NewTransactor(store)).Backup(&bundle)
+	require.NoError(t, err)
+	return bundle.Bytes(), manifest
+}
+
+func restore(
+	t *testing.T,
+	store *memory.Store,
+	bundle []byte,
+	mode service.RestoreMode,
+	dryRun bool,
+) (*service.RestoreReport, error) {
+	t.Helper()
+	svc := service.NewBackupService(memory.NewTransactor(store))
+	return svc.Restore(context.Background(), bytes.NewReader(bundle), int64(len(bundle)), service.RestoreOptions{
+		Mode:   mode,
+		DryRun: dryRun,
+	})
+}
+
+func tableReport(t *testing.T, report *service.RestoreReport, name string) service.RestoreTableReport {
+	t.Helper()
+	for _, table := range report.Tables {
+		if table.Name == name {
+			return table
+		}
+	}
+	t.Fatalf("no report for table %s", name)
+	return service.RestoreTableReport{}
+}
+
+func TestBackupService_Backup(t *testing.T) {
+	t.Parallel()
+	bundle, manifest := backup(t, seedBackupStore(t))
+
+	assert.Equal(t, service.SnapshotFormat, manifest.Format)
+	assert.Equal(t, service.SnapshotSchemaVersion, manifest.SchemaVersion)
+	assert.False(t, manifest.CreatedAt.IsZero())
+	rows := map[string]int{}
+	for _, table := range manifest.Tables {
+		rows[table.Name] = table.Rows
+		assert.Len(t, table.SHA256, 64)
+	}
+	assert.Equal(t, map[string]int{"sources": 1, "writers": 2, "writer_aliases": 1, "works": 1, "opinions": 1}, rows)
+
+	archive, err := zip.NewReader(bytes.NewReader(bundle), int64(len(bundle)))
+	require.NoError(t, err)
+	var names []string
+	for _, file := range archive.File {
+		names = append(names, file.Name)
+	}
+	assert.Equal(t, []string{
+		"manifest.json", "sources.ndjson", "writers.ndjson", "writer_aliases.ndjson", "works.ndjson", "opinions.ndjson",
+	}, names)
+
+	file, err := archive.File[5].Open()
+	require.NoError(t, err)
+	var opinion map[string]any
+	require.NoError(t, json.NewDecoder(file).Decode(&opinion))
+	assert.Equal(t, "Крайне плохо", opinion["quote"])
+	assert.Equal(t, "-2", opinion["sentiment_grade"])
+}
+
+func TestBackupService_Restore(t *testing.T) {
+	t.Parallel()
+
+	t.Run("replace into an empty store keeps IDs", func(t *testing.T) {
+		t.Parallel()
+		bundle, _ := backup(t, seedBackupStore(t))
+		store := memory.NewStore()
+
+		report, err := restore(t, store, bundle, service.RestoreReplace, false)
+		require.NoError(t, err)
+		assert.Empty(t, report.Errors)
+		assert.True(t, report.Applied())
+		assert.Equal(t, 2, tableReport(t, report, "writers").Created)
+
+		opinion, err := memory.NewOpinionRepository(store).GetByID(1)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), opinion.SourceID())
+		assert.Equal(t, "ru", opinion.Languages().Language)
+		require.Len(t, opinion.Languages().Translations, 1)
+		assert.Equal(t, "Very poor", opinion.Languages().Translations[0].Text)
+		aliases, err := memory.NewWriterAliasRepository(store).ListByWriter(1)
+		require.NoError(t, err)
+		require.Len(t, aliases, 1)
+		assert.Equal(t, "Лев Толстой", aliases[0].Name())
+
+		// Every record gets an audit entry, and the opinion its first revision
+		entries, err := memory.NewAuditRepository(store).Find(repository.AuditFilter{}, 20, 0)
+		require.NoError(t, err)
+		assert.Len(t, entries, 6)
+		revisions, err := memory.NewOpinionRevisionRepository(store).ListByOpinion(1)
+		require.NoError(t, err)
+		assert.Len(t, revisions, 1)
+
+		// A new record is numbered after the restored ones
+		writer := domain.NewWriter(0, "Anton Chekhov", 1860, nil, nil)
+		require.NoError(t, memory.NewWriterRepository(store).Create(writer))
+		assert.Equal(t, uint64(3), writer.ID())
+	})
+
+	t.Run("replace deletes records missing from the bundle", func(t *testing.T) {
+		t.Parallel()
+		bundle, _ := backup(t, seedBackupStore(t))
+		store := seedBackupStore(t)
+		require.NoError(t, memory.NewWriterRepository(store).Create(newWriter(3, "Anton Chekhov", 1860)))
+		require.NoError(t, memory.NewWorkRepository(store).Create(newWork(2, "The Seagull", 3)))
+		require.NoError(t, memory.NewOpinionRepository(store).Create(domain.NewOpinion(
+			0, 1, 2, domain.SentimentNegative, "Worse than Shakespeare", "Diary", nil, nil,
+		)))
+
+		report, err := restore(t, store, bundle, service.RestoreReplace, false)
+		require.NoError(t, err)
+		require.Empty(t, report.Errors)
+		assert.Equal(t, service.RestoreTableReport{Name: "writers", Rows: 2, Unchanged: 2, Deleted: 1},
+			tableReport(t, report, "writers"))
+		assert.Equal(t, 1, tableReport(t, report, "opinions").Deleted)
+
+		_, err = memory.NewWriterRepository(store).GetByID(3)
+		require.Error(t, err)
+		_, err = memory.NewWorkRepository(store).GetByID(2)
+		require.Error(t, err)
+	})
+
+	t.Run("merge keeps other records and updates changed ones", func(t *testing.T) {
+		t.Parallel()
+		source := seedBackupStore(t)
+		require.NoError(t, memory.NewWriterRepository(source).Update(newWriter(2, "W. Shakespeare", 1564)))
+		bundle, _ := backup(t, source)
+
+		store := seedBackupStore(t)
+		require.NoError(t, memory.NewWriterRepository(store).Create(newWriter(3, "Anton Chekhov", 1860)))
+
+		report, err := restore(t, store, bundle, service.RestoreMerge, false)
+		require.NoError(t, err)
+		require.Empty(t, report.Errors)
+		assert.Equal(t, service.RestoreTableReport{Name: "writers", Rows: 2, Updated: 1, Unchanged: 1},
+			tableReport(t, report, "writers"))
+
+		writer, err := memory.NewWriterRepository(store).GetByID(2)
+		require.NoError(t, err)
+		assert.Equal(t, "W. Shakespeare", writer.Name())
+		_, err = memory.NewWriterRepository(store).GetByID(3)
+		require.NoError(t, err)
+
+		// Merging the same bundle again changes nothing
+		entries, err := memory.NewAuditRepository(store).Find(repository.AuditFilter{}, 20, 0)
+		require.NoError(t, err)
+		report, err = restore(t, store, bundle, service.RestoreMerge, false)
+		require.NoError(t, err)
+		assert.Equal(t, 0, tableReport(t, report, "writers").Updated)
+		after, err := memory.NewAuditRepository(store).Find(repository.AuditFilter{}, 20, 0)
+		require.NoError(t, err)
+		assert.Len(t, after, len(entries))
+	})
+
+	t.Run("dry run writes nothing", func(t *testing.T) {
+		t.Parallel()
+		bundle, _ := backup(t, seedBackupStore(t))
+		store := memory.NewStore()
+
+		report, err := restore(t, store, bundle, service.RestoreReplace, true)
+		require.NoError(t, err)
+		assert.False(t, report.Applied())
+		assert.Equal(t, 1, tableReport(t, report, "opinions").Created)
+		_, err = memory.NewWriterRepository(store).GetByID(1)
+		require.Error(t, err)
+	})
+}
+
+func TestBackupService_RestoreTradesAliasNames(t *testing.T) {
+	t.Parallel()
+	aliases := func(names map[uint64]string) *memory.Store {
+		store := memory.NewStore()
+		require.NoError(t, memory.NewWriterRepository(store).Create(newWriter(1, "Leo Tolstoy", 1828)))
+		for _, id := range []uint64{1, 2, 3, 4} {
+			if name, ok := names[id]; ok {
+				require.NoError(t, memory.NewWriterAliasRepository(store).Create(
+					domain.NewWriterAlias(id, 1, name, domain.AliasKindTransliteration),
+				))
+			}
+		}
+		return store
+	}
+
+	// Aliases 1 and 2 swap names, and alias 3 takes the name of alias 4
+	bundle, _ := backup(t, aliases(map[uint64]string{1: "Lev Tolstoi", 2: "Lev Tolstoy", 3: "Graf Tolstoy"}))
+	store := aliases(map[uint64]string{1: "Lev Tolstoy", 2: "Lev Tolstoi", 4: "Graf Tolstoy"})
+
+	report, err := restore(t, store, bundle, service.RestoreReplace, false)
+	require.NoError(t, err)
+	require.Empty(t, report.Errors)
+	assert.Equal(t, service.RestoreTableReport{Name: "writer_aliases", Rows: 3, Created: 1, Updated: 2, Deleted: 1},
+		tableReport(t, report, "writer_aliases"))
+
+	names := map[uint64]string{}
+	require.NoError(t, memory.NewWriterAliasRepository(store).ForEach(func(alias *domain.WriterAlias) error {
+		names[alias.ID()] = alias.Name()
+		return nil
+	}))
+	assert.Equal(t, map[uint64]string{1: "Lev Tolstoi", 2: "Lev Tolstoy", 3: "Graf Tolstoy"}, names)
+}
+
+func TestBackupService_RestoreChecksIntegrity(t *testing.T) {
+	t.Parallel()
+
+	t.Run("broken references and own-work opinions", func(t *testing.T) {
+		t.Parallel()
+		// The in-memory store holds whatever its repositories are given
+		source := seedBackupStore(t)
+		require.NoError(t, memory.NewWorkRepository(source).Create(newWork(2, "Hamlet", 9)))
+		require.NoError(t, memory.NewOpinionRepository(source).Create(domain.NewOpinion(
+			0, 2, 2, domain.SentimentPositive, "My best", "Letters", nil, nil,
+		)))
+		require.NoError(t, memory.NewWorkRepository(source).Update(newWork(2, "Hamlet", 2, 9)))
+		require.NoError(t, memory.NewWriterRepository(source).Delete(1))
+		bundle, _ := backup(t, source)
+
+		store := memory.NewStore()
+		report, err := restore(t, store, bundle, service.RestoreReplace, false)
+		require.NoError(t, err)
+		assert.False(t, report.Applied())
+		assert.Equal(t, []service.RestoreError{
+			{Table: "works", ID: 2, Message: "author 9 not found"},
+			{Table: "opinions", ID: 1, Message: "writer not found"},
+			{Table: "opinions", ID: 2, Message: "writer cannot express opinion about their own work"},
+		}, report.Errors)
+		_, err = memory.NewWriterRepository(store).GetByID(2)
+		require.Error(t, err)
+	})
+
+	t.Run("merge checks stored opinions about restored works", func(t *testing.T) {
+		t.Parallel()
+		// The bundle credits King Lear to Tolstoy as well, who has an
+		// opinion about it in storage
+		source := memory.NewStore()
+		require.NoError(t, memory.NewWriterRepository(source).Create(newWriter(1, "Leo Tolstoy", 1828)))
+		require.NoError(t, memory.NewWriterRepository(source).Create(newWriter(2, "William Shakespeare", 1564)))
+		require.NoError(t, memory.NewWorkRepository(source).Create(
+			domain.NewWork(1, "King Lear", []uint64{2, 1}, domain.WorkDetails{}),
+		))
+		bundle, _ := backup(t, source)
+
+		store := seedBackupStore(t)
+		report, err := restore(t, store, bundle, service.RestoreMerge, false)
+		require.NoError(t, err)
+		assert.Equal(t, []service.RestoreError{
+			{Table: "opinions", ID: 1, Message: "writer cannot express opinion about their own work"},
+		}, report.Errors)
+
+		// Replacing drops the opinion instead
+		report, err = restore(t, store, bundle, service.RestoreReplace, false)
+		require.NoError(t, err)
+		assert.Empty(t, report.Errors)
+		assert.Equal(t, 1, tableReport(t, report, "opinions").Deleted)
+	})
+
+	t.Run("bundles that do not match their manifest", func(t *testing.T) {
+		t.Parallel()
+		bundle, _ := backup(t, seedBackupStore(t))
+
+		tampered := rewriteBundle(t, bundle, "writers.ndjson", func(contents []byte) []byte {
+			return bytes.Replace(contents, []byte("Leo Tolstoy"), []byte("Lev Tolstoy"), 1)
+		})
+		_, err := restore(t, memory.NewStore(), tampered, service.RestoreReplace, false)
+		require.ErrorIs(t, err, service.ErrInvalidSnapshot)
+		assert.Contains(t, err.Error(), "checksum")
+
+		newer := rewriteBundle(t, bundle, "manifest.json", func(contents []byte) []byte {
+			return bytes.Replace(contents, []byte(`"schema_version": 1`), []byte(`"schema_version": 2`), 1)
+		})
+		_, err = restore(t, memory.NewStore(), newer, service.RestoreReplace, false)
+		require.ErrorIs(t, err, service.ErrInvalidSnapshot)
+		assert.Contains(t, err.Error(), "schema version 2")
+
+		_, err = restore(t, memory.NewStore(), []byte("name,birth_year\n"), service.RestoreReplace, false)
+		require.ErrorIs(t, err, service.ErrInvalidSnapshot)
+	})
+}
+
+// rewriteBundle copies a bundle with the contents of one file changed.
+func rewriteBundle(t *testing.T, bundle []byte, name string, change func([]byte) []byte) []byte {
+	t.Helper()
+	archive, err := zip.NewReader(bytes.NewReader(bundle), int64(len(bundle)))
+	require.NoError(t, err)
+	var out bytes.Buffer
+	writer := zip.NewWriter(&out)
+	for _, file := range archive.File {
+		reader, err := file.Open()
+		require.NoError(t, err)
+		contents, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		if file.Name == name {
+			contents = change(contents)
+		}
+		w, err := writer.Create(file.Name)
+		require.NoError(t, err)
+		_, err = w.Write(contents)
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	return out.Bytes()
+}
+
+func TestParseRestoreMode(t *testing.T) {
+	t.Parallel()
+	mode, err := service.ParseRestoreMode("merge")
+	require.NoError(t, err)
+	assert.Equal(t, service.RestoreMerge, mode)
+	_, err = service.ParseRestoreMode("")
+	require.Error(t, err)
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/repository"
+)
+
+// ExportService reads out every writer, work, opinion and source for full
+// dumps of the data. Each method calls fn with the rows one at a time in
+// order of ID, reading them from storage in batches so that a dump takes
+// the same memory however large the table. The walk stops at the first
+// error fn returns, or once ctx is done.
+type ExportService interface {
+	ExportWriters(ctx context.Context, fn func(*domain.Writer) error) error
+	ExportWorks(ctx context.Context, fn func(*domain.Work) error) error
+	ExportOpinions(ctx context.Context, fn func(*domain.Opinion) error) error
+	ExportSources(ctx context.Context, fn func(*domain.Source) error) error
+}
+
+type exportService struct {
+	writerRepo  repository.WriterRepository
+	workRepo    repository.WorkRepository
+	opinionRepo repository.OpinionRepository
+	sourceRepo  repository.SourceRepository
+}
+
+func NewExportService(
+	writerRepo repository.WriterRepository,
+	workRepo repository.WorkRepository,
+	opinionRepo repository.OpinionRepository,
+	sourceRepo repository.SourceRepository,
+) ExportService {
+	return &exportService{
+		writerRepo:  writerRepo,
+		workRepo:    workRepo,
+		opinionRepo: opinionRepo,
+		sourceRepo:  sourceRepo,
+	}
+}
+
+func (s *exportService) ExportWriters(ctx context.Context, fn func(*domain.Writer) error) error {
+	return s.writerRepo.ForEach(untilDone(ctx, fn))
+}
+
+func (s *exportService) ExportWorks(ctx context.Context, fn func(*domain.Work) error) error {
+	return s.workRepo.ForEach(untilDone(ctx, fn))
+}
+
+func (s *exportService) ExportOpinions(ctx context.Context, fn func(*domain.Opinion) error) error {
+	return s.opinionRepo.ForEach(untilDone(ctx, fn))
+}
+
+func (s *exportService) ExportSources(ctx context.Context, fn func(*domain.Source) error) error {
+	return s.sourceRepo.ForEach(untilDone(ctx, fn))
+}
+
+// untilDone wraps fn to fail with the context's error once ctx is done,
+// such as when the client of a download has gone away.
+func untilDone[T any](ctx context.Context, fn func(T) error) func(T) error {
+	return func(row T) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return fn(row)
+	}
+}
@@ -0,0 +1,49 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/repository/memory"
+	"github.com/what-writers-like/backend/internal/service"
+)
+
+func newExportService(store *memory.Store) service.ExportService {
+	return service.NewExportService(
+		memory.NewWriterRepository(store),
+		memory.NewWorkRepository(store),
+		memory.NewOpinionRepository(store),
+		memory.NewSourceRepository(store),
+	)
+}
+
+func TestExportService_ExportWriters(t *testing.T) {
+	t.Parallel()
+	store := memory.NewStore()
+	writers := memory.NewWriterRepository(store)
+	require.NoError(t, writers.Create(domain.NewWriter(2, "Charles Dickens", 1812, nil, nil)))
+	require.NoError(t, writers.Create(domain.NewWriter(1, "Jane Austen", 1775, nil, nil)))
+	svc := newExportService(store)
+
+	var names []string
+	err := svc.ExportWriters(context.Background(), func(w *domain.Writer) error {
+		names = append(names, w.Name())
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Jane Austen", "Charles Dickens"}, names)
+
+	// A cancelled export stops before the next row
+	ctx, cancel := context.WithCancel(context.Background())
+	names = nil
+	err = svc.ExportWriters(ctx, func(w *domain.Writer) error {
+		names = append(names, w.Name())
+		cancel()
+		return nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []string{"Jane Austen"}, names)
+}
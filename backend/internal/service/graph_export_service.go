@@ -0,0 +1,224 @@
+package service
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/what-writers-like/backend/internal/domain"
+)
+
+// GraphFormat is a file format for graph analysis tools: GraphML for
+// Cytoscape and Gephi, GEXF for Gephi, DOT for Graphviz, and a Cypher
+// script for Neo4j.
+type GraphFormat string
+
+const (
+	GraphFormatGraphML GraphFormat = "graphml"
+	GraphFormatGEXF    GraphFormat = "gexf"
+	GraphFormatDOT     GraphFormat = "dot"
+	GraphFormatCypher  GraphFormat = "cypher"
+)
+
+// GraphFormats lists every format a graph can be exported in.
+var GraphFormats = []GraphFormat{GraphFormatGraphML, GraphFormatGEXF, GraphFormatDOT, GraphFormatCypher}
+
+func ParseGraphFormat(s string) (GraphFormat, error) {
+	if _, ok := graphWriters[GraphFormat(s)]; !ok {
+		return "", fmt.Errorf("invalid format %q: expected graphml, gexf, dot or cypher", s)
+	}
+	return GraphFormat(s), nil
+}
+
+// GraphExportService writes the graph of writers, works and opinions in the
+// formats of graph analysis tools. Writers and works are nodes; edges run
+// from authors to their works and from opinion holders to the work or
+// writer their opinion is about. Nodes carry names, titles and years, and
+// opinion edges the sentiment, quote, source and statement year.
+type GraphExportService interface {
+	// ExportGraph writes the graph that GraphService's GetGraph returns for
+	// filter.
+	ExportGraph(w io.Writer, filter GraphFilter, format GraphFormat) error
+}
+
+type graphExportService struct {
+	graphService GraphService
+}
+
+func NewGraphExportService(graphService GraphService) GraphExportService {
+	return &graphExportService{graphService: graphService}
+}
+
+func (s *graphExportService) ExportGraph(w io.Writer, filter GraphFilter, format GraphFormat) error {
+	if _, err := ParseGraphFormat(string(format)); err != nil {
+		return err
+	}
+	graph, err := s.graphService.GetGraph(filter)
+	if err != nil {
+		return err
+	}
+
+	// bufio.Writer keeps the first error, so the format writers need not
+	// check each write
+	out := bufio.NewWriter(w)
+	graphWriters[format](out, newGraphDocument(graph))
+	return out.Flush()
+}
+
+var graphWriters = map[GraphFormat]func(w *bufio.Writer, doc *graphDocument){
+	GraphFormatGraphML: writeGraphML,
+	GraphFormatGEXF:    writeGEXF,
+	GraphFormatDOT:     writeDOT,
+	GraphFormatCypher:  writeCypher,
+}
+
+// graphAttribute is a property that nodes or edges may have. Its values are
+// strings, or ints when integer is set.
+type graphAttribute struct {
+	name    string
+	integer bool
+}
+
+var (
+	nodeAttributes = []graphAttribute{
+		{name: "type"},
+		{name: "name"},
+		{name: "birth_year", integer: true},
+		{name: "death_year", integer: true},
+		{name: "title"},
+		{name: "publication_year", integer: true},
+	}
+	edgeAttributes = []graphAttribute{
+		{name: "type"},
+		{name: "sentiment"},
+		{name: "sentiment_score", integer: true},
+		{name: "quote"},
+		{name: "source"},
+		{name: "statement_year", integer: true},
+	}
+)
+
+const (
+	nodeTypeWriter   = "writer"
+	nodeTypeWork     = "work"
+	edgeTypeAuthored = "authored"
+	edgeTypeOpinion  = "opinion"
+)
+
+// graphNode is a writer or work of an exported graph. values holds its
+// attributes by name, leaving out those it has no value for.
+type graphNode struct {
+	kind   string
+	id     uint64
+	label  string
+	values map[string]any
+}
+
+// key identifies the node among writers and works alike, as the graph API
+// does.
+func (n *graphNode) key() string {
+	return fmt.Sprintf("%s-%d", n.kind, n.id)
+}
+
+// graphEdge is an authorship or opinion link. id is the opinion's ID, and
+// zero for authorship.
+type graphEdge struct {
+	kind           string
+	id             uint64
+	source, target *graphNode
+	values         map[string]any
+}
+
+func (e *graphEdge) key() string {
+	if e.kind == edgeTypeAuthored {
+		return fmt.Sprintf("authored-%d-%d", e.target.id, e.source.id)
+	}
+	return fmt.Sprintf("opinion-%d", e.id)
+}
+
+// graphDocument is a graph as the formats lay it out, writers before works
+// and authorship before opinions.
+type graphDocument struct {
+	nodes []*graphNode
+	edges []*graphEdge
+}
+
+// newGraphDocument leaves out edges to nodes the graph does not hold, such
+// as the author of a work whose writer was deleted, like the graph API.
+func newGraphDocument(graph *Graph) *graphDocument {
+	doc := &graphDocument{}
+	writers := make(map[uint64]*graphNode, len(graph.Writers))
+	for _, w := range graph.Writers {
+		node := &graphNode{kind: nodeTypeWriter, id: w.ID(), label: w.Name(), values: map[string]any{
+			"type":       nodeTypeWriter,
+			"name":       w.Name(),
+			"birth_year": w.BirthYear(),
+		}}
+		if w.DeathYear() != nil {
+			node.values["death_year"] = *w.DeathYear()
+		}
+		writers[w.ID()] = node
+		doc.nodes = append(doc.nodes, node)
+	}
+	works := make(map[uint64]*graphNode, len(graph.Works))
+	for _, w := range graph.Works {
+		node := &graphNode{kind: nodeTypeWork, id: w.ID(), label: w.Title(), values: map[string]any{
+			"type":  nodeTypeWork,
+			"title": w.Title(),
+		}}
+		if year := w.Details().PublicationYear; year != nil {
+			node.values["publication_year"] = *year
+		}
+		works[w.ID()] = node
+		doc.nodes = append(doc.nodes, node)
+	}
+
+	for _, w := range graph.Works {
+		for _, authorID := range w.AuthorIDs() {
+			if author, ok := writers[authorID]; ok {
+				doc.edges = append(doc.edges, &graphEdge{
+					kind:   edgeTypeAuthored,
+					source: author,
+					target: works[w.ID()],
+					values: map[string]any{"type": edgeTypeAuthored},
+				})
+			}
+		}
+	}
+	for _, o := range graph.Opinions {
+		target, ok := works[o.WorkID()]
+		if o.IsAboutWriter() {
+			target, ok = writers[o.TargetWriterID()]
+		}
+		source, known := writers[o.WriterID()]
+		if !ok || !known {
+			continue
+		}
+		doc.edges = append(doc.edges, &graphEdge{
+			kind:   edgeTypeOpinion,
+			id:     o.ID(),
+			source: source,
+			target: target,
+			values: opinionValues(o),
+		})
+	}
+	return doc
+}
+
+// opinionValues gives a mixed sentiment no score, since it has no place on
+// the scale.
+func opinionValues(o *domain.Opinion) map[string]any {
+	values := map[string]any{
+		"type":      edgeTypeOpinion,
+		"sentiment": string(o.Sentiment()),
+		"quote":     o.Quote(),
+		"source":    o.Source(),
+	}
+	if score, ok := o.Sentiment().Score(); ok {
+		values["sentiment_score"] = score
+	}
+	if o.StatementYear() != nil {
+		values["statement_year"] = *o.StatementYear()
+	}
+	return values
+}
@@ -0,0 +1,181 @@
+package service_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/repository/memory"
+	"github.com/what-writers-like/backend/internal/service"
+)
+
+func setupGraphExport(t *testing.T) service.GraphExportService {
+	store := memory.NewStore()
+	writerRepo := memory.NewWriterRepository(store)
+	workRepo := memory.NewWorkRepository(store)
+	opinionRepo := memory.NewOpinionRepository(store)
+
+	died, published, stated := 1817, 1813, 1850
+	require.NoError(t, writerRepo.Create(domain.NewWriter(1, "Jane Austen", 1775, &died, nil)))
+	require.NoError(t, writerRepo.Create(domain.NewWriter(2, `Charlotte "Currer Bell" Bronte`, 1816, nil, nil)))
+	require.NoError(t, workRepo.Create(domain.NewWork(1, "Pride & Prejudice", []uint64{1}, domain.WorkDetails{
+		PublicationYear: &published,
+	})))
+	require.NoError(t, opinionRepo.Create(domain.NewOpinion(
+		0, 2, 1, domain.SentimentMixed, "It's <accurate>\nbut \\ cold", "Letter to G. H. Lewes", nil, &stated,
+	)))
+	require.NoError(t, opinionRepo.Create(domain.NewWriterOpinion(
+		0, 1, 2, domain.SentimentPositive, "A fine mind", "Letters", nil, nil,
+	)))
+
+	graphService := service.NewGraphService(writerRepo, workRepo, opinionRepo, memory.NewGraphRepository(store))
+	return service.NewGraphExportService(graphService)
+}
+
+func exportGraph(t *testing.T, svc service.GraphExportService, filter service.GraphFilter, format string) string {
+	t.Helper()
+	graphFormat, err := service.ParseGraphFormat(format)
+	require.NoError(t, err)
+	var out bytes.Buffer
+	require.NoError(t, svc.ExportGraph(&out, filter, graphFormat))
+	return out.String()
+}
+
+type xmlData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+func TestGraphExportService_GraphML(t *testing.T) {
+	t.Parallel()
+	svc := setupGraphExport(t)
+
+	var doc struct {
+		Keys []struct {
+			ID   string `xml:"id,attr"`
+			For  string `xml:"for,attr"`
+			Type string `xml:"attr.type,attr"`
+		} `xml:"key"`
+		Graph struct {
+			EdgeDefault string `xml:"edgedefault,attr"`
+			Nodes       []struct {
+				ID   string    `xml:"id,attr"`
+				Data []xmlData `xml:"data"`
+			} `xml:"node"`
+			Edges []struct {
+				ID     string    `xml:"id,attr"`
+				Source string    `xml:"source,attr"`
+				Target string    `xml:"target,attr"`
+				Data   []xmlData `xml:"data"`
+			} `xml:"edge"`
+		} `xml:"graph"`
+	}
+	require.NoError(t, xml.Unmarshal([]byte(exportGraph(t, svc, service.GraphFilter{}, "graphml")), &doc))
+
+	assert.Equal(t, "directed", doc.Graph.EdgeDefault)
+	require.Len(t, doc.Graph.Nodes, 3)
+	assert.Equal(t, "writer-1", doc.Graph.Nodes[0].ID)
+	assert.Contains(t, doc.Graph.Nodes[0].Data, xmlData{Key: "node_death_year", Value: "1817"})
+	assert.Equal(t, "work-1", doc.Graph.Nodes[2].ID)
+	assert.Contains(t, doc.Graph.Nodes[2].Data, xmlData{Key: "label", Value: "Pride & Prejudice"})
+	assert.Contains(t, doc.Graph.Nodes[2].Data, xmlData{Key: "node_publication_year", Value: "1813"})
+
+	require.Len(t, doc.Graph.Edges, 3)
+	assert.Equal(t, "authored-1-1", doc.Graph.Edges[0].ID)
+	// Opinions come grouped by writer
+	assert.Equal(t, "writer-2", doc.Graph.Edges[1].Target)
+	assert.Contains(t, doc.Graph.Edges[1].Data, xmlData{Key: "edge_sentiment_score", Value: "1"})
+	assert.Equal(t, "writer-2", doc.Graph.Edges[2].Source)
+	assert.Equal(t, "work-1", doc.Graph.Edges[2].Target)
+	assert.Contains(t, doc.Graph.Edges[2].Data, xmlData{Key: "edge_quote", Value: "It's <accurate>\nbut \\ cold"})
+	assert.Contains(t, doc.Graph.Edges[2].Data, xmlData{Key: "edge_sentiment", Value: "mixed"})
+	assert.Contains(t, doc.Graph.Edges[2].Data, xmlData{Key: "edge_statement_year", Value: "1850"})
+	for _, d := range doc.Graph.Edges[2].Data {
+		assert.NotEqual(t, "edge_sentiment_score", d.Key, "a mixed sentiment has no score")
+	}
+}
+
+func TestGraphExportService_GEXF(t *testing.T) {
+	t.Parallel()
+	svc := setupGraphExport(t)
+
+	type attValue struct {
+		For   string `xml:"for,attr"`
+		Value string `xml:"value,attr"`
+	}
+	var doc struct {
+		Version string `xml:"version,attr"`
+		Graph   struct {
+			Attributes []struct {
+				Class string `xml:"class,attr"`
+			} `xml:"attributes"`
+			Nodes []struct {
+				ID     string     `xml:"id,attr"`
+				Label  string     `xml:"label,attr"`
+				Values []attValue `xml:"attvalues>attvalue"`
+			} `xml:"nodes>node"`
+			Edges []struct {
+				Source string     `xml:"source,attr"`
+				Values []attValue `xml:"attvalues>attvalue"`
+			} `xml:"edges>edge"`
+		} `xml:"graph"`
+	}
+	require.NoError(t, xml.Unmarshal([]byte(exportGraph(t, svc, service.GraphFilter{}, "gexf")), &doc))
+
+	assert.Equal(t, "1.3", doc.Version)
+	require.Len(t, doc.Graph.Attributes, 2)
+	require.Len(t, doc.Graph.Nodes, 3)
+	assert.Equal(t, `Charlotte "Currer Bell" Bronte`, doc.Graph.Nodes[1].Label)
+	assert.Contains(t, doc.Graph.Nodes[1].Values, attValue{For: "birth_year", Value: "1816"})
+	require.Len(t, doc.Graph.Edges, 3)
+	assert.Contains(t, doc.Graph.Edges[2].Values, attValue{For: "source", Value: "Letter to G. H. Lewes"})
+}
+
+func TestGraphExportService_DOT(t *testing.T) {
+	t.Parallel()
+	svc := setupGraphExport(t)
+
+	assert.Equal(t, `digraph "what-writers-like" {
+  "writer-1" [label="Jane Austen", type="writer", name="Jane Austen", birth_year=1775, death_year=1817];
+  "writer-2" [label="Charlotte \"Currer Bell\" Bronte", type="writer", name="Charlotte \"Currer Bell\" Bronte", `+
+		`birth_year=1816];
+  "work-1" [label="Pride & Prejudice", shape=box, type="work", title="Pride & Prejudice", publication_year=1813];
+  "writer-1" -> "work-1" [type="authored"];
+  "writer-1" -> "writer-2" [label="+1", type="opinion", sentiment="+1", sentiment_score=1, quote="A fine mind", `+
+		`source="Letters"];
+  "writer-2" -> "work-1" [label="mixed", type="opinion", sentiment="mixed", quote="It's <accurate>\nbut \\ cold", `+
+		`source="Letter to G. H. Lewes", statement_year=1850];
+}
+`, exportGraph(t, svc, service.GraphFilter{}, "dot"))
+}
+
+func TestGraphExportService_Cypher(t *testing.T) {
+	t.Parallel()
+	svc := setupGraphExport(t)
+
+	// Only Charlotte Bronte's opinions, with the work they are about
+	cypher := exportGraph(t, svc, service.GraphFilter{WriterIDs: []uint64{2}}, "cypher")
+	assert.Equal(t, `CREATE CONSTRAINT writer_id IF NOT EXISTS FOR (n:Writer) REQUIRE n.id IS UNIQUE;
+CREATE CONSTRAINT work_id IF NOT EXISTS FOR (n:Work) REQUIRE n.id IS UNIQUE;
+MERGE (n:Writer {id: 1}) SET n += {name: 'Jane Austen', birth_year: 1775, death_year: 1817};
+MERGE (n:Writer {id: 2}) SET n += {name: 'Charlotte "Currer Bell" Bronte', birth_year: 1816};
+MERGE (n:Work {id: 1}) SET n += {title: 'Pride & Prejudice', publication_year: 1813};
+MATCH (a:Writer {id: 1}), (b:Work {id: 1}) MERGE (a)-[:WROTE]->(b);
+MATCH (a:Writer {id: 2}), (b:Work {id: 1}) MERGE (a)-[r:OPINION {id: 1}]->(b) SET r += {sentiment: 'mixed', `+
+		`quote: 'It\'s <accurate>\nbut \\ cold', source: 'Letter to G. H. Lewes', statement_year: 1850};
+`, cypher)
+}
+
+func TestGraphExportService_InvalidFormat(t *testing.T) {
+	t.Parallel()
+	_, err := service.ParseGraphFormat("svg")
+	require.Error(t, err)
+
+	var out bytes.Buffer
+	err = setupGraphExport(t).ExportGraph(&out, service.GraphFilter{}, "svg")
+	require.Error(t, err)
+	assert.Empty(t, out.String())
+}
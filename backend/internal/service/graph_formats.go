@@ -0,0 +1,205 @@
+package service
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// writeGraphML writes a GraphML document, with the label of each node as a
+// label attribute since the format has none of its own.
+func writeGraphML(w *bufio.Writer, doc *graphDocument) {
+	fmt.Fprint(w, xml.Header)
+	fmt.Fprint(w, `<graphml xmlns="http://graphml.graphdrawing.org/xmlns">`+"\n")
+	fmt.Fprint(w, `  <key id="label" for="node" attr.name="label" attr.type="string"/>`+"\n")
+	writeGraphMLKeys(w, "node", nodeAttributes)
+	writeGraphMLKeys(w, "edge", edgeAttributes)
+	fmt.Fprint(w, `  <graph id="G" edgedefault="directed">`+"\n")
+	for _, n := range doc.nodes {
+		fmt.Fprintf(w, "    <node id=\"%s\">\n", xmlEscape(n.key()))
+		fmt.Fprintf(w, "      <data key=\"label\">%s</data>\n", xmlEscape(n.label))
+		writeGraphMLData(w, "node", nodeAttributes, n.values)
+		fmt.Fprint(w, "    </node>\n")
+	}
+	for _, e := range doc.edges {
+		fmt.Fprintf(w, "    <edge id=\"%s\" source=\"%s\" target=\"%s\">\n",
+			xmlEscape(e.key()), xmlEscape(e.source.key()), xmlEscape(e.target.key()))
+		writeGraphMLData(w, "edge", edgeAttributes, e.values)
+		fmt.Fprint(w, "    </edge>\n")
+	}
+	fmt.Fprint(w, "  </graph>\n</graphml>\n")
+}
+
+// writeGraphMLKeys declares attributes. Key IDs are prefixed with what they
+// are for, since nodes and edges both have a type.
+func writeGraphMLKeys(w *bufio.Writer, kind string, attributes []graphAttribute) {
+	for _, a := range attributes {
+		attrType := "string"
+		if a.integer {
+			attrType = "int"
+		}
+		fmt.Fprintf(w, "  <key id=\"%s_%s\" for=\"%s\" attr.name=\"%s\" attr.type=\"%s\"/>\n",
+			kind, a.name, kind, a.name, attrType)
+	}
+}
+
+func writeGraphMLData(w *bufio.Writer, kind string, attributes []graphAttribute, values map[string]any) {
+	for _, a := range attributes {
+		if value, ok := values[a.name]; ok {
+			fmt.Fprintf(w, "      <data key=\"%s_%s\">%s</data>\n", kind, a.name, xmlEscape(fmt.Sprint(value)))
+		}
+	}
+}
+
+// writeGEXF writes a GEXF 1.3 document.
+func writeGEXF(w *bufio.Writer, doc *graphDocument) {
+	fmt.Fprint(w, xml.Header)
+	fmt.Fprint(w, `<gexf xmlns="http://gexf.net/1.3" version="1.3">`+"\n")
+	fmt.Fprint(w, `  <graph defaultedgetype="directed" mode="static">`+"\n")
+	writeGEXFAttributes(w, "node", nodeAttributes)
+	writeGEXFAttributes(w, "edge", edgeAttributes)
+	fmt.Fprint(w, "    <nodes>\n")
+	for _, n := range doc.nodes {
+		fmt.Fprintf(w, "      <node id=\"%s\" label=\"%s\">\n", xmlEscape(n.key()), xmlEscape(n.label))
+		writeGEXFValues(w, nodeAttributes, n.values)
+		fmt.Fprint(w, "      </node>\n")
+	}
+	fmt.Fprint(w, "    </nodes>\n    <edges>\n")
+	for _, e := range doc.edges {
+		fmt.Fprintf(w, "      <edge id=\"%s\" source=\"%s\" target=\"%s\">\n",
+			xmlEscape(e.key()), xmlEscape(e.source.key()), xmlEscape(e.target.key()))
+		writeGEXFValues(w, edgeAttributes, e.values)
+		fmt.Fprint(w, "      </edge>\n")
+	}
+	fmt.Fprint(w, "    </edges>\n  </graph>\n</gexf>\n")
+}
+
+func writeGEXFAttributes(w *bufio.Writer, class string, attributes []graphAttribute) {
+	fmt.Fprintf(w, "    <attributes class=\"%s\">\n", class)
+	for _, a := range attributes {
+		attrType := "string"
+		if a.integer {
+			attrType = "integer"
+		}
+		fmt.Fprintf(w, "      <attribute id=\"%s\" title=\"%s\" type=\"%s\"/>\n", a.name, a.name, attrType)
+	}
+	fmt.Fprint(w, "    </attributes>\n")
+}
+
+func writeGEXFValues(w *bufio.Writer, attributes []graphAttribute, values map[string]any) {
+	fmt.Fprint(w, "        <attvalues>\n")
+	for _, a := range attributes {
+		if value, ok := values[a.name]; ok {
+			fmt.Fprintf(w, "          <attvalue for=\"%s\" value=\"%s\"/>\n", a.name, xmlEscape(fmt.Sprint(value)))
+		}
+	}
+	fmt.Fprint(w, "        </attvalues>\n")
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	// Writing to a strings.Builder cannot fail
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// writeDOT writes a Graphviz digraph. Works are drawn as boxes, and opinion
+// edges are labelled with their sentiment.
+func writeDOT(w *bufio.Writer, doc *graphDocument) {
+	fmt.Fprint(w, "digraph \"what-writers-like\" {\n")
+	for _, n := range doc.nodes {
+		attributes := []string{"label=" + dotQuote(n.label)}
+		if n.kind == nodeTypeWork {
+			attributes = append(attributes, "shape=box")
+		}
+		attributes = append(attributes, dotAttributes(nodeAttributes, n.values)...)
+		fmt.Fprintf(w, "  %s [%s];\n", dotQuote(n.key()), strings.Join(attributes, ", "))
+	}
+	for _, e := range doc.edges {
+		var attributes []string
+		if e.kind == edgeTypeOpinion {
+			attributes = append(attributes, "label="+dotQuote(fmt.Sprint(e.values["sentiment"])))
+		}
+		attributes = append(attributes, dotAttributes(edgeAttributes, e.values)...)
+		fmt.Fprintf(w, "  %s -> %s [%s];\n",
+			dotQuote(e.source.key()), dotQuote(e.target.key()), strings.Join(attributes, ", "))
+	}
+	fmt.Fprint(w, "}\n")
+}
+
+func dotAttributes(attributes []graphAttribute, values map[string]any) []string {
+	var result []string
+	for _, a := range attributes {
+		value, ok := values[a.name]
+		if !ok {
+			continue
+		}
+		if a.integer {
+			result = append(result, fmt.Sprintf("%s=%d", a.name, value))
+		} else {
+			result = append(result, fmt.Sprintf("%s=%s", a.name, dotQuote(fmt.Sprint(value))))
+		}
+	}
+	return result
+}
+
+// dotQuote quotes s as a DOT string. Backslashes are doubled so that none
+// is read as one of Graphviz's label escapes.
+func dotQuote(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`).Replace(s)
+	return `"` + s + `"`
+}
+
+// writeCypher writes a Neo4j script of Writer and Work nodes joined by
+// WROTE and OPINION relationships. Nodes keep their IDs as an id property
+// and are merged on it, so running the script again updates the graph
+// rather than duplicating it.
+func writeCypher(w *bufio.Writer, doc *graphDocument) {
+	fmt.Fprint(w, "CREATE CONSTRAINT writer_id IF NOT EXISTS FOR (n:Writer) REQUIRE n.id IS UNIQUE;\n")
+	fmt.Fprint(w, "CREATE CONSTRAINT work_id IF NOT EXISTS FOR (n:Work) REQUIRE n.id IS UNIQUE;\n")
+	for _, n := range doc.nodes {
+		fmt.Fprintf(w, "MERGE (n:%s {id: %d}) SET n += %s;\n",
+			cypherLabel(n), n.id, cypherMap(nodeAttributes, n.values))
+	}
+	for _, e := range doc.edges {
+		match := fmt.Sprintf("MATCH (a:%s {id: %d}), (b:%s {id: %d})",
+			cypherLabel(e.source), e.source.id, cypherLabel(e.target), e.target.id)
+		if e.kind == edgeTypeAuthored {
+			fmt.Fprintf(w, "%s MERGE (a)-[:WROTE]->(b);\n", match)
+			continue
+		}
+		fmt.Fprintf(w, "%s MERGE (a)-[r:OPINION {id: %d}]->(b) SET r += %s;\n",
+			match, e.id, cypherMap(edgeAttributes, e.values))
+	}
+}
+
+func cypherLabel(n *graphNode) string {
+	if n.kind == nodeTypeWork {
+		return "Work"
+	}
+	return "Writer"
+}
+
+// cypherMap writes values as a map literal, leaving out the type, which
+// labels and relationship types already tell.
+func cypherMap(attributes []graphAttribute, values map[string]any) string {
+	var entries []string
+	for _, a := range attributes {
+		value, ok := values[a.name]
+		if !ok || a.name == "type" {
+			continue
+		}
+		if a.integer {
+			entries = append(entries, fmt.Sprintf("%s: %d", a.name, value))
+		} else {
+			entries = append(entries, fmt.Sprintf("%s: %s", a.name, cypherQuote(fmt.Sprint(value))))
+		}
+	}
+	return "{" + strings.Join(entries, ", ") + "}"
+}
+
+func cypherQuote(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\r", `\r`, "\n", `\n`, "\t", `\t`).Replace(s)
+	return "'" + s + "'"
+}
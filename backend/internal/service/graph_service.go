@@ -0,0 +1,367 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/repository"
+)
+
+// GraphFilter selects the opinions that make up a graph. WriterIDs and
+// WorkIDs restrict opinions to those expressed by the given writers and
+// about the given works; when WorkIDs is set, opinions about writers as a
+// whole are left out. The listed writers and works are always part of the
+// graph even when no opinion matches.
+type GraphFilter struct {
+	WriterIDs  []uint64
+	WorkIDs    []uint64
+	Sentiments []domain.Sentiment
+}
+
+// Graph holds the entities behind a nodes-and-edges document: every writer
+// and work that takes part in an opinion or authorship link, including the
+// writers that opinions are about. Truncated is
+// set when a traversal hit the node cap before exhausting its depth.
+type Graph struct {
+	Writers   []*domain.Writer
+	Works     []*domain.Work
+	Opinions  []*domain.Opinion
+	Truncated bool
+}
+
+// PathHop is one step of a "who read whom" chain: From expressed Opinion
+// about Work, which was written by To. Work is nil when the opinion is
+// about To as a whole.
+type PathHop struct {
+	From    *domain.Writer
+	Opinion *domain.Opinion
+	Work    *domain.Work
+	To      *domain.Writer
+}
+
+type Path struct {
+	Hops []PathHop
+}
+
+const (
+	DefaultNeighborhoodDepth = 2
+	MaxNeighborhoodDepth     = 6
+	MaxNeighborhoodNodes     = 500
+	MaxPathHops              = 6
+)
+
+var ErrNoPath = errors.New("no path found")
+
+// ErrPathToSelf is returned when a path is asked for from a writer to
+// themselves.
+var ErrPathToSelf = errors.New("from and to must be different writers")
+
+// ErrWriterNotFound is returned when a path starts or ends at a writer
+// that does not exist.
+var ErrWriterNotFound = errors.New("writer not found")
+
+// ErrNodeNotFound is returned when a neighborhood starts from a writer or
+// work that does not exist.
+var ErrNodeNotFound = errors.New("not found")
+
+type GraphService interface {
+	GetGraph(filter GraphFilter) (*Graph, error)
+	GetWriterNeighborhood(writerID uint64, depth int) (*Graph, error)
+	GetWorkNeighborhood(workID uint64, depth int) (*Graph, error)
+	FindShortestPath(fromID, toID uint64, sentiments []domain.Sentiment) (*Path, error)
+}
+
+type graphService struct {
+	writerRepo  repository.WriterRepository
+	workRepo    repository.WorkRepository
+	opinionRepo repository.OpinionRepository
+	graphRepo   repository.GraphRepository
+}
+
+func NewGraphService(
+	writerRepo repository.WriterRepository,
+	workRepo repository.WorkRepository,
+	opinionRepo repository.OpinionRepository,
+	graphRepo repository.GraphRepository,
+) GraphService {
+	return &graphService{
+		writerRepo:  writerRepo,
+		workRepo:    workRepo,
+		opinionRepo: opinionRepo,
+		graphRepo:   graphRepo,
+	}
+}
+
+// GetGraph loads the graph with three queries regardless of its size:
+// matching opinions, then the works they reference, then every writer
+// involved either as opinion holder or as author.
+func (s *graphService) GetGraph(filter GraphFilter) (*Graph, error) {
+	opinions, err := s.opinionRepo.Find(repository.OpinionFilter{
+		WriterIDs:  filter.WriterIDs,
+		WorkIDs:    filter.WorkIDs,
+		Sentiments: filter.Sentiments,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	workIDs := newIDSet(filter.WorkIDs...)
+	for _, o := range opinions {
+		if !o.IsAboutWriter() {
+			workIDs.add(o.WorkID())
+		}
+	}
+	works, err := s.workRepo.GetByIDs(workIDs.list())
+	if err != nil {
+		return nil, err
+	}
+
+	writerIDs := newIDSet(filter.WriterIDs...)
+	for _, o := range opinions {
+		writerIDs.add(o.WriterID())
+		if o.IsAboutWriter() {
+			writerIDs.add(o.TargetWriterID())
+		}
+	}
+	for _, w := range works {
+		for _, authorID := range w.AuthorIDs() {
+			writerIDs.add(authorID)
+		}
+	}
+	writers, err := s.writerRepo.GetByIDs(writerIDs.list())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Graph{
+		Writers:  writers,
+		Works:    works,
+		Opinions: opinions,
+	}, nil
+}
+
+func (s *graphService) GetWriterNeighborhood(writerID uint64, depth int) (*Graph, error) {
+	writers, err := s.writerRepo.GetByIDs([]uint64{writerID})
+	if err != nil {
+		return nil, err
+	}
+	if len(writers) == 0 {
+		return nil, fmt.Errorf("writer %w", ErrNodeNotFound)
+	}
+	return s.neighborhood(repository.NodeTypeWriter, writerID, depth)
+}
+
+func (s *graphService) GetWorkNeighborhood(workID uint64, depth int) (*Graph, error) {
+	works, err := s.workRepo.GetByIDs([]uint64{workID})
+	if err != nil {
+		return nil, err
+	}
+	if len(works) == 0 {
+		return nil, fmt.Errorf("work %w", ErrNodeNotFound)
+	}
+	return s.neighborhood(repository.NodeTypeWork, workID, depth)
+}
+
+// neighborhood resolves the nodes found by the recursive traversal into
+// entities and loads the opinions between them.
+func (s *graphService) neighborhood(startType repository.NodeType, startID uint64, depth int) (*Graph, error) {
+	if depth < 1 {
+		return nil, errors.New("depth must be at least 1")
+	}
+	if depth > MaxNeighborhoodDepth {
+		depth = MaxNeighborhoodDepth
+	}
+
+	// Ask for one node more than the cap to learn whether the result was cut
+	refs, err := s.graphRepo.Neighborhood(startType, startID, depth, MaxNeighborhoodNodes+1)
+	if err != nil {
+		return nil, err
+	}
+	truncated := len(refs) > MaxNeighborhoodNodes
+	if truncated {
+		refs = refs[:MaxNeighborhoodNodes]
+	}
+
+	writerIDs := newIDSet()
+	workIDs := newIDSet()
+	for _, ref := range refs {
+		switch ref.Type {
+		case repository.NodeTypeWriter:
+			writerIDs.add(ref.ID)
+		case repository.NodeTypeWork:
+			workIDs.add(ref.ID)
+		}
+	}
+
+	writers, err := s.writerRepo.GetByIDs(writerIDs.list())
+	if err != nil {
+		return nil, err
+	}
+	works, err := s.workRepo.GetByIDs(workIDs.list())
+	if err != nil {
+		return nil, err
+	}
+
+	opinions := []*domain.Opinion{}
+	// An empty ID list would leave the filter unconstrained; opinions need a
+	// writer to hold them, and the writers found may also be their targets
+	if len(writerIDs.list()) > 0 {
+		opinions, err = s.opinionRepo.Find(repository.OpinionFilter{
+			WriterIDs:       writerIDs.list(),
+			WorkIDs:         workIDs.list(),
+			TargetWriterIDs: writerIDs.list(),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &Graph{
+		Writers:   writers,
+		Works:     works,
+		Opinions:  opinions,
+		Truncated: truncated,
+	}, nil
+}
+
+// pathStep records how a writer was first reached during the search. work
+// is nil when the writer was reached by an opinion about them.
+type pathStep struct {
+	prevWriterID uint64
+	opinion      *domain.Opinion
+	work         *domain.Work
+}
+
+// FindShortestPath runs a breadth-first search from one writer to another
+// over writer -> work -> author hops and direct writer -> writer opinions,
+// each counting as one hop, expanding a whole level per pair of
+// queries. When sentiments are given, only opinions with one of them are
+// followed.
+func (s *graphService) FindShortestPath(fromID, toID uint64, sentiments []domain.Sentiment) (*Path, error) {
+	if fromID == toID {
+		return nil, ErrPathToSelf
+	}
+	ends, err := s.writerRepo.GetByIDs([]uint64{fromID, toID})
+	if err != nil {
+		return nil, err
+	}
+	if len(ends) != 2 {
+		return nil, ErrWriterNotFound
+	}
+
+	visited := map[uint64]pathStep{fromID: {}}
+	frontier := []uint64{fromID}
+	for hop := 0; hop < MaxPathHops && len(frontier) > 0; hop++ {
+		opinions, err := s.opinionRepo.Find(repository.OpinionFilter{
+			WriterIDs:  frontier,
+			Sentiments: sentiments,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		workIDs := newIDSet()
+		for _, o := range opinions {
+			if !o.IsAboutWriter() {
+				workIDs.add(o.WorkID())
+			}
+		}
+		works, err := s.workRepo.GetByIDs(workIDs.list())
+		if err != nil {
+			return nil, err
+		}
+		worksByID := make(map[uint64]*domain.Work, len(works))
+		for _, w := range works {
+			worksByID[w.ID()] = w
+		}
+
+		var next []uint64
+		for _, o := range opinions {
+			step := pathStep{prevWriterID: o.WriterID(), opinion: o}
+			reachedIDs := []uint64{o.TargetWriterID()}
+			if !o.IsAboutWriter() {
+				// An opinion about a co-written work reaches every author
+				work, ok := worksByID[o.WorkID()]
+				if !ok {
+					continue
+				}
+				step.work = work
+				reachedIDs = work.AuthorIDs()
+			}
+			for _, reachedID := range reachedIDs {
+				if _, seen := visited[reachedID]; seen {
+					continue
+				}
+				visited[reachedID] = step
+				if reachedID == toID {
+					return s.buildPath(visited, fromID, toID)
+				}
+				next = append(next, reachedID)
+			}
+		}
+		frontier = next
+	}
+
+	return nil, ErrNoPath
+}
+
+func (s *graphService) buildPath(visited map[uint64]pathStep, fromID, toID uint64) (*Path, error) {
+	var steps []pathStep
+	var reachedIDs []uint64
+	writerIDs := newIDSet(toID)
+	for id := toID; id != fromID; {
+		step := visited[id]
+		steps = append(steps, step)
+		reachedIDs = append(reachedIDs, id)
+		writerIDs.add(step.prevWriterID)
+		id = step.prevWriterID
+	}
+
+	writers, err := s.writerRepo.GetByIDs(writerIDs.list())
+	if err != nil {
+		return nil, err
+	}
+	writersByID := make(map[uint64]*domain.Writer, len(writers))
+	for _, w := range writers {
+		writersByID[w.ID()] = w
+	}
+
+	hops := make([]PathHop, len(steps))
+	for i, step := range steps {
+		// Steps were collected walking back from the target
+		hops[len(steps)-1-i] = PathHop{
+			From:    writersByID[step.prevWriterID],
+			Opinion: step.opinion,
+			Work:    step.work,
+			To:      writersByID[reachedIDs[i]],
+		}
+	}
+	return &Path{Hops: hops}, nil
+}
+
+// idSet collects IDs while preserving the order they were first seen in.
+type idSet struct {
+	seen map[uint64]struct{}
+	ids  []uint64
+}
+
+func newIDSet(ids ...uint64) *idSet {
+	s := &idSet{seen: make(map[uint64]struct{})}
+	for _, id := range ids {
+		s.add(id)
+	}
+	return s
+}
+
+func (s *idSet) add(id uint64) {
+	if _, ok := s.seen[id]; ok {
+		return
+	}
+	s.seen[id] = struct{}{}
+	s.ids = append(s.ids, id)
+}
+
+func (s *idSet) list() []uint64 {
+	return s.ids
+}
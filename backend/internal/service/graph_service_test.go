@@ -0,0 +1,271 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/repository/memory"
+	"github.com/what-writers-like/backend/internal/service"
+)
+
+func setupGraphData(t *testing.T, store *memory.Store) service.GraphService {
+	writerRepo := memory.NewWriterRepository(store)
+	workRepo := memory.NewWorkRepository(store)
+	opinionRepo := memory.NewOpinionRepository(store)
+
+	require.NoError(t, writerRepo.Create(domain.NewWriter(1, "Jane Austen", 1775, nil, nil)))
+	require.NoError(t, writerRepo.Create(domain.NewWriter(2, "Charlotte Bronte", 1816, nil, nil)))
+	require.NoError(t, writerRepo.Create(domain.NewWriter(3, "Charles Dickens", 1812, nil, nil)))
+
+	require.NoError(t, workRepo.Create(domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})))
+	require.NoError(t, workRepo.Create(domain.NewWork(2, "Jane Eyre", []uint64{2}, domain.WorkDetails{})))
+
+	require.NoError(t, opinionRepo.Create(domain.NewOpinion(
+		0, 2, 1, domain.SentimentNegative, "Quote 1", "Source 1", nil, nil,
+	)))
+	require.NoError(t, opinionRepo.Create(domain.NewOpinion(
+		0, 3, 1, domain.SentimentPositive, "Quote 2", "Source 2", nil, nil,
+	)))
+	require.NoError(t, opinionRepo.Create(domain.NewOpinion(
+		0, 3, 2, domain.SentimentPositive, "Quote 3", "Source 3", nil, nil,
+	)))
+
+	return service.NewGraphService(writerRepo, workRepo, opinionRepo, memory.NewGraphRepository(store))
+}
+
+func TestGraphService_GetGraph(t *testing.T) {
+	t.Parallel()
+	t.Run("whole graph", func(t *testing.T) {
+		t.Parallel()
+		store := memory.NewStore()
+		svc := setupGraphData(t, store)
+
+		graph, err := svc.GetGraph(service.GraphFilter{})
+		require.NoError(t, err)
+		assert.Len(t, graph.Writers, 3)
+		assert.Len(t, graph.Works, 2)
+		assert.Len(t, graph.Opinions, 3)
+	})
+
+	t.Run("filter by writer", func(t *testing.T) {
+		t.Parallel()
+		store := memory.NewStore()
+		svc := setupGraphData(t, store)
+
+		graph, err := svc.GetGraph(service.GraphFilter{WriterIDs: []uint64{2}})
+		require.NoError(t, err)
+		require.Len(t, graph.Opinions, 1)
+		assert.Equal(t, uint64(1), graph.Opinions[0].WorkID())
+		require.Len(t, graph.Works, 1)
+		// The opinion holder and the author of the work are both included
+		assert.Len(t, graph.Writers, 2)
+	})
+
+	t.Run("filter by sentiment", func(t *testing.T) {
+		t.Parallel()
+		store := memory.NewStore()
+		svc := setupGraphData(t, store)
+
+		positive := []domain.Sentiment{domain.SentimentPositive, domain.SentimentVeryPositive}
+		graph, err := svc.GetGraph(service.GraphFilter{WorkIDs: []uint64{1}, Sentiments: positive})
+		require.NoError(t, err)
+		require.Len(t, graph.Opinions, 1)
+		assert.Equal(t, uint64(3), graph.Opinions[0].WriterID())
+	})
+
+	t.Run("opinions about writers", func(t *testing.T) {
+		t.Parallel()
+		store := memory.NewStore()
+		svc := setupGraphData(t, store)
+		require.NoError(t, memory.NewOpinionRepository(store).Create(domain.NewWriterOpinion(
+			0, 1, 3, domain.SentimentPositive, "Quote 4", "Source 4", nil, nil,
+		)))
+
+		// Austen wrote nothing about Dickens' works, yet is linked to him
+		graph, err := svc.GetGraph(service.GraphFilter{WriterIDs: []uint64{1}})
+		require.NoError(t, err)
+		require.Len(t, graph.Opinions, 1)
+		assert.True(t, graph.Opinions[0].IsAboutWriter())
+		assert.Empty(t, graph.Works)
+		assert.Len(t, graph.Writers, 2)
+
+		// Work filters leave them out
+		graph, err = svc.GetGraph(service.GraphFilter{WorkIDs: []uint64{1}})
+		require.NoError(t, err)
+		assert.Len(t, graph.Opinions, 2)
+
+		neighborhood, err := svc.GetWriterNeighborhood(1, 1)
+		require.NoError(t, err)
+		assert.Len(t, neighborhood.Writers, 2)
+		assert.Len(t, neighborhood.Opinions, 1)
+	})
+
+	t.Run("filtered writer without opinions", func(t *testing.T) {
+		t.Parallel()
+		store := memory.NewStore()
+		svc := setupGraphData(t, store)
+
+		graph, err := svc.GetGraph(service.GraphFilter{WriterIDs: []uint64{1}})
+		require.NoError(t, err)
+		assert.Empty(t, graph.Opinions)
+		require.Len(t, graph.Writers, 1)
+		assert.Equal(t, "Jane Austen", graph.Writers[0].Name())
+	})
+}
+
+func TestGraphService_GetWriterNeighborhood(t *testing.T) {
+	t.Parallel()
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		store := memory.NewStore()
+		svc := setupGraphData(t, store)
+
+		graph, err := svc.GetWriterNeighborhood(2, 2)
+		require.NoError(t, err)
+		assert.Len(t, graph.Writers, 2)
+		require.Len(t, graph.Works, 1)
+		assert.Equal(t, "Pride and Prejudice", graph.Works[0].Title())
+		assert.Len(t, graph.Opinions, 1)
+		assert.False(t, graph.Truncated)
+	})
+
+	t.Run("one hop", func(t *testing.T) {
+		t.Parallel()
+		store := memory.NewStore()
+		svc := setupGraphData(t, store)
+
+		graph, err := svc.GetWriterNeighborhood(3, 1)
+		require.NoError(t, err)
+		assert.Len(t, graph.Writers, 1)
+		assert.Len(t, graph.Works, 2)
+		assert.Len(t, graph.Opinions, 2)
+	})
+
+	t.Run("writer not found", func(t *testing.T) {
+		t.Parallel()
+		store := memory.NewStore()
+		svc := setupGraphData(t, store)
+
+		_, err := svc.GetWriterNeighborhood(999, 2)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "writer not found")
+	})
+}
+
+func TestGraphService_GetWorkNeighborhood(t *testing.T) {
+	t.Parallel()
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		store := memory.NewStore()
+		svc := setupGraphData(t, store)
+
+		graph, err := svc.GetWorkNeighborhood(2, 3)
+		require.NoError(t, err)
+		assert.Len(t, graph.Works, 2)
+		assert.Len(t, graph.Writers, 2)
+		assert.Len(t, graph.Opinions, 1)
+	})
+
+	t.Run("work not found", func(t *testing.T) {
+		t.Parallel()
+		store := memory.NewStore()
+		svc := setupGraphData(t, store)
+
+		_, err := svc.GetWorkNeighborhood(999, 2)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "work not found")
+	})
+}
+
+func TestGraphService_FindShortestPath(t *testing.T) {
+	t.Parallel()
+	t.Run("direct", func(t *testing.T) {
+		t.Parallel()
+		store := memory.NewStore()
+		svc := setupGraphData(t, store)
+
+		path, err := svc.FindShortestPath(3, 1, nil)
+		require.NoError(t, err)
+		require.Len(t, path.Hops, 1)
+		assert.Equal(t, "Charles Dickens", path.Hops[0].From.Name())
+		assert.Equal(t, "Pride and Prejudice", path.Hops[0].Work.Title())
+		assert.Equal(t, "Jane Austen", path.Hops[0].To.Name())
+		assert.Equal(t, "Quote 2", path.Hops[0].Opinion.Quote())
+		assert.Equal(t, "Source 2", path.Hops[0].Opinion.Source())
+	})
+
+	t.Run("several hops", func(t *testing.T) {
+		t.Parallel()
+		store := memory.NewStore()
+
+		writerRepo := memory.NewWriterRepository(store)
+		workRepo := memory.NewWorkRepository(store)
+		opinionRepo := memory.NewOpinionRepository(store)
+		svc := service.NewGraphService(writerRepo, workRepo, opinionRepo, memory.NewGraphRepository(store))
+
+		require.NoError(t, writerRepo.Create(domain.NewWriter(1, "Jane Austen", 1775, nil, nil)))
+		require.NoError(t, writerRepo.Create(domain.NewWriter(2, "Charlotte Bronte", 1816, nil, nil)))
+		require.NoError(t, writerRepo.Create(domain.NewWriter(3, "Charles Dickens", 1812, nil, nil)))
+		require.NoError(t, workRepo.Create(domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})))
+		require.NoError(t, workRepo.Create(domain.NewWork(2, "Jane Eyre", []uint64{2}, domain.WorkDetails{})))
+		require.NoError(t, opinionRepo.Create(domain.NewOpinion(
+			0, 3, 2, domain.SentimentPositive, "Quote 1", "Source 1", nil, nil,
+		)))
+		require.NoError(t, opinionRepo.Create(domain.NewOpinion(
+			0, 2, 1, domain.SentimentNegative, "Quote 2", "Source 2", nil, nil,
+		)))
+
+		path, err := svc.FindShortestPath(3, 1, nil)
+		require.NoError(t, err)
+		require.Len(t, path.Hops, 2)
+		assert.Equal(t, uint64(3), path.Hops[0].From.ID())
+		assert.Equal(t, uint64(2), path.Hops[0].To.ID())
+		assert.Equal(t, uint64(2), path.Hops[1].From.ID())
+		assert.Equal(t, uint64(1), path.Hops[1].To.ID())
+
+		// The second hop is a negative opinion
+		positive := []domain.Sentiment{domain.SentimentPositive, domain.SentimentVeryPositive}
+		_, err = svc.FindShortestPath(3, 1, positive)
+		require.ErrorIs(t, err, service.ErrNoPath)
+	})
+
+	t.Run("through an opinion about a writer", func(t *testing.T) {
+		t.Parallel()
+		store := memory.NewStore()
+		svc := setupGraphData(t, store)
+		require.NoError(t, memory.NewOpinionRepository(store).Create(domain.NewWriterOpinion(
+			0, 1, 3, domain.SentimentMixed, "Quote 4", "Source 4", nil, nil,
+		)))
+
+		// Austen -> Dickens himself, then Dickens -> Jane Eyre -> Bronte
+		path, err := svc.FindShortestPath(1, 2, nil)
+		require.NoError(t, err)
+		require.Len(t, path.Hops, 2)
+		assert.Nil(t, path.Hops[0].Work)
+		assert.Equal(t, "Jane Austen", path.Hops[0].From.Name())
+		assert.Equal(t, "Charles Dickens", path.Hops[0].To.Name())
+		assert.Equal(t, "Quote 4", path.Hops[0].Opinion.Quote())
+		assert.Equal(t, "Jane Eyre", path.Hops[1].Work.Title())
+		assert.Equal(t, "Charlotte Bronte", path.Hops[1].To.Name())
+	})
+
+	t.Run("no path", func(t *testing.T) {
+		t.Parallel()
+		store := memory.NewStore()
+		svc := setupGraphData(t, store)
+
+		_, err := svc.FindShortestPath(1, 3, nil)
+		require.ErrorIs(t, err, service.ErrNoPath)
+	})
+
+	t.Run("writer not found", func(t *testing.T) {
+		t.Parallel()
+		store := memory.NewStore()
+		svc := setupGraphData(t, store)
+
+		_, err := svc.FindShortestPath(3, 999, nil)
+		require.ErrorIs(t, err, service.ErrWriterNotFound)
+	})
+}
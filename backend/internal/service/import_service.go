@@ -0,0 +1,583 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/repository"
+)
+
+// ConflictPolicy decides what an import does with a row whose natural key
+// matches a stored record: a writer's name and birth year, a work's title
+// and authors, or an opinion's writer, target and quote.
+type ConflictPolicy string
+
+const (
+	// ConflictFail reports the row as an error.
+	ConflictFail ConflictPolicy = "error"
+	// ConflictSkip leaves the stored record as it is.
+	ConflictSkip ConflictPolicy = "skip"
+	// ConflictUpdate overwrites the stored record with the row. Columns
+	// the file leaves out keep their stored values.
+	ConflictUpdate ConflictPolicy = "update"
+)
+
+// ParseConflictPolicy accepts error, skip or update; empty means error.
+func ParseConflictPolicy(s string) (ConflictPolicy, error) {
+	switch policy := ConflictPolicy(s); policy {
+	case "":
+		return ConflictFail, nil
+	case ConflictFail, ConflictSkip, ConflictUpdate:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("invalid conflict policy %q: expected error, skip or update", s)
+	}
+}
+
+// ImportOptions control an import. A dry run checks every row, and
+// reports what it would do, without writing anything.
+type ImportOptions struct {
+	DryRun     bool
+	OnConflict ConflictPolicy
+}
+
+// ImportReport accounts for the rows of an import file. Created, Updated
+// and Skipped count the valid rows by what was, or in a dry run would have
+// been, done with them. An import with any Errors writes nothing.
+type ImportReport struct {
+	DryRun  bool
+	Rows    int
+	Created int
+	Updated int
+	Skipped int
+	Errors  []ImportError
+}
+
+// Applied reports whether the rows were written.
+func (r *ImportReport) Applied() bool {
+	return !r.DryRun && len(r.Errors) == 0
+}
+
+// ImportError is a problem with one row of an import file. Row is the
+// line the row starts on, the header being line 1. Field names the column
+// at fault, or is empty when the row as a whole breaks a rule.
+type ImportError struct {
+	Row     int
+	Field   string
+	Message string
+}
+
+// ImportService loads writers, works and opinions from CSV files with a
+// header row; column names are case-insensitive and unknown columns are
+// ignored. Every row is held to the same rules as creating or updating
+// the record through the other services, and the rows are written in a
+// single transaction, all of them or, if any is invalid, none.
+type ImportService interface {
+	// ImportWriters reads the columns name, birth_year, death_year and
+	// bio.
+	ImportWriters(ctx context.Context, file io.Reader, options ImportOptions) (*ImportReport, error)
+	// ImportWorks reads the columns title, author_ids (separated by
+	// semicolons, or author_id for a single author), publication_year,
+	// genre, original_language and original_title.
+	ImportWorks(ctx context.Context, file io.Reader, options ImportOptions) (*ImportReport, error)
+	// ImportOpinions reads the columns writer_id, work_id or
+	// target_writer_id, sentiment_grade (or the true or false sentiment of
+	// older files), quote, source, source_id, page, statement_year and
+	// language.
+	ImportOpinions(ctx context.Context, file io.Reader, options ImportOptions) (*ImportReport, error)
+}
+
+type importService struct {
+	transactor repository.Transactor
+}
+
+func NewImportService(transactor repository.Transactor) ImportService {
+	return &importService{transactor: transactor}
+}
+
+// ErrInvalidImportFile is matched by the errors of a file that cannot be
+// imported at all, such as one that is not CSV or lacks a required column.
+// Other errors are failures to read or write the stored records.
+var ErrInvalidImportFile = errors.New("invalid import file")
+
+// invalidImportFile keeps the message of an error while making it match
+// ErrInvalidImportFile.
+type invalidImportFile struct{ error }
+
+func (e invalidImportFile) Is(target error) bool { return target == ErrInvalidImportFile }
+
+func (e invalidImportFile) Unwrap() error { return e.error }
+
+// errImportRolledBack undoes the writes of a dry run, or of an import with
+// invalid rows, once every row has been checked.
+var errImportRolledBack = errors.New("import rolled back")
+
+// rowOutcome is what an import did with a row.
+type rowOutcome int
+
+const (
+	rowFailed rowOutcome = iota
+	rowCreated
+	rowUpdated
+	rowSkipped
+)
+
+// importRowFunc checks a row and writes it. Broken rules are recorded on
+// the row; the error is for storage failures, which abort the import.
+type importRowFunc func(repos *repository.Repositories, row *csvRow) (rowOutcome, error)
+
+func (s *importService) ImportWriters(
+	ctx context.Context,
+	file io.Reader,
+	options ImportOptions,
+) (*ImportReport, error) {
+	return s.run(file, []string{"name", "birth_year"}, options,
+		func(repos *repository.Repositories, row *csvRow) (rowOutcome, error) {
+			name := row.text("name")
+			birthYear := row.year("birth_year")
+			deathYear := row.optionalYear("death_year")
+			bio := row.optionalText("bio")
+			if row.failed() {
+				return rowFailed, nil
+			}
+			if err := validateWriter(name, birthYear); err != nil {
+				return row.fail("", err), nil
+			}
+
+			matches, err := repos.Writers.GetByNameAndBirthYear(name, birthYear)
+			if err != nil {
+				return rowFailed, err
+			}
+			if len(matches) == 0 {
+				return rowCreated, createWriter(ctx, repos, domain.NewWriter(0, name, birthYear, deathYear, bio))
+			}
+			outcome := resolveConflict(row, options.OnConflict, "writer", len(matches))
+			if outcome != rowUpdated {
+				return outcome, nil
+			}
+
+			before := matches[0]
+			if !row.has("death_year") {
+				deathYear = before.DeathYear()
+			}
+			if !row.has("bio") {
+				bio = before.Bio()
+			}
+			writer := domain.NewWriter(before.ID(), name, birthYear, deathYear, bio)
+			return rowUpdated, updateWriter(ctx, repos, before, writer)
+		})
+}
+
+func (s *importService) ImportWorks(
+	ctx context.Context,
+	file io.Reader,
+	options ImportOptions,
+) (*ImportReport, error) {
+	return s.run(file, []string{"title"}, options,
+		func(repos *repository.Repositories, row *csvRow) (rowOutcome, error) {
+			title := row.text("title")
+			authorIDs := row.ids("author_ids")
+			if !row.has("author_ids") {
+				if authorID := row.id("author_id"); authorID != 0 {
+					authorIDs = []uint64{authorID}
+				}
+			}
+			details := domain.WorkDetails{
+				PublicationYear:  row.optionalYear("publication_year"),
+				Genre:            row.optionalText("genre"),
+				OriginalLanguage: row.optionalText("original_language"),
+				OriginalTitle:    row.optionalText("original_title"),
+			}
+			if row.failed() {
+				return rowFailed, nil
+			}
+			if err := validateWork(title); err != nil {
+				return row.fail("title", err), nil
+			}
+			if err := checkAuthors(repos.Writers, authorIDs); err != nil {
+				return row.fail("author_ids", err), nil
+			}
+
+			sameTitle, err := repos.Works.GetByTitle(title)
+			if err != nil {
+				return rowFailed, err
+			}
+			var matches []*domain.Work
+			for _, work := range sameTitle {
+				if sameAuthors(work.AuthorIDs(), authorIDs) {
+					matches = append(matches, work)
+				}
+			}
+			if len(matches) == 0 {
+				return rowCreated, createWork(ctx, repos, domain.NewWork(0, title, authorIDs, details))
+			}
+			outcome := resolveConflict(row, options.OnConflict, "work", len(matches))
+			if outcome != rowUpdated {
+				return outcome, nil
+			}
+
+			before := matches[0]
+			stored := before.Details()
+			if !row.has("publication_year") {
+				details.PublicationYear = stored.PublicationYear
+			}
+			if !row.has("genre") {
+				details.Genre = stored.Genre
+			}
+			if !row.has("original_language") {
+				details.OriginalLanguage = stored.OriginalLanguage
+			}
+			if !row.has("original_title") {
+				details.OriginalTitle = stored.OriginalTitle
+			}
+			work := domain.NewWork(before.ID(), title, authorIDs, details)
+			return rowUpdated, updateWork(ctx, repos, before, work)
+		})
+}
+
+func (s *importService) ImportOpinions(
+	ctx context.Context,
+	file io.Reader,
+	options ImportOptions,
+) (*ImportReport, error) {
+	return s.run(file, []string{"writer_id", "quote"}, options,
+		func(repos *repository.Repositories, row *csvRow) (rowOutcome, error) {
+			writerID := row.id("writer_id")
+			workID := row.id("work_id")
+			targetWriterID := row.id("target_writer_id")
+			sentiment := row.sentiment()
+			quote := row.text("quote")
+			source := row.text("source")
+			sourceID := row.id("source_id")
+			page := row.optionalText("page")
+			statementYear := row.optionalYear("statement_year")
+			language := row.text("language")
+			if row.text("writer_id") == "" {
+				row.fail("writer_id", errors.New("writer_id is required"))
+			}
+			if (row.text("work_id") == "") == (row.text("target_writer_id") == "") {
+				row.fail("work_id", errors.New("exactly one of work_id and target_writer_id is required"))
+			}
+			if row.failed() {
+				return rowFailed, nil
+			}
+
+			var statements []*domain.Opinion
+			var err error
+			if targetWriterID != 0 {
+				statements, err = repos.Opinions.GetByWriterAndTargetWriter(writerID, targetWriterID)
+			} else {
+				statements, err = repos.Opinions.GetByWriterAndWork(writerID, workID)
+			}
+			if err != nil {
+				return rowFailed, err
+			}
+			var matches []*domain.Opinion
+			for _, opinion := range statements {
+				if opinion.Quote() == quote {
+					matches = append(matches, opinion)
+				}
+			}
+
+			var before, opinion *domain.Opinion
+			languages := domain.QuoteLanguages{Language: language}
+			switch {
+			case len(matches) == 0 && targetWriterID != 0:
+				opinion = domain.NewWriterOpinion(
+					0, writerID, targetWriterID, sentiment, quote, source, page, statementYear,
+				)
+			case len(matches) == 0:
+				opinion = domain.NewOpinion(0, writerID, workID, sentiment, quote, source, page, statementYear)
+			default:
+				outcome := resolveConflict(row, options.OnConflict, "opinion", len(matches))
+				if outcome != rowUpdated {
+					return outcome, nil
+				}
+				before = matches[0]
+				if !row.has("source") {
+					source = before.Source()
+				}
+				if !row.has("source_id") {
+					sourceID = before.SourceID()
+				}
+				if !row.has("page") {
+					page = before.Page()
+				}
+				if !row.has("statement_year") {
+					statementYear = before.StatementYear()
+				}
+				languages = before.Languages()
+				if row.has("language") {
+					languages.Language = language
+				}
+				opinion = before.Revise(sentiment, quote, source, page, statementYear)
+			}
+
+			if err := validateOpinionContent(sentiment, quote, source, sourceID); err != nil {
+				return row.fail("", err), nil
+			}
+			if languages, err = validateLanguages(languages); err != nil {
+				return row.fail("language", err), nil
+			}
+			opinion.SetLanguages(languages)
+			if opinion, err = citeSource(repos.Sources, opinion, sourceID); err != nil {
+				return row.fail("source_id", err), nil
+			}
+			if err := checkParticipants(repos.Writers, repos.Works, opinion); err != nil {
+				return row.fail("", err), nil
+			}
+
+			if before != nil {
+				return rowUpdated, updateOpinion(ctx, repos, before, opinion)
+			}
+			return rowCreated, createOpinion(ctx, repos, opinion)
+		})
+}
+
+// run imports the rows of file one by one in a transaction, which it rolls
+// back after a dry run or when any row is invalid. Rows are written as
+// they are checked, so a later row sees the earlier ones; a file that
+// repeats a record conflicts with itself.
+func (s *importService) run(
+	file io.Reader,
+	required []string,
+	options ImportOptions,
+	importRow importRowFunc,
+) (*ImportReport, error) {
+	switch options.OnConflict {
+	case ConflictFail, ConflictSkip, ConflictUpdate:
+	default:
+		return nil, invalidImportFile{fmt.Errorf("invalid conflict policy %q", options.OnConflict)}
+	}
+	rows, err := readCSV(file, required)
+	if err != nil {
+		return nil, invalidImportFile{err}
+	}
+
+	report := &ImportReport{DryRun: options.DryRun, Rows: len(rows)}
+	err = s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		for _, row := range rows {
+			outcome := rowFailed
+			if !row.failed() {
+				var err error
+				if outcome, err = importRow(repos, row); err != nil {
+					return fmt.Errorf("row %d: %w", row.line, err)
+				}
+			}
+			switch outcome {
+			case rowCreated:
+				report.Created++
+			case rowUpdated:
+				report.Updated++
+			case rowSkipped:
+				report.Skipped++
+			case rowFailed:
+				report.Errors = append(report.Errors, row.errors...)
+			}
+		}
+		if !report.Applied() {
+			return errImportRolledBack
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errImportRolledBack) {
+		return nil, err
+	}
+	return report, nil
+}
+
+// resolveConflict applies the conflict policy to a row matching stored
+// records. Updating is refused when the row matches more than one, since
+// it could not say which.
+func resolveConflict(row *csvRow, policy ConflictPolicy, entity string, matches int) rowOutcome {
+	switch {
+	case policy == ConflictSkip:
+		return rowSkipped
+	case policy == ConflictUpdate && matches == 1:
+		return rowUpdated
+	case policy == ConflictUpdate:
+		return row.fail("", fmt.Errorf("%s matches %d stored records", entity, matches))
+	default:
+		return row.fail("", fmt.Errorf("%s already exists", entity))
+	}
+}
+
+// sameAuthors reports whether two works are credited to the same writers,
+// in whatever order.
+func sameAuthors(a, b []uint64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(a, b)
+}
+
+// readCSV reads the header and rows of an import file, requiring the given
+// columns. A malformed file is an error; a row with the wrong number of
+// fields is only marked as failed.
+func readCSV(file io.Reader, required []string) ([]*csvRow, error) {
+	reader := csv.NewReader(file)
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range required {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing column %q", name)
+		}
+	}
+
+	var rows []*csvRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil && !errors.Is(err, csv.ErrFieldCount) {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		row := &csvRow{line: line, columns: columns, record: record}
+		if err != nil {
+			row.fail("", fmt.Errorf("row has %d fields, expected %d", len(record), len(header)))
+		}
+		rows = append(rows, row)
+	}
+}
+
+// csvRow is a row of an import file, read by column name with surrounding
+// spaces trimmed. Fields that cannot be parsed are recorded as errors
+// rather than returned, so that a report lists every one of them.
+type csvRow struct {
+	line    int
+	columns map[string]int
+	record  []string
+	errors  []ImportError
+}
+
+// has reports whether the file has the column.
+func (r *csvRow) has(column string) bool {
+	_, ok := r.columns[column]
+	return ok
+}
+
+func (r *csvRow) text(column string) string {
+	i, ok := r.columns[column]
+	if !ok || i >= len(r.record) {
+		return ""
+	}
+	return strings.TrimSpace(r.record[i])
+}
+
+// optionalText returns nil for an empty field.
+func (r *csvRow) optionalText(column string) *string {
+	if s := r.text(column); s != "" {
+		return &s
+	}
+	return nil
+}
+
+// year returns zero for an empty field.
+func (r *csvRow) year(column string) int {
+	if year := r.optionalYear(column); year != nil {
+		return *year
+	}
+	return 0
+}
+
+func (r *csvRow) optionalYear(column string) *int {
+	s := r.text(column)
+	if s == "" {
+		return nil
+	}
+	year, err := strconv.Atoi(s)
+	if err != nil {
+		r.fail(column, errors.New("expected a year"))
+		return nil
+	}
+	return &year
+}
+
+// id returns zero for an empty field.
+func (r *csvRow) id(column string) uint64 {
+	s := r.text(column)
+	if s == "" {
+		return 0
+	}
+	id, err := strconv.ParseUint(s, 10, 64)
+	if err != nil || id == 0 {
+		r.fail(column, errors.New("expected an ID"))
+		return 0
+	}
+	return id
+}
+
+// ids reads a list of IDs separated by semicolons.
+func (r *csvRow) ids(column string) []uint64 {
+	s := r.text(column)
+	if s == "" {
+		return nil
+	}
+	var ids []uint64
+	for _, part := range strings.Split(s, ";") {
+		id, err := strconv.ParseUint(strings.TrimSpace(part), 10, 64)
+		if err != nil || id == 0 {
+			r.fail(column, errors.New("expected IDs separated by semicolons"))
+			return nil
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// sentiment reads the sentiment_grade column, falling back to the true or
+// false sentiment column of files exported before grades existed.
+func (r *csvRow) sentiment() domain.Sentiment {
+	if grade := r.text("sentiment_grade"); grade != "" {
+		sentiment, err := domain.ParseSentiment(grade)
+		if err != nil {
+			r.fail("sentiment_grade", err)
+		}
+		return sentiment
+	}
+	if legacy := r.text("sentiment"); legacy != "" {
+		positive, err := strconv.ParseBool(legacy)
+		if err != nil {
+			r.fail("sentiment", errors.New("expected true or false"))
+			return ""
+		}
+		return domain.SentimentFromBool(positive)
+	}
+	r.fail("sentiment_grade", errors.New("sentiment_grade is required"))
+	return ""
+}
+
+// fail records a problem with the row, or with one of its fields.
+func (r *csvRow) fail(field string, err error) rowOutcome {
+	r.errors = append(r.errors, ImportError{Row: r.line, Field: field, Message: err.Error()})
+	return rowFailed
+}
+
+func (r *csvRow) failed() bool {
+	return len(r.errors) > 0
+}
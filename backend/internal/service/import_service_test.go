@@ -0,0 +1,305 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/repository"
+	"github.com/what-writers-like/backend/internal/repository/memory"
+	"github.com/what-writers-like/backend/internal/service"
+)
+
+func newImportService(store *memory.Store) service.ImportService {
+	return service.NewImportService(memory.NewTransactor(store))
+}
+
+func importOptions(dryRun bool, onConflict service.ConflictPolicy) service.ImportOptions {
+	return service.ImportOptions{DryRun: dryRun, OnConflict: onConflict}
+}
+
+func TestImportService_ImportWriters(t *testing.T) {
+	t.Parallel()
+	const file = "Name,Birth_Year,death_year,bio\n" +
+		"Jane Austen,1775,1817,Novelist\n" +
+		"\"Charlotte Bronte\",1816,,\n"
+
+	t.Run("creates every row", func(t *testing.T) {
+		t.Parallel()
+		store := memory.NewStore()
+		svc := newImportService(store)
+
+		report, err := svc.ImportWriters(
+			context.Background(), strings.NewReader(file), importOptions(false, service.ConflictFail),
+		)
+		require.NoError(t, err)
+		assert.True(t, report.Applied())
+		assert.Equal(t, 2, report.Rows)
+		assert.Equal(t, 2, report.Created)
+		assert.Empty(t, report.Errors)
+
+		writers, err := memory.NewWriterRepository(store).GetByNameAndBirthYear("Jane Austen", 1775)
+		require.NoError(t, err)
+		require.Len(t, writers, 1)
+		assert.Equal(t, 1817, *writers[0].DeathYear())
+		assert.Equal(t, "Novelist", *writers[0].Bio())
+
+		entries, err := memory.NewAuditRepository(store).Find(repository.AuditFilter{}, 10, 0)
+		require.NoError(t, err)
+		assert.Len(t, entries, 2)
+	})
+
+	t.Run("dry run writes nothing", func(t *testing.T) {
+		t.Parallel()
+		store := memory.NewStore()
+		svc := newImportService(store)
+
+		report, err := svc.ImportWriters(
+			context.Background(), strings.NewReader(file), importOptions(true, service.ConflictFail),
+		)
+		require.NoError(t, err)
+		assert.False(t, report.Applied())
+		assert.Equal(t, 2, report.Created)
+
+		page, err := memory.NewWriterRepository(store).List(repository.WriterFilter{}, repository.WriterSort{}, nil, 10)
+		require.NoError(t, err)
+		assert.Zero(t, page.Total)
+	})
+
+	t.Run("invalid rows are reported and nothing is written", func(t *testing.T) {
+		t.Parallel()
+		store := memory.NewStore()
+		svc := newImportService(store)
+
+		const invalid = "name,birth_year,death_year\n" +
+			"Jane Austen,1775,\n" +
+			",1816,\n" +
+			"Charles Dickens,eighteen twelve,18x0\n"
+		report, err := svc.ImportWriters(
+			context.Background(), strings.NewReader(invalid), importOptions(false, service.ConflictFail),
+		)
+		require.NoError(t, err)
+		assert.False(t, report.Applied())
+		assert.Equal(t, 1, report.Created)
+		assert.Equal(t, []service.ImportError{
+			{Row: 3, Message: "name is required"},
+			{Row: 4, Field: "birth_year", Message: "expected a year"},
+			{Row: 4, Field: "death_year", Message: "expected a year"},
+		}, report.Errors)
+
+		page, err := memory.NewWriterRepository(store).List(repository.WriterFilter{}, repository.WriterSort{}, nil, 10)
+		require.NoError(t, err)
+		assert.Zero(t, page.Total)
+	})
+
+	t.Run("conflicts", func(t *testing.T) {
+		t.Parallel()
+		store := memory.NewStore()
+		writerRepo := memory.NewWriterRepository(store)
+		bio := "Stored bio"
+		require.NoError(t, writerRepo.Create(domain.NewWriter(0, "Jane Austen", 1775, nil, &bio)))
+		svc := newImportService(store)
+		const update = "name,birth_year,death_year\nJane Austen,1775,1817\n"
+
+		report, err := svc.ImportWriters(context.Background(), strings.NewReader(update), importOptions(false, ""))
+		require.Error(t, err)
+		assert.Nil(t, report)
+
+		report, err = svc.ImportWriters(
+			context.Background(), strings.NewReader(update), importOptions(false, service.ConflictFail),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, []service.ImportError{{Row: 2, Message: "writer already exists"}}, report.Errors)
+
+		report, err = svc.ImportWriters(
+			context.Background(), strings.NewReader(update), importOptions(false, service.ConflictSkip),
+		)
+		require.NoError(t, err)
+		assert.True(t, report.Applied())
+		assert.Equal(t, 1, report.Skipped)
+
+		report, err = svc.ImportWriters(
+			context.Background(), strings.NewReader(update), importOptions(false, service.ConflictUpdate),
+		)
+		require.NoError(t, err)
+		assert.True(t, report.Applied())
+		assert.Equal(t, 1, report.Updated)
+
+		// The file has no bio column, so the stored bio stays
+		writer, err := writerRepo.GetByID(1)
+		require.NoError(t, err)
+		assert.Equal(t, 1817, *writer.DeathYear())
+		assert.Equal(t, "Stored bio", *writer.Bio())
+	})
+
+	t.Run("a file repeating a writer conflicts with itself", func(t *testing.T) {
+		t.Parallel()
+		svc := newImportService(memory.NewStore())
+
+		const repeated = "name,birth_year\nJane Austen,1775\nJane Austen,1775\n"
+		report, err := svc.ImportWriters(
+			context.Background(), strings.NewReader(repeated), importOptions(true, service.ConflictFail),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, 1, report.Created)
+		assert.Equal(t, []service.ImportError{{Row: 3, Message: "writer already exists"}}, report.Errors)
+	})
+
+	t.Run("malformed files", func(t *testing.T) {
+		t.Parallel()
+		svc := newImportService(memory.NewStore())
+
+		for _, file := range []string{"", "name,death_year\nJane Austen,1817\n", "name,birth_year\n\"Jane,1775\n"} {
+			_, err := svc.ImportWriters(
+				context.Background(), strings.NewReader(file), importOptions(true, service.ConflictFail),
+			)
+			assert.Error(t, err, file)
+		}
+	})
+}
+
+func TestImportService_ImportWorks(t *testing.T) {
+	t.Parallel()
+	store := memory.NewStore()
+	writerRepo := memory.NewWriterRepository(store)
+	workRepo := memory.NewWorkRepository(store)
+	require.NoError(t, writerRepo.Create(domain.NewWriter(1, "Francis Beaumont", 1584, nil, nil)))
+	require.NoError(t, writerRepo.Create(domain.NewWriter(2, "John Fletcher", 1579, nil, nil)))
+	require.NoError(t, workRepo.Create(domain.NewWork(1, "The Maid's Tragedy", []uint64{1, 2}, domain.WorkDetails{})))
+	svc := newImportService(store)
+
+	const file = "title,author_ids,publication_year,genre\n" +
+		"The Maid's Tragedy,2;1,1619,tragedy\n" +
+		"The Faithful Shepherdess,2,1610,\n" +
+		"Everyman,,,morality play\n" +
+		"Philaster,1;3,,\n"
+	report, err := svc.ImportWorks(
+		context.Background(), strings.NewReader(file), importOptions(true, service.ConflictUpdate),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Updated)
+	assert.Equal(t, 2, report.Created)
+	assert.Equal(t, []service.ImportError{{Row: 5, Field: "author_ids", Message: "author not found"}}, report.Errors)
+
+	const valid = "title,author_ids,publication_year,genre\n" +
+		"The Maid's Tragedy,2;1,1619,tragedy\n" +
+		"Everyman,,,morality play\n"
+	report, err = svc.ImportWorks(
+		context.Background(), strings.NewReader(valid), importOptions(false, service.ConflictUpdate),
+	)
+	require.NoError(t, err)
+	assert.True(t, report.Applied())
+
+	work, err := workRepo.GetByID(1)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{2, 1}, work.AuthorIDs())
+	assert.Equal(t, 1619, *work.Details().PublicationYear)
+	anonymous, err := workRepo.GetByTitle("Everyman")
+	require.NoError(t, err)
+	require.Len(t, anonymous, 1)
+	assert.Empty(t, anonymous[0].AuthorIDs())
+
+	// Files exported with a single author_id column still import
+	const legacy = "id,title,author_id\n7,Bonduca,2\n"
+	report, err = svc.ImportWorks(
+		context.Background(), strings.NewReader(legacy), importOptions(false, service.ConflictFail),
+	)
+	require.NoError(t, err)
+	assert.True(t, report.Applied())
+	bonduca, err := workRepo.GetByTitle("Bonduca")
+	require.NoError(t, err)
+	require.Len(t, bonduca, 1)
+	assert.Equal(t, []uint64{2}, bonduca[0].AuthorIDs())
+}
+
+func TestImportService_ImportOpinions(t *testing.T) {
+	t.Parallel()
+	setup := func(t *testing.T) (*memory.Store, service.ImportService) {
+		t.Helper()
+		store := memory.NewStore()
+		writerRepo := memory.NewWriterRepository(store)
+		require.NoError(t, writerRepo.Create(domain.NewWriter(1, "Leo Tolstoy", 1828, nil, nil)))
+		require.NoError(t, writerRepo.Create(domain.NewWriter(2, "William Shakespeare", 1564, nil, nil)))
+		require.NoError(t, memory.NewWorkRepository(store).Create(
+			domain.NewWork(1, "King Lear", []uint64{2}, domain.WorkDetails{}),
+		))
+		return store, newImportService(store)
+	}
+
+	t.Run("creates and validates", func(t *testing.T) {
+		t.Parallel()
+		store, svc := setup(t)
+
+		const file = "writer_id,work_id,target_writer_id,sentiment_grade,sentiment,quote,source,statement_year\n" +
+			"1,1,,-2,,\"Not only is it not a great work, it is a very poor one\",Essay,1906\n" +
+			"1,,2,,false,He was no genius,Essay,\n" +
+			"2,1,,+1,,Mine own,Folio,\n" +
+			"1,1,2,0,,Both,Essay,\n" +
+			"1,1,,,,No grade,Essay,\n"
+		report, err := svc.ImportOpinions(
+			context.Background(), strings.NewReader(file), importOptions(false, service.ConflictFail),
+		)
+		require.NoError(t, err)
+		assert.False(t, report.Applied())
+		assert.Equal(t, 2, report.Created)
+		assert.Equal(t, []service.ImportError{
+			{Row: 4, Message: "writer cannot express opinion about their own work"},
+			{Row: 5, Field: "work_id", Message: "exactly one of work_id and target_writer_id is required"},
+			{Row: 6, Field: "sentiment_grade", Message: "sentiment_grade is required"},
+		}, report.Errors)
+
+		opinions, err := memory.NewOpinionRepository(store).GetByWriterID(1)
+		require.NoError(t, err)
+		assert.Empty(t, opinions)
+	})
+
+	t.Run("updates with a revision", func(t *testing.T) {
+		t.Parallel()
+		store, svc := setup(t)
+
+		const file = "writer_id,work_id,sentiment_grade,quote,source,page\n" +
+			"1,1,-1,A poor play,Essay,12\n"
+		report, err := svc.ImportOpinions(
+			context.Background(), strings.NewReader(file), importOptions(false, service.ConflictFail),
+		)
+		require.NoError(t, err)
+		require.True(t, report.Applied())
+
+		const revised = "writer_id,work_id,sentiment_grade,quote\n" +
+			"1,1,-2,A poor play\n"
+		report, err = svc.ImportOpinions(
+			context.Background(), strings.NewReader(revised), importOptions(false, service.ConflictUpdate),
+		)
+		require.NoError(t, err)
+		require.True(t, report.Applied())
+		assert.Equal(t, 1, report.Updated)
+
+		opinions, err := memory.NewOpinionRepository(store).GetByWriterAndWork(1, 1)
+		require.NoError(t, err)
+		require.Len(t, opinions, 1)
+		assert.Equal(t, domain.SentimentVeryNegative, opinions[0].Sentiment())
+		assert.Equal(t, "Essay", opinions[0].Source())
+		assert.Equal(t, "12", *opinions[0].Page())
+
+		revisions, err := memory.NewOpinionRevisionRepository(store).ListByOpinion(opinions[0].ID())
+		require.NoError(t, err)
+		assert.Len(t, revisions, 2)
+	})
+}
+
+func TestParseConflictPolicy(t *testing.T) {
+	t.Parallel()
+	policy, err := service.ParseConflictPolicy("")
+	require.NoError(t, err)
+	assert.Equal(t, service.ConflictFail, policy)
+
+	policy, err = service.ParseConflictPolicy("update")
+	require.NoError(t, err)
+	assert.Equal(t, service.ConflictUpdate, policy)
+
+	_, err = service.ParseConflictPolicy("merge")
+	assert.Error(t, err)
+}
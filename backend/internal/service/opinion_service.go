@@ -1,81 +1,176 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/what-writers-like/backend/internal/domain"
 	"github.com/what-writers-like/backend/internal/repository"
 )
 
+// OpinionService records opinions. A non-zero sourceID links an opinion to a
+// catalogued source, whose title becomes the citation when source is empty.
+// Languages gives the language of the quote and its translations.
 type OpinionService interface {
 	CreateOpinion(
+		ctx context.Context,
 		writerID, workID uint64,
-		sentiment bool,
+		sentiment domain.Sentiment,
 		quote, source string,
 		page *string,
 		statementYear *int,
+		sourceID uint64,
+		languages domain.QuoteLanguages,
 	) (*domain.Opinion, error)
+	// CreateWriterOpinion records an opinion about targetWriterID as a
+	// person rather than about one of their works.
+	CreateWriterOpinion(
+		ctx context.Context,
+		writerID, targetWriterID uint64,
+		sentiment domain.Sentiment,
+		quote, source string,
+		page *string,
+		statementYear *int,
+		sourceID uint64,
+		languages domain.QuoteLanguages,
+	) (*domain.Opinion, error)
+	GetOpinion(id uint64) (*domain.Opinion, error)
 	GetOpinionsByWriter(writerID uint64) ([]*domain.Opinion, error)
 	GetOpinionsByWork(workID uint64) ([]*domain.Opinion, error)
-	GetOpinion(writerID, workID uint64) (*domain.Opinion, error)
-	ListOpinions(limit, offset int) ([]*domain.Opinion, error)
-	UpdateOpinion(writerID, workID uint64, sentiment bool, quote, source string, page *string, statementYear *int) error
-	DeleteOpinion(writerID, workID uint64) error
+	GetOpinionsByWriterAndWork(writerID, workID uint64) ([]*domain.Opinion, error)
+	GetOpinionsAboutWriter(targetWriterID uint64) ([]*domain.Opinion, error)
+	GetOpinionsByWriterAboutWriter(writerID, targetWriterID uint64) ([]*domain.Opinion, error)
+	// ListOpinions returns a page of opinions after the one marked by
+	// after, or the first page when it is nil.
+	ListOpinions(
+		filter repository.OpinionFilter,
+		sort repository.OpinionSort,
+		after *repository.OpinionCursor,
+		limit int,
+	) (*repository.Page[*domain.Opinion, repository.OpinionCursor], error)
+	// SearchQuotes runs a full-text search of quotes, in the syntax of
+	// repository.OpinionRepository.SearchQuotes. A non-empty language is
+	// a BCP 47 tag restricting the search to quotes written in it.
+	SearchQuotes(query, language string, limit, offset int) ([]*domain.QuoteMatch, error)
+	// UpdateOpinion revises an opinion. A nil source ID, language or
+	// translations keeps the stored one; a source ID of zero unlinks the
+	// opinion from its catalogued source.
+	UpdateOpinion(
+		ctx context.Context,
+		id uint64,
+		sentiment domain.Sentiment,
+		quote, source string,
+		page *string,
+		statementYear *int,
+		sourceID *uint64,
+		language *string,
+		translations *[]domain.Translation,
+	) error
+	DeleteOpinion(ctx context.Context, id uint64) error
+	DeleteOpinionsByWriterAndWork(ctx context.Context, writerID, workID uint64) error
+	ListRevisions(opinionID uint64) ([]*domain.OpinionRevision, error)
+	GetRevision(opinionID uint64, number int) (*domain.OpinionRevision, error)
+	DiffRevisions(opinionID uint64, from, to int) ([]domain.FieldChange, error)
+	RestoreRevision(ctx context.Context, opinionID uint64, number int) (*domain.OpinionRevision, error)
 }
 
 type opinionService struct {
 	opinionRepo repository.OpinionRepository
 	writerRepo  repository.WriterRepository
 	workRepo    repository.WorkRepository
+	revisions   repository.OpinionRevisionRepository
+	sources     repository.SourceRepository
+	transactor  repository.Transactor
 }
 
 func NewOpinionService(
 	opinionRepo repository.OpinionRepository,
 	writerRepo repository.WriterRepository,
 	workRepo repository.WorkRepository,
+	revisions repository.OpinionRevisionRepository,
+	sources repository.SourceRepository,
+	transactor repository.Transactor,
 ) OpinionService {
 	return &opinionService{
 		opinionRepo: opinionRepo,
 		writerRepo:  writerRepo,
 		workRepo:    workRepo,
+		revisions:   revisions,
+		sources:     sources,
+		transactor:  transactor,
 	}
 }
 
 func (s *opinionService) CreateOpinion(
+	ctx context.Context,
 	writerID, workID uint64,
-	sentiment bool,
+	sentiment domain.Sentiment,
 	quote, source string,
 	page *string,
 	statementYear *int,
+	sourceID uint64,
+	languages domain.QuoteLanguages,
 ) (*domain.Opinion, error) {
-	if quote == "" {
-		return nil, errors.New("quote is required")
-	}
-	if source == "" {
-		return nil, errors.New("source is required")
-	}
+	return s.create(
+		ctx, domain.NewOpinion(0, writerID, workID, sentiment, quote, source, page, statementYear), sourceID, languages,
+	)
+}
 
-	work, err := s.workRepo.GetByID(workID)
+func (s *opinionService) CreateWriterOpinion(
+	ctx context.Context,
+	writerID, targetWriterID uint64,
+	sentiment domain.Sentiment,
+	quote, source string,
+	page *string,
+	statementYear *int,
+	sourceID uint64,
+	languages domain.QuoteLanguages,
+) (*domain.Opinion, error) {
+	return s.create(
+		ctx,
+		domain.NewWriterOpinion(0, writerID, targetWriterID, sentiment, quote, source, page, statementYear),
+		sourceID,
+		languages,
+	)
+}
+
+func (s *opinionService) create(
+	ctx context.Context,
+	opinion *domain.Opinion,
+	sourceID uint64,
+	languages domain.QuoteLanguages,
+) (*domain.Opinion, error) {
+	err := validateOpinionContent(opinion.Sentiment(), opinion.Quote(), opinion.Source(), sourceID)
 	if err != nil {
-		return nil, errors.New("work not found")
+		return nil, err
 	}
-
-	if work.AuthorID() == writerID {
-		return nil, errors.New("writer cannot express opinion about their own work")
+	if languages, err = validateLanguages(languages); err != nil {
+		return nil, err
 	}
-
-	_, err = s.writerRepo.GetByID(writerID)
-	if err != nil {
-		return nil, errors.New("writer not found")
+	opinion.SetLanguages(languages)
+	if opinion, err = citeSource(s.sources, opinion, sourceID); err != nil {
+		return nil, err
+	}
+	if err := checkParticipants(s.writerRepo, s.workRepo, opinion); err != nil {
+		return nil, err
 	}
 
-	opinion := domain.NewOpinion(writerID, workID, sentiment, quote, source, page, statementYear)
-	if err := s.opinionRepo.Create(opinion); err != nil {
+	err = s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		return createOpinion(ctx, repos, opinion)
+	})
+	if err != nil {
 		return nil, err
 	}
 	return opinion, nil
 }
 
+func (s *opinionService) GetOpinion(id uint64) (*domain.Opinion, error) {
+	return s.opinionRepo.GetByID(id)
+}
+
 func (s *opinionService) GetOpinionsByWriter(writerID uint64) ([]*domain.Opinion, error) {
 	return s.opinionRepo.GetByWriterID(writerID)
 }
@@ -84,41 +179,375 @@ func (s *opinionService) GetOpinionsByWork(workID uint64) ([]*domain.Opinion, er
 	return s.opinionRepo.GetByWorkID(workID)
 }
 
-func (s *opinionService) GetOpinion(writerID, workID uint64) (*domain.Opinion, error) {
+func (s *opinionService) GetOpinionsByWriterAndWork(writerID, workID uint64) ([]*domain.Opinion, error) {
 	return s.opinionRepo.GetByWriterAndWork(writerID, workID)
 }
 
-func (s *opinionService) ListOpinions(limit, offset int) ([]*domain.Opinion, error) {
-	return s.opinionRepo.List(limit, offset)
+func (s *opinionService) GetOpinionsAboutWriter(targetWriterID uint64) ([]*domain.Opinion, error) {
+	return s.opinionRepo.GetByTargetWriterID(targetWriterID)
+}
+
+func (s *opinionService) GetOpinionsByWriterAboutWriter(writerID, targetWriterID uint64) ([]*domain.Opinion, error) {
+	return s.opinionRepo.GetByWriterAndTargetWriter(writerID, targetWriterID)
+}
+
+func (s *opinionService) ListOpinions(
+	filter repository.OpinionFilter,
+	sort repository.OpinionSort,
+	after *repository.OpinionCursor,
+	limit int,
+) (*repository.Page[*domain.Opinion, repository.OpinionCursor], error) {
+	return s.opinionRepo.List(filter, sort, after, limit)
+}
+
+func (s *opinionService) SearchQuotes(query, language string, limit, offset int) ([]*domain.QuoteMatch, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, errors.New("query is required")
+	}
+	if language != "" {
+		var err error
+		if language, err = domain.ParseLanguage(language); err != nil {
+			return nil, err
+		}
+	}
+	return s.opinionRepo.SearchQuotes(query, language, limit, offset)
 }
 
 func (s *opinionService) UpdateOpinion(
-	writerID, workID uint64,
-	sentiment bool,
+	ctx context.Context,
+	id uint64,
+	sentiment domain.Sentiment,
 	quote, source string,
 	page *string,
 	statementYear *int,
+	sourceID *uint64,
+	language *string,
+	translations *[]domain.Translation,
 ) error {
+	before, err := s.opinionRepo.GetByID(id)
+	if err != nil {
+		return errors.New("opinion not found")
+	}
+	citedID := before.SourceID()
+	if sourceID != nil {
+		citedID = *sourceID
+	}
+	if err := validateOpinionContent(sentiment, quote, source, citedID); err != nil {
+		return err
+	}
+	languages := before.Languages()
+	if language != nil {
+		languages.Language = *language
+	}
+	if translations != nil {
+		languages.Translations = *translations
+	}
+	if languages, err = validateLanguages(languages); err != nil {
+		return err
+	}
+	revised := before.Revise(sentiment, quote, source, page, statementYear)
+	revised.SetLanguages(languages)
+	opinion, err := citeSource(s.sources, revised, citedID)
+	if err != nil {
+		return err
+	}
+
+	// The target never changes, but a work may have been reattributed to
+	// the writer since the opinion was recorded
+	if !before.IsAboutWriter() {
+		work, err := s.workRepo.GetByID(before.WorkID())
+		if err != nil {
+			return errors.New("work not found")
+		}
+		if work.HasAuthor(before.WriterID()) {
+			return errors.New("writer cannot express opinion about their own work")
+		}
+	}
+
+	// Updating the opinion row first holds concurrent edits of it back
+	// until this one commits, so each numbers its revision after the last
+	return s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		return updateOpinion(ctx, repos, before, opinion)
+	})
+}
+
+func createOpinion(ctx context.Context, repos *repository.Repositories, opinion *domain.Opinion) error {
+	if err := repos.Opinions.Create(opinion); err != nil {
+		return err
+	}
+	err := recordChange(
+		ctx, repos.Audit, domain.AuditEntityOpinion, entityID(opinion.ID()),
+		domain.AuditActionCreate, nil, opinionSnapshot(opinion),
+	)
+	if err != nil {
+		return err
+	}
+	_, err = recordRevision(ctx, repos.OpinionRevisions, opinion)
+	return err
+}
+
+func updateOpinion(ctx context.Context, repos *repository.Repositories, before, opinion *domain.Opinion) error {
+	if err := repos.Opinions.Update(opinion); err != nil {
+		return err
+	}
+	err := recordChange(
+		ctx, repos.Audit, domain.AuditEntityOpinion, entityID(opinion.ID()),
+		domain.AuditActionUpdate, opinionSnapshot(before), opinionSnapshot(opinion),
+	)
+	if err != nil {
+		return err
+	}
+	_, err = recordRevision(ctx, repos.OpinionRevisions, opinion)
+	return err
+}
+
+func (s *opinionService) DeleteOpinion(ctx context.Context, id uint64) error {
+	before, err := s.opinionRepo.GetByID(id)
+	if err != nil {
+		return errors.New("opinion not found")
+	}
+	return s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		return deleteOpinion(ctx, repos, before)
+	})
+}
+
+// DeleteOpinionsByWriterAndWork deletes every statement the writer made
+// about the work.
+func (s *opinionService) DeleteOpinionsByWriterAndWork(ctx context.Context, writerID, workID uint64) error {
+	opinions, err := s.opinionRepo.GetByWriterAndWork(writerID, workID)
+	if err != nil {
+		return err
+	}
+	if len(opinions) == 0 {
+		return errors.New("opinion not found")
+	}
+	return s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		for _, opinion := range opinions {
+			if err := deleteOpinion(ctx, repos, opinion); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func deleteOpinion(ctx context.Context, repos *repository.Repositories, opinion *domain.Opinion) error {
+	if err := repos.Opinions.Delete(opinion.ID()); err != nil {
+		return err
+	}
+	return recordChange(
+		ctx, repos.Audit, domain.AuditEntityOpinion, entityID(opinion.ID()),
+		domain.AuditActionDelete, opinionSnapshot(opinion), nil,
+	)
+}
+
+func (s *opinionService) ListRevisions(opinionID uint64) ([]*domain.OpinionRevision, error) {
+	revisions, err := s.revisions.ListByOpinion(opinionID)
+	if err != nil {
+		return nil, err
+	}
+	if len(revisions) == 0 {
+		return nil, errors.New("opinion not found")
+	}
+	return revisions, nil
+}
+
+func (s *opinionService) GetRevision(opinionID uint64, number int) (*domain.OpinionRevision, error) {
+	revision, err := s.revisions.Get(opinionID, number)
+	if err != nil {
+		return nil, fmt.Errorf("revision %d not found", number)
+	}
+	return revision, nil
+}
+
+func (s *opinionService) DiffRevisions(opinionID uint64, from, to int) ([]domain.FieldChange, error) {
+	fromRevision, err := s.GetRevision(opinionID, from)
+	if err != nil {
+		return nil, err
+	}
+	toRevision, err := s.GetRevision(opinionID, to)
+	if err != nil {
+		return nil, err
+	}
+	return domain.DiffOpinions(fromRevision.Opinion(), toRevision.Opinion()), nil
+}
+
+// RestoreRevision makes an old revision current again. The restore is itself
+// a new revision, so it can be undone the same way; an opinion that has been
+// deleted since is recreated under its old ID.
+func (s *opinionService) RestoreRevision(
+	ctx context.Context,
+	opinionID uint64,
+	number int,
+) (*domain.OpinionRevision, error) {
+	old, err := s.GetRevision(opinionID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	// The writer, target or authorship may have changed since the revision
+	// was made
+	opinion := old.Opinion()
+	if err := checkParticipants(s.writerRepo, s.workRepo, opinion); err != nil {
+		return nil, err
+	}
+	if opinion.SourceID() != 0 {
+		if _, err := s.sources.GetByID(opinion.SourceID()); err != nil {
+			return nil, errors.New("source not found")
+		}
+	}
+
+	var revision *domain.OpinionRevision
+	err = s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		var beforeSnapshot map[string]any
+		action := domain.AuditActionUpdate
+		if before, err := repos.Opinions.GetByID(opinionID); err == nil {
+			beforeSnapshot = opinionSnapshot(before)
+			if err := repos.Opinions.Update(opinion); err != nil {
+				return err
+			}
+		} else {
+			action = domain.AuditActionCreate
+			if err := repos.Opinions.Create(opinion); err != nil {
+				return err
+			}
+		}
+		err := recordChange(
+			ctx, repos.Audit, domain.AuditEntityOpinion, entityID(opinionID),
+			action, beforeSnapshot, opinionSnapshot(opinion),
+		)
+		if err != nil {
+			return err
+		}
+		revision, err = recordRevision(ctx, repos.OpinionRevisions, opinion)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
+
+// validateOpinionContent requires a citation unless the opinion is linked
+// to a catalogued source, whose title then stands in for it.
+func validateOpinionContent(sentiment domain.Sentiment, quote, source string, sourceID uint64) error {
+	if !sentiment.IsValid() {
+		return errors.New("invalid sentiment")
+	}
 	if quote == "" {
 		return errors.New("quote is required")
 	}
-	if source == "" {
+	if source == "" && sourceID == 0 {
 		return errors.New("source is required")
 	}
+	return nil
+}
+
+// validateLanguages checks the language of a quote and its translations and
+// returns them with their tags in canonical form. A quote may only be
+// translated once its own language is known, and into each other language
+// once.
+func validateLanguages(languages domain.QuoteLanguages) (domain.QuoteLanguages, error) {
+	if languages.Language == "" {
+		if len(languages.Translations) > 0 {
+			return domain.QuoteLanguages{}, errors.New("language is required for a translated quote")
+		}
+		return languages, nil
+	}
+	original, err := domain.ParseLanguage(languages.Language)
+	if err != nil {
+		return domain.QuoteLanguages{}, err
+	}
 
-	work, err := s.workRepo.GetByID(workID)
+	seen := map[string]bool{original: true}
+	var translations []domain.Translation
+	for _, t := range languages.Translations {
+		tag, err := domain.ParseLanguage(t.Language)
+		if err != nil {
+			return domain.QuoteLanguages{}, err
+		}
+		if t.Text == "" {
+			return domain.QuoteLanguages{}, fmt.Errorf("translation into %s has no text", tag)
+		}
+		if tag == original {
+			return domain.QuoteLanguages{}, fmt.Errorf("translation into %s is in the quote's own language", tag)
+		}
+		if seen[tag] {
+			return domain.QuoteLanguages{}, fmt.Errorf("quote is translated into %s more than once", tag)
+		}
+		seen[tag] = true
+		t.Language = tag
+		translations = append(translations, t)
+	}
+	return domain.QuoteLanguages{Language: original, Translations: translations}, nil
+}
+
+// citeSource links the opinion to the catalogued source with the given ID,
+// or unlinks it when the ID is zero. An empty citation is filled in with
+// the source's title.
+func citeSource(
+	sources repository.SourceRepository,
+	opinion *domain.Opinion,
+	sourceID uint64,
+) (*domain.Opinion, error) {
+	opinion.SetSourceID(sourceID)
+	if sourceID == 0 {
+		return opinion, nil
+	}
+	source, err := sources.GetByID(sourceID)
 	if err != nil {
-		return errors.New("work not found")
+		return nil, errors.New("source not found")
+	}
+	if opinion.Source() == "" {
+		opinion = opinion.Revise(
+			opinion.Sentiment(), opinion.Quote(), source.Title(), opinion.Page(), opinion.StatementYear(),
+		)
 	}
+	return opinion, nil
+}
 
-	if work.AuthorID() == writerID {
-		return errors.New("writer cannot express opinion about their own work")
+// checkParticipants verifies that the writer and target of an opinion exist
+// and that the writer is neither the target nor the target work's author.
+func checkParticipants(
+	writers repository.WriterRepository,
+	works repository.WorkRepository,
+	opinion *domain.Opinion,
+) error {
+	writerID := opinion.WriterID()
+	if opinion.IsAboutWriter() {
+		if opinion.TargetWriterID() == writerID {
+			return errors.New("writer cannot express opinion about themselves")
+		}
+		if _, err := writers.GetByID(opinion.TargetWriterID()); err != nil {
+			return errors.New("target writer not found")
+		}
+	} else {
+		work, err := works.GetByID(opinion.WorkID())
+		if err != nil {
+			return errors.New("work not found")
+		}
+		if work.HasAuthor(writerID) {
+			return errors.New("writer cannot express opinion about their own work")
+		}
 	}
 
-	opinion := domain.NewOpinion(writerID, workID, sentiment, quote, source, page, statementYear)
-	return s.opinionRepo.Update(opinion)
+	if _, err := writers.GetByID(writerID); err != nil {
+		return errors.New("writer not found")
+	}
+	return nil
 }
 
-func (s *opinionService) DeleteOpinion(writerID, workID uint64) error {
-	return s.opinionRepo.Delete(writerID, workID)
+// recordRevision stores the current state of an opinion as its newest
+// revision.
+func recordRevision(
+	ctx context.Context,
+	revisions repository.OpinionRevisionRepository,
+	opinion *domain.Opinion,
+) (*domain.OpinionRevision, error) {
+	revision := domain.NewOpinionRevision(0, opinion, actorFromContext(ctx), time.Now().UTC())
+	if err := revisions.Create(revision); err != nil {
+		return nil, fmt.Errorf("failed to record opinion revision: %w", err)
+	}
+	return revision, nil
 }
@@ -1,25 +1,28 @@
 package service_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/what-writers-like/backend/internal/domain"
-	"github.com/what-writers-like/backend/internal/repository/gorm"
+	"github.com/what-writers-like/backend/internal/repository"
+	"github.com/what-writers-like/backend/internal/repository/memory"
 	"github.com/what-writers-like/backend/internal/service"
-	"github.com/what-writers-like/backend/internal/testutils"
 )
 
 func TestOpinionService_ListOpinions(t *testing.T) {
 	t.Parallel()
-	db, cleanup := testutils.SetupTestDB(t)
-	defer cleanup()
+	store := memory.NewStore()
 
-	opinionRepo := gorm.NewOpinionRepository(db)
-	writerRepo := gorm.NewWriterRepository(db)
-	workRepo := gorm.NewWorkRepository(db)
-	svc := service.NewOpinionService(opinionRepo, writerRepo, workRepo)
+	opinionRepo := memory.NewOpinionRepository(store)
+	writerRepo := memory.NewWriterRepository(store)
+	workRepo := memory.NewWorkRepository(store)
+	svc := service.NewOpinionService(
+		opinionRepo, writerRepo, workRepo, memory.NewOpinionRevisionRepository(store), memory.NewSourceRepository(store),
+		memory.NewTransactor(store),
+	)
 
 	writer1 := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
 	writer2 := domain.NewWriter(2, "Charlotte Bronte", 1816, nil, nil)
@@ -28,141 +31,285 @@ func TestOpinionService_ListOpinions(t *testing.T) {
 	require.NoError(t, writerRepo.Create(writer2))
 	require.NoError(t, writerRepo.Create(writer3))
 
-	work1 := domain.NewWork(1, "Pride and Prejudice", 1)
-	work2 := domain.NewWork(2, "Jane Eyre", 2)
+	work1 := domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})
+	work2 := domain.NewWork(2, "Jane Eyre", []uint64{2}, domain.WorkDetails{})
 	require.NoError(t, workRepo.Create(work1))
 	require.NoError(t, workRepo.Create(work2))
 
-	opinion1 := domain.NewOpinion(2, 1, true, "Quote 1", "Source 1", nil, nil)
-	opinion2 := domain.NewOpinion(3, 2, false, "Quote 2", "Source 2", nil, nil)
+	opinion1 := domain.NewOpinion(0, 2, 1, domain.SentimentPositive, "Quote 1", "Source 1", nil, nil)
+	opinion2 := domain.NewOpinion(0, 3, 2, domain.SentimentNegative, "Quote 2", "Source 2", nil, nil)
 	require.NoError(t, opinionRepo.Create(opinion1))
 	require.NoError(t, opinionRepo.Create(opinion2))
 
-	opinions, err := svc.ListOpinions(10, 0)
+	page, err := svc.ListOpinions(repository.OpinionFilter{}, repository.OpinionSort{}, nil, 10)
 	require.NoError(t, err)
-	assert.Len(t, opinions, 2)
+	assert.Len(t, page.Items, 2)
+}
+
+func TestOpinionService_SearchQuotes(t *testing.T) {
+	t.Parallel()
+	store := memory.NewStore()
+
+	opinionRepo := memory.NewOpinionRepository(store)
+	writerRepo := memory.NewWriterRepository(store)
+	workRepo := memory.NewWorkRepository(store)
+	svc := service.NewOpinionService(
+		opinionRepo, writerRepo, workRepo, memory.NewOpinionRevisionRepository(store), memory.NewSourceRepository(store),
+		memory.NewTransactor(store),
+	)
+
+	require.NoError(t, writerRepo.Create(domain.NewWriter(1, "Jane Austen", 1775, nil, nil)))
+	require.NoError(t, writerRepo.Create(domain.NewWriter(2, "Leo Tolstoy", 1828, nil, nil)))
+	require.NoError(t, workRepo.Create(domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})))
+	opinion := domain.NewOpinion(0, 2, 1, domain.SentimentNegative, "Tedious and vulgar", "Diary", nil, nil)
+	opinion.SetLanguages(domain.QuoteLanguages{Language: "en"})
+	require.NoError(t, opinionRepo.Create(opinion))
+
+	// The query is trimmed and the language canonicalized
+	matches, err := svc.SearchQuotes("  vulgar ", "EN", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, opinion.ID(), matches[0].Opinion.ID())
+
+	_, err = svc.SearchQuotes(" ", "", 10, 0)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "query is required")
+
+	_, err = svc.SearchQuotes("vulgar", "english please", 10, 0)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid language")
 }
 
 func TestOpinionService_CreateOpinion(t *testing.T) {
 	t.Parallel()
 	t.Run("success", func(t *testing.T) {
 		t.Parallel()
-		db, cleanup := testutils.SetupTestDB(t)
-		defer cleanup()
+		store := memory.NewStore()
 
-		opinionRepo := gorm.NewOpinionRepository(db)
-		writerRepo := gorm.NewWriterRepository(db)
-		workRepo := gorm.NewWorkRepository(db)
-		svc := service.NewOpinionService(opinionRepo, writerRepo, workRepo)
+		opinionRepo := memory.NewOpinionRepository(store)
+		writerRepo := memory.NewWriterRepository(store)
+		workRepo := memory.NewWorkRepository(store)
+		svc := service.NewOpinionService(
+			opinionRepo, writerRepo, workRepo, memory.NewOpinionRevisionRepository(store), memory.NewSourceRepository(store),
+			memory.NewTransactor(store),
+		)
 
 		writer1 := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
 		writer2 := domain.NewWriter(2, "Charlotte Bronte", 1816, nil, nil)
 		require.NoError(t, writerRepo.Create(writer1))
 		require.NoError(t, writerRepo.Create(writer2))
 
-		work := domain.NewWork(1, "Pride and Prejudice", 1)
+		work := domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})
 		require.NoError(t, workRepo.Create(work))
 
-		opinion, err := svc.CreateOpinion(2, 1, true, "A delightful novel", "Personal Letters", nil, nil)
+		opinion, err := svc.CreateOpinion(
+			context.Background(), 2, 1, domain.SentimentPositive, "A delightful novel", "Personal Letters", nil, nil, 0,
+			domain.QuoteLanguages{},
+		)
 		require.NoError(t, err)
 		assert.Equal(t, uint64(2), opinion.WriterID())
 		assert.Equal(t, uint64(1), opinion.WorkID())
-		assert.True(t, opinion.Sentiment())
+		assert.Equal(t, domain.SentimentPositive, opinion.Sentiment())
 	})
 
 	t.Run("empty quote", func(t *testing.T) {
 		t.Parallel()
-		db, cleanup := testutils.SetupTestDB(t)
-		defer cleanup()
-
-		opinionRepo := gorm.NewOpinionRepository(db)
-		writerRepo := gorm.NewWriterRepository(db)
-		workRepo := gorm.NewWorkRepository(db)
-		svc := service.NewOpinionService(opinionRepo, writerRepo, workRepo)
-
-		_, err := svc.CreateOpinion(2, 1, true, "", "Source", nil, nil)
+		store := memory.NewStore()
+
+		opinionRepo := memory.NewOpinionRepository(store)
+		writerRepo := memory.NewWriterRepository(store)
+		workRepo := memory.NewWorkRepository(store)
+		svc := service.NewOpinionService(
+			opinionRepo, writerRepo, workRepo, memory.NewOpinionRevisionRepository(store), memory.NewSourceRepository(store),
+			memory.NewTransactor(store),
+		)
+
+		_, err := svc.CreateOpinion(
+			context.Background(), 2, 1, domain.SentimentPositive, "", "Source", nil, nil, 0, domain.QuoteLanguages{},
+		)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "quote is required")
 	})
 
 	t.Run("empty source", func(t *testing.T) {
 		t.Parallel()
-		db, cleanup := testutils.SetupTestDB(t)
-		defer cleanup()
-
-		opinionRepo := gorm.NewOpinionRepository(db)
-		writerRepo := gorm.NewWriterRepository(db)
-		workRepo := gorm.NewWorkRepository(db)
-		svc := service.NewOpinionService(opinionRepo, writerRepo, workRepo)
-
-		_, err := svc.CreateOpinion(2, 1, true, "Quote", "", nil, nil)
+		store := memory.NewStore()
+
+		opinionRepo := memory.NewOpinionRepository(store)
+		writerRepo := memory.NewWriterRepository(store)
+		workRepo := memory.NewWorkRepository(store)
+		svc := service.NewOpinionService(
+			opinionRepo, writerRepo, workRepo, memory.NewOpinionRevisionRepository(store), memory.NewSourceRepository(store),
+			memory.NewTransactor(store),
+		)
+
+		_, err := svc.CreateOpinion(
+			context.Background(), 2, 1, domain.SentimentPositive, "Quote", "", nil, nil, 0, domain.QuoteLanguages{},
+		)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "source is required")
 	})
 
 	t.Run("work not found", func(t *testing.T) {
 		t.Parallel()
-		db, cleanup := testutils.SetupTestDB(t)
-		defer cleanup()
-
-		opinionRepo := gorm.NewOpinionRepository(db)
-		writerRepo := gorm.NewWriterRepository(db)
-		workRepo := gorm.NewWorkRepository(db)
-		svc := service.NewOpinionService(opinionRepo, writerRepo, workRepo)
-
-		_, err := svc.CreateOpinion(2, 999, true, "Quote", "Source", nil, nil)
+		store := memory.NewStore()
+
+		opinionRepo := memory.NewOpinionRepository(store)
+		writerRepo := memory.NewWriterRepository(store)
+		workRepo := memory.NewWorkRepository(store)
+		svc := service.NewOpinionService(
+			opinionRepo, writerRepo, workRepo, memory.NewOpinionRevisionRepository(store), memory.NewSourceRepository(store),
+			memory.NewTransactor(store),
+		)
+
+		_, err := svc.CreateOpinion(
+			context.Background(), 2, 999, domain.SentimentPositive, "Quote", "Source", nil, nil, 0,
+			domain.QuoteLanguages{},
+		)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "work not found")
 	})
 
 	t.Run("writer cannot express opinion about own work", func(t *testing.T) {
 		t.Parallel()
-		db, cleanup := testutils.SetupTestDB(t)
-		defer cleanup()
+		store := memory.NewStore()
 
-		opinionRepo := gorm.NewOpinionRepository(db)
-		writerRepo := gorm.NewWriterRepository(db)
-		workRepo := gorm.NewWorkRepository(db)
-		svc := service.NewOpinionService(opinionRepo, writerRepo, workRepo)
+		opinionRepo := memory.NewOpinionRepository(store)
+		writerRepo := memory.NewWriterRepository(store)
+		workRepo := memory.NewWorkRepository(store)
+		svc := service.NewOpinionService(
+			opinionRepo, writerRepo, workRepo, memory.NewOpinionRevisionRepository(store), memory.NewSourceRepository(store),
+			memory.NewTransactor(store),
+		)
 
 		writer := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
 		require.NoError(t, writerRepo.Create(writer))
-		work := domain.NewWork(1, "Pride and Prejudice", 1)
+		work := domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})
 		require.NoError(t, workRepo.Create(work))
 
-		_, err := svc.CreateOpinion(1, 1, true, "Quote", "Source", nil, nil)
+		_, err := svc.CreateOpinion(
+			context.Background(), 1, 1, domain.SentimentPositive, "Quote", "Source", nil, nil, 0,
+			domain.QuoteLanguages{},
+		)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "writer cannot express opinion about their own work")
 	})
 
 	t.Run("writer not found", func(t *testing.T) {
 		t.Parallel()
-		db, cleanup := testutils.SetupTestDB(t)
-		defer cleanup()
+		store := memory.NewStore()
 
-		opinionRepo := gorm.NewOpinionRepository(db)
-		writerRepo := gorm.NewWriterRepository(db)
-		workRepo := gorm.NewWorkRepository(db)
-		svc := service.NewOpinionService(opinionRepo, writerRepo, workRepo)
+		opinionRepo := memory.NewOpinionRepository(store)
+		writerRepo := memory.NewWriterRepository(store)
+		workRepo := memory.NewWorkRepository(store)
+		svc := service.NewOpinionService(
+			opinionRepo, writerRepo, workRepo, memory.NewOpinionRevisionRepository(store), memory.NewSourceRepository(store),
+			memory.NewTransactor(store),
+		)
 
-		work := domain.NewWork(1, "Pride and Prejudice", 1)
+		work := domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})
 		require.NoError(t, workRepo.Create(work))
 
-		_, err := svc.CreateOpinion(999, 1, true, "Quote", "Source", nil, nil)
+		_, err := svc.CreateOpinion(
+			context.Background(), 999, 1, domain.SentimentPositive, "Quote", "Source", nil, nil, 0,
+			domain.QuoteLanguages{},
+		)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "writer not found")
 	})
 }
 
+func TestOpinionService_CreateWriterOpinion(t *testing.T) {
+	t.Parallel()
+	store := memory.NewStore()
+
+	opinionRepo := memory.NewOpinionRepository(store)
+	writerRepo := memory.NewWriterRepository(store)
+	workRepo := memory.NewWorkRepository(store)
+	svc := service.NewOpinionService(
+		opinionRepo, writerRepo, workRepo, memory.NewOpinionRevisionRepository(store), memory.NewSourceRepository(store),
+		memory.NewTransactor(store),
+	)
+
+	require.NoError(t, writerRepo.Create(domain.NewWriter(1, "Anton Chekhov", 1860, nil, nil)))
+	require.NoError(t, writerRepo.Create(domain.NewWriter(2, "Leo Tolstoy", 1828, nil, nil)))
+	require.NoError(t, workRepo.Create(domain.NewWork(1, "The Seagull", []uint64{1}, domain.WorkDetails{})))
+
+	ctx := context.Background()
+	opinion, err := svc.CreateWriterOpinion(
+		ctx, 2, 1, domain.SentimentVeryPositive, "Chekhov is a genius", "Diary", nil, nil, 0,
+		domain.QuoteLanguages{},
+	)
+	require.NoError(t, err)
+	assert.True(t, opinion.IsAboutWriter())
+	assert.Equal(t, uint64(1), opinion.TargetWriterID())
+
+	// Opinions about the writer and about their works are listed apart
+	_, err = svc.CreateOpinion(
+		ctx, 2, 1, domain.SentimentNegative, "Unbearable", "Letters", nil, nil, 0, domain.QuoteLanguages{},
+	)
+	require.NoError(t, err)
+	about, err := svc.GetOpinionsAboutWriter(1)
+	require.NoError(t, err)
+	require.Len(t, about, 1)
+	assert.Equal(t, opinion.ID(), about[0].ID())
+	pair, err := svc.GetOpinionsByWriterAboutWriter(2, 1)
+	require.NoError(t, err)
+	assert.Len(t, pair, 1)
+	byWriter, err := svc.GetOpinionsByWriter(2)
+	require.NoError(t, err)
+	assert.Len(t, byWriter, 2)
+
+	// Updates and restores keep the target
+	require.NoError(t, svc.UpdateOpinion(
+		ctx, opinion.ID(), domain.SentimentPositive, "Talented", "Diary", nil, nil, nil, nil, nil,
+	))
+	updated, err := svc.GetOpinion(opinion.ID())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), updated.TargetWriterID())
+	_, err = svc.RestoreRevision(ctx, opinion.ID(), 1)
+	require.NoError(t, err)
+	restored, err := svc.GetOpinion(opinion.ID())
+	require.NoError(t, err)
+	assert.Equal(t, "Chekhov is a genius", restored.Quote())
+	assert.Equal(t, uint64(1), restored.TargetWriterID())
+
+	_, err = svc.CreateWriterOpinion(
+		ctx, 1, 1, domain.SentimentPositive, "I am a genius", "Diary", nil, nil, 0, domain.QuoteLanguages{},
+	)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "writer cannot express opinion about themselves")
+
+	_, err = svc.CreateWriterOpinion(
+		ctx, 2, 999, domain.SentimentPositive, "Quote", "Diary", nil, nil, 0, domain.QuoteLanguages{},
+	)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "target writer not found")
+
+	_, err = svc.CreateWriterOpinion(
+		ctx, 999, 1, domain.SentimentPositive, "Quote", "Diary", nil, nil, 0, domain.QuoteLanguages{},
+	)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "writer not found")
+
+	_, err = svc.CreateWriterOpinion(
+		ctx, 2, 1, domain.SentimentPositive, "", "Diary", nil, nil, 0, domain.QuoteLanguages{},
+	)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "quote is required")
+}
+
 func TestOpinionService_GetOpinionsByWriter(t *testing.T) {
 	t.Parallel()
-	db, cleanup := testutils.SetupTestDB(t)
-	defer cleanup()
+	store := memory.NewStore()
 
-	opinionRepo := gorm.NewOpinionRepository(db)
-	writerRepo := gorm.NewWriterRepository(db)
-	workRepo := gorm.NewWorkRepository(db)
-	svc := service.NewOpinionService(opinionRepo, writerRepo, workRepo)
+	opinionRepo := memory.NewOpinionRepository(store)
+	writerRepo := memory.NewWriterRepository(store)
+	workRepo := memory.NewWorkRepository(store)
+	svc := service.NewOpinionService(
+		opinionRepo, writerRepo, workRepo, memory.NewOpinionRevisionRepository(store), memory.NewSourceRepository(store),
+		memory.NewTransactor(store),
+	)
 
 	writer1 := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
 	writer2 := domain.NewWriter(2, "Charlotte Bronte", 1816, nil, nil)
@@ -171,15 +318,15 @@ func TestOpinionService_GetOpinionsByWriter(t *testing.T) {
 	require.NoError(t, writerRepo.Create(writer2))
 	require.NoError(t, writerRepo.Create(writer3))
 
-	work1 := domain.NewWork(1, "Pride and Prejudice", 1)
-	work2 := domain.NewWork(2, "Jane Eyre", 2)
+	work1 := domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})
+	work2 := domain.NewWork(2, "Jane Eyre", []uint64{2}, domain.WorkDetails{})
 	require.NoError(t, workRepo.Create(work1))
 	require.NoError(t, workRepo.Create(work2))
 
 	// Writer 2 (Charlotte Bronte) expresses opinion about work 1 (Jane Austen's work)
 	// Writer 3 (Charles Dickens) expresses opinion about work 2 (Charlotte Bronte's work)
-	opinion1 := domain.NewOpinion(2, 1, true, "Quote 1", "Source 1", nil, nil)
-	opinion2 := domain.NewOpinion(3, 2, false, "Quote 2", "Source 2", nil, nil)
+	opinion1 := domain.NewOpinion(0, 2, 1, domain.SentimentPositive, "Quote 1", "Source 1", nil, nil)
+	opinion2 := domain.NewOpinion(0, 3, 2, domain.SentimentNegative, "Quote 2", "Source 2", nil, nil)
 	require.NoError(t, opinionRepo.Create(opinion1))
 	require.NoError(t, opinionRepo.Create(opinion2))
 
@@ -191,13 +338,15 @@ func TestOpinionService_GetOpinionsByWriter(t *testing.T) {
 
 func TestOpinionService_GetOpinionsByWork(t *testing.T) {
 	t.Parallel()
-	db, cleanup := testutils.SetupTestDB(t)
-	defer cleanup()
+	store := memory.NewStore()
 
-	opinionRepo := gorm.NewOpinionRepository(db)
-	writerRepo := gorm.NewWriterRepository(db)
-	workRepo := gorm.NewWorkRepository(db)
-	svc := service.NewOpinionService(opinionRepo, writerRepo, workRepo)
+	opinionRepo := memory.NewOpinionRepository(store)
+	writerRepo := memory.NewWriterRepository(store)
+	workRepo := memory.NewWorkRepository(store)
+	svc := service.NewOpinionService(
+		opinionRepo, writerRepo, workRepo, memory.NewOpinionRevisionRepository(store), memory.NewSourceRepository(store),
+		memory.NewTransactor(store),
+	)
 
 	writer1 := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
 	writer2 := domain.NewWriter(2, "Charlotte Bronte", 1816, nil, nil)
@@ -206,11 +355,11 @@ func TestOpinionService_GetOpinionsByWork(t *testing.T) {
 	require.NoError(t, writerRepo.Create(writer2))
 	require.NoError(t, writerRepo.Create(writer3))
 
-	work := domain.NewWork(1, "Pride and Prejudice", 1)
+	work := domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})
 	require.NoError(t, workRepo.Create(work))
 
-	opinion1 := domain.NewOpinion(2, 1, true, "Quote 1", "Source 1", nil, nil)
-	opinion2 := domain.NewOpinion(3, 1, false, "Quote 2", "Source 2", nil, nil)
+	opinion1 := domain.NewOpinion(0, 2, 1, domain.SentimentPositive, "Quote 1", "Source 1", nil, nil)
+	opinion2 := domain.NewOpinion(0, 3, 1, domain.SentimentNegative, "Quote 2", "Source 2", nil, nil)
 	require.NoError(t, opinionRepo.Create(opinion1))
 	require.NoError(t, opinionRepo.Create(opinion2))
 
@@ -221,123 +370,391 @@ func TestOpinionService_GetOpinionsByWork(t *testing.T) {
 
 func TestOpinionService_GetOpinion(t *testing.T) {
 	t.Parallel()
-	db, cleanup := testutils.SetupTestDB(t)
-	defer cleanup()
+	store := memory.NewStore()
 
-	opinionRepo := gorm.NewOpinionRepository(db)
-	writerRepo := gorm.NewWriterRepository(db)
-	workRepo := gorm.NewWorkRepository(db)
-	svc := service.NewOpinionService(opinionRepo, writerRepo, workRepo)
+	opinionRepo := memory.NewOpinionRepository(store)
+	writerRepo := memory.NewWriterRepository(store)
+	workRepo := memory.NewWorkRepository(store)
+	svc := service.NewOpinionService(
+		opinionRepo, writerRepo, workRepo, memory.NewOpinionRevisionRepository(store), memory.NewSourceRepository(store),
+		memory.NewTransactor(store),
+	)
 
 	writer1 := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
 	writer2 := domain.NewWriter(2, "Charlotte Bronte", 1816, nil, nil)
 	require.NoError(t, writerRepo.Create(writer1))
 	require.NoError(t, writerRepo.Create(writer2))
 
-	work := domain.NewWork(1, "Pride and Prejudice", 1)
+	work := domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})
 	require.NoError(t, workRepo.Create(work))
 
-	expectedOpinion := domain.NewOpinion(2, 1, true, "Quote", "Source", nil, nil)
+	expectedOpinion := domain.NewOpinion(0, 2, 1, domain.SentimentPositive, "Quote", "Source", nil, nil)
 	require.NoError(t, opinionRepo.Create(expectedOpinion))
 
-	opinion, err := svc.GetOpinion(2, 1)
+	opinion, err := svc.GetOpinion(expectedOpinion.ID())
 	require.NoError(t, err)
 	assert.Equal(t, expectedOpinion.WriterID(), opinion.WriterID())
 	assert.Equal(t, expectedOpinion.WorkID(), opinion.WorkID())
 }
 
+func TestOpinionService_GetOpinionsByWriterAndWork(t *testing.T) {
+	t.Parallel()
+	store := memory.NewStore()
+
+	opinionRepo := memory.NewOpinionRepository(store)
+	writerRepo := memory.NewWriterRepository(store)
+	workRepo := memory.NewWorkRepository(store)
+	svc := service.NewOpinionService(
+		opinionRepo, writerRepo, workRepo, memory.NewOpinionRevisionRepository(store), memory.NewSourceRepository(store),
+		memory.NewTransactor(store),
+	)
+
+	require.NoError(t, writerRepo.Create(domain.NewWriter(1, "Jane Austen", 1775, nil, nil)))
+	require.NoError(t, writerRepo.Create(domain.NewWriter(2, "Charlotte Bronte", 1816, nil, nil)))
+	require.NoError(t, workRepo.Create(domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})))
+
+	// A writer may revisit a work and change their mind
+	year1848, year1850 := 1848, 1850
+	ctx := context.Background()
+	_, err := svc.CreateOpinion(
+		ctx, 2, 1, domain.SentimentPositive, "Accurate", "Letters", nil, &year1848, 0, domain.QuoteLanguages{},
+	)
+	require.NoError(t, err)
+	_, err = svc.CreateOpinion(
+		ctx, 2, 1, domain.SentimentNegative, "A carefully fenced garden", "Letters", nil, &year1850, 0,
+		domain.QuoteLanguages{},
+	)
+	require.NoError(t, err)
+
+	opinions, err := svc.GetOpinionsByWriterAndWork(2, 1)
+	require.NoError(t, err)
+	require.Len(t, opinions, 2)
+	assert.Equal(t, domain.SentimentPositive, opinions[0].Sentiment())
+	assert.Equal(t, domain.SentimentNegative, opinions[1].Sentiment())
+
+	require.NoError(t, svc.DeleteOpinionsByWriterAndWork(ctx, 2, 1))
+	opinions, err = svc.GetOpinionsByWriterAndWork(2, 1)
+	require.NoError(t, err)
+	assert.Empty(t, opinions)
+
+	err = svc.DeleteOpinionsByWriterAndWork(ctx, 2, 1)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "opinion not found")
+}
+
 func TestOpinionService_UpdateOpinion(t *testing.T) {
 	t.Parallel()
 	t.Run("success", func(t *testing.T) {
 		t.Parallel()
-		db, cleanup := testutils.SetupTestDB(t)
-		defer cleanup()
+		store := memory.NewStore()
 
-		opinionRepo := gorm.NewOpinionRepository(db)
-		writerRepo := gorm.NewWriterRepository(db)
-		workRepo := gorm.NewWorkRepository(db)
-		svc := service.NewOpinionService(opinionRepo, writerRepo, workRepo)
+		opinionRepo := memory.NewOpinionRepository(store)
+		writerRepo := memory.NewWriterRepository(store)
+		workRepo := memory.NewWorkRepository(store)
+		svc := service.NewOpinionService(
+			opinionRepo, writerRepo, workRepo, memory.NewOpinionRevisionRepository(store), memory.NewSourceRepository(store),
+			memory.NewTransactor(store),
+		)
 
 		writer1 := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
 		writer2 := domain.NewWriter(2, "Charlotte Bronte", 1816, nil, nil)
 		require.NoError(t, writerRepo.Create(writer1))
 		require.NoError(t, writerRepo.Create(writer2))
 
-		work := domain.NewWork(1, "Pride and Prejudice", 1)
+		work := domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})
 		require.NoError(t, workRepo.Create(work))
 
-		opinion := domain.NewOpinion(2, 1, true, "Quote", "Source", nil, nil)
+		opinion := domain.NewOpinion(0, 2, 1, domain.SentimentPositive, "Quote", "Source", nil, nil)
 		require.NoError(t, opinionRepo.Create(opinion))
 
-		err := svc.UpdateOpinion(2, 1, false, "Updated quote", "Updated source", nil, nil)
+		err := svc.UpdateOpinion(
+			context.Background(), opinion.ID(), domain.SentimentNegative, "Updated quote", "Updated source", nil, nil, nil,
+			nil, nil,
+		)
 		require.NoError(t, err)
 
-		updated, err := opinionRepo.GetByWriterAndWork(2, 1)
+		updated, err := opinionRepo.GetByID(opinion.ID())
 		require.NoError(t, err)
-		assert.False(t, updated.Sentiment())
+		assert.Equal(t, domain.SentimentNegative, updated.Sentiment())
 		assert.Equal(t, "Updated quote", updated.Quote())
 	})
 
 	t.Run("empty quote", func(t *testing.T) {
 		t.Parallel()
-		db, cleanup := testutils.SetupTestDB(t)
-		defer cleanup()
+		store := memory.NewStore()
+
+		opinionRepo := memory.NewOpinionRepository(store)
+		writerRepo := memory.NewWriterRepository(store)
+		workRepo := memory.NewWorkRepository(store)
+		svc := service.NewOpinionService(
+			opinionRepo, writerRepo, workRepo, memory.NewOpinionRevisionRepository(store), memory.NewSourceRepository(store),
+			memory.NewTransactor(store),
+		)
 
-		opinionRepo := gorm.NewOpinionRepository(db)
-		writerRepo := gorm.NewWriterRepository(db)
-		workRepo := gorm.NewWorkRepository(db)
-		svc := service.NewOpinionService(opinionRepo, writerRepo, workRepo)
+		require.NoError(t, opinionRepo.Create(domain.NewOpinion(0, 2, 1, domain.SentimentPositive, "Quote", "Source", nil, nil)))
 
-		err := svc.UpdateOpinion(2, 1, true, "", "Source", nil, nil)
+		err := svc.UpdateOpinion(
+			context.Background(), 1, domain.SentimentPositive, "", "Source", nil, nil, nil, nil, nil,
+		)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "quote is required")
 	})
 
 	t.Run("writer cannot express opinion about own work", func(t *testing.T) {
 		t.Parallel()
-		db, cleanup := testutils.SetupTestDB(t)
-		defer cleanup()
-
-		opinionRepo := gorm.NewOpinionRepository(db)
-		writerRepo := gorm.NewWriterRepository(db)
-		workRepo := gorm.NewWorkRepository(db)
-		svc := service.NewOpinionService(opinionRepo, writerRepo, workRepo)
+		store := memory.NewStore()
+
+		opinionRepo := memory.NewOpinionRepository(store)
+		writerRepo := memory.NewWriterRepository(store)
+		workRepo := memory.NewWorkRepository(store)
+		svc := service.NewOpinionService(
+			opinionRepo, writerRepo, workRepo, memory.NewOpinionRevisionRepository(store), memory.NewSourceRepository(store),
+			memory.NewTransactor(store),
+		)
+
+		require.NoError(t, writerRepo.Create(domain.NewWriter(1, "Jane Austen", 1775, nil, nil)))
+		require.NoError(t, writerRepo.Create(domain.NewWriter(2, "Charlotte Bronte", 1816, nil, nil)))
+		require.NoError(t, workRepo.Create(domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})))
+		opinion := domain.NewOpinion(0, 2, 1, domain.SentimentPositive, "Quote", "Source", nil, nil)
+		require.NoError(t, opinionRepo.Create(opinion))
 
-		writer := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
-		require.NoError(t, writerRepo.Create(writer))
-		work := domain.NewWork(1, "Pride and Prejudice", 1)
-		require.NoError(t, workRepo.Create(work))
+		// The work has since been attributed to the writer who commented on it
+		require.NoError(t, workRepo.Update(domain.NewWork(1, "Pride and Prejudice", []uint64{2}, domain.WorkDetails{})))
 
-		err := svc.UpdateOpinion(1, 1, true, "Quote", "Source", nil, nil)
+		err := svc.UpdateOpinion(
+			context.Background(), opinion.ID(), domain.SentimentPositive, "Quote", "Source", nil, nil, nil,
+			nil, nil,
+		)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "writer cannot express opinion about their own work")
 	})
+
+	t.Run("source is unlinked only when asked", func(t *testing.T) {
+		t.Parallel()
+		store := memory.NewStore()
+
+		opinionRepo := memory.NewOpinionRepository(store)
+		writerRepo := memory.NewWriterRepository(store)
+		workRepo := memory.NewWorkRepository(store)
+		sourceRepo := memory.NewSourceRepository(store)
+		svc := service.NewOpinionService(
+			opinionRepo, writerRepo, workRepo, memory.NewOpinionRevisionRepository(store), sourceRepo,
+			memory.NewTransactor(store),
+		)
+
+		require.NoError(t, writerRepo.Create(domain.NewWriter(1, "Jane Austen", 1775, nil, nil)))
+		require.NoError(t, writerRepo.Create(domain.NewWriter(2, "Charlotte Bronte", 1816, nil, nil)))
+		require.NoError(t, workRepo.Create(domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})))
+		source := domain.NewSource(0, domain.SourceTypeLetter, "Letters to G. H. Lewes", domain.SourceDetails{}, true)
+		require.NoError(t, sourceRepo.Create(source))
+		opinion := domain.NewOpinion(0, 2, 1, domain.SentimentPositive, "Quote", "Letters", nil, nil)
+		opinion.SetSourceID(source.ID())
+		require.NoError(t, opinionRepo.Create(opinion))
+
+		ctx := context.Background()
+		err := svc.UpdateOpinion(ctx, opinion.ID(), domain.SentimentMixed, "Quote", "Letters", nil, nil, nil, nil, nil)
+		require.NoError(t, err)
+		updated, err := opinionRepo.GetByID(opinion.ID())
+		require.NoError(t, err)
+		assert.Equal(t, source.ID(), updated.SourceID())
+
+		unlinked := uint64(0)
+		err = svc.UpdateOpinion(
+			ctx, opinion.ID(), domain.SentimentMixed, "Quote", "Letters", nil, nil, &unlinked, nil, nil,
+		)
+		require.NoError(t, err)
+		updated, err = opinionRepo.GetByID(opinion.ID())
+		require.NoError(t, err)
+		assert.Zero(t, updated.SourceID())
+	})
 }
 
 func TestOpinionService_DeleteOpinion(t *testing.T) {
 	t.Parallel()
-	db, cleanup := testutils.SetupTestDB(t)
-	defer cleanup()
+	store := memory.NewStore()
 
-	opinionRepo := gorm.NewOpinionRepository(db)
-	writerRepo := gorm.NewWriterRepository(db)
-	workRepo := gorm.NewWorkRepository(db)
-	svc := service.NewOpinionService(opinionRepo, writerRepo, workRepo)
+	opinionRepo := memory.NewOpinionRepository(store)
+	writerRepo := memory.NewWriterRepository(store)
+	workRepo := memory.NewWorkRepository(store)
+	svc := service.NewOpinionService(
+		opinionRepo, writerRepo, workRepo, memory.NewOpinionRevisionRepository(store), memory.NewSourceRepository(store),
+		memory.NewTransactor(store),
+	)
 
 	writer1 := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
 	writer2 := domain.NewWriter(2, "Charlotte Bronte", 1816, nil, nil)
 	require.NoError(t, writerRepo.Create(writer1))
 	require.NoError(t, writerRepo.Create(writer2))
 
-	work := domain.NewWork(1, "Pride and Prejudice", 1)
+	work := domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})
 	require.NoError(t, workRepo.Create(work))
 
-	opinion := domain.NewOpinion(2, 1, true, "Quote", "Source", nil, nil)
+	opinion := domain.NewOpinion(0, 2, 1, domain.SentimentPositive, "Quote", "Source", nil, nil)
 	require.NoError(t, opinionRepo.Create(opinion))
 
-	err := svc.DeleteOpinion(2, 1)
+	err := svc.DeleteOpinion(context.Background(), opinion.ID())
+	require.NoError(t, err)
+
+	_, err = opinionRepo.GetByID(opinion.ID())
+	require.Error(t, err)
+}
+
+func TestOpinionService_Revisions(t *testing.T) {
+	t.Parallel()
+	store := memory.NewStore()
+
+	opinionRepo := memory.NewOpinionRepository(store)
+	writerRepo := memory.NewWriterRepository(store)
+	workRepo := memory.NewWorkRepository(store)
+	svc := service.NewOpinionService(
+		opinionRepo, writerRepo, workRepo, memory.NewOpinionRevisionRepository(store), memory.NewSourceRepository(store),
+		memory.NewTransactor(store),
+	)
+
+	require.NoError(t, writerRepo.Create(domain.NewWriter(1, "Jane Austen", 1775, nil, nil)))
+	require.NoError(t, writerRepo.Create(domain.NewWriter(2, "Charlotte Bronte", 1816, nil, nil)))
+	require.NoError(t, workRepo.Create(domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})))
+
+	ctx := service.WithActor(context.Background(), "alice")
+	opinion, err := svc.CreateOpinion(
+		ctx, 2, 1, domain.SentimentNegative, "Original quote", "Letters", nil, nil, 0, domain.QuoteLanguages{},
+	)
+	require.NoError(t, err)
+	id := opinion.ID()
+	year := 1850
+	err = svc.UpdateOpinion(
+		service.WithActor(context.Background(), "bob"), id, domain.SentimentNegative, "Typo", "Letters", nil, &year, nil,
+		nil, nil,
+	)
+	require.NoError(t, err)
+
+	revisions, err := svc.ListRevisions(id)
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, "alice", revisions[0].Actor())
+	assert.Equal(t, "bob", revisions[1].Actor())
+
+	changes, err := svc.DiffRevisions(id, 1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.FieldChange{
+		{Field: "quote", From: "Original quote", To: "Typo"},
+		{Field: "statement_year", From: (*int)(nil), To: &year},
+	}, changes)
+
+	_, err = svc.DiffRevisions(id, 1, 5)
+	require.Error(t, err)
+
+	// A deleted opinion can be brought back from its history
+	require.NoError(t, svc.DeleteOpinion(ctx, id))
+	restored, err := svc.RestoreRevision(ctx, id, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 3, restored.Number())
+
+	// The restored opinion keeps its ID
+	current, err := svc.GetOpinion(id)
+	require.NoError(t, err)
+	assert.Equal(t, "Original quote", current.Quote())
+	assert.Nil(t, current.StatementYear())
+
+	changes, err = svc.DiffRevisions(id, 1, 3)
 	require.NoError(t, err)
+	assert.Empty(t, changes)
 
-	_, err = opinionRepo.GetByWriterAndWork(2, 1)
+	_, err = svc.ListRevisions(id + 1)
 	require.Error(t, err)
 }
+
+func TestOpinionService_Translations(t *testing.T) {
+	t.Parallel()
+	store := memory.NewStore()
+
+	writerRepo := memory.NewWriterRepository(store)
+	workRepo := memory.NewWorkRepository(store)
+	svc := service.NewOpinionService(
+		memory.NewOpinionRepository(store), writerRepo, workRepo, memory.NewOpinionRevisionRepository(store),
+		memory.NewSourceRepository(store), memory.NewTransactor(store),
+	)
+
+	require.NoError(t, writerRepo.Create(domain.NewWriter(1, "Anton Chekhov", 1860, nil, nil)))
+	require.NoError(t, writerRepo.Create(domain.NewWriter(2, "Leo Tolstoy", 1828, nil, nil)))
+	require.NoError(t, workRepo.Create(domain.NewWork(1, "The Seagull", []uint64{1}, domain.WorkDetails{})))
+
+	ctx := context.Background()
+	translator := "Constance Garnett"
+	opinion, err := svc.CreateOpinion(
+		ctx, 2, 1, domain.SentimentNegative, "Скверно", "Diary", nil, nil, 0,
+		domain.QuoteLanguages{
+			Language: "RU",
+			Translations: []domain.Translation{
+				{Language: "en-gb", Text: "Nasty", Translator: &translator},
+				{Language: "fr", Text: "Mauvais"},
+			},
+		},
+	)
+	require.NoError(t, err)
+
+	// Tags are stored in canonical form
+	found, err := svc.GetOpinion(opinion.ID())
+	require.NoError(t, err)
+	assert.Equal(t, "ru", found.Languages().Language)
+	require.Len(t, found.Languages().Translations, 2)
+	assert.Equal(t, "en-GB", found.Languages().Translations[0].Language)
+	assert.Equal(t, &translator, found.Languages().Translations[0].Translator)
+
+	invalid := []struct {
+		name      string
+		languages domain.QuoteLanguages
+		err       string
+	}{
+		{
+			"translation without original language",
+			domain.QuoteLanguages{Translations: []domain.Translation{{Language: "en", Text: "Nasty"}}},
+			"language is required",
+		},
+		{"invalid language", domain.QuoteLanguages{Language: "not a language"}, "invalid language"},
+		{
+			"translation without text",
+			domain.QuoteLanguages{Language: "ru", Translations: []domain.Translation{{Language: "en"}}},
+			"has no text",
+		},
+		{
+			"translation into the original language",
+			domain.QuoteLanguages{Language: "ru", Translations: []domain.Translation{{Language: "ru", Text: "Скверно"}}},
+			"quote's own language",
+		},
+		{
+			"duplicate translation",
+			domain.QuoteLanguages{Language: "ru", Translations: []domain.Translation{
+				{Language: "en", Text: "Nasty"}, {Language: "EN", Text: "Vile"},
+			}},
+			"more than once",
+		},
+	}
+	for _, tc := range invalid {
+		err := svc.UpdateOpinion(
+			ctx, opinion.ID(), domain.SentimentNegative, "Скверно", "Diary", nil, nil, nil,
+			&tc.languages.Language, &tc.languages.Translations,
+		)
+		require.Error(t, err, tc.name)
+		assert.Contains(t, err.Error(), tc.err, tc.name)
+	}
+
+	// Translations are revised with the rest of the opinion, and the
+	// language of the quote kept when left out
+	translations := []domain.Translation{{Language: "de", Text: "Scheußlich"}}
+	err = svc.UpdateOpinion(
+		ctx, opinion.ID(), domain.SentimentNegative, "Скверно", "Diary", nil, nil, nil, nil, &translations,
+	)
+	require.NoError(t, err)
+
+	changes, err := svc.DiffRevisions(opinion.ID(), 1, 2)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, "translations", changes[0].Field)
+
+	_, err = svc.RestoreRevision(ctx, opinion.ID(), 1)
+	require.NoError(t, err)
+	found, err = svc.GetOpinion(opinion.ID())
+	require.NoError(t, err)
+	assert.Equal(t, opinion.Languages(), found.Languages())
+}
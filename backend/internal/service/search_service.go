@@ -0,0 +1,46 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/infrastructure/trigram"
+	"github.com/what-writers-like/backend/internal/repository"
+)
+
+// SearchService searches writers, works and opinions together, ranking the
+// hits against each other.
+type SearchService interface {
+	// Search returns the best hits first, with the words of the matched
+	// field that the query was found in. Empty types searches every kind of
+	// entity.
+	Search(query string, types []domain.SearchHitType, limit, offset int) ([]*domain.SearchHit, error)
+}
+
+type searchService struct {
+	searchRepo repository.SearchRepository
+}
+
+func NewSearchService(searchRepo repository.SearchRepository) SearchService {
+	return &searchService{searchRepo: searchRepo}
+}
+
+func (s *searchService) Search(
+	query string,
+	types []domain.SearchHitType,
+	limit, offset int,
+) ([]*domain.SearchHit, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, errors.New("query is required")
+	}
+	hits, err := s.searchRepo.Search(query, types, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	for _, hit := range hits {
+		hit.MatchStart, hit.MatchEnd = trigram.BestExtent(query, hit.Text)
+	}
+	return hits, nil
+}
@@ -0,0 +1,31 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/repository/memory"
+	"github.com/what-writers-like/backend/internal/service"
+)
+
+func TestSearchService_Search(t *testing.T) {
+	t.Parallel()
+	store := memory.NewStore()
+	svc := service.NewSearchService(memory.NewSearchRepository(store))
+
+	bio := "Novelist, admired by Tolstoy for his short stories"
+	require.NoError(t, memory.NewWriterRepository(store).Create(domain.NewWriter(1, "Anton Chekhov", 1860, nil, &bio)))
+
+	_, err := svc.Search("  ", nil, 10, 0)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "query is required")
+
+	// The words the query was found in are located within the field
+	hits, err := svc.Search(" short stories ", nil, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+	assert.Equal(t, "bio", hits[0].Field)
+	assert.Equal(t, "short stories", hits[0].Text[hits[0].MatchStart:hits[0].MatchEnd])
+}
@@ -0,0 +1,423 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/repository"
+)
+
+// SnapshotFormat names the bundles Backup writes in their manifest.
+const SnapshotFormat = "what-writers-like-snapshot"
+
+// SnapshotSchemaVersion is the layout of the bundles Backup writes: the
+// tables they hold and the fields of their rows. It goes up whenever
+// either changes, and Restore reads bundles of this version only.
+const SnapshotSchemaVersion = 1
+
+// ErrInvalidSnapshot is wrapped by the errors of a bundle that cannot be
+// read: one that is not a snapshot bundle, is of another schema version,
+// or does not match its manifest.
+var ErrInvalidSnapshot = errors.New("invalid snapshot")
+
+const snapshotManifestFile = "manifest.json"
+
+// Snapshot tables, in the order they are written and restored, each after
+// the tables its rows refer to.
+const (
+	snapshotSources       = "sources"
+	snapshotWriters       = "writers"
+	snapshotWriterAliases = "writer_aliases"
+	snapshotWorks         = "works"
+	snapshotOpinions      = "opinions"
+)
+
+var snapshotTables = []string{
+	snapshotSources, snapshotWriters, snapshotWriterAliases, snapshotWorks, snapshotOpinions,
+}
+
+// SnapshotManifest describes a snapshot bundle: a zip archive of this
+// manifest, as manifest.json, and a file of newline-delimited JSON for
+// each table, with a row as the API shows it on each line.
+type SnapshotManifest struct {
+	Format        string          `json:"format"`
+	SchemaVersion int             `json:"schema_version"`
+	CreatedAt     time.Time       `json:"created_at"`
+	Tables        []SnapshotTable `json:"tables"`
+}
+
+// SnapshotTable is the file of one table, with its number of rows and the
+// SHA-256 checksum of its contents in hex.
+type SnapshotTable struct {
+	Name   string `json:"name"`
+	File   string `json:"file"`
+	Rows   int    `json:"rows"`
+	SHA256 string `json:"sha256"`
+}
+
+// snapshotData is the rows of every table, from a bundle or from storage.
+type snapshotData struct {
+	sources  []*domain.Source
+	writers  []*domain.Writer
+	aliases  []*domain.WriterAlias
+	works    []*domain.Work
+	opinions []*domain.Opinion
+}
+
+// loadSnapshotData reads every row in storage.
+func loadSnapshotData(repos *repository.Repositories) (*snapshotData, error) {
+	data := &snapshotData{}
+	for _, forEach := range []func() error{
+		func() error { return repos.Sources.ForEach(collect(&data.sources)) },
+		func() error { return repos.Writers.ForEach(collect(&data.writers)) },
+		func() error { return repos.WriterAliases.ForEach(collect(&data.aliases)) },
+		func() error { return repos.Works.ForEach(collect(&data.works)) },
+		func() error { return repos.Opinions.ForEach(collect(&data.opinions)) },
+	} {
+		if err := forEach(); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+func collect[T any](rows *[]*T) func(*T) error {
+	return func(row *T) error {
+		*rows = append(*rows, row)
+		return nil
+	}
+}
+
+// writeSnapshotBundle writes data as a bundle, the manifest first so that
+// it can be read without unpacking the rest.
+func writeSnapshotBundle(w io.Writer, data *snapshotData, createdAt time.Time) (*SnapshotManifest, error) {
+	files := map[string][]map[string]any{
+		snapshotSources:       snapshotRows(data.sources, sourceSnapshot),
+		snapshotWriters:       snapshotRows(data.writers, writerSnapshot),
+		snapshotWriterAliases: snapshotRows(data.aliases, writerAliasSnapshot),
+		snapshotWorks:         snapshotRows(data.works, workSnapshot),
+		snapshotOpinions:      snapshotRows(data.opinions, opinionSnapshot),
+	}
+	manifest := &SnapshotManifest{Format: SnapshotFormat, SchemaVersion: SnapshotSchemaVersion, CreatedAt: createdAt}
+	contents := make([][]byte, len(snapshotTables))
+	for i, name := range snapshotTables {
+		var buf bytes.Buffer
+		encoder := json.NewEncoder(&buf)
+		for _, row := range files[name] {
+			if err := encoder.Encode(row); err != nil {
+				return nil, err
+			}
+		}
+		contents[i] = buf.Bytes()
+		sum := sha256.Sum256(contents[i])
+		manifest.Tables = append(manifest.Tables, SnapshotTable{
+			Name:   name,
+			File:   name + ".ndjson",
+			Rows:   len(files[name]),
+			SHA256: hex.EncodeToString(sum[:]),
+		})
+	}
+
+	archive := zip.NewWriter(w)
+	file, err := archive.Create(snapshotManifestFile)
+	if err != nil {
+		return nil, err
+	}
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(manifest); err != nil {
+		return nil, err
+	}
+	for i, table := range manifest.Tables {
+		file, err := archive.Create(table.File)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := file.Write(contents[i]); err != nil {
+			return nil, err
+		}
+	}
+	return manifest, archive.Close()
+}
+
+func snapshotRows[T any](rows []*T, snapshot func(*T) map[string]any) []map[string]any {
+	result := make([]map[string]any, len(rows))
+	for i, row := range rows {
+		result[i] = snapshot(row)
+	}
+	return result
+}
+
+// readSnapshotBundle reads a bundle and checks it against its manifest.
+// Rows that cannot be made into records, such as an opinion with no
+// target, are reported as errors rather than read.
+func readSnapshotBundle(bundle io.ReaderAt, size int64) (*SnapshotManifest, *snapshotData, []RestoreError, error) {
+	archive, err := zip.NewReader(bundle, size)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("%w: not a zip archive", ErrInvalidSnapshot)
+	}
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+
+	var manifest SnapshotManifest
+	if err := readBundleFile(files, snapshotManifestFile, func(contents []byte) error {
+		return json.Unmarshal(contents, &manifest)
+	}); err != nil {
+		return nil, nil, nil, err
+	}
+	if manifest.Format != SnapshotFormat {
+		return nil, nil, nil, fmt.Errorf("%w: format is %q, expected %q",
+			ErrInvalidSnapshot, manifest.Format, SnapshotFormat)
+	}
+	if manifest.SchemaVersion != SnapshotSchemaVersion {
+		return nil, nil, nil, fmt.Errorf("%w: schema version %d, expected %d",
+			ErrInvalidSnapshot, manifest.SchemaVersion, SnapshotSchemaVersion)
+	}
+
+	data := &snapshotData{}
+	var rowErrors []RestoreError
+	for _, name := range snapshotTables {
+		table, ok := manifest.table(name)
+		if !ok {
+			return nil, nil, nil, fmt.Errorf("%w: manifest lists no %s table", ErrInvalidSnapshot, name)
+		}
+		err := readBundleFile(files, table.File, func(contents []byte) error {
+			sum := sha256.Sum256(contents)
+			if hex.EncodeToString(sum[:]) != table.SHA256 {
+				return errors.New("checksum does not match the manifest")
+			}
+			rows, errs, err := decodeSnapshotTable(name, contents, data)
+			if err != nil {
+				return err
+			}
+			if rows != table.Rows {
+				return fmt.Errorf("%d rows, the manifest says %d", rows, table.Rows)
+			}
+			rowErrors = append(rowErrors, errs...)
+			return nil
+		})
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	return &manifest, data, rowErrors, nil
+}
+
+func (m *SnapshotManifest) table(name string) (SnapshotTable, bool) {
+	for _, t := range m.Tables {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return SnapshotTable{}, false
+}
+
+// readBundleFile hands the contents of a file of the bundle to read, and
+// wraps what goes wrong as ErrInvalidSnapshot.
+func readBundleFile(files map[string]*zip.File, name string, read func(contents []byte) error) error {
+	file, ok := files[name]
+	if !ok {
+		return fmt.Errorf("%w: missing %s", ErrInvalidSnapshot, name)
+	}
+	reader, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrInvalidSnapshot, name, err)
+	}
+	defer reader.Close()
+	contents, err := io.ReadAll(reader)
+	if err == nil {
+		err = read(contents)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrInvalidSnapshot, name, err)
+	}
+	return nil
+}
+
+// decodeSnapshotTable adds the rows of a table to data and counts them.
+func decodeSnapshotTable(name string, contents []byte, data *snapshotData) (int, []RestoreError, error) {
+	switch name {
+	case snapshotSources:
+		return decodeRows(contents, &data.sources, sourceRow.toDomain)
+	case snapshotWriters:
+		return decodeRows(contents, &data.writers, writerRow.toDomain)
+	case snapshotWriterAliases:
+		return decodeRows(contents, &data.aliases, writerAliasRow.toDomain)
+	case snapshotWorks:
+		return decodeRows(contents, &data.works, workRow.toDomain)
+	default:
+		return decodeRows(contents, &data.opinions, opinionRow.toDomain)
+	}
+}
+
+// decodeRows reads rows of type R and adds them to rows as records. A row
+// that is no record, such as an opinion with no target, is reported and
+// left out.
+func decodeRows[R any, T any](
+	contents []byte,
+	rows *[]*T,
+	toDomain func(R) (*T, *RestoreError),
+) (int, []RestoreError, error) {
+	decoder := json.NewDecoder(bytes.NewReader(contents))
+	count := 0
+	var errs []RestoreError
+	for {
+		var row R
+		err := decoder.Decode(&row)
+		if errors.Is(err, io.EOF) {
+			return count, errs, nil
+		}
+		if err != nil {
+			return 0, nil, fmt.Errorf("row %d: %w", count+1, err)
+		}
+		count++
+		record, rowErr := toDomain(row)
+		if rowErr != nil {
+			errs = append(errs, *rowErr)
+			continue
+		}
+		*rows = append(*rows, record)
+	}
+}
+
+// The rows of a bundle have the fields of the audit snapshots that write
+// them.
+
+type sourceRow struct {
+	ID              uint64             `json:"id"`
+	Type            *domain.SourceType `json:"type"`
+	Title           string             `json:"title"`
+	Author          *string            `json:"author"`
+	Publisher       *string            `json:"publisher"`
+	Year            *int               `json:"year"`
+	ISBN            *string            `json:"isbn"`
+	DOI             *string            `json:"doi"`
+	URL             *string            `json:"url"`
+	ArchiveLocation *string            `json:"archive_location"`
+	Confirmed       bool               `json:"confirmed"`
+}
+
+func (r sourceRow) toDomain() (*domain.Source, *RestoreError) {
+	var sourceType domain.SourceType
+	if r.Type != nil {
+		sourceType = *r.Type
+	}
+	return domain.NewSource(r.ID, sourceType, r.Title, domain.SourceDetails{
+		Author:          r.Author,
+		Publisher:       r.Publisher,
+		Year:            r.Year,
+		ISBN:            r.ISBN,
+		DOI:             r.DOI,
+		URL:             r.URL,
+		ArchiveLocation: r.ArchiveLocation,
+	}, r.Confirmed), nil
+}
+
+type writerRow struct {
+	ID        uint64  `json:"id"`
+	Name      string  `json:"name"`
+	BirthYear int     `json:"birth_year"`
+	DeathYear *int    `json:"death_year"`
+	Bio       *string `json:"bio"`
+}
+
+func (r writerRow) toDomain() (*domain.Writer, *RestoreError) {
+	return domain.NewWriter(r.ID, r.Name, r.BirthYear, r.DeathYear, r.Bio), nil
+}
+
+type writerAliasRow struct {
+	ID       uint64           `json:"id"`
+	WriterID uint64           `json:"writer_id"`
+	Name     string           `json:"name"`
+	Kind     domain.AliasKind `json:"kind"`
+}
+
+func (r writerAliasRow) toDomain() (*domain.WriterAlias, *RestoreError) {
+	return domain.NewWriterAlias(r.ID, r.WriterID, r.Name, r.Kind), nil
+}
+
+type workRow struct {
+	ID               uint64   `json:"id"`
+	Title            string   `json:"title"`
+	AuthorIDs        []uint64 `json:"author_ids"`
+	PublicationYear  *int     `json:"publication_year"`
+	Genre            *string  `json:"genre"`
+	OriginalLanguage *string  `json:"original_language"`
+	OriginalTitle    *string  `json:"original_title"`
+}
+
+func (r workRow) toDomain() (*domain.Work, *RestoreError) {
+	return domain.NewWork(r.ID, r.Title, r.AuthorIDs, domain.WorkDetails{
+		PublicationYear:  r.PublicationYear,
+		Genre:            r.Genre,
+		OriginalLanguage: r.OriginalLanguage,
+		OriginalTitle:    r.OriginalTitle,
+	}), nil
+}
+
+type opinionRow struct {
+	ID             uint64           `json:"id"`
+	WriterID       uint64           `json:"writer_id"`
+	WorkID         *uint64          `json:"work_id"`
+	TargetWriterID *uint64          `json:"target_writer_id"`
+	SentimentGrade domain.Sentiment `json:"sentiment_grade"`
+	Quote          string           `json:"quote"`
+	Source         string           `json:"source"`
+	SourceID       *uint64          `json:"source_id"`
+	Page           *string          `json:"page"`
+	StatementYear  *int             `json:"statement_year"`
+	Language       *string          `json:"language"`
+	Translations   []struct {
+		Language   string  `json:"language"`
+		Text       string  `json:"text"`
+		Translator *string `json:"translator"`
+		Source     *string `json:"source"`
+	} `json:"translations"`
+}
+
+func (r opinionRow) toDomain() (*domain.Opinion, *RestoreError) {
+	var opinion *domain.Opinion
+	switch {
+	case r.WorkID != nil && r.TargetWriterID == nil:
+		opinion = domain.NewOpinion(
+			r.ID, r.WriterID, *r.WorkID, r.SentimentGrade, r.Quote, r.Source, r.Page, r.StatementYear,
+		)
+	case r.WorkID == nil && r.TargetWriterID != nil:
+		opinion = domain.NewWriterOpinion(
+			r.ID, r.WriterID, *r.TargetWriterID, r.SentimentGrade, r.Quote, r.Source, r.Page, r.StatementYear,
+		)
+	default:
+		return nil, &RestoreError{
+			Table:   snapshotOpinions,
+			ID:      r.ID,
+			Message: "exactly one of work_id and target_writer_id is required",
+		}
+	}
+	if r.SourceID != nil {
+		opinion.SetSourceID(*r.SourceID)
+	}
+	languages := domain.QuoteLanguages{}
+	if r.Language != nil {
+		languages.Language = *r.Language
+	}
+	for _, t := range r.Translations {
+		languages.Translations = append(languages.Translations, domain.Translation{
+			Language:   t.Language,
+			Text:       t.Text,
+			Translator: t.Translator,
+			Source:     t.Source,
+		})
+	}
+	opinion.SetLanguages(languages)
+	return opinion, nil
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/repository"
+)
+
+// SourceService catalogues the sources opinions are quoted from. Sources
+// created or updated through it are confirmed, so updating a candidate is
+// how a curator accepts it.
+type SourceService interface {
+	CreateSource(
+		ctx context.Context,
+		sourceType domain.SourceType,
+		title string,
+		details domain.SourceDetails,
+	) (*domain.Source, error)
+	GetSource(id uint64) (*domain.Source, error)
+	ListSources(limit, offset int) ([]*domain.Source, error)
+	ListCandidates(limit, offset int) ([]*domain.Source, error)
+	UpdateSource(
+		ctx context.Context,
+		id uint64,
+		sourceType domain.SourceType,
+		title string,
+		details domain.SourceDetails,
+	) error
+	// ConfirmSource accepts a candidate as it stands, apart from the type
+	// the curator assigns it.
+	ConfirmSource(ctx context.Context, id uint64, sourceType domain.SourceType) (*domain.Source, error)
+	DeleteSource(ctx context.Context, id uint64) error
+}
+
+type sourceService struct {
+	sourceRepo  repository.SourceRepository
+	opinionRepo repository.OpinionRepository
+	transactor  repository.Transactor
+}
+
+func NewSourceService(
+	sourceRepo repository.SourceRepository,
+	opinionRepo repository.OpinionRepository,
+	transactor repository.Transactor,
+) SourceService {
+	return &sourceService{
+		sourceRepo:  sourceRepo,
+		opinionRepo: opinionRepo,
+		transactor:  transactor,
+	}
+}
+
+func (s *sourceService) CreateSource(
+	ctx context.Context,
+	sourceType domain.SourceType,
+	title string,
+	details domain.SourceDetails,
+) (*domain.Source, error) {
+	if err := validateSource(sourceType, title); err != nil {
+		return nil, err
+	}
+
+	source := domain.NewSource(0, sourceType, title, details, true)
+	err := s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		if err := repos.Sources.Create(source); err != nil {
+			return err
+		}
+		return recordChange(
+			ctx, repos.Audit, domain.AuditEntitySource, entityID(source.ID()),
+			domain.AuditActionCreate, nil, sourceSnapshot(source),
+		)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return source, nil
+}
+
+func (s *sourceService) GetSource(id uint64) (*domain.Source, error) {
+	return s.sourceRepo.GetByID(id)
+}
+
+func (s *sourceService) ListSources(limit, offset int) ([]*domain.Source, error) {
+	return s.sourceRepo.List(limit, offset)
+}
+
+func (s *sourceService) ListCandidates(limit, offset int) ([]*domain.Source, error) {
+	return s.sourceRepo.ListCandidates(limit, offset)
+}
+
+func (s *sourceService) UpdateSource(
+	ctx context.Context,
+	id uint64,
+	sourceType domain.SourceType,
+	title string,
+	details domain.SourceDetails,
+) error {
+	if err := validateSource(sourceType, title); err != nil {
+		return err
+	}
+
+	before, err := s.sourceRepo.GetByID(id)
+	if err != nil {
+		return errors.New("source not found")
+	}
+
+	source := domain.NewSource(id, sourceType, title, details, true)
+	return s.update(ctx, before, source)
+}
+
+func (s *sourceService) ConfirmSource(
+	ctx context.Context,
+	id uint64,
+	sourceType domain.SourceType,
+) (*domain.Source, error) {
+	before, err := s.sourceRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("source not found")
+	}
+	if err := validateSource(sourceType, before.Title()); err != nil {
+		return nil, err
+	}
+
+	source := domain.NewSource(id, sourceType, before.Title(), before.Details(), true)
+	if err := s.update(ctx, before, source); err != nil {
+		return nil, err
+	}
+	return source, nil
+}
+
+func (s *sourceService) update(ctx context.Context, before, source *domain.Source) error {
+	return s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		if err := repos.Sources.Update(source); err != nil {
+			return err
+		}
+		return recordChange(
+			ctx, repos.Audit, domain.AuditEntitySource, entityID(source.ID()),
+			domain.AuditActionUpdate, sourceSnapshot(before), sourceSnapshot(source),
+		)
+	})
+}
+
+// DeleteSource refuses to delete a source that opinions still cite, since
+// they would be left pointing at nothing.
+func (s *sourceService) DeleteSource(ctx context.Context, id uint64) error {
+	before, err := s.sourceRepo.GetByID(id)
+	if err != nil {
+		return errors.New("source not found")
+	}
+	cited, err := s.opinionRepo.Find(repository.OpinionFilter{SourceIDs: []uint64{id}})
+	if err != nil {
+		return err
+	}
+	if len(cited) > 0 {
+		return errors.New("cannot delete source cited by opinions")
+	}
+
+	return s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		if err := repos.Sources.Delete(id); err != nil {
+			return err
+		}
+		return recordChange(
+			ctx, repos.Audit, domain.AuditEntitySource, entityID(id),
+			domain.AuditActionDelete, sourceSnapshot(before), nil,
+		)
+	})
+}
+
+func validateSource(sourceType domain.SourceType, title string) error {
+	if !sourceType.IsValid() {
+		return errors.New("invalid source type")
+	}
+	if title == "" {
+		return errors.New("title is required")
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/repository/memory"
+	"github.com/what-writers-like/backend/internal/service"
+)
+
+func TestSourceService_CreateSource(t *testing.T) {
+	t.Parallel()
+	store := memory.NewStore()
+	svc := service.NewSourceService(
+		memory.NewSourceRepository(store), memory.NewOpinionRepository(store), memory.NewTransactor(store),
+	)
+	ctx := context.Background()
+
+	editor := "R. W. Chapman"
+	source, err := svc.CreateSource(ctx, domain.SourceTypeLetter, "Jane Austen's Letters", domain.SourceDetails{
+		Author: &editor,
+	})
+	require.NoError(t, err)
+	assert.NotZero(t, source.ID())
+	assert.True(t, source.Confirmed())
+
+	_, err = svc.CreateSource(ctx, "memoir", "Memoirs", domain.SourceDetails{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid source type")
+
+	_, err = svc.CreateSource(ctx, domain.SourceTypeDiary, "", domain.SourceDetails{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "title is required")
+}
+
+func TestSourceService_ConfirmSource(t *testing.T) {
+	t.Parallel()
+	store := memory.NewStore()
+	sourceRepo := memory.NewSourceRepository(store)
+	svc := service.NewSourceService(sourceRepo, memory.NewOpinionRepository(store), memory.NewTransactor(store))
+	ctx := context.Background()
+
+	year := 1932
+	candidate := domain.NewSource(0, "", "Letters", domain.SourceDetails{Year: &year}, false)
+	require.NoError(t, sourceRepo.Create(candidate))
+
+	confirmed, err := svc.ConfirmSource(ctx, candidate.ID(), domain.SourceTypeLetter)
+	require.NoError(t, err)
+	assert.True(t, confirmed.Confirmed())
+	assert.Equal(t, domain.SourceTypeLetter, confirmed.Type())
+	assert.Equal(t, &year, confirmed.Details().Year)
+
+	candidates, err := svc.ListCandidates(10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, candidates)
+
+	_, err = svc.ConfirmSource(ctx, 999, domain.SourceTypeLetter)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "source not found")
+}
+
+func TestSourceService_DeleteSource(t *testing.T) {
+	t.Parallel()
+	store := memory.NewStore()
+	sourceRepo := memory.NewSourceRepository(store)
+	opinionRepo := memory.NewOpinionRepository(store)
+	writerRepo := memory.NewWriterRepository(store)
+	workRepo := memory.NewWorkRepository(store)
+	transactor := memory.NewTransactor(store)
+	svc := service.NewSourceService(sourceRepo, opinionRepo, transactor)
+	opinionSvc := service.NewOpinionService(
+		opinionRepo, writerRepo, workRepo, memory.NewOpinionRevisionRepository(store), sourceRepo, transactor,
+	)
+	ctx := context.Background()
+
+	require.NoError(t, writerRepo.Create(domain.NewWriter(1, "Jane Austen", 1775, nil, nil)))
+	require.NoError(t, writerRepo.Create(domain.NewWriter(2, "Charlotte Bronte", 1816, nil, nil)))
+	require.NoError(t, workRepo.Create(domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})))
+
+	source, err := svc.CreateSource(ctx, domain.SourceTypeLetter, "Letters to W. S. Williams", domain.SourceDetails{})
+	require.NoError(t, err)
+	unused, err := svc.CreateSource(ctx, domain.SourceTypeDiary, "Diary", domain.SourceDetails{})
+	require.NoError(t, err)
+
+	// The citation falls back to the source's title
+	opinion, err := opinionSvc.CreateOpinion(
+		ctx, 2, 1, domain.SentimentNegative, "Quote", "", nil, nil, source.ID(), domain.QuoteLanguages{},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "Letters to W. S. Williams", opinion.Source())
+	assert.Equal(t, source.ID(), opinion.SourceID())
+
+	err = svc.DeleteSource(ctx, source.ID())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cited by opinions")
+	require.NoError(t, svc.DeleteSource(ctx, unused.ID()))
+}
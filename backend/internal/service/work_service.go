@@ -1,59 +1,98 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"fmt"
 
 	"github.com/what-writers-like/backend/internal/domain"
 	"github.com/what-writers-like/backend/internal/repository"
 )
 
 type WorkService interface {
-	CreateWork(title string, authorID uint64) (*domain.Work, error)
+	CreateWork(ctx context.Context, title string, authorIDs []uint64, details domain.WorkDetails) (*domain.Work, error)
 	GetWork(id uint64) (*domain.Work, error)
 	GetWorksByAuthor(authorID uint64) ([]*domain.Work, error)
-	ListWorks(limit, offset int) ([]*domain.Work, error)
-	SearchWorks(query string, limit, offset int) ([]*domain.Work, error)
-	UpdateWork(id uint64, title string, authorID uint64) error
-	DeleteWork(id uint64) error
+	// ListWorks returns a page of works after the one marked by after, or
+	// the first page when it is nil.
+	ListWorks(
+		filter repository.WorkFilter,
+		after *repository.WorkCursor,
+		limit int,
+	) (*repository.Page[*domain.Work, repository.WorkCursor], error)
+	// SearchWorks matches the works accepted by filter by title, a page at a
+	// time from offset.
+	SearchWorks(
+		query string,
+		filter repository.WorkFilter,
+		limit, offset int,
+	) (*repository.Page[*domain.Work, int], error)
+	// UpdateWork retitles a work and credits it to authorIDs. No one who
+	// has expressed an opinion about the work may become its author.
+	UpdateWork(ctx context.Context, id uint64, title string, authorIDs []uint64, details WorkDetailsUpdate) error
+	DeleteWork(ctx context.Context, id uint64) error
+}
+
+// WorkDetailsUpdate changes the details of a work a field at a time: a nil
+// field keeps the stored value, and one pointing to nil clears it.
+type WorkDetailsUpdate struct {
+	PublicationYear  **int
+	Genre            **string
+	OriginalLanguage **string
+	OriginalTitle    **string
+}
+
+func (u WorkDetailsUpdate) apply(details domain.WorkDetails) domain.WorkDetails {
+	if u.PublicationYear != nil {
+		details.PublicationYear = *u.PublicationYear
+	}
+	if u.Genre != nil {
+		details.Genre = *u.Genre
+	}
+	if u.OriginalLanguage != nil {
+		details.OriginalLanguage = *u.OriginalLanguage
+	}
+	if u.OriginalTitle != nil {
+		details.OriginalTitle = *u.OriginalTitle
+	}
+	return details
 }
 
 type workService struct {
 	workRepo   repository.WorkRepository
 	writerRepo repository.WriterRepository
+	transactor repository.Transactor
 }
 
-func NewWorkService(workRepo repository.WorkRepository, writerRepo repository.WriterRepository) WorkService {
+func NewWorkService(
+	workRepo repository.WorkRepository,
+	writerRepo repository.WriterRepository,
+	transactor repository.Transactor,
+) WorkService {
 	return &workService{
 		workRepo:   workRepo,
 		writerRepo: writerRepo,
+		transactor: transactor,
 	}
 }
 
-func (s *workService) CreateWork(title string, authorID uint64) (*domain.Work, error) {
-	if title == "" {
-		return nil, errors.New("title is required")
+// CreateWork credits the work to authorIDs in order; an anonymous work has
+// none.
+func (s *workService) CreateWork(
+	ctx context.Context, title string, authorIDs []uint64, details domain.WorkDetails,
+) (*domain.Work, error) {
+	if err := validateWork(title); err != nil {
+		return nil, err
 	}
-
-	_, err := s.writerRepo.GetByID(authorID)
-	if err != nil {
-		return nil, errors.New("author not found")
-	}
-
-	id := uint64(1)
-	works, _ := s.workRepo.List(1, 0)
-	if len(works) > 0 {
-		maxID := uint64(0)
-		allWorks, _ := s.workRepo.List(1000, 0)
-		for _, w := range allWorks {
-			if w.ID() > maxID {
-				maxID = w.ID()
-			}
-		}
-		id = maxID + 1
+	if err := checkAuthors(s.writerRepo, authorIDs); err != nil {
+		return nil, err
 	}
 
-	work := domain.NewWork(id, title, authorID)
-	if err := s.workRepo.Create(work); err != nil {
+	work := domain.NewWork(0, title, authorIDs, details)
+	err := s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		return createWork(ctx, repos, work)
+	})
+	if err != nil {
 		return nil, err
 	}
 	return work, nil
@@ -67,42 +106,122 @@ func (s *workService) GetWorksByAuthor(authorID uint64) ([]*domain.Work, error)
 	return s.workRepo.GetByAuthorID(authorID)
 }
 
-func (s *workService) ListWorks(limit, offset int) ([]*domain.Work, error) {
-	return s.workRepo.List(limit, offset)
+func (s *workService) ListWorks(
+	filter repository.WorkFilter,
+	after *repository.WorkCursor,
+	limit int,
+) (*repository.Page[*domain.Work, repository.WorkCursor], error) {
+	return s.workRepo.List(filter, after, limit)
 }
 
-func (s *workService) SearchWorks(query string, limit, offset int) ([]*domain.Work, error) {
+func (s *workService) SearchWorks(
+	query string,
+	filter repository.WorkFilter,
+	limit, offset int,
+) (*repository.Page[*domain.Work, int], error) {
 	if query == "" {
-		return s.workRepo.List(limit, offset)
+		return nil, errors.New("query is required")
 	}
-	return s.workRepo.Search(query, limit, offset)
+	return s.workRepo.Search(query, filter, limit, offset)
 }
 
-func (s *workService) UpdateWork(id uint64, title string, authorID uint64) error {
-	if title == "" {
-		return errors.New("title is required")
+func (s *workService) UpdateWork(
+	ctx context.Context, id uint64, title string, authorIDs []uint64, details WorkDetailsUpdate,
+) error {
+	if err := validateWork(title); err != nil {
+		return err
 	}
 
 	// Check if work exists
-	_, err := s.workRepo.GetByID(id)
+	before, err := s.workRepo.GetByID(id)
 	if err != nil {
 		return errors.New("work not found")
 	}
 
-	_, err = s.writerRepo.GetByID(authorID)
-	if err != nil {
-		return errors.New("author not found")
+	if err := checkAuthors(s.writerRepo, authorIDs); err != nil {
+		return err
 	}
 
-	work := domain.NewWork(id, title, authorID)
-	return s.workRepo.Update(work)
+	work := domain.NewWork(id, title, authorIDs, details.apply(before.Details()))
+	return s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		return updateWork(ctx, repos, before, work)
+	})
 }
 
-func (s *workService) DeleteWork(id uint64) error {
+func (s *workService) DeleteWork(ctx context.Context, id uint64) error {
 	// Check if work exists
-	_, err := s.workRepo.GetByID(id)
+	before, err := s.workRepo.GetByID(id)
 	if err != nil {
 		return errors.New("work not found")
 	}
-	return s.workRepo.Delete(id)
+	return s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		if err := repos.Works.Delete(id); err != nil {
+			return err
+		}
+		return recordChange(
+			ctx, repos.Audit, domain.AuditEntityWork, entityID(id),
+			domain.AuditActionDelete, workSnapshot(before), nil,
+		)
+	})
+}
+
+func validateWork(title string) error {
+	if title == "" {
+		return errors.New("title is required")
+	}
+	return nil
+}
+
+func createWork(ctx context.Context, repos *repository.Repositories, work *domain.Work) error {
+	if err := repos.Works.Create(work); err != nil {
+		return err
+	}
+	return recordChange(
+		ctx, repos.Audit, domain.AuditEntityWork, entityID(work.ID()),
+		domain.AuditActionCreate, nil, workSnapshot(work),
+	)
+}
+
+// updateWork saves the work, refusing to credit it to anyone who has
+// expressed an opinion about it, which would leave them holding an opinion
+// about their own work.
+func updateWork(ctx context.Context, repos *repository.Repositories, before, work *domain.Work) error {
+	for _, authorID := range work.AuthorIDs() {
+		if before.HasAuthor(authorID) {
+			continue
+		}
+		opinions, err := repos.Opinions.GetByWriterAndWork(authorID, work.ID())
+		if err != nil {
+			return err
+		}
+		if len(opinions) > 0 {
+			return fmt.Errorf("author %d has expressed an opinion about this work", authorID)
+		}
+	}
+	if err := repos.Works.Update(work); err != nil {
+		return err
+	}
+	return recordChange(
+		ctx, repos.Audit, domain.AuditEntityWork, entityID(work.ID()),
+		domain.AuditActionUpdate, workSnapshot(before), workSnapshot(work),
+	)
+}
+
+// checkAuthors requires every author to exist and to be credited once.
+func checkAuthors(writers repository.WriterRepository, authorIDs []uint64) error {
+	seen := make(map[uint64]struct{}, len(authorIDs))
+	for _, id := range authorIDs {
+		if _, ok := seen[id]; ok {
+			return fmt.Errorf("author %d is listed more than once", id)
+		}
+		seen[id] = struct{}{}
+	}
+	found, err := writers.GetByIDs(authorIDs)
+	if err != nil {
+		return err
+	}
+	if len(found) != len(authorIDs) {
+		return errors.New("author not found")
+	}
+	return nil
 }
@@ -1,77 +1,142 @@
 package service_test
 
 import (
+	"context"
+	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/what-writers-like/backend/internal/domain"
-	"github.com/what-writers-like/backend/internal/repository/gorm"
+	"github.com/what-writers-like/backend/internal/repository"
+	"github.com/what-writers-like/backend/internal/repository/memory"
 	"github.com/what-writers-like/backend/internal/service"
-	"github.com/what-writers-like/backend/internal/testutils"
 )
 
 func TestWorkService_CreateWork(t *testing.T) {
 	t.Parallel()
 	t.Run("success", func(t *testing.T) {
 		t.Parallel()
-		db, cleanup := testutils.SetupTestDB(t)
-		defer cleanup()
+		store := memory.NewStore()
 
-		workRepo := gorm.NewWorkRepository(db)
-		writerRepo := gorm.NewWriterRepository(db)
-		svc := service.NewWorkService(workRepo, writerRepo)
+		workRepo := memory.NewWorkRepository(store)
+		writerRepo := memory.NewWriterRepository(store)
+		svc := service.NewWorkService(workRepo, writerRepo, memory.NewTransactor(store))
 
 		writer := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
 		require.NoError(t, writerRepo.Create(writer))
 
-		work, err := svc.CreateWork("Pride and Prejudice", 1)
+		work, err := svc.CreateWork(context.Background(), "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})
 		require.NoError(t, err)
 		assert.Equal(t, "Pride and Prejudice", work.Title())
-		assert.Equal(t, uint64(1), work.AuthorID())
+		assert.Equal(t, []uint64{1}, work.AuthorIDs())
 	})
 
 	t.Run("empty title", func(t *testing.T) {
 		t.Parallel()
-		db, cleanup := testutils.SetupTestDB(t)
-		defer cleanup()
+		store := memory.NewStore()
 
-		workRepo := gorm.NewWorkRepository(db)
-		writerRepo := gorm.NewWriterRepository(db)
-		svc := service.NewWorkService(workRepo, writerRepo)
+		workRepo := memory.NewWorkRepository(store)
+		writerRepo := memory.NewWriterRepository(store)
+		svc := service.NewWorkService(workRepo, writerRepo, memory.NewTransactor(store))
 
-		_, err := svc.CreateWork("", 1)
+		_, err := svc.CreateWork(context.Background(), "", []uint64{1}, domain.WorkDetails{})
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "title is required")
 	})
 
 	t.Run("author not found", func(t *testing.T) {
 		t.Parallel()
-		db, cleanup := testutils.SetupTestDB(t)
-		defer cleanup()
+		store := memory.NewStore()
 
-		workRepo := gorm.NewWorkRepository(db)
-		writerRepo := gorm.NewWriterRepository(db)
-		svc := service.NewWorkService(workRepo, writerRepo)
+		workRepo := memory.NewWorkRepository(store)
+		writerRepo := memory.NewWriterRepository(store)
+		svc := service.NewWorkService(workRepo, writerRepo, memory.NewTransactor(store))
 
-		_, err := svc.CreateWork("Pride and Prejudice", 999)
+		_, err := svc.CreateWork(context.Background(), "Pride and Prejudice", []uint64{999}, domain.WorkDetails{})
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "author not found")
 	})
+
+	t.Run("co-authors", func(t *testing.T) {
+		t.Parallel()
+		store := memory.NewStore()
+
+		workRepo := memory.NewWorkRepository(store)
+		writerRepo := memory.NewWriterRepository(store)
+		svc := service.NewWorkService(workRepo, writerRepo, memory.NewTransactor(store))
+
+		require.NoError(t, writerRepo.Create(domain.NewWriter(1, "Jane Austen", 1775, nil, nil)))
+		require.NoError(t, writerRepo.Create(domain.NewWriter(2, "Charlotte Bronte", 1816, nil, nil)))
+
+		work, err := svc.CreateWork(context.Background(), "A Joint Novel", []uint64{2, 1}, domain.WorkDetails{})
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{2, 1}, work.AuthorIDs())
+
+		anonymous, err := svc.CreateWork(context.Background(), "Beowulf", nil, domain.WorkDetails{})
+		require.NoError(t, err)
+		assert.Empty(t, anonymous.AuthorIDs())
+
+		_, err = svc.CreateWork(context.Background(), "A Joint Novel", []uint64{1, 2, 1}, domain.WorkDetails{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "author 1 is listed more than once")
+
+		err = svc.UpdateWork(
+			context.Background(), work.ID(), "A Joint Novel", []uint64{1, 999}, service.WorkDetailsUpdate{},
+		)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "author not found")
+	})
+
+	t.Run("concurrent creates get distinct ids", func(t *testing.T) {
+		t.Parallel()
+		store := memory.NewStore()
+
+		workRepo := memory.NewWorkRepository(store)
+		writerRepo := memory.NewWriterRepository(store)
+		svc := service.NewWorkService(workRepo, writerRepo, memory.NewTransactor(store))
+
+		writer := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
+		require.NoError(t, writerRepo.Create(writer))
+
+		const count = 20
+		ids := make([]uint64, count)
+		errs := make([]error, count)
+		var wg sync.WaitGroup
+		for i := 0; i < count; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				work, err := svc.CreateWork(context.Background(), fmt.Sprintf("Work %d", i), []uint64{1}, domain.WorkDetails{})
+				errs[i] = err
+				if err == nil {
+					ids[i] = work.ID()
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		seen := make(map[uint64]bool, count)
+		for i := 0; i < count; i++ {
+			require.NoError(t, errs[i])
+			assert.False(t, seen[ids[i]], "duplicate id %d", ids[i])
+			seen[ids[i]] = true
+		}
+	})
 }
 
 func TestWorkService_GetWork(t *testing.T) {
 	t.Parallel()
 	t.Run("success", func(t *testing.T) {
 		t.Parallel()
-		db, cleanup := testutils.SetupTestDB(t)
-		defer cleanup()
+		store := memory.NewStore()
 
-		workRepo := gorm.NewWorkRepository(db)
-		writerRepo := gorm.NewWriterRepository(db)
-		svc := service.NewWorkService(workRepo, writerRepo)
+		workRepo := memory.NewWorkRepository(store)
+		writerRepo := memory.NewWriterRepository(store)
+		svc := service.NewWorkService(workRepo, writerRepo, memory.NewTransactor(store))
 
-		expectedWork := domain.NewWork(1, "Pride and Prejudice", 1)
+		expectedWork := domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})
 		require.NoError(t, workRepo.Create(expectedWork))
 
 		work, err := svc.GetWork(1)
@@ -82,12 +147,11 @@ func TestWorkService_GetWork(t *testing.T) {
 
 	t.Run("not found", func(t *testing.T) {
 		t.Parallel()
-		db, cleanup := testutils.SetupTestDB(t)
-		defer cleanup()
+		store := memory.NewStore()
 
-		workRepo := gorm.NewWorkRepository(db)
-		writerRepo := gorm.NewWriterRepository(db)
-		svc := service.NewWorkService(workRepo, writerRepo)
+		workRepo := memory.NewWorkRepository(store)
+		writerRepo := memory.NewWriterRepository(store)
+		svc := service.NewWorkService(workRepo, writerRepo, memory.NewTransactor(store))
 
 		_, err := svc.GetWork(999)
 		require.Error(t, err)
@@ -96,18 +160,17 @@ func TestWorkService_GetWork(t *testing.T) {
 
 func TestWorkService_GetWorksByAuthor(t *testing.T) {
 	t.Parallel()
-	db, cleanup := testutils.SetupTestDB(t)
-	defer cleanup()
+	store := memory.NewStore()
 
-	workRepo := gorm.NewWorkRepository(db)
-	writerRepo := gorm.NewWriterRepository(db)
-	svc := service.NewWorkService(workRepo, writerRepo)
+	workRepo := memory.NewWorkRepository(store)
+	writerRepo := memory.NewWriterRepository(store)
+	svc := service.NewWorkService(workRepo, writerRepo, memory.NewTransactor(store))
 
 	writer := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
 	require.NoError(t, writerRepo.Create(writer))
 
-	work1 := domain.NewWork(1, "Pride and Prejudice", 1)
-	work2 := domain.NewWork(2, "Sense and Sensibility", 1)
+	work1 := domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})
+	work2 := domain.NewWork(2, "Sense and Sensibility", []uint64{1}, domain.WorkDetails{})
 	require.NoError(t, workRepo.Create(work1))
 	require.NoError(t, workRepo.Create(work2))
 
@@ -118,40 +181,40 @@ func TestWorkService_GetWorksByAuthor(t *testing.T) {
 
 func TestWorkService_ListWorks(t *testing.T) {
 	t.Parallel()
-	db, cleanup := testutils.SetupTestDB(t)
-	defer cleanup()
+	store := memory.NewStore()
 
-	workRepo := gorm.NewWorkRepository(db)
-	writerRepo := gorm.NewWriterRepository(db)
-	svc := service.NewWorkService(workRepo, writerRepo)
+	workRepo := memory.NewWorkRepository(store)
+	writerRepo := memory.NewWriterRepository(store)
+	svc := service.NewWorkService(workRepo, writerRepo, memory.NewTransactor(store))
 
-	work1 := domain.NewWork(1, "Pride and Prejudice", 1)
-	work2 := domain.NewWork(2, "Sense and Sensibility", 1)
+	work1 := domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})
+	work2 := domain.NewWork(2, "Sense and Sensibility", []uint64{1}, domain.WorkDetails{})
 	require.NoError(t, workRepo.Create(work1))
 	require.NoError(t, workRepo.Create(work2))
 
-	works, err := svc.ListWorks(10, 0)
+	page, err := svc.ListWorks(repository.WorkFilter{}, nil, 10)
 	require.NoError(t, err)
-	assert.Len(t, works, 2)
+	assert.Len(t, page.Items, 2)
 }
 
 func TestWorkService_UpdateWork(t *testing.T) {
 	t.Parallel()
 	t.Run("success", func(t *testing.T) {
 		t.Parallel()
-		db, cleanup := testutils.SetupTestDB(t)
-		defer cleanup()
+		store := memory.NewStore()
 
-		workRepo := gorm.NewWorkRepository(db)
-		writerRepo := gorm.NewWriterRepository(db)
-		svc := service.NewWorkService(workRepo, writerRepo)
+		workRepo := memory.NewWorkRepository(store)
+		writerRepo := memory.NewWriterRepository(store)
+		svc := service.NewWorkService(workRepo, writerRepo, memory.NewTransactor(store))
 
 		writer := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
 		require.NoError(t, writerRepo.Create(writer))
-		work := domain.NewWork(1, "Pride and Prejudice", 1)
+		work := domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})
 		require.NoError(t, workRepo.Create(work))
 
-		err := svc.UpdateWork(1, "Pride and Prejudice (Revised)", 1)
+		err := svc.UpdateWork(
+			context.Background(), 1, "Pride and Prejudice (Revised)", []uint64{1}, service.WorkDetailsUpdate{},
+		)
 		require.NoError(t, err)
 
 		updated, err := workRepo.GetByID(1)
@@ -161,65 +224,119 @@ func TestWorkService_UpdateWork(t *testing.T) {
 
 	t.Run("empty title", func(t *testing.T) {
 		t.Parallel()
-		db, cleanup := testutils.SetupTestDB(t)
-		defer cleanup()
+		store := memory.NewStore()
 
-		workRepo := gorm.NewWorkRepository(db)
-		writerRepo := gorm.NewWriterRepository(db)
-		svc := service.NewWorkService(workRepo, writerRepo)
+		workRepo := memory.NewWorkRepository(store)
+		writerRepo := memory.NewWriterRepository(store)
+		svc := service.NewWorkService(workRepo, writerRepo, memory.NewTransactor(store))
 
-		err := svc.UpdateWork(1, "", 1)
+		err := svc.UpdateWork(context.Background(), 1, "", []uint64{1}, service.WorkDetailsUpdate{})
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "title is required")
 	})
 
 	t.Run("work not found", func(t *testing.T) {
 		t.Parallel()
-		db, cleanup := testutils.SetupTestDB(t)
-		defer cleanup()
+		store := memory.NewStore()
 
-		workRepo := gorm.NewWorkRepository(db)
-		writerRepo := gorm.NewWriterRepository(db)
-		svc := service.NewWorkService(workRepo, writerRepo)
+		workRepo := memory.NewWorkRepository(store)
+		writerRepo := memory.NewWriterRepository(store)
+		svc := service.NewWorkService(workRepo, writerRepo, memory.NewTransactor(store))
 
-		err := svc.UpdateWork(999, "Pride and Prejudice", 1)
+		err := svc.UpdateWork(
+			context.Background(), 999, "Pride and Prejudice", []uint64{1}, service.WorkDetailsUpdate{},
+		)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "work not found")
 	})
 
 	t.Run("author not found", func(t *testing.T) {
 		t.Parallel()
-		db, cleanup := testutils.SetupTestDB(t)
-		defer cleanup()
+		store := memory.NewStore()
 
-		workRepo := gorm.NewWorkRepository(db)
-		writerRepo := gorm.NewWriterRepository(db)
-		svc := service.NewWorkService(workRepo, writerRepo)
+		workRepo := memory.NewWorkRepository(store)
+		writerRepo := memory.NewWriterRepository(store)
+		svc := service.NewWorkService(workRepo, writerRepo, memory.NewTransactor(store))
 
 		writer := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
 		require.NoError(t, writerRepo.Create(writer))
-		work := domain.NewWork(1, "Pride and Prejudice", 1)
+		work := domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})
 		require.NoError(t, workRepo.Create(work))
 
-		err := svc.UpdateWork(1, "Pride and Prejudice", 999)
+		err := svc.UpdateWork(
+			context.Background(), 1, "Pride and Prejudice", []uint64{999}, service.WorkDetailsUpdate{},
+		)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "author not found")
 	})
+
+	t.Run("details left out are kept", func(t *testing.T) {
+		t.Parallel()
+		store := memory.NewStore()
+
+		workRepo := memory.NewWorkRepository(store)
+		writerRepo := memory.NewWriterRepository(store)
+		svc := service.NewWorkService(workRepo, writerRepo, memory.NewTransactor(store))
+
+		require.NoError(t, writerRepo.Create(domain.NewWriter(1, "Anton Chekhov", 1860, nil, nil)))
+		year, genre := 1896, "play"
+		require.NoError(t, workRepo.Create(domain.NewWork(1, "The Seagull", []uint64{1}, domain.WorkDetails{
+			PublicationYear: &year,
+			Genre:           &genre,
+		})))
+
+		// The genre is cleared and the year kept
+		var cleared *string
+		err := svc.UpdateWork(
+			context.Background(), 1, "The Seagull", []uint64{1}, service.WorkDetailsUpdate{Genre: &cleared},
+		)
+		require.NoError(t, err)
+
+		updated, err := workRepo.GetByID(1)
+		require.NoError(t, err)
+		assert.Equal(t, &year, updated.Details().PublicationYear)
+		assert.Nil(t, updated.Details().Genre)
+	})
+
+	t.Run("author with an opinion about the work", func(t *testing.T) {
+		t.Parallel()
+		store := memory.NewStore()
+
+		workRepo := memory.NewWorkRepository(store)
+		writerRepo := memory.NewWriterRepository(store)
+		svc := service.NewWorkService(workRepo, writerRepo, memory.NewTransactor(store))
+
+		require.NoError(t, writerRepo.Create(domain.NewWriter(1, "Jane Austen", 1775, nil, nil)))
+		require.NoError(t, writerRepo.Create(domain.NewWriter(2, "Charlotte Bronte", 1816, nil, nil)))
+		require.NoError(t, workRepo.Create(domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})))
+		require.NoError(t, memory.NewOpinionRepository(store).Create(
+			domain.NewOpinion(0, 2, 1, domain.SentimentNegative, "Quote", "Letters", nil, nil),
+		))
+
+		err := svc.UpdateWork(
+			context.Background(), 1, "Pride and Prejudice", []uint64{1, 2}, service.WorkDetailsUpdate{},
+		)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "author 2 has expressed an opinion about this work")
+
+		work, err := workRepo.GetByID(1)
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{1}, work.AuthorIDs())
+	})
 }
 
 func TestWorkService_DeleteWork(t *testing.T) {
 	t.Parallel()
-	db, cleanup := testutils.SetupTestDB(t)
-	defer cleanup()
+	store := memory.NewStore()
 
-	workRepo := gorm.NewWorkRepository(db)
-	writerRepo := gorm.NewWriterRepository(db)
-	svc := service.NewWorkService(workRepo, writerRepo)
+	workRepo := memory.NewWorkRepository(store)
+	writerRepo := memory.NewWriterRepository(store)
+	svc := service.NewWorkService(workRepo, writerRepo, memory.NewTransactor(store))
 
-	work := domain.NewWork(1, "Pride and Prejudice", 1)
+	work := domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})
 	require.NoError(t, workRepo.Create(work))
 
-	err := svc.DeleteWork(1)
+	err := svc.DeleteWork(context.Background(), 1)
 	require.NoError(t, err)
 
 	_, err = workRepo.GetByID(1)
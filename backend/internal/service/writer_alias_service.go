@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/repository"
+)
+
+// WriterAliasService manages the other names writers are known by. Aliases
+// are addressed through their writer, so one belonging to another writer is
+// reported as not found.
+type WriterAliasService interface {
+	AddAlias(ctx context.Context, writerID uint64, name string, kind domain.AliasKind) (*domain.WriterAlias, error)
+	ListAliases(writerID uint64) ([]*domain.WriterAlias, error)
+	UpdateAlias(
+		ctx context.Context,
+		writerID, aliasID uint64,
+		name string,
+		kind domain.AliasKind,
+	) (*domain.WriterAlias, error)
+	DeleteAlias(ctx context.Context, writerID, aliasID uint64) error
+}
+
+type writerAliasService struct {
+	aliasRepo  repository.WriterAliasRepository
+	writerRepo repository.WriterRepository
+	transactor repository.Transactor
+}
+
+func NewWriterAliasService(
+	aliasRepo repository.WriterAliasRepository,
+	writerRepo repository.WriterRepository,
+	transactor repository.Transactor,
+) WriterAliasService {
+	return &writerAliasService{
+		aliasRepo:  aliasRepo,
+		writerRepo: writerRepo,
+		transactor: transactor,
+	}
+}
+
+func (s *writerAliasService) AddAlias(
+	ctx context.Context,
+	writerID uint64,
+	name string,
+	kind domain.AliasKind,
+) (*domain.WriterAlias, error) {
+	if err := validateAlias(name, kind); err != nil {
+		return nil, err
+	}
+	if _, err := s.writerRepo.GetByID(writerID); err != nil {
+		return nil, errors.New("writer not found")
+	}
+
+	alias := domain.NewWriterAlias(0, writerID, name, kind)
+	if err := s.checkUnique(alias); err != nil {
+		return nil, err
+	}
+	err := s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		if err := repos.WriterAliases.Create(alias); err != nil {
+			return err
+		}
+		return recordChange(
+			ctx, repos.Audit, domain.AuditEntityWriterAlias, entityID(alias.ID()),
+			domain.AuditActionCreate, nil, writerAliasSnapshot(alias),
+		)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return alias, nil
+}
+
+func (s *writerAliasService) ListAliases(writerID uint64) ([]*domain.WriterAlias, error) {
+	if _, err := s.writerRepo.GetByID(writerID); err != nil {
+		return nil, errors.New("writer not found")
+	}
+	return s.aliasRepo.ListByWriter(writerID)
+}
+
+func (s *writerAliasService) UpdateAlias(
+	ctx context.Context,
+	writerID, aliasID uint64,
+	name string,
+	kind domain.AliasKind,
+) (*domain.WriterAlias, error) {
+	if err := validateAlias(name, kind); err != nil {
+		return nil, err
+	}
+	before, err := s.getAlias(writerID, aliasID)
+	if err != nil {
+		return nil, err
+	}
+
+	alias := domain.NewWriterAlias(aliasID, writerID, name, kind)
+	if err := s.checkUnique(alias); err != nil {
+		return nil, err
+	}
+	err = s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		if err := repos.WriterAliases.Update(alias); err != nil {
+			return err
+		}
+		return recordChange(
+			ctx, repos.Audit, domain.AuditEntityWriterAlias, entityID(aliasID),
+			domain.AuditActionUpdate, writerAliasSnapshot(before), writerAliasSnapshot(alias),
+		)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return alias, nil
+}
+
+func (s *writerAliasService) DeleteAlias(ctx context.Context, writerID, aliasID uint64) error {
+	before, err := s.getAlias(writerID, aliasID)
+	if err != nil {
+		return err
+	}
+	return s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		if err := repos.WriterAliases.Delete(aliasID); err != nil {
+			return err
+		}
+		return recordChange(
+			ctx, repos.Audit, domain.AuditEntityWriterAlias, entityID(aliasID),
+			domain.AuditActionDelete, writerAliasSnapshot(before), nil,
+		)
+	})
+}
+
+func (s *writerAliasService) getAlias(writerID, aliasID uint64) (*domain.WriterAlias, error) {
+	alias, err := s.aliasRepo.GetByID(aliasID)
+	if err != nil || alias.WriterID() != writerID {
+		return nil, errors.New("alias not found")
+	}
+	return alias, nil
+}
+
+// checkUnique reports a name the writer already has as an alias, which
+// the database would reject less helpfully.
+func (s *writerAliasService) checkUnique(alias *domain.WriterAlias) error {
+	existing, err := s.aliasRepo.ListByWriter(alias.WriterID())
+	if err != nil {
+		return err
+	}
+	for _, other := range existing {
+		if other.ID() != alias.ID() && other.Name() == alias.Name() {
+			return fmt.Errorf("writer already has alias %q", alias.Name())
+		}
+	}
+	return nil
+}
+
+func validateAlias(name string, kind domain.AliasKind) error {
+	if name == "" {
+		return errors.New("name is required")
+	}
+	if !kind.IsValid() {
+		return errors.New("invalid alias kind")
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/what-writers-like/backend/internal/domain"
+	"github.com/what-writers-like/backend/internal/repository"
+	"github.com/what-writers-like/backend/internal/repository/memory"
+	"github.com/what-writers-like/backend/internal/service"
+)
+
+func TestWriterAliasService(t *testing.T) {
+	t.Parallel()
+	store := memory.NewStore()
+	writerRepo := memory.NewWriterRepository(store)
+	transactor := memory.NewTransactor(store)
+	svc := service.NewWriterAliasService(memory.NewWriterAliasRepository(store), writerRepo, transactor)
+	writerSvc := service.NewWriterService(writerRepo, memory.NewWorkRepository(store), transactor)
+	ctx := context.Background()
+
+	require.NoError(t, writerRepo.Create(domain.NewWriter(1, "Maxim Gorky", 1868, nil, nil)))
+	require.NoError(t, writerRepo.Create(domain.NewWriter(2, "Leo Tolstoy", 1828, nil, nil)))
+
+	alias, err := svc.AddAlias(ctx, 1, "Alexei Peshkov", domain.AliasKindBirthName)
+	require.NoError(t, err)
+	assert.NotZero(t, alias.ID())
+
+	_, err = svc.AddAlias(ctx, 1, "Alexei Peshkov", domain.AliasKindPseudonym)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `writer already has alias "Alexei Peshkov"`)
+	_, err = svc.AddAlias(ctx, 1, "", domain.AliasKindPseudonym)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "name is required")
+	_, err = svc.AddAlias(ctx, 999, "Nobody", domain.AliasKindPseudonym)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "writer not found")
+
+	// An alias is only reachable through its own writer
+	_, err = svc.UpdateAlias(ctx, 2, alias.ID(), "Lev Tolstoi", domain.AliasKindTransliteration)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "alias not found")
+
+	updated, err := svc.UpdateAlias(ctx, 1, alias.ID(), "Aleksei Peshkov", domain.AliasKindBirthName)
+	require.NoError(t, err)
+	assert.Equal(t, "Aleksei Peshkov", updated.Name())
+
+	page, err := writerSvc.SearchWriters("Peshkov", repository.WriterFilter{}, 10, 0)
+	require.NoError(t, err)
+	matches := page.Items
+	require.Len(t, matches, 1)
+	require.NotNil(t, matches[0].Alias)
+	assert.Equal(t, alias.ID(), matches[0].Alias.ID())
+
+	// Deleting the writer deletes the alias and audits that too
+	require.NoError(t, writerSvc.DeleteWriter(ctx, 1))
+	_, err = svc.ListAliases(1)
+	require.Error(t, err)
+	entries, err := memory.NewAuditRepository(store).Find(
+		repository.AuditFilter{EntityType: domain.AuditEntityWriterAlias}, 10, 0,
+	)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, domain.AuditActionDelete, entries[0].Action())
+}
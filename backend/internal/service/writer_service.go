@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 
 	"github.com/what-writers-like/backend/internal/domain"
@@ -8,49 +9,61 @@ import (
 )
 
 type WriterService interface {
-	CreateWriter(name string, birthYear int, deathYear *int, bio *string) (*domain.Writer, error)
+	CreateWriter(ctx context.Context, name string, birthYear int, deathYear *int, bio *string) (*domain.Writer, error)
 	GetWriter(id uint64) (*domain.Writer, error)
-	ListWriters(limit, offset int) ([]*domain.Writer, error)
-	SearchWriters(query string, limit, offset int) ([]*domain.Writer, error)
-	UpdateWriter(id uint64, name string, birthYear int, deathYear *int, bio *string) error
-	DeleteWriter(id uint64) error
+	// ListWriters returns a page of writers after the one marked by after,
+	// or the first page when it is nil.
+	ListWriters(
+		filter repository.WriterFilter,
+		sort repository.WriterSort,
+		after *repository.WriterCursor,
+		limit int,
+	) (*repository.Page[*domain.Writer, repository.WriterCursor], error)
+	// SearchWriters matches the writers accepted by filter by name, bio or
+	// alias, a page at a time from offset.
+	SearchWriters(
+		query string,
+		filter repository.WriterFilter,
+		limit, offset int,
+	) (*repository.Page[*domain.WriterMatch, int], error)
+	UpdateWriter(ctx context.Context, id uint64, name string, birthYear int, deathYear *int, bio *string) error
+	DeleteWriter(ctx context.Context, id uint64) error
 }
 
 type writerService struct {
 	writerRepo repository.WriterRepository
 	workRepo   repository.WorkRepository
+	transactor repository.Transactor
 }
 
-func NewWriterService(writerRepo repository.WriterRepository, workRepo repository.WorkRepository) WriterService {
+func NewWriterService(
+	writerRepo repository.WriterRepository,
+	workRepo repository.WorkRepository,
+	transactor repository.Transactor,
+) WriterService {
 	return &writerService{
 		writerRepo: writerRepo,
 		workRepo:   workRepo,
+		transactor: transactor,
 	}
 }
 
-func (s *writerService) CreateWriter(name string, birthYear int, deathYear *int, bio *string) (*domain.Writer, error) {
-	if name == "" {
-		return nil, errors.New("name is required")
-	}
-	if birthYear <= 0 {
-		return nil, errors.New("birth year must be positive")
-	}
-
-	id := uint64(1)
-	writers, _ := s.writerRepo.List(1, 0)
-	if len(writers) > 0 {
-		maxID := uint64(0)
-		allWriters, _ := s.writerRepo.List(1000, 0)
-		for _, w := range allWriters {
-			if w.ID() > maxID {
-				maxID = w.ID()
-			}
-		}
-		id = maxID + 1
+func (s *writerService) CreateWriter(
+	ctx context.Context,
+	name string,
+	birthYear int,
+	deathYear *int,
+	bio *string,
+) (*domain.Writer, error) {
+	if err := validateWriter(name, birthYear); err != nil {
+		return nil, err
 	}
 
-	writer := domain.NewWriter(id, name, birthYear, deathYear, bio)
-	if err := s.writerRepo.Create(writer); err != nil {
+	writer := domain.NewWriter(0, name, birthYear, deathYear, bio)
+	err := s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		return createWriter(ctx, repos, writer)
+	})
+	if err != nil {
 		return nil, err
 	}
 	return writer, nil
@@ -60,38 +73,53 @@ func (s *writerService) GetWriter(id uint64) (*domain.Writer, error) {
 	return s.writerRepo.GetByID(id)
 }
 
-func (s *writerService) ListWriters(limit, offset int) ([]*domain.Writer, error) {
-	return s.writerRepo.List(limit, offset)
+func (s *writerService) ListWriters(
+	filter repository.WriterFilter,
+	sort repository.WriterSort,
+	after *repository.WriterCursor,
+	limit int,
+) (*repository.Page[*domain.Writer, repository.WriterCursor], error) {
+	return s.writerRepo.List(filter, sort, after, limit)
 }
 
-func (s *writerService) SearchWriters(query string, limit, offset int) ([]*domain.Writer, error) {
+func (s *writerService) SearchWriters(
+	query string,
+	filter repository.WriterFilter,
+	limit, offset int,
+) (*repository.Page[*domain.WriterMatch, int], error) {
 	if query == "" {
-		return s.writerRepo.List(limit, offset)
+		return nil, errors.New("query is required")
 	}
-	return s.writerRepo.Search(query, limit, offset)
+	return s.writerRepo.Search(query, filter, limit, offset)
 }
 
-func (s *writerService) UpdateWriter(id uint64, name string, birthYear int, deathYear *int, bio *string) error {
-	if name == "" {
-		return errors.New("name is required")
-	}
-	if birthYear <= 0 {
-		return errors.New("birth year must be positive")
+func (s *writerService) UpdateWriter(
+	ctx context.Context,
+	id uint64,
+	name string,
+	birthYear int,
+	deathYear *int,
+	bio *string,
+) error {
+	if err := validateWriter(name, birthYear); err != nil {
+		return err
 	}
 
 	// Check if writer exists
-	_, err := s.writerRepo.GetByID(id)
+	before, err := s.writerRepo.GetByID(id)
 	if err != nil {
 		return errors.New("writer not found")
 	}
 
 	writer := domain.NewWriter(id, name, birthYear, deathYear, bio)
-	return s.writerRepo.Update(writer)
+	return s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		return updateWriter(ctx, repos, before, writer)
+	})
 }
 
-func (s *writerService) DeleteWriter(id uint64) error {
+func (s *writerService) DeleteWriter(ctx context.Context, id uint64) error {
 	// Check if writer exists
-	_, err := s.writerRepo.GetByID(id)
+	before, err := s.writerRepo.GetByID(id)
 	if err != nil {
 		return errors.New("writer not found")
 	}
@@ -103,5 +131,57 @@ func (s *writerService) DeleteWriter(id uint64) error {
 	if len(works) > 0 {
 		return errors.New("cannot delete writer with existing works")
 	}
-	return s.writerRepo.Delete(id)
+	return s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		// The writer's aliases go with them, each with its own audit entry
+		aliases, err := repos.WriterAliases.ListByWriter(id)
+		if err != nil {
+			return err
+		}
+		for _, alias := range aliases {
+			err := recordChange(
+				ctx, repos.Audit, domain.AuditEntityWriterAlias, entityID(alias.ID()),
+				domain.AuditActionDelete, writerAliasSnapshot(alias), nil,
+			)
+			if err != nil {
+				return err
+			}
+		}
+		if err := repos.Writers.Delete(id); err != nil {
+			return err
+		}
+		return recordChange(
+			ctx, repos.Audit, domain.AuditEntityWriter, entityID(id),
+			domain.AuditActionDelete, writerSnapshot(before), nil,
+		)
+	})
+}
+
+func validateWriter(name string, birthYear int) error {
+	if name == "" {
+		return errors.New("name is required")
+	}
+	if birthYear <= 0 {
+		return errors.New("birth year must be positive")
+	}
+	return nil
+}
+
+func createWriter(ctx context.Context, repos *repository.Repositories, writer *domain.Writer) error {
+	if err := repos.Writers.Create(writer); err != nil {
+		return err
+	}
+	return recordChange(
+		ctx, repos.Audit, domain.AuditEntityWriter, entityID(writer.ID()),
+		domain.AuditActionCreate, nil, writerSnapshot(writer),
+	)
+}
+
+func updateWriter(ctx context.Context, repos *repository.Repositories, before, writer *domain.Writer) error {
+	if err := repos.Writers.Update(writer); err != nil {
+		return err
+	}
+	return recordChange(
+		ctx, repos.Audit, domain.AuditEntityWriter, entityID(writer.ID()),
+		domain.AuditActionUpdate, writerSnapshot(before), writerSnapshot(writer),
+	)
 }
@@ -1,28 +1,30 @@
 package service_test
 
 import (
+	"context"
+	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/what-writers-like/backend/internal/domain"
-	"github.com/what-writers-like/backend/internal/repository/gorm"
+	"github.com/what-writers-like/backend/internal/repository"
+	"github.com/what-writers-like/backend/internal/repository/memory"
 	"github.com/what-writers-like/backend/internal/service"
-	"github.com/what-writers-like/backend/internal/testutils"
 )
 
 func TestWriterService_CreateWriter(t *testing.T) {
 	t.Parallel()
 	t.Run("success", func(t *testing.T) {
 		t.Parallel()
-		db, cleanup := testutils.SetupTestDB(t)
-		defer cleanup()
+		store := memory.NewStore()
 
-		writerRepo := gorm.NewWriterRepository(db)
-		workRepo := gorm.NewWorkRepository(db)
-		svc := service.NewWriterService(writerRepo, workRepo)
+		writerRepo := memory.NewWriterRepository(store)
+		workRepo := memory.NewWorkRepository(store)
+		svc := service.NewWriterService(writerRepo, workRepo, memory.NewTransactor(store))
 
-		writer, err := svc.CreateWriter("Jane Austen", 1775, nil, nil)
+		writer, err := svc.CreateWriter(context.Background(), "Jane Austen", 1775, nil, nil)
 		require.NoError(t, err)
 		assert.Equal(t, "Jane Austen", writer.Name())
 		assert.Equal(t, 1775, writer.BirthYear())
@@ -30,40 +32,37 @@ func TestWriterService_CreateWriter(t *testing.T) {
 
 	t.Run("empty name", func(t *testing.T) {
 		t.Parallel()
-		db, cleanup := testutils.SetupTestDB(t)
-		defer cleanup()
+		store := memory.NewStore()
 
-		writerRepo := gorm.NewWriterRepository(db)
-		workRepo := gorm.NewWorkRepository(db)
-		svc := service.NewWriterService(writerRepo, workRepo)
+		writerRepo := memory.NewWriterRepository(store)
+		workRepo := memory.NewWorkRepository(store)
+		svc := service.NewWriterService(writerRepo, workRepo, memory.NewTransactor(store))
 
-		_, err := svc.CreateWriter("", 1775, nil, nil)
+		_, err := svc.CreateWriter(context.Background(), "", 1775, nil, nil)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "name is required")
 	})
 
 	t.Run("invalid birth year", func(t *testing.T) {
 		t.Parallel()
-		db, cleanup := testutils.SetupTestDB(t)
-		defer cleanup()
+		store := memory.NewStore()
 
-		writerRepo := gorm.NewWriterRepository(db)
-		workRepo := gorm.NewWorkRepository(db)
-		svc := service.NewWriterService(writerRepo, workRepo)
+		writerRepo := memory.NewWriterRepository(store)
+		workRepo := memory.NewWorkRepository(store)
+		svc := service.NewWriterService(writerRepo, workRepo, memory.NewTransactor(store))
 
-		_, err := svc.CreateWriter("Jane Austen", 0, nil, nil)
+		_, err := svc.CreateWriter(context.Background(), "Jane Austen", 0, nil, nil)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "birth year must be positive")
 	})
 
 	t.Run("increments id correctly", func(t *testing.T) {
 		t.Parallel()
-		db, cleanup := testutils.SetupTestDB(t)
-		defer cleanup()
+		store := memory.NewStore()
 
-		writerRepo := gorm.NewWriterRepository(db)
-		workRepo := gorm.NewWorkRepository(db)
-		svc := service.NewWriterService(writerRepo, workRepo)
+		writerRepo := memory.NewWriterRepository(store)
+		workRepo := memory.NewWorkRepository(store)
+		svc := service.NewWriterService(writerRepo, workRepo, memory.NewTransactor(store))
 
 		// Create writers with specific IDs
 		writer1 := domain.NewWriter(1, "Writer 1", 1800, nil, nil)
@@ -71,22 +70,59 @@ func TestWriterService_CreateWriter(t *testing.T) {
 		require.NoError(t, writerRepo.Create(writer1))
 		require.NoError(t, writerRepo.Create(writer3))
 
-		writer, err := svc.CreateWriter("New Writer", 1900, nil, nil)
+		writer, err := svc.CreateWriter(context.Background(), "New Writer", 1900, nil, nil)
 		require.NoError(t, err)
 		assert.Equal(t, uint64(4), writer.ID())
 	})
+
+	t.Run("concurrent creates get distinct ids", func(t *testing.T) {
+		t.Parallel()
+		store := memory.NewStore()
+
+		writerRepo := memory.NewWriterRepository(store)
+		workRepo := memory.NewWorkRepository(store)
+		svc := service.NewWriterService(writerRepo, workRepo, memory.NewTransactor(store))
+
+		const count = 20
+		ids := make([]uint64, count)
+		errs := make([]error, count)
+		var wg sync.WaitGroup
+		for i := 0; i < count; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				writer, err := svc.CreateWriter(context.Background(), fmt.Sprintf("Writer %d", i), 1800+i, nil, nil)
+				errs[i] = err
+				if err == nil {
+					ids[i] = writer.ID()
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		seen := make(map[uint64]bool, count)
+		for i := 0; i < count; i++ {
+			require.NoError(t, errs[i])
+			assert.NotZero(t, ids[i])
+			assert.False(t, seen[ids[i]], "duplicate id %d", ids[i])
+			seen[ids[i]] = true
+		}
+
+		page, err := svc.ListWriters(repository.WriterFilter{}, repository.WriterSort{}, nil, 100)
+		require.NoError(t, err)
+		assert.Len(t, page.Items, count)
+	})
 }
 
 func TestWriterService_GetWriter(t *testing.T) {
 	t.Parallel()
 	t.Run("success", func(t *testing.T) {
 		t.Parallel()
-		db, cleanup := testutils.SetupTestDB(t)
-		defer cleanup()
+		store := memory.NewStore()
 
-		writerRepo := gorm.NewWriterRepository(db)
-		workRepo := gorm.NewWorkRepository(db)
-		svc := service.NewWriterService(writerRepo, workRepo)
+		writerRepo := memory.NewWriterRepository(store)
+		workRepo := memory.NewWorkRepository(store)
+		svc := service.NewWriterService(writerRepo, workRepo, memory.NewTransactor(store))
 
 		expectedWriter := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
 		require.NoError(t, writerRepo.Create(expectedWriter))
@@ -99,12 +135,11 @@ func TestWriterService_GetWriter(t *testing.T) {
 
 	t.Run("not found", func(t *testing.T) {
 		t.Parallel()
-		db, cleanup := testutils.SetupTestDB(t)
-		defer cleanup()
+		store := memory.NewStore()
 
-		writerRepo := gorm.NewWriterRepository(db)
-		workRepo := gorm.NewWorkRepository(db)
-		svc := service.NewWriterService(writerRepo, workRepo)
+		writerRepo := memory.NewWriterRepository(store)
+		workRepo := memory.NewWorkRepository(store)
+		svc := service.NewWriterService(writerRepo, workRepo, memory.NewTransactor(store))
 
 		_, err := svc.GetWriter(999)
 		require.Error(t, err)
@@ -113,39 +148,37 @@ func TestWriterService_GetWriter(t *testing.T) {
 
 func TestWriterService_ListWriters(t *testing.T) {
 	t.Parallel()
-	db, cleanup := testutils.SetupTestDB(t)
-	defer cleanup()
+	store := memory.NewStore()
 
-	writerRepo := gorm.NewWriterRepository(db)
-	workRepo := gorm.NewWorkRepository(db)
-	svc := service.NewWriterService(writerRepo, workRepo)
+	writerRepo := memory.NewWriterRepository(store)
+	workRepo := memory.NewWorkRepository(store)
+	svc := service.NewWriterService(writerRepo, workRepo, memory.NewTransactor(store))
 
 	writer1 := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
 	writer2 := domain.NewWriter(2, "Charles Dickens", 1812, nil, nil)
 	require.NoError(t, writerRepo.Create(writer1))
 	require.NoError(t, writerRepo.Create(writer2))
 
-	writers, err := svc.ListWriters(10, 0)
+	page, err := svc.ListWriters(repository.WriterFilter{}, repository.WriterSort{}, nil, 10)
 	require.NoError(t, err)
-	assert.Len(t, writers, 2)
+	assert.Len(t, page.Items, 2)
 }
 
 func TestWriterService_UpdateWriter(t *testing.T) {
 	t.Parallel()
 	t.Run("success", func(t *testing.T) {
 		t.Parallel()
-		db, cleanup := testutils.SetupTestDB(t)
-		defer cleanup()
+		store := memory.NewStore()
 
-		writerRepo := gorm.NewWriterRepository(db)
-		workRepo := gorm.NewWorkRepository(db)
-		svc := service.NewWriterService(writerRepo, workRepo)
+		writerRepo := memory.NewWriterRepository(store)
+		workRepo := memory.NewWorkRepository(store)
+		svc := service.NewWriterService(writerRepo, workRepo, memory.NewTransactor(store))
 
 		writer := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
 		require.NoError(t, writerRepo.Create(writer))
 
 		bio := "English novelist"
-		err := svc.UpdateWriter(1, "Jane Austen", 1775, nil, &bio)
+		err := svc.UpdateWriter(context.Background(), 1, "Jane Austen", 1775, nil, &bio)
 		require.NoError(t, err)
 
 		updated, err := writerRepo.GetByID(1)
@@ -156,28 +189,26 @@ func TestWriterService_UpdateWriter(t *testing.T) {
 
 	t.Run("empty name", func(t *testing.T) {
 		t.Parallel()
-		db, cleanup := testutils.SetupTestDB(t)
-		defer cleanup()
+		store := memory.NewStore()
 
-		writerRepo := gorm.NewWriterRepository(db)
-		workRepo := gorm.NewWorkRepository(db)
-		svc := service.NewWriterService(writerRepo, workRepo)
+		writerRepo := memory.NewWriterRepository(store)
+		workRepo := memory.NewWorkRepository(store)
+		svc := service.NewWriterService(writerRepo, workRepo, memory.NewTransactor(store))
 
-		err := svc.UpdateWriter(1, "", 1775, nil, nil)
+		err := svc.UpdateWriter(context.Background(), 1, "", 1775, nil, nil)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "name is required")
 	})
 
 	t.Run("invalid birth year", func(t *testing.T) {
 		t.Parallel()
-		db, cleanup := testutils.SetupTestDB(t)
-		defer cleanup()
+		store := memory.NewStore()
 
-		writerRepo := gorm.NewWriterRepository(db)
-		workRepo := gorm.NewWorkRepository(db)
-		svc := service.NewWriterService(writerRepo, workRepo)
+		writerRepo := memory.NewWriterRepository(store)
+		workRepo := memory.NewWorkRepository(store)
+		svc := service.NewWriterService(writerRepo, workRepo, memory.NewTransactor(store))
 
-		err := svc.UpdateWriter(1, "Jane Austen", 0, nil, nil)
+		err := svc.UpdateWriter(context.Background(), 1, "Jane Austen", 0, nil, nil)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "birth year must be positive")
 	})
@@ -187,17 +218,16 @@ func TestWriterService_DeleteWriter(t *testing.T) {
 	t.Parallel()
 	t.Run("success", func(t *testing.T) {
 		t.Parallel()
-		db, cleanup := testutils.SetupTestDB(t)
-		defer cleanup()
+		store := memory.NewStore()
 
-		writerRepo := gorm.NewWriterRepository(db)
-		workRepo := gorm.NewWorkRepository(db)
-		svc := service.NewWriterService(writerRepo, workRepo)
+		writerRepo := memory.NewWriterRepository(store)
+		workRepo := memory.NewWorkRepository(store)
+		svc := service.NewWriterService(writerRepo, workRepo, memory.NewTransactor(store))
 
 		writer := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
 		require.NoError(t, writerRepo.Create(writer))
 
-		err := svc.DeleteWriter(1)
+		err := svc.DeleteWriter(context.Background(), 1)
 		require.NoError(t, err)
 
 		_, err = writerRepo.GetByID(1)
@@ -206,19 +236,18 @@ func TestWriterService_DeleteWriter(t *testing.T) {
 
 	t.Run("cannot delete writer with works", func(t *testing.T) {
 		t.Parallel()
-		db, cleanup := testutils.SetupTestDB(t)
-		defer cleanup()
+		store := memory.NewStore()
 
-		writerRepo := gorm.NewWriterRepository(db)
-		workRepo := gorm.NewWorkRepository(db)
-		svc := service.NewWriterService(writerRepo, workRepo)
+		writerRepo := memory.NewWriterRepository(store)
+		workRepo := memory.NewWorkRepository(store)
+		svc := service.NewWriterService(writerRepo, workRepo, memory.NewTransactor(store))
 
 		writer := domain.NewWriter(1, "Jane Austen", 1775, nil, nil)
 		require.NoError(t, writerRepo.Create(writer))
-		work := domain.NewWork(1, "Pride and Prejudice", 1)
+		work := domain.NewWork(1, "Pride and Prejudice", []uint64{1}, domain.WorkDetails{})
 		require.NoError(t, workRepo.Create(work))
 
-		err := svc.DeleteWriter(1)
+		err := svc.DeleteWriter(context.Background(), 1)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "cannot delete writer with existing works")
 	})
@@ -2,6 +2,7 @@ package testutils
 
 import (
 	"context"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -43,7 +44,12 @@ func SetupTestDB(t *testing.T) (*database.Database, func()) {
 		}
 	}
 
-	cfg := &config.Config{DatabaseDSN: connStr, ServerPort: "8080"}
+	cfg := &config.Config{
+		Storage:        config.StoragePostgres,
+		DatabaseDSN:    connStr,
+		ServerPort:     "8080",
+		MigrateOnStart: true,
+	}
 	db, err := database.NewDatabase(cfg)
 	require.NoError(t, err)
 
@@ -53,3 +59,24 @@ func SetupTestDB(t *testing.T) (*database.Database, func()) {
 
 	return db, cleanup
 }
+
+// SetupSQLiteTestDB creates a migrated SQLite database in a file that is
+// removed when the test ends.
+func SetupSQLiteTestDB(t *testing.T) (*database.Database, func()) {
+	cfg := &config.Config{
+		Storage:        config.StorageSQLite,
+		DatabaseDSN:    filepath.Join(t.TempDir(), "test.db"),
+		ServerPort:     "8080",
+		MigrateOnStart: true,
+	}
+	db, err := database.NewDatabase(cfg)
+	require.NoError(t, err)
+
+	cleanup := func() {
+		if sqlDB, err := db.DB().DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	}
+
+	return db, cleanup
+}
@@ -3,24 +3,30 @@
 import React, { useEffect, useRef, useState } from "react";
 import { type Column, DataTable } from "@/components/admin/DataTable";
 import { DeleteConfirmDialog } from "@/components/admin/DeleteConfirmDialog";
+import { ImportPreview } from "@/components/admin/ImportPreview";
 import { Button } from "@/components/common/Button";
 import { ErrorMessage } from "@/components/common/ErrorMessage";
+import { ExportService } from "@/services/exportService";
+import { ImportService } from "@/services/importService";
 import { WriterService } from "@/services/writerService";
 import { WorkService } from "@/services/workService";
 import { useOpinionStore } from "@/stores/opinionStore";
-import type { CreateOpinionRequest, Opinion, UpdateOpinionRequest } from "@/types/opinion";
+import type { ConflictPolicy, ImportReport } from "@/types/import";
+import {
+  type CreateOpinionRequest,
+  type Opinion,
+  SENTIMENT_GRADES,
+  type SentimentGrade,
+  type UpdateOpinionRequest,
+} from "@/types/opinion";
 import type { Work } from "@/types/work";
 import type { Writer } from "@/types/writer";
-import {
-  type CSVImportResult,
-  downloadCSV,
-  exportOpinionsToCSV,
-  importOpinionsFromCSV,
-} from "@/utils/csvUtils";
 
+// target is "work:<id>" or "writer:<id>", since an opinion is about either
+// a work or a writer as a whole.
 interface OpinionFormData {
   writer_id: string;
-  work_id: string;
+  target: string;
   sentiment: string;
   quote: string;
   source: string;
@@ -30,18 +36,14 @@ interface OpinionFormData {
 
 const initialFormData: OpinionFormData = {
   writer_id: "",
-  work_id: "",
-  sentiment: "true",
+  target: "",
+  sentiment: "+1",
   quote: "",
   source: "",
   page: "",
   statement_year: "",
 };
 
-const getOpinionKey = (opinion: Opinion): string => {
-  return `${opinion.writer_id}-${opinion.work_id}`;
-};
-
 export default function OpinionsAdminPage(): React.JSX.Element {
   const {
     opinions,
@@ -56,24 +58,27 @@ export default function OpinionsAdminPage(): React.JSX.Element {
 
   const [writers, setWriters] = useState<Writer[]>([]);
   const [works, setWorks] = useState<Work[]>([]);
-  const [editingId, setEditingId] = useState<string | null>(null);
+  const [editingId, setEditingId] = useState<number | "new" | null>(null);
   const [formData, setFormData] = useState<OpinionFormData>(initialFormData);
   const [formErrors, setFormErrors] = useState<Partial<Record<keyof OpinionFormData, string>>>({});
   const [deleteConfirmOpen, setDeleteConfirmOpen] = useState<boolean>(false);
   const [opinionToDelete, setOpinionToDelete] = useState<Opinion | null>(null);
-  const [importPreview, setImportPreview] = useState<CSVImportResult<Opinion> | null>(null);
-  const [showImportPreview, setShowImportPreview] = useState<boolean>(false);
+  const [importCSV, setImportCSV] = useState<string | null>(null);
+  const [importPreview, setImportPreview] = useState<ImportReport | null>(null);
+  const [onConflict, setOnConflict] = useState<ConflictPolicy>("error");
+  const [isImporting, setIsImporting] = useState<boolean>(false);
+  const [importError, setImportError] = useState<string | null>(null);
   const fileInputRef = useRef<HTMLInputElement>(null);
   const firstInputRef = useRef<HTMLSelectElement>(null);
 
   useEffect(() => {
-    void fetchOpinions(1000, 0);
+    void fetchOpinions();
   }, [fetchOpinions]);
 
   useEffect(() => {
     const loadWriters = async (): Promise<void> => {
       try {
-        const writersData = await WriterService.list(1000, 0);
+        const writersData = await WriterService.listAll();
         setWriters(writersData);
       } catch (error) {
         console.error("Failed to load writers:", error);
@@ -85,7 +90,7 @@ export default function OpinionsAdminPage(): React.JSX.Element {
   useEffect(() => {
     const loadWorks = async (): Promise<void> => {
       try {
-        const worksData = await WorkService.list(1000, 0);
+        const worksData = await WorkService.listAll();
         setWorks(worksData);
       } catch (error) {
         console.error("Failed to load works:", error);
@@ -110,23 +115,34 @@ export default function OpinionsAdminPage(): React.JSX.Element {
     return work ? work.title : `ID: ${workId}`;
   };
 
-  const getWorkAuthorId = (workId: number): number | null => {
+  const getWorkAuthorIds = (workId: number): number[] => {
     const work = works.find((w) => w.id === workId);
-    return work ? work.author_id : null;
+    return work ? work.author_ids : [];
   };
 
-  const handleEdit = (id: string | number): void => {
-    const [writerIdStr, workIdStr] = String(id).split("-");
-    const writerId = parseInt(writerIdStr, 10);
-    const workId = parseInt(workIdStr, 10);
+  const opinionTarget = (opinion: Opinion): string =>
+    opinion.target_type === "writer"
+      ? `writer:${opinion.target_writer_id}`
+      : `work:${opinion.work_id}`;
+
+  const getTargetLabel = (opinion: Opinion): string =>
+    opinion.target_type === "writer"
+      ? getWriterName(opinion.target_writer_id ?? 0)
+      : getWorkTitle(opinion.work_id ?? 0);
+
+  const parseTarget = (target: string): { type: string; id: number } => {
+    const [type, id] = target.split(":");
+    return { type, id: parseInt(id, 10) };
+  };
 
-    const opinion = opinions.find((o) => o.writer_id === writerId && o.work_id === workId);
+  const handleEdit = (id: string | number): void => {
+    const opinion = opinions.find((o) => o.id === id);
     if (opinion) {
-      setEditingId(getOpinionKey(opinion));
+      setEditingId(opinion.id);
       setFormData({
         writer_id: opinion.writer_id.toString(),
-        work_id: opinion.work_id.toString(),
-        sentiment: opinion.sentiment ? "true" : "false",
+        target: opinionTarget(opinion),
+        sentiment: opinion.sentiment_grade,
         quote: opinion.quote,
         source: opinion.source,
         page: opinion.page ?? "",
@@ -155,17 +171,18 @@ export default function OpinionsAdminPage(): React.JSX.Element {
       errors.writer_id = "Writer is required";
     }
 
-    if (!formData.work_id.trim()) {
-      errors.work_id = "Work is required";
+    if (!formData.target) {
+      errors.target = "Work or writer is required";
     }
 
-    // Validate writer is not the work's author
-    if (formData.writer_id && formData.work_id) {
+    // Validate writer is neither the target nor the work's author
+    if (formData.writer_id && formData.target) {
       const writerId = parseInt(formData.writer_id, 10);
-      const workId = parseInt(formData.work_id, 10);
-      const workAuthorId = getWorkAuthorId(workId);
+      const target = parseTarget(formData.target);
 
-      if (workAuthorId === writerId) {
+      if (target.type === "writer" && target.id === writerId) {
+        errors.writer_id = "Writer cannot express an opinion about themselves";
+      } else if (target.type === "work" && getWorkAuthorIds(target.id).includes(writerId)) {
         errors.writer_id = "Writer cannot express an opinion about their own work";
       }
     }
@@ -195,8 +212,8 @@ export default function OpinionsAdminPage(): React.JSX.Element {
     }
 
     const writerId = parseInt(formData.writer_id, 10);
-    const workId = parseInt(formData.work_id, 10);
-    const sentiment = formData.sentiment === "true";
+    const target = parseTarget(formData.target);
+    const sentimentGrade = formData.sentiment as SentimentGrade;
     const statementYear = formData.statement_year.trim()
       ? parseInt(formData.statement_year, 10)
       : null;
@@ -205,8 +222,8 @@ export default function OpinionsAdminPage(): React.JSX.Element {
     if (editingId === "new" || id === "new") {
       const createData: CreateOpinionRequest = {
         writer_id: writerId,
-        work_id: workId,
-        sentiment,
+        ...(target.type === "writer" ? { target_writer_id: target.id } : { work_id: target.id }),
+        sentiment_grade: sentimentGrade,
         quote: formData.quote.trim(),
         source: formData.source.trim(),
         page: formData.page.trim() || null,
@@ -215,38 +232,34 @@ export default function OpinionsAdminPage(): React.JSX.Element {
 
       const result = await createOpinion(createData);
       if (result) {
-        await fetchOpinions(1000, 0);
+        await fetchOpinions();
         // Exit edit mode after successful creation
         setEditingId(null);
         setFormData(initialFormData);
         setFormErrors({});
       }
     } else {
-      const [oldWriterIdStr, oldWorkIdStr] = String(editingId).split("-");
-      const oldWriterId = parseInt(oldWriterIdStr, 10);
-      const oldWorkId = parseInt(oldWorkIdStr, 10);
-
+      // The form does not edit translations, so the recorded ones are kept
+      const current = opinions.find((o) => o.id === Number(id));
       const updateData: UpdateOpinionRequest = {
-        sentiment,
+        sentiment_grade: sentimentGrade,
         quote: formData.quote.trim(),
         source: formData.source.trim(),
         page: formData.page.trim() || null,
         statement_year: statementYear,
+        language: current?.language ?? undefined,
+        translations: current?.translations,
       };
 
-      await updateOpinion(oldWriterId, oldWorkId, updateData);
+      await updateOpinion(Number(id), updateData);
       setEditingId(null);
       setFormData(initialFormData);
-      await fetchOpinions(1000, 0);
+      await fetchOpinions();
     }
   };
 
   const handleDeleteClick = (id: string | number): void => {
-    const [writerIdStr, workIdStr] = String(id).split("-");
-    const writerId = parseInt(writerIdStr, 10);
-    const workId = parseInt(workIdStr, 10);
-
-    const opinion = opinions.find((o) => o.writer_id === writerId && o.work_id === workId);
+    const opinion = opinions.find((o) => o.id === id);
     if (opinion) {
       setOpinionToDelete(opinion);
       setDeleteConfirmOpen(true);
@@ -260,7 +273,7 @@ export default function OpinionsAdminPage(): React.JSX.Element {
       // Clear any previous errors
       clearError();
       
-      await deleteOpinion(opinionToDelete.writer_id, opinionToDelete.work_id);
+      await deleteOpinion(opinionToDelete.id);
       // The store will set error state if deletion fails
       // We'll use useEffect to handle dialog closing on success
     }
@@ -275,30 +288,40 @@ export default function OpinionsAdminPage(): React.JSX.Element {
       !error
     ) {
       // Check if the opinion was actually deleted (not in the list anymore)
-      const stillExists = opinions.some(
-        (o) =>
-          o.writer_id === opinionToDelete.writer_id &&
-          o.work_id === opinionToDelete.work_id
-      );
+      const stillExists = opinions.some((o) => o.id === opinionToDelete.id);
       if (!stillExists) {
         setDeleteConfirmOpen(false);
         setOpinionToDelete(null);
-        void fetchOpinions(1000, 0);
+        void fetchOpinions();
       }
     }
     // eslint-disable-next-line react-hooks/exhaustive-deps
   }, [deleteConfirmOpen, opinionToDelete, isLoading, error, opinions]);
 
   const handleExportCSV = (): void => {
-    const csvContent = exportOpinionsToCSV(opinions);
-    const filename = `opinions-${new Date().toISOString().split("T")[0]}.csv`;
-    downloadCSV(csvContent, filename);
+    ExportService.download("opinions");
   };
 
   const handleImportCSV = (): void => {
     fileInputRef.current?.click();
   };
 
+  // Every import is tried as a dry run first; the server checks each row
+  // and reports what it would do without writing anything
+  const previewImport = async (csv: string, policy: ConflictPolicy): Promise<void> => {
+    setIsImporting(true);
+    setImportError(null);
+    try {
+      const report = await ImportService.importCSV("opinions", csv, { dryRun: true, onConflict: policy });
+      setImportCSV(csv);
+      setImportPreview(report);
+    } catch (err) {
+      setImportError(err instanceof Error ? err.message : "Failed to import CSV");
+    } finally {
+      setIsImporting(false);
+    }
+  };
+
   const handleFileChange = (e: React.ChangeEvent<HTMLInputElement>): void => {
     const file = e.target.files?.[0];
     if (!file) {
@@ -307,10 +330,7 @@ export default function OpinionsAdminPage(): React.JSX.Element {
 
     const reader = new FileReader();
     reader.onload = (event) => {
-      const csvString = event.target?.result as string;
-      const result = importOpinionsFromCSV(csvString);
-      setImportPreview(result);
-      setShowImportPreview(true);
+      void previewImport(event.target?.result as string, onConflict);
     };
     reader.readAsText(file);
 
@@ -319,41 +339,42 @@ export default function OpinionsAdminPage(): React.JSX.Element {
     }
   };
 
+  const handleConflictChange = (policy: ConflictPolicy): void => {
+    setOnConflict(policy);
+    if (importCSV) {
+      void previewImport(importCSV, policy);
+    }
+  };
+
+  const handleImportCancel = (): void => {
+    setImportCSV(null);
+    setImportPreview(null);
+  };
+
   const handleImportConfirm = async (): Promise<void> => {
-    if (!importPreview) {
+    if (!importCSV) {
       return;
     }
 
-    let successCount = 0;
-    let errorCount = 0;
-
-    for (const opinion of importPreview.data) {
-      try {
-        const createData: CreateOpinionRequest = {
-          writer_id: opinion.writer_id,
-          work_id: opinion.work_id,
-          sentiment: opinion.sentiment,
-          quote: opinion.quote,
-          source: opinion.source,
-          page: opinion.page,
-          statement_year: opinion.statement_year,
-        };
-        const result = await createOpinion(createData);
-        if (result) {
-          successCount++;
-        } else {
-          errorCount++;
-        }
-      } catch {
-        errorCount++;
+    setIsImporting(true);
+    setImportError(null);
+    try {
+      const report = await ImportService.importCSV("opinions", importCSV, { dryRun: false, onConflict });
+      if (!report.applied) {
+        // The records changed since the preview
+        setImportPreview(report);
+        return;
       }
+      handleImportCancel();
+      void fetchOpinions();
+      alert(
+        `Import completed: ${report.created} created, ${report.updated} updated, ${report.skipped} skipped`
+      );
+    } catch (err) {
+      setImportError(err instanceof Error ? err.message : "Failed to import CSV");
+    } finally {
+      setIsImporting(false);
     }
-
-    setShowImportPreview(false);
-    setImportPreview(null);
-    void fetchOpinions(1000, 0);
-
-    alert(`Import completed: ${successCount} successful, ${errorCount} failed`);
   };
 
   const truncateText = (text: string, maxLength: number): string => {
@@ -369,7 +390,7 @@ export default function OpinionsAdminPage(): React.JSX.Element {
         if (isEditing) {
           return (
             <select
-              ref={editingId === getOpinionKey(opinion) ? firstInputRef : undefined}
+              ref={editingId === opinion.id ? firstInputRef : undefined}
               value={formData.writer_id}
               onChange={(e) => setFormData({ ...formData, writer_id: e.target.value })}
               className="w-full px-2 py-1 border border-gray-300 rounded focus:outline-none focus:ring-2 focus:ring-blue-500"
@@ -395,75 +416,67 @@ export default function OpinionsAdminPage(): React.JSX.Element {
       },
     },
     {
-      key: "work_id",
-      label: "Work ID",
-      sortable: true,
+      key: "target",
+      label: "About",
+      sortable: false,
       render: (opinion, isEditing) => {
         if (isEditing) {
+          // The target of an existing opinion cannot be changed
           return (
             <select
-              value={formData.work_id}
-              onChange={(e) => setFormData({ ...formData, work_id: e.target.value })}
+              value={formData.target}
+              onChange={(e) => setFormData({ ...formData, target: e.target.value })}
+              disabled={editingId !== "new"}
               className="w-full px-2 py-1 border border-gray-300 rounded focus:outline-none focus:ring-2 focus:ring-blue-500"
             >
-              <option value="">Select Work</option>
-              {works.map((work) => (
-                <option key={work.id} value={work.id.toString()}>
-                  {work.title} (ID: {work.id})
-                </option>
-              ))}
+              <option value="">Select Work or Writer</option>
+              <optgroup label="Works">
+                {works.map((work) => (
+                  <option key={`work-${work.id}`} value={`work:${work.id}`}>
+                    {work.title} (ID: {work.id})
+                  </option>
+                ))}
+              </optgroup>
+              <optgroup label="Writers as a whole">
+                {writers.map((writer) => (
+                  <option key={`writer-${writer.id}`} value={`writer:${writer.id}`}>
+                    {writer.name} (ID: {writer.id})
+                  </option>
+                ))}
+              </optgroup>
             </select>
           );
         }
-        return <span>{opinion.work_id}</span>;
-      },
-    },
-    {
-      key: "work_title",
-      label: "Work Title",
-      sortable: false,
-      render: (opinion) => {
-        return <span className="text-gray-600">{getWorkTitle(opinion.work_id)}</span>;
+        return (
+          <span className="text-gray-600">
+            {opinion.target_type === "writer" ? "Writer: " : "Work: "}
+            {getTargetLabel(opinion)}
+          </span>
+        );
       },
     },
     {
-      key: "sentiment",
+      key: "sentiment_grade",
       label: "Sentiment",
       sortable: true,
       render: (opinion, isEditing) => {
         if (isEditing) {
           return (
-            <div className="flex gap-4">
-              <label className="flex items-center">
-                <input
-                  type="radio"
-                  name={`sentiment-${getOpinionKey(opinion)}`}
-                  value="true"
-                  checked={formData.sentiment === "true"}
-                  onChange={(e) => setFormData({ ...formData, sentiment: e.target.value })}
-                  className="mr-2"
-                />
-                <span className="text-green-600">Positive</span>
-              </label>
-              <label className="flex items-center">
-                <input
-                  type="radio"
-                  name={`sentiment-${getOpinionKey(opinion)}`}
-                  value="false"
-                  checked={formData.sentiment === "false"}
-                  onChange={(e) => setFormData({ ...formData, sentiment: e.target.value })}
-                  className="mr-2"
-                />
-                <span className="text-red-600">Negative</span>
-              </label>
-            </div>
+            <select
+              name={`sentiment-${opinion.id}`}
+              value={formData.sentiment}
+              onChange={(e) => setFormData({ ...formData, sentiment: e.target.value })}
+              className="w-full px-2 py-1 border border-gray-300 rounded focus:outline-none focus:ring-2 focus:ring-blue-500"
+            >
+              {SENTIMENT_GRADES.map((grade) => (
+                <option key={grade.value} value={grade.value}>
+                  {grade.value} {grade.label}
+                </option>
+              ))}
+            </select>
           );
         }
-        return (
-          <span className={`font-medium ${opinion.sentiment ? "text-green-600" : "text-red-600"}`}>
-            {opinion.sentiment ? "Positive" : "Negative"}
-          </span>
-        );
+        return <SentimentLabel grade={opinion.sentiment_grade} />;
       },
     },
     {
@@ -545,7 +558,7 @@ export default function OpinionsAdminPage(): React.JSX.Element {
   ];
 
   const displayData =
-    editingId === "new" ? [{ writer_id: -1, work_id: -1 } as Opinion, ...opinions] : opinions;
+    editingId === "new" ? [{ id: -1 } as Opinion, ...opinions] : opinions;
 
   return (
     <div className="flex flex-col h-full">
@@ -562,10 +575,8 @@ export default function OpinionsAdminPage(): React.JSX.Element {
         )}
 
         <div className="mb-4 flex gap-3">
-          <Button onClick={handleExportCSV} disabled={isLoading || opinions.length === 0}>
-            Export CSV
-          </Button>
-          <Button variant="secondary" onClick={handleImportCSV} disabled={isLoading}>
+          <Button onClick={handleExportCSV}>Export CSV</Button>
+          <Button variant="secondary" onClick={handleImportCSV} disabled={isLoading || isImporting}>
             Import CSV
           </Button>
           <input
@@ -577,101 +588,28 @@ export default function OpinionsAdminPage(): React.JSX.Element {
           />
         </div>
 
-        {showImportPreview && importPreview && (
-          <div className="mb-4 bg-white p-4 rounded-lg shadow-sm border border-gray-200">
-            <h3 className="text-lg font-semibold mb-2">Import Preview</h3>
-            <p className="text-sm text-gray-600 mb-4">
-              {importPreview.data.length} rows to import
-              {importPreview.errors.length > 0 && (
-                <span className="text-red-600 ml-2">({importPreview.errors.length} errors)</span>
-              )}
-            </p>
-            {importPreview.errors.length > 0 && (
-              <div className="mb-4 max-h-40 overflow-y-auto">
-                <p className="text-sm font-medium text-red-600 mb-2">Validation Errors:</p>
-                <ul className="list-disc list-inside text-sm text-red-600">
-                  {importPreview.errors.map((error, index) => (
-                    <li key={index}>
-                      Row {error.row}, {error.field}: {error.message}
-                    </li>
-                  ))}
-                </ul>
-              </div>
-            )}
-            {importPreview.data.length > 0 && (
-              <div className="mb-4 max-h-60 overflow-y-auto border border-gray-200 rounded">
-                <table className="min-w-full divide-y divide-gray-200">
-                  <thead className="bg-gray-50">
-                    <tr>
-                      <th className="px-4 py-2 text-left text-xs font-medium text-gray-500 uppercase tracking-wider">
-                        Writer ID
-                      </th>
-                      <th className="px-4 py-2 text-left text-xs font-medium text-gray-500 uppercase tracking-wider">
-                        Work ID
-                      </th>
-                      <th className="px-4 py-2 text-left text-xs font-medium text-gray-500 uppercase tracking-wider">
-                        Sentiment
-                      </th>
-                      <th className="px-4 py-2 text-left text-xs font-medium text-gray-500 uppercase tracking-wider">
-                        Quote
-                      </th>
-                      <th className="px-4 py-2 text-left text-xs font-medium text-gray-500 uppercase tracking-wider">
-                        Source
-                      </th>
-                    </tr>
-                  </thead>
-                  <tbody className="bg-white divide-y divide-gray-200">
-                    {importPreview.data.slice(0, 20).map((opinion, index) => (
-                      <tr key={index}>
-                        <td className="px-4 py-2 text-sm text-gray-900">{opinion.writer_id}</td>
-                        <td className="px-4 py-2 text-sm text-gray-900">{opinion.work_id}</td>
-                        <td className="px-4 py-2 text-sm text-gray-900">
-                          {opinion.sentiment ? (
-                            <span className="text-green-600">Positive</span>
-                          ) : (
-                            <span className="text-red-600">Negative</span>
-                          )}
-                        </td>
-                        <td className="px-4 py-2 text-sm text-gray-600">
-                          {opinion.quote.length > 30
-                            ? `${opinion.quote.substring(0, 30)}...`
-                            : opinion.quote}
-                        </td>
-                        <td className="px-4 py-2 text-sm text-gray-600">
-                          {opinion.source.length > 20
-                            ? `${opinion.source.substring(0, 20)}...`
-                            : opinion.source}
-                        </td>
-                      </tr>
-                    ))}
-                  </tbody>
-                </table>
-                {importPreview.data.length > 20 && (
-                  <p className="px-4 py-2 text-sm text-gray-500 bg-gray-50">
-                    ... and {importPreview.data.length - 20} more rows
-                  </p>
-                )}
-              </div>
-            )}
-            {importPreview.data.length === 0 && importPreview.errors.length === 0 && (
-              <p className="text-sm text-gray-500 mb-4">No valid data found in CSV file.</p>
-            )}
-            <div className="flex gap-3">
-              <Button onClick={handleImportConfirm} disabled={!importPreview.isValid}>
-                Confirm Import
-              </Button>
-              <Button variant="secondary" onClick={() => setShowImportPreview(false)}>
-                Cancel
-              </Button>
-            </div>
+        {importError && (
+          <div className="mb-4">
+            <ErrorMessage message={importError} onDismiss={() => setImportError(null)} />
           </div>
         )}
 
+        {importPreview && (
+          <ImportPreview
+            report={importPreview}
+            onConflict={onConflict}
+            onConflictChange={handleConflictChange}
+            onConfirm={handleImportConfirm}
+            onCancel={handleImportCancel}
+            isLoading={isImporting}
+          />
+        )}
+
         {formErrors.writer_id && (
           <div className="mb-2 text-sm text-red-600">Writer: {formErrors.writer_id}</div>
         )}
-        {formErrors.work_id && (
-          <div className="mb-2 text-sm text-red-600">Work: {formErrors.work_id}</div>
+        {formErrors.target && (
+          <div className="mb-2 text-sm text-red-600">About: {formErrors.target}</div>
         )}
         {formErrors.quote && (
           <div className="mb-2 text-sm text-red-600">Quote: {formErrors.quote}</div>
@@ -696,7 +634,7 @@ export default function OpinionsAdminPage(): React.JSX.Element {
             onDelete={handleDeleteClick}
             onCreate={handleCreate}
             isLoading={isLoading}
-            getRowId={(opinion) => getOpinionKey(opinion)}
+            getRowId={(opinion) => opinion.id}
           />
         </div>
 
@@ -730,7 +668,7 @@ export default function OpinionsAdminPage(): React.JSX.Element {
         entityName="opinion"
         entityDetails={
           opinionToDelete
-            ? `Writer ${getWriterName(opinionToDelete.writer_id)} about "${getWorkTitle(opinionToDelete.work_id)}"`
+            ? `Writer ${getWriterName(opinionToDelete.writer_id)} about "${getTargetLabel(opinionToDelete)}"`
             : ""
         }
         isLoading={isLoading}
@@ -739,3 +677,13 @@ export default function OpinionsAdminPage(): React.JSX.Element {
     </div>
   );
 }
+
+function SentimentLabel({ grade }: { grade: SentimentGrade }): React.JSX.Element {
+  const label = SENTIMENT_GRADES.find((g) => g.value === grade)?.label ?? grade;
+  const color = grade.startsWith("+")
+    ? "text-green-600"
+    : grade.startsWith("-")
+      ? "text-red-600"
+      : "text-gray-600";
+  return <span className={`font-medium ${color}`}>{label}</span>;
+}
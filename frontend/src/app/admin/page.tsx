@@ -1,3 +1,7 @@
+import { ApiTokenForm } from "@/components/admin/ApiTokenForm";
+import { ExportService } from "@/services/exportService";
+import { EXPORT_FORMATS } from "@/types/export";
+
 export default function AdminHome(): React.JSX.Element {
   return (
     <div className="flex flex-col h-full">
@@ -8,14 +12,32 @@ export default function AdminHome(): React.JSX.Element {
 
       <div className="flex-1 p-6 overflow-auto">
         <div className="max-w-4xl">
+          <ApiTokenForm />
           <p className="text-gray-600 mb-6">
             Use the sidebar to navigate to Writers, Works, or Opinions management pages.
           </p>
+          <div className="mb-6">
+            <h2 className="font-semibold text-gray-900 mb-2">Full Export</h2>
+            <p className="text-sm text-gray-600 mb-3">
+              Every writer, work, opinion and source, one file each in a zip archive.
+            </p>
+            <div className="flex gap-3">
+              {EXPORT_FORMATS.map(({ value, label }) => (
+                <a
+                  key={value}
+                  href={ExportService.exportURL("all", value)}
+                  className="px-4 py-2 text-sm font-medium text-blue-700 bg-white border border-blue-300 rounded-md hover:bg-blue-50"
+                >
+                  {label}
+                </a>
+              ))}
+            </div>
+          </div>
           <div className="bg-blue-50 border border-blue-200 rounded-lg p-4">
             <h2 className="font-semibold text-blue-900 mb-2">Quick Tips</h2>
             <ul className="text-sm text-blue-800 space-y-1 list-disc list-inside">
               <li>All data tables support inline editing</li>
-              <li>Use CSV import/export for bulk operations</li>
+              <li>Use CSV import/export for bulk operations; exports cover every row</li>
               <li>Click Edit on any row to modify data</li>
             </ul>
           </div>
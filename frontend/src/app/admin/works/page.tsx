@@ -2,18 +2,16 @@
 
 import { type Column, DataTable } from "@/components/admin/DataTable";
 import { DeleteConfirmDialog } from "@/components/admin/DeleteConfirmDialog";
+import { ImportPreview } from "@/components/admin/ImportPreview";
 import { Button } from "@/components/common/Button";
 import { ErrorMessage } from "@/components/common/ErrorMessage";
+import { ExportService } from "@/services/exportService";
+import { ImportService } from "@/services/importService";
 import { WriterService } from "@/services/writerService";
 import { useWorkStore } from "@/stores/workStore";
+import type { ConflictPolicy, ImportReport } from "@/types/import";
 import type { CreateWorkRequest, UpdateWorkRequest, Work } from "@/types/work";
 import type { Writer } from "@/types/writer";
-import {
-  type CSVImportResult,
-  downloadCSV,
-  exportWorksToCSV,
-  importWorksFromCSV,
-} from "@/utils/csvUtils";
 import React, { useEffect, useRef, useState } from "react";
 
 interface WorkFormData {
@@ -36,19 +34,22 @@ export default function WorksAdminPage(): React.JSX.Element {
   const [formErrors, setFormErrors] = useState<Partial<Record<keyof WorkFormData, string>>>({});
   const [deleteConfirmOpen, setDeleteConfirmOpen] = useState<boolean>(false);
   const [workToDelete, setWorkToDelete] = useState<Work | null>(null);
-  const [importPreview, setImportPreview] = useState<CSVImportResult<Work> | null>(null);
-  const [showImportPreview, setShowImportPreview] = useState<boolean>(false);
+  const [importCSV, setImportCSV] = useState<string | null>(null);
+  const [importPreview, setImportPreview] = useState<ImportReport | null>(null);
+  const [onConflict, setOnConflict] = useState<ConflictPolicy>("error");
+  const [isImporting, setIsImporting] = useState<boolean>(false);
+  const [importError, setImportError] = useState<string | null>(null);
   const fileInputRef = useRef<HTMLInputElement>(null);
   const firstInputRef = useRef<HTMLInputElement>(null);
 
   useEffect(() => {
-    void fetchWorks(1000, 0);
+    void fetchWorks();
   }, [fetchWorks]);
 
   useEffect(() => {
     const loadWriters = async (): Promise<void> => {
       try {
-        const writersData = await WriterService.list(1000, 0);
+        const writersData = await WriterService.listAll();
         setWriters(writersData);
       } catch (error) {
         console.error("Failed to load writers:", error);
@@ -68,13 +69,16 @@ export default function WorksAdminPage(): React.JSX.Element {
     return writer ? writer.name : `ID: ${authorId}`;
   };
 
+  const getAuthorNames = (work: Work): string =>
+    work.author_ids.length > 0 ? work.author_ids.map(getAuthorName).join(", ") : "Anonymous";
+
   const handleEdit = (id: number | string): void => {
     const work = works.find((w) => w.id === Number(id));
     if (work) {
       setEditingId(work.id);
       setFormData({
         title: work.title,
-        author_id: work.author_id.toString(),
+        author_id: work.author_id?.toString() ?? "",
       });
       setFormErrors({});
     }
@@ -128,22 +132,27 @@ export default function WorksAdminPage(): React.JSX.Element {
 
       const result = await createWork(createData);
       if (result) {
-        await fetchWorks(1000, 0);
+        await fetchWorks();
         // Exit edit mode after successful creation
         setEditingId(null);
         setFormData(initialFormData);
         setFormErrors({});
       }
     } else {
+      // The form edits the title and first author; keep the co-authors it
+      // does not show, since an update replaces them. Details left out of
+      // an update are kept.
+      const existing = works.find((w) => w.id === Number(id));
+      const coAuthors = existing ? existing.author_ids.slice(1).filter((a) => a !== authorId) : [];
       const updateData: UpdateWorkRequest = {
         title: formData.title.trim(),
-        author_id: authorId,
+        author_ids: [authorId, ...coAuthors],
       };
 
       await updateWork(Number(id), updateData);
       setEditingId(null);
       setFormData(initialFormData);
-      await fetchWorks(1000, 0);
+      await fetchWorks();
     }
   };
 
@@ -176,22 +185,36 @@ export default function WorksAdminPage(): React.JSX.Element {
       if (!stillExists) {
         setDeleteConfirmOpen(false);
         setWorkToDelete(null);
-        void fetchWorks(1000, 0);
+        void fetchWorks();
       }
     }
     // eslint-disable-next-line react-hooks/exhaustive-deps
   }, [deleteConfirmOpen, workToDelete, isLoading, error, works]);
 
   const handleExportCSV = (): void => {
-    const csvContent = exportWorksToCSV(works);
-    const filename = `works-${new Date().toISOString().split("T")[0]}.csv`;
-    downloadCSV(csvContent, filename);
+    ExportService.download("works");
   };
 
   const handleImportCSV = (): void => {
     fileInputRef.current?.click();
   };
 
+  // Every import is tried as a dry run first; the server checks each row
+  // and reports what it would do without writing anything
+  const previewImport = async (csv: string, policy: ConflictPolicy): Promise<void> => {
+    setIsImporting(true);
+    setImportError(null);
+    try {
+      const report = await ImportService.importCSV("works", csv, { dryRun: true, onConflict: policy });
+      setImportCSV(csv);
+      setImportPreview(report);
+    } catch (err) {
+      setImportError(err instanceof Error ? err.message : "Failed to import CSV");
+    } finally {
+      setIsImporting(false);
+    }
+  };
+
   const handleFileChange = (e: React.ChangeEvent<HTMLInputElement>): void => {
     const file = e.target.files?.[0];
     if (!file) {
@@ -200,10 +223,7 @@ export default function WorksAdminPage(): React.JSX.Element {
 
     const reader = new FileReader();
     reader.onload = (event) => {
-      const csvString = event.target?.result as string;
-      const result = importWorksFromCSV(csvString);
-      setImportPreview(result);
-      setShowImportPreview(true);
+      void previewImport(event.target?.result as string, onConflict);
     };
     reader.readAsText(file);
 
@@ -212,36 +232,42 @@ export default function WorksAdminPage(): React.JSX.Element {
     }
   };
 
+  const handleConflictChange = (policy: ConflictPolicy): void => {
+    setOnConflict(policy);
+    if (importCSV) {
+      void previewImport(importCSV, policy);
+    }
+  };
+
+  const handleImportCancel = (): void => {
+    setImportCSV(null);
+    setImportPreview(null);
+  };
+
   const handleImportConfirm = async (): Promise<void> => {
-    if (!importPreview) {
+    if (!importCSV) {
       return;
     }
 
-    let successCount = 0;
-    let errorCount = 0;
-
-    for (const work of importPreview.data) {
-      try {
-        const createData: CreateWorkRequest = {
-          title: work.title,
-          author_id: work.author_id,
-        };
-        const result = await createWork(createData);
-        if (result) {
-          successCount++;
-        } else {
-          errorCount++;
-        }
-      } catch {
-        errorCount++;
+    setIsImporting(true);
+    setImportError(null);
+    try {
+      const report = await ImportService.importCSV("works", importCSV, { dryRun: false, onConflict });
+      if (!report.applied) {
+        // The records changed since the preview
+        setImportPreview(report);
+        return;
       }
+      handleImportCancel();
+      void fetchWorks();
+      alert(
+        `Import completed: ${report.created} created, ${report.updated} updated, ${report.skipped} skipped`
+      );
+    } catch (err) {
+      setImportError(err instanceof Error ? err.message : "Failed to import CSV");
+    } finally {
+      setIsImporting(false);
     }
-
-    setShowImportPreview(false);
-    setImportPreview(null);
-    void fetchWorks(1000, 0);
-
-    alert(`Import completed: ${successCount} successful, ${errorCount} failed`);
   };
 
   const columns: Column<Work>[] = [
@@ -305,7 +331,7 @@ export default function WorksAdminPage(): React.JSX.Element {
       label: "Author Name",
       sortable: false,
       render: (work) => {
-        return <span className="text-gray-600">{getAuthorName(work.author_id)}</span>;
+        return <span className="text-gray-600">{getAuthorNames(work)}</span>;
       },
     },
   ];
@@ -327,10 +353,8 @@ export default function WorksAdminPage(): React.JSX.Element {
         )}
 
         <div className="mb-4 flex gap-3">
-          <Button onClick={handleExportCSV} disabled={isLoading || works.length === 0}>
-            Export CSV
-          </Button>
-          <Button variant="secondary" onClick={handleImportCSV} disabled={isLoading}>
+          <Button onClick={handleExportCSV}>Export CSV</Button>
+          <Button variant="secondary" onClick={handleImportCSV} disabled={isLoading || isImporting}>
             Import CSV
           </Button>
           <input
@@ -342,70 +366,23 @@ export default function WorksAdminPage(): React.JSX.Element {
           />
         </div>
 
-        {showImportPreview && importPreview && (
-          <div className="mb-4 bg-white p-4 rounded-lg shadow-sm border border-gray-200">
-            <h3 className="text-lg font-semibold mb-2">Import Preview</h3>
-            <p className="text-sm text-gray-600 mb-4">
-              {importPreview.data.length} rows to import
-              {importPreview.errors.length > 0 && (
-                <span className="text-red-600 ml-2">({importPreview.errors.length} errors)</span>
-              )}
-            </p>
-            {importPreview.errors.length > 0 && (
-              <div className="mb-4 max-h-40 overflow-y-auto">
-                <p className="text-sm font-medium text-red-600 mb-2">Validation Errors:</p>
-                <ul className="list-disc list-inside text-sm text-red-600">
-                  {importPreview.errors.map((error, index) => (
-                    <li key={index}>
-                      Row {error.row}, {error.field}: {error.message}
-                    </li>
-                  ))}
-                </ul>
-              </div>
-            )}
-            {importPreview.data.length > 0 && (
-              <div className="mb-4 max-h-60 overflow-y-auto border border-gray-200 rounded">
-                <table className="min-w-full divide-y divide-gray-200">
-                  <thead className="bg-gray-50">
-                    <tr>
-                      <th className="px-4 py-2 text-left text-xs font-medium text-gray-500 uppercase tracking-wider">
-                        Title
-                      </th>
-                      <th className="px-4 py-2 text-left text-xs font-medium text-gray-500 uppercase tracking-wider">
-                        Author ID
-                      </th>
-                    </tr>
-                  </thead>
-                  <tbody className="bg-white divide-y divide-gray-200">
-                    {importPreview.data.slice(0, 20).map((work, index) => (
-                      <tr key={index}>
-                        <td className="px-4 py-2 text-sm text-gray-900">{work.title}</td>
-                        <td className="px-4 py-2 text-sm text-gray-900">{work.author_id}</td>
-                      </tr>
-                    ))}
-                  </tbody>
-                </table>
-                {importPreview.data.length > 20 && (
-                  <p className="px-4 py-2 text-sm text-gray-500 bg-gray-50">
-                    ... and {importPreview.data.length - 20} more rows
-                  </p>
-                )}
-              </div>
-            )}
-            {importPreview.data.length === 0 && importPreview.errors.length === 0 && (
-              <p className="text-sm text-gray-500 mb-4">No valid data found in CSV file.</p>
-            )}
-            <div className="flex gap-3">
-              <Button onClick={handleImportConfirm} disabled={!importPreview.isValid}>
-                Confirm Import
-              </Button>
-              <Button variant="secondary" onClick={() => setShowImportPreview(false)}>
-                Cancel
-              </Button>
-            </div>
+        {importError && (
+          <div className="mb-4">
+            <ErrorMessage message={importError} onDismiss={() => setImportError(null)} />
           </div>
         )}
 
+        {importPreview && (
+          <ImportPreview
+            report={importPreview}
+            onConflict={onConflict}
+            onConflictChange={handleConflictChange}
+            onConfirm={handleImportConfirm}
+            onCancel={handleImportCancel}
+            isLoading={isImporting}
+          />
+        )}
+
         {formErrors.title && (
           <div className="mb-2 text-sm text-red-600">Title: {formErrors.title}</div>
         )}
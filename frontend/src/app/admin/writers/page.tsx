@@ -2,16 +2,14 @@
 
 import { type Column, DataTable } from "@/components/admin/DataTable";
 import { DeleteConfirmDialog } from "@/components/admin/DeleteConfirmDialog";
+import { ImportPreview } from "@/components/admin/ImportPreview";
 import { Button } from "@/components/common/Button";
 import { ErrorMessage } from "@/components/common/ErrorMessage";
+import { ExportService } from "@/services/exportService";
+import { ImportService } from "@/services/importService";
 import { useWriterStore } from "@/stores/writerStore";
+import type { ConflictPolicy, ImportReport } from "@/types/import";
 import type { CreateWriterRequest, UpdateWriterRequest, Writer } from "@/types/writer";
-import {
-  type CSVImportResult,
-  downloadCSV,
-  exportWritersToCSV,
-  importWritersFromCSV,
-} from "@/utils/csvUtils";
 import React, { useEffect, useRef, useState } from "react";
 
 interface WriterFormData {
@@ -45,13 +43,16 @@ export default function WritersAdminPage(): React.JSX.Element {
   const [formErrors, setFormErrors] = useState<Partial<Record<keyof WriterFormData, string>>>({});
   const [deleteConfirmOpen, setDeleteConfirmOpen] = useState<boolean>(false);
   const [writerToDelete, setWriterToDelete] = useState<Writer | null>(null);
-  const [importPreview, setImportPreview] = useState<CSVImportResult<Writer> | null>(null);
-  const [showImportPreview, setShowImportPreview] = useState<boolean>(false);
+  const [importCSV, setImportCSV] = useState<string | null>(null);
+  const [importPreview, setImportPreview] = useState<ImportReport | null>(null);
+  const [onConflict, setOnConflict] = useState<ConflictPolicy>("error");
+  const [isImporting, setIsImporting] = useState<boolean>(false);
+  const [importError, setImportError] = useState<string | null>(null);
   const fileInputRef = useRef<HTMLInputElement>(null);
   const firstInputRef = useRef<HTMLInputElement>(null);
 
   useEffect(() => {
-    void fetchWriters(1000, 0); // Fetch a large number for admin view
+    void fetchWriters();
   }, [fetchWriters]);
 
   useEffect(() => {
@@ -132,7 +133,7 @@ export default function WritersAdminPage(): React.JSX.Element {
 
       const result = await createWriter(createData);
       if (result) {
-        await fetchWriters(1000, 0);
+        await fetchWriters();
         // Exit edit mode after successful creation
         setEditingId(null);
         setFormData(initialFormData);
@@ -149,7 +150,7 @@ export default function WritersAdminPage(): React.JSX.Element {
       await updateWriter(Number(id), updateData);
       setEditingId(null);
       setFormData(initialFormData);
-      await fetchWriters(1000, 0);
+      await fetchWriters();
     }
   };
 
@@ -189,22 +190,36 @@ export default function WritersAdminPage(): React.JSX.Element {
       if (!stillExists) {
         setDeleteConfirmOpen(false);
         setWriterToDelete(null);
-        void fetchWriters(1000, 0);
+        void fetchWriters();
       }
     }
     // eslint-disable-next-line react-hooks/exhaustive-deps
   }, [deleteConfirmOpen, writerToDelete, isLoading, error, writers]);
 
   const handleExportCSV = (): void => {
-    const csvContent = exportWritersToCSV(writers);
-    const filename = `writers-${new Date().toISOString().split("T")[0]}.csv`;
-    downloadCSV(csvContent, filename);
+    ExportService.download("writers");
   };
 
   const handleImportCSV = (): void => {
     fileInputRef.current?.click();
   };
 
+  // Every import is tried as a dry run first; the server checks each row
+  // and reports what it would do without writing anything
+  const previewImport = async (csv: string, policy: ConflictPolicy): Promise<void> => {
+    setIsImporting(true);
+    setImportError(null);
+    try {
+      const report = await ImportService.importCSV("writers", csv, { dryRun: true, onConflict: policy });
+      setImportCSV(csv);
+      setImportPreview(report);
+    } catch (err) {
+      setImportError(err instanceof Error ? err.message : "Failed to import CSV");
+    } finally {
+      setIsImporting(false);
+    }
+  };
+
   const handleFileChange = (e: React.ChangeEvent<HTMLInputElement>): void => {
     const file = e.target.files?.[0];
     if (!file) {
@@ -213,51 +228,51 @@ export default function WritersAdminPage(): React.JSX.Element {
 
     const reader = new FileReader();
     reader.onload = (event) => {
-      const csvString = event.target?.result as string;
-      const result = importWritersFromCSV(csvString);
-      setImportPreview(result);
-      setShowImportPreview(true);
+      void previewImport(event.target?.result as string, onConflict);
     };
     reader.readAsText(file);
 
-    // Reset file input
     if (fileInputRef.current) {
       fileInputRef.current.value = "";
     }
   };
 
+  const handleConflictChange = (policy: ConflictPolicy): void => {
+    setOnConflict(policy);
+    if (importCSV) {
+      void previewImport(importCSV, policy);
+    }
+  };
+
+  const handleImportCancel = (): void => {
+    setImportCSV(null);
+    setImportPreview(null);
+  };
+
   const handleImportConfirm = async (): Promise<void> => {
-    if (!importPreview) {
+    if (!importCSV) {
       return;
     }
 
-    let successCount = 0;
-    let errorCount = 0;
-
-    for (const writer of importPreview.data) {
-      try {
-        const createData: CreateWriterRequest = {
-          name: writer.name,
-          birth_year: writer.birth_year,
-          death_year: writer.death_year,
-          bio: writer.bio,
-        };
-        const result = await createWriter(createData);
-        if (result) {
-          successCount++;
-        } else {
-          errorCount++;
-        }
-      } catch {
-        errorCount++;
+    setIsImporting(true);
+    setImportError(null);
+    try {
+      const report = await ImportService.importCSV("writers", importCSV, { dryRun: false, onConflict });
+      if (!report.applied) {
+        // The records changed since the preview
+        setImportPreview(report);
+        return;
       }
+      handleImportCancel();
+      void fetchWriters();
+      alert(
+        `Import completed: ${report.created} created, ${report.updated} updated, ${report.skipped} skipped`
+      );
+    } catch (err) {
+      setImportError(err instanceof Error ? err.message : "Failed to import CSV");
+    } finally {
+      setIsImporting(false);
     }
-
-    setShowImportPreview(false);
-    setImportPreview(null);
-    void fetchWriters(1000, 0);
-
-    alert(`Import completed: ${successCount} successful, ${errorCount} failed`);
   };
 
   const truncateText = (text: string | null, maxLength: number): string => {
@@ -380,10 +395,8 @@ export default function WritersAdminPage(): React.JSX.Element {
         )}
 
         <div className="mb-4 flex gap-3">
-          <Button onClick={handleExportCSV} disabled={isLoading || writers.length === 0}>
-            Export CSV
-          </Button>
-          <Button variant="secondary" onClick={handleImportCSV} disabled={isLoading}>
+          <Button onClick={handleExportCSV}>Export CSV</Button>
+          <Button variant="secondary" onClick={handleImportCSV} disabled={isLoading || isImporting}>
             Import CSV
           </Button>
           <input
@@ -395,82 +408,23 @@ export default function WritersAdminPage(): React.JSX.Element {
           />
         </div>
 
-        {showImportPreview && importPreview && (
-          <div className="mb-4 bg-white p-4 rounded-lg shadow-sm border border-gray-200">
-            <h3 className="text-lg font-semibold mb-2">Import Preview</h3>
-            <p className="text-sm text-gray-600 mb-4">
-              {importPreview.data.length} rows to import
-              {importPreview.errors.length > 0 && (
-                <span className="text-red-600 ml-2">({importPreview.errors.length} errors)</span>
-              )}
-            </p>
-            {importPreview.errors.length > 0 && (
-              <div className="mb-4 max-h-40 overflow-y-auto">
-                <p className="text-sm font-medium text-red-600 mb-2">Validation Errors:</p>
-                <ul className="list-disc list-inside text-sm text-red-600">
-                  {importPreview.errors.map((error, index) => (
-                    <li key={index}>
-                      Row {error.row}, {error.field}: {error.message}
-                    </li>
-                  ))}
-                </ul>
-              </div>
-            )}
-            {importPreview.data.length > 0 && (
-              <div className="mb-4 max-h-60 overflow-y-auto border border-gray-200 rounded">
-                <table className="min-w-full divide-y divide-gray-200">
-                  <thead className="bg-gray-50">
-                    <tr>
-                      <th className="px-4 py-2 text-left text-xs font-medium text-gray-500 uppercase tracking-wider">
-                        Name
-                      </th>
-                      <th className="px-4 py-2 text-left text-xs font-medium text-gray-500 uppercase tracking-wider">
-                        Birth Year
-                      </th>
-                      <th className="px-4 py-2 text-left text-xs font-medium text-gray-500 uppercase tracking-wider">
-                        Death Year
-                      </th>
-                      <th className="px-4 py-2 text-left text-xs font-medium text-gray-500 uppercase tracking-wider">
-                        Bio
-                      </th>
-                    </tr>
-                  </thead>
-                  <tbody className="bg-white divide-y divide-gray-200">
-                    {importPreview.data.slice(0, 20).map((writer, index) => (
-                      <tr key={index}>
-                        <td className="px-4 py-2 text-sm text-gray-900">{writer.name}</td>
-                        <td className="px-4 py-2 text-sm text-gray-900">{writer.birth_year}</td>
-                        <td className="px-4 py-2 text-sm text-gray-900">
-                          {writer.death_year ?? "—"}
-                        </td>
-                        <td className="px-4 py-2 text-sm text-gray-600">
-                          {writer.bio ? (writer.bio.length > 50 ? `${writer.bio.substring(0, 50)}...` : writer.bio) : "—"}
-                        </td>
-                      </tr>
-                    ))}
-                  </tbody>
-                </table>
-                {importPreview.data.length > 20 && (
-                  <p className="px-4 py-2 text-sm text-gray-500 bg-gray-50">
-                    ... and {importPreview.data.length - 20} more rows
-                  </p>
-                )}
-              </div>
-            )}
-            {importPreview.data.length === 0 && importPreview.errors.length === 0 && (
-              <p className="text-sm text-gray-500 mb-4">No valid data found in CSV file.</p>
-            )}
-            <div className="flex gap-3">
-              <Button onClick={handleImportConfirm} disabled={!importPreview.isValid}>
-                Confirm Import
-              </Button>
-              <Button variant="secondary" onClick={() => setShowImportPreview(false)}>
-                Cancel
-              </Button>
-            </div>
+        {importError && (
+          <div className="mb-4">
+            <ErrorMessage message={importError} onDismiss={() => setImportError(null)} />
           </div>
         )}
 
+        {importPreview && (
+          <ImportPreview
+            report={importPreview}
+            onConflict={onConflict}
+            onConflictChange={handleConflictChange}
+            onConfirm={handleImportConfirm}
+            onCancel={handleImportCancel}
+            isLoading={isImporting}
+          />
+        )}
+
         {formErrors.name && (
           <div className="mb-2 text-sm text-red-600">Name: {formErrors.name}</div>
         )}
@@ -0,0 +1,47 @@
+"use client";
+
+import { Button } from "@/components/common/Button";
+import { Input } from "@/components/common/Input";
+import { getAuthToken, setAuthToken } from "@/services/authToken";
+import React, { useEffect, useState } from "react";
+
+export const ApiTokenForm: React.FC = (): React.JSX.Element => {
+  const [token, setToken] = useState("");
+  const [saved, setSaved] = useState(false);
+
+  useEffect(() => {
+    const stored = getAuthToken();
+    if (stored) {
+      setToken(stored);
+      setSaved(true);
+    }
+  }, []);
+
+  const handleSave = (): void => {
+    setAuthToken(token.trim() || null);
+    setSaved(token.trim() !== "");
+  };
+
+  const handleClear = (): void => {
+    setAuthToken(null);
+    setToken("");
+    setSaved(false);
+  };
+
+  return (
+    <div className="bg-white border border-gray-200 rounded-lg p-4 mb-6">
+      <h2 className="font-semibold text-gray-900 mb-2">API Token</h2>
+      <p className="text-sm text-gray-600 mb-4">
+        Creating and editing requires an editor token; deleting requires an admin token.
+      </p>
+      <Input label="Bearer token" type="password" value={token} onChange={setToken} />
+      <div className="flex items-center gap-2">
+        <Button onClick={handleSave}>Save</Button>
+        <Button variant="secondary" onClick={handleClear}>
+          Clear
+        </Button>
+        {saved && <span className="text-sm text-green-700">Token saved</span>}
+      </div>
+    </div>
+  );
+};
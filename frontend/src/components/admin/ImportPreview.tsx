@@ -0,0 +1,77 @@
+import { Button } from "@/components/common/Button";
+import { CONFLICT_POLICIES, type ConflictPolicy, type ImportReport } from "@/types/import";
+import React from "react";
+
+interface ImportPreviewProps {
+  report: ImportReport;
+  onConflict: ConflictPolicy;
+  onConflictChange: (policy: ConflictPolicy) => void;
+  onConfirm: () => void;
+  onCancel: () => void;
+  isLoading?: boolean;
+}
+
+// Shows the dry run of an import: what it would do with each row, and the
+// rows that would stop it
+export const ImportPreview: React.FC<ImportPreviewProps> = ({
+  report,
+  onConflict,
+  onConflictChange,
+  onConfirm,
+  onCancel,
+  isLoading = false,
+}): React.JSX.Element => {
+  const isValid = report.errors.length === 0;
+
+  return (
+    <div className="mb-4 bg-white p-4 rounded-lg shadow-sm border border-gray-200">
+      <h3 className="text-lg font-semibold mb-2">Import Preview</h3>
+      <p className="text-sm text-gray-600 mb-4">
+        {report.rows} rows: {report.created} to create, {report.updated} to update,{" "}
+        {report.skipped} to skip
+        {!isValid && <span className="text-red-600 ml-2">({report.errors.length} errors)</span>}
+      </p>
+      <label className="block text-sm text-gray-700 mb-4">
+        Existing records:{" "}
+        <select
+          value={onConflict}
+          onChange={(e) => onConflictChange(e.target.value as ConflictPolicy)}
+          disabled={isLoading}
+          className="ml-2 px-2 py-1 border border-gray-300 rounded focus:outline-none focus:ring-2 focus:ring-blue-500"
+        >
+          {CONFLICT_POLICIES.map((policy) => (
+            <option key={policy.value} value={policy.value}>
+              {policy.label}
+            </option>
+          ))}
+        </select>
+      </label>
+      {!isValid && (
+        <div className="mb-4 max-h-40 overflow-y-auto">
+          <p className="text-sm font-medium text-red-600 mb-2">
+            Validation Errors (nothing will be imported until they are fixed):
+          </p>
+          <ul className="list-disc list-inside text-sm text-red-600">
+            {report.errors.map((error, index) => (
+              <li key={index}>
+                Row {error.row}
+                {error.field && `, ${error.field}`}: {error.message}
+              </li>
+            ))}
+          </ul>
+        </div>
+      )}
+      {report.rows === 0 && (
+        <p className="text-sm text-gray-500 mb-4">No rows found in CSV file.</p>
+      )}
+      <div className="flex gap-3">
+        <Button onClick={onConfirm} disabled={!isValid || report.rows === 0 || isLoading}>
+          {isLoading ? "Importing..." : "Confirm Import"}
+        </Button>
+        <Button variant="secondary" onClick={onCancel} disabled={isLoading}>
+          Cancel
+        </Button>
+      </div>
+    </div>
+  );
+};
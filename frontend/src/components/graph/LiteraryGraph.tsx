@@ -1,8 +1,6 @@
 "use client";
 
-import { OpinionService } from "@/services/opinionService";
-import { WorkService } from "@/services/workService";
-import { WriterService } from "@/services/writerService";
+import { GraphService } from "@/services/graphService";
 import type { Work } from "@/types/work";
 import type { Writer } from "@/types/writer";
 import dynamic from "next/dynamic";
@@ -160,150 +158,53 @@ export const LiteraryGraph: React.FC<LiteraryGraphProps> = ({
         return;
       }
 
-      const nodeMap = new Map<string, GraphNode>();
-      const linkList: GraphLink[] = [];
+      if (selectedWork && (!selectedWork.id || !selectedWork.title)) {
+        // eslint-disable-next-line no-console
+        console.error("Invalid work object:", selectedWork);
+        setError("Invalid work data: missing id or title");
+        setIsLoading(false);
+        return;
+      }
 
-      if (selectedWriter) {
-        // Always add the selected writer node
-        const writerNodeId = `writer-${selectedWriter.id}`;
-        nodeMap.set(writerNodeId, {
-          id: writerNodeId,
-          name: selectedWriter.name,
-          type: "writer",
-          writerId: selectedWriter.id,
-        });
+      // The backend assembles the whole neighbourhood in a single request
+      const graph = selectedWriter
+        ? await GraphService.get({ writerIds: [selectedWriter.id] })
+        : await GraphService.get({ workIds: selectedWork ? [selectedWork.id] : [] });
 
-        try {
-          // Get opinions by this writer
-          const opinions = await OpinionService.getByWriter(selectedWriter.id);
-          
-          if (opinions.length > 0) {
-            // Get unique work IDs from opinions
-            const workIds = [...new Set(opinions.map((o) => o.work_id))];
-            
-            // Load only the works that this writer has opinions about
-            const workResults = await Promise.allSettled(
-              workIds.map((workId) => WorkService.getById(workId))
-            );
-
-            // Add work nodes (filter out failed requests)
-            workResults.forEach((result) => {
-              if (result.status === "fulfilled" && result.value) {
-                const work = result.value;
-                const workNodeId = `work-${work.id}`;
-                nodeMap.set(workNodeId, {
-                  id: workNodeId,
-                  name: work.title,
-                  type: "work",
-                  workId: work.id,
-                });
-              }
-            });
-
-            // Add links from opinions
-            opinions.forEach((opinion) => {
-              const workNodeId = `work-${opinion.work_id}`;
-              if (nodeMap.has(workNodeId)) {
-                linkList.push({
-                  source: writerNodeId,
-                  target: workNodeId,
-                  sentiment: opinion.sentiment,
-                  quote: opinion.quote,
-                  sourceRef: opinion.source,
-                });
-              }
-            });
-          }
-        } catch (err) {
-          console.error("Error loading opinions for writer:", err);
-          // Still show the writer node even if opinions fail to load
-        }
-      } else if (selectedWork) {
-        // Validate work object
-        if (!selectedWork.id || !selectedWork.title) {
-          // eslint-disable-next-line no-console
-          console.error("Invalid work object:", selectedWork);
-          setError("Invalid work data: missing id or title");
-          setIsLoading(false);
+      const selectedNodeId = selectedWriter
+        ? `writer-${selectedWriter.id}`
+        : `work-${selectedWork?.id}`;
+
+      const linkList: GraphLink[] = [];
+      const linkedNodeIds = new Set<string>([selectedNodeId]);
+      graph.edges.forEach((edge) => {
+        if (edge.type !== "opinion" || !edge.opinion) {
           return;
         }
-
-        // Always add the selected work node
-        const workNodeId = `work-${selectedWork.id}`;
-        nodeMap.set(workNodeId, {
-          id: workNodeId,
-          name: selectedWork.title,
-          type: "work",
-          workId: selectedWork.id,
+        linkedNodeIds.add(edge.source);
+        linkedNodeIds.add(edge.target);
+        linkList.push({
+          source: edge.source,
+          target: edge.target,
+          sentiment: edge.opinion.sentiment,
+          quote: edge.opinion.quote,
+          sourceRef: edge.opinion.source,
         });
+      });
 
-        // eslint-disable-next-line no-console
-        console.log("Selected work:", selectedWork);
-
-        try {
-          // Get opinions about this work
-          const opinions = await OpinionService.getByWork(selectedWork.id);
-          
-          // eslint-disable-next-line no-console
-          console.log(`Found ${opinions.length} opinions for work ${selectedWork.id}`);
-          
-          if (opinions.length > 0) {
-            // Get unique writer IDs from opinions
-            const writerIds = [...new Set(opinions.map((o) => o.writer_id))];
-            
-            // eslint-disable-next-line no-console
-            console.log(`Loading ${writerIds.length} writers for work ${selectedWork.id}`);
-            
-            // Load only the writers who have opinions about this work
-            const writerResults = await Promise.allSettled(
-              writerIds.map((writerId) => WriterService.getById(writerId))
-            );
-
-            // Add writer nodes (filter out failed requests)
-            let writersLoaded = 0;
-            writerResults.forEach((result) => {
-              if (result.status === "fulfilled" && result.value) {
-                const writer = result.value;
-                const writerNodeId = `writer-${writer.id}`;
-                nodeMap.set(writerNodeId, {
-                  id: writerNodeId,
-                  name: writer.name,
-                  type: "writer",
-                  writerId: writer.id,
-                });
-                writersLoaded++;
-              } else if (result.status === "rejected") {
-                // eslint-disable-next-line no-console
-                console.error("Failed to load writer:", result.reason);
-              }
-            });
-
-            // eslint-disable-next-line no-console
-            console.log(`Loaded ${writersLoaded} writers successfully`);
-
-            // Add links from opinions
-            opinions.forEach((opinion) => {
-              const writerNodeId = `writer-${opinion.writer_id}`;
-              if (nodeMap.has(writerNodeId)) {
-                linkList.push({
-                  source: writerNodeId,
-                  target: workNodeId,
-                  sentiment: opinion.sentiment,
-                  quote: opinion.quote,
-                  sourceRef: opinion.source,
-                });
-              }
-            });
-          } else {
-            // eslint-disable-next-line no-console
-            console.log(`No opinions found for work ${selectedWork.id}, showing work node only`);
-          }
-        } catch (err) {
-          // eslint-disable-next-line no-console
-          console.error("Error loading opinions for work:", err);
-          // Still show the work node even if opinions fail to load
+      const nodeMap = new Map<string, GraphNode>();
+      graph.nodes.forEach((node) => {
+        if (!linkedNodeIds.has(node.id)) {
+          return;
         }
-      }
+        nodeMap.set(node.id, {
+          id: node.id,
+          name: node.label,
+          type: node.type,
+          writerId: node.writer_id,
+          workId: node.work_id,
+        });
+      });
 
       const finalNodes = Array.from(nodeMap.values());
       setNodes(finalNodes);
@@ -3,6 +3,8 @@
 import { useEffect, useMemo, useState } from "react";
 import { WriterService } from "@/services/writerService";
 import { WorkService } from "@/services/workService";
+import { SearchService } from "@/services/searchService";
+import type { SearchHit } from "@/types/search";
 import type { Writer } from "@/types/writer";
 import type { Work } from "@/types/work";
 
@@ -20,8 +22,7 @@ export const SearchBar: React.FC<SearchBarProps> = ({
   selectedWork,
 }): React.JSX.Element => {
   const [searchQuery, setSearchQuery] = useState<string>("");
-  const [searchType, setSearchType] = useState<"writer" | "work">("writer");
-  const [results, setResults] = useState<(Writer | Work)[]>([]);
+  const [results, setResults] = useState<SearchHit[]>([]);
   const [isLoading, setIsLoading] = useState<boolean>(false);
   const [error, setError] = useState<string | null>(null);
 
@@ -36,13 +37,8 @@ export const SearchBar: React.FC<SearchBarProps> = ({
         setIsLoading(true);
         setError(null);
         try {
-          if (searchType === "writer") {
-            const writersData = await WriterService.search(searchQuery.trim(), 20, 0);
-            setResults(writersData);
-          } else {
-            const worksData = await WorkService.search(searchQuery.trim(), 20, 0);
-            setResults(worksData);
-          }
+          const hits = await SearchService.search(searchQuery.trim(), ["writer", "work"], 20, 0);
+          setResults(hits);
         } catch (err) {
           setError(err instanceof Error ? err.message : "Search failed");
           setResults([]);
@@ -57,22 +53,26 @@ export const SearchBar: React.FC<SearchBarProps> = ({
     return () => {
       clearTimeout(debounceTimer);
     };
-  }, [searchQuery, searchType]);
+  }, [searchQuery]);
 
   const showResults = useMemo(
     () => results.length > 0 && searchQuery.trim().length > 0 && !isLoading,
     [results, searchQuery, isLoading]
   );
 
-  const handleSelect = (item: Writer | Work): void => {
-    if (searchType === "writer") {
-      onWriterSelect(item as Writer);
-      onWorkSelect(null);
-    } else {
-      onWorkSelect(item as Work);
-      onWriterSelect(null);
-    }
+  const handleSelect = async (hit: SearchHit): Promise<void> => {
     setSearchQuery("");
+    try {
+      if (hit.type === "writer") {
+        onWriterSelect(await WriterService.getById(hit.id));
+        onWorkSelect(null);
+      } else {
+        onWorkSelect(await WorkService.getById(hit.id));
+        onWriterSelect(null);
+      }
+    } catch (err) {
+      setError(err instanceof Error ? err.message : "Failed to load selection");
+    }
   };
 
   const handleClear = (): void => {
@@ -93,7 +93,7 @@ export const SearchBar: React.FC<SearchBarProps> = ({
               onFocus={() => {
                 // Focus handled by showResults computed value
               }}
-              placeholder={`Search ${searchType === "writer" ? "writers" : "works"}...`}
+              placeholder="Search writers and works..."
               className="w-full rounded-md border border-gray-300 px-4 py-2 pl-10 focus:border-blue-500 focus:outline-none focus:ring-2 focus:ring-blue-500"
             />
             <div className="absolute left-3 top-1/2 -translate-y-1/2">
@@ -125,25 +125,29 @@ export const SearchBar: React.FC<SearchBarProps> = ({
           )}
           {showResults && (
             <div className="absolute z-10 mt-1 max-h-60 w-full overflow-auto rounded-md border border-gray-200 bg-white shadow-lg">
-              {results.map((item) => (
+              {results.map((hit) => (
                 <button
-                  key={searchType === "writer" ? (item as Writer).id : (item as Work).id}
+                  key={`${hit.type}-${hit.id}`}
                   type="button"
-                  onClick={() => handleSelect(item)}
+                  onClick={() => void handleSelect(hit)}
                   className="w-full px-4 py-2 text-left hover:bg-gray-100"
                 >
-                  {searchType === "writer" ? (
-                    <div>
-                      <div className="font-medium text-gray-900">{(item as Writer).name}</div>
-                      {(item as Writer).birth_year && (
-                        <div className="text-sm text-gray-500">
-                          {(item as Writer).birth_year}
-                          {(item as Writer).death_year ? ` - ${(item as Writer).death_year}` : ""}
-                        </div>
+                  <div className="flex items-baseline gap-2">
+                    <span className="font-medium text-gray-900">{hit.label}</span>
+                    <span className="text-xs uppercase text-gray-400">{hit.type}</span>
+                  </div>
+                  {hit.field !== "name" && hit.field !== "title" && (
+                    <div className="truncate text-sm text-gray-500">
+                      {hit.highlight.map((segment, i) =>
+                        segment.match ? (
+                          <mark key={i} className="bg-yellow-100 text-gray-900">
+                            {segment.text}
+                          </mark>
+                        ) : (
+                          <span key={i}>{segment.text}</span>
+                        )
                       )}
                     </div>
-                  ) : (
-                    <div className="font-medium text-gray-900">{(item as Work).title}</div>
                   )}
                 </button>
               ))}
@@ -151,37 +155,6 @@ export const SearchBar: React.FC<SearchBarProps> = ({
           )}
         </div>
 
-        <div className="flex gap-2">
-          <button
-            type="button"
-            onClick={() => {
-              setSearchType("writer");
-              setSearchQuery("");
-            }}
-            className={`rounded-md px-4 py-2 text-sm font-medium ${
-              searchType === "writer"
-                ? "bg-blue-600 text-white"
-                : "bg-gray-200 text-gray-700 hover:bg-gray-300"
-            }`}
-          >
-            Writers
-          </button>
-          <button
-            type="button"
-            onClick={() => {
-              setSearchType("work");
-              setSearchQuery("");
-            }}
-            className={`rounded-md px-4 py-2 text-sm font-medium ${
-              searchType === "work"
-                ? "bg-blue-600 text-white"
-                : "bg-gray-200 text-gray-700 hover:bg-gray-300"
-            }`}
-          >
-            Works
-          </button>
-        </div>
-
         {(selectedWriter || selectedWork) && (
           <button
             type="button"
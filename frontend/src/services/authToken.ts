@@ -0,0 +1,23 @@
+const STORAGE_KEY = "apiToken";
+
+// The API accepts bearer tokens for write operations. The token is kept in
+// localStorage so that it survives page reloads.
+export function getAuthToken(): string | null {
+  if (typeof window === "undefined") {
+    return null;
+  }
+  return window.localStorage.getItem(STORAGE_KEY);
+}
+
+export function setAuthToken(token: string | null): void {
+  if (token) {
+    window.localStorage.setItem(STORAGE_KEY, token);
+  } else {
+    window.localStorage.removeItem(STORAGE_KEY);
+  }
+}
+
+export function authHeaders(): Record<string, string> {
+  const token = getAuthToken();
+  return token ? { Authorization: `Bearer ${token}` } : {};
+}
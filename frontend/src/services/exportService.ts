@@ -0,0 +1,17 @@
+import type { ExportEntity, ExportFormat } from "@/types/export";
+
+export class ExportService {
+  private static readonly BASE_URL =
+    process.env.NEXT_PUBLIC_API_URL || "http://localhost:8080/api/v1";
+
+  // The server streams every row, not just the page a table shows. Exports
+  // need no token, so navigating to the URL downloads the file.
+  static exportURL(entity: ExportEntity, format: ExportFormat = "csv"): string {
+    const params = new URLSearchParams({ format });
+    return `${this.BASE_URL}/export/${entity}?${params.toString()}`;
+  }
+
+  static download(entity: ExportEntity, format: ExportFormat = "csv"): void {
+    window.location.assign(this.exportURL(entity, format));
+  }
+}
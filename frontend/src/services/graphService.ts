@@ -0,0 +1,34 @@
+import type { GraphData, GraphFilter } from "@/types/graph";
+
+export class GraphService {
+  private static readonly BASE_URL =
+    process.env.NEXT_PUBLIC_API_URL || "http://localhost:8080/api/v1";
+
+  static async get(filter: GraphFilter = {}): Promise<GraphData> {
+    const params = new URLSearchParams();
+    if (filter.writerIds && filter.writerIds.length > 0) {
+      params.set("writer_ids", filter.writerIds.join(","));
+    }
+    if (filter.workIds && filter.workIds.length > 0) {
+      params.set("work_ids", filter.workIds.join(","));
+    }
+    if (filter.sentiment) {
+      params.set("sentiment", filter.sentiment);
+    }
+
+    const query = params.toString();
+    const response = await fetch(`${this.BASE_URL}/graph${query ? `?${query}` : ""}`, {
+      method: "GET",
+      headers: {
+        "Content-Type": "application/json",
+      },
+    });
+
+    if (!response.ok) {
+      const error = await response.json().catch(() => ({ error: response.statusText }));
+      throw new Error(error.error || `GraphService.get failed: ${response.statusText}`);
+    }
+
+    return response.json();
+  }
+}
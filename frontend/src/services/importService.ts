@@ -0,0 +1,34 @@
+import { authHeaders } from "@/services/authToken";
+import type { ImportEntity, ImportOptions, ImportReport } from "@/types/import";
+
+export class ImportService {
+  private static readonly BASE_URL =
+    process.env.NEXT_PUBLIC_API_URL || "http://localhost:8080/api/v1";
+
+  // Sends a CSV file to the import endpoint. The server applies every row or
+  // none; a file with invalid rows comes back as a report rather than an error.
+  static async importCSV(
+    entity: ImportEntity,
+    csv: string,
+    options: ImportOptions
+  ): Promise<ImportReport> {
+    const params = new URLSearchParams({
+      dry_run: String(options.dryRun),
+      on_conflict: options.onConflict,
+    });
+    const response = await fetch(`${this.BASE_URL}/import/${entity}?${params.toString()}`, {
+      method: "POST",
+      headers: {
+        "Content-Type": "text/csv",
+        ...authHeaders(),
+      },
+      body: csv,
+    });
+
+    const body = await response.json().catch(() => ({ error: response.statusText }));
+    if (Array.isArray(body.errors)) {
+      return body as ImportReport;
+    }
+    throw new Error(body.error || `ImportService.importCSV failed: ${response.statusText}`);
+  }
+}
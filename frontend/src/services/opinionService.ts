@@ -1,4 +1,11 @@
-import type { CreateOpinionRequest, Opinion, UpdateOpinionRequest } from "@/types/opinion";
+import { authHeaders } from "@/services/authToken";
+import type {
+  CreateOpinionRequest,
+  Opinion,
+  QuoteMatch,
+  UpdateOpinionRequest,
+} from "@/types/opinion";
+import { MAX_PAGE_SIZE, type Page } from "@/types/page";
 
 export class OpinionService {
   private static readonly BASE_URL =
@@ -9,6 +16,7 @@ export class OpinionService {
       method: "POST",
       headers: {
         "Content-Type": "application/json",
+        ...authHeaders(),
       },
       body: JSON.stringify(params),
     });
@@ -53,7 +61,40 @@ export class OpinionService {
     return response.json();
   }
 
-  static async getByWriterAndWork(writerId: number, workId: number): Promise<Opinion> {
+  // Opinions about the writer as a whole, not about their works
+  static async getAboutWriter(writerId: number): Promise<Opinion[]> {
+    const response = await fetch(`${this.BASE_URL}/opinions/about-writer/${writerId}`, {
+      method: "GET",
+      headers: {
+        "Content-Type": "application/json",
+      },
+    });
+
+    if (!response.ok) {
+      const error = await response.json().catch(() => ({ error: response.statusText }));
+      throw new Error(error.error || `OpinionService.getAboutWriter failed: ${response.statusText}`);
+    }
+
+    return response.json();
+  }
+
+  static async getById(id: number): Promise<Opinion> {
+    const response = await fetch(`${this.BASE_URL}/opinions/${id}`, {
+      method: "GET",
+      headers: {
+        "Content-Type": "application/json",
+      },
+    });
+
+    if (!response.ok) {
+      const error = await response.json().catch(() => ({ error: response.statusText }));
+      throw new Error(error.error || `OpinionService.getById failed: ${response.statusText}`);
+    }
+
+    return response.json();
+  }
+
+  static async getByWriterAndWork(writerId: number, workId: number): Promise<Opinion[]> {
     const response = await fetch(`${this.BASE_URL}/opinions/writer/${writerId}/work/${workId}`, {
       method: "GET",
       headers: {
@@ -71,8 +112,9 @@ export class OpinionService {
     return response.json();
   }
 
-  static async list(limit: number = 10, offset: number = 0): Promise<Opinion[]> {
-    const response = await fetch(`${this.BASE_URL}/opinions?limit=${limit}&offset=${offset}`, {
+  static async list(limit: number = 10, cursor?: string): Promise<Page<Opinion>> {
+    const cursorParam = cursor ? `&cursor=${encodeURIComponent(cursor)}` : "";
+    const response = await fetch(`${this.BASE_URL}/opinions?limit=${limit}${cursorParam}`, {
       method: "GET",
       headers: {
         "Content-Type": "application/json",
@@ -87,15 +129,53 @@ export class OpinionService {
     return response.json();
   }
 
-  static async update(
-    writerId: number,
-    workId: number,
-    params: UpdateOpinionRequest
-  ): Promise<void> {
-    const response = await fetch(`${this.BASE_URL}/opinions/writer/${writerId}/work/${workId}`, {
+  // Follows the cursors through every page
+  static async listAll(): Promise<Opinion[]> {
+    const items: Opinion[] = [];
+    let cursor: string | undefined;
+    do {
+      const page = await this.list(MAX_PAGE_SIZE, cursor);
+      items.push(...page.items);
+      cursor = page.next_cursor ?? undefined;
+    } while (cursor);
+    return items;
+  }
+
+  static async searchQuotes(
+    query: string,
+    language: string = "",
+    limit: number = 10,
+    offset: number = 0
+  ): Promise<QuoteMatch[]> {
+    const params = new URLSearchParams({
+      q: query,
+      limit: String(limit),
+      offset: String(offset),
+    });
+    if (language) {
+      params.set("language", language);
+    }
+    const response = await fetch(`${this.BASE_URL}/opinions/search?${params.toString()}`, {
+      method: "GET",
+      headers: {
+        "Content-Type": "application/json",
+      },
+    });
+
+    if (!response.ok) {
+      const error = await response.json().catch(() => ({ error: response.statusText }));
+      throw new Error(error.error || `OpinionService.searchQuotes failed: ${response.statusText}`);
+    }
+
+    return response.json();
+  }
+
+  static async update(id: number, params: UpdateOpinionRequest): Promise<void> {
+    const response = await fetch(`${this.BASE_URL}/opinions/${id}`, {
       method: "PUT",
       headers: {
         "Content-Type": "application/json",
+        ...authHeaders(),
       },
       body: JSON.stringify(params),
     });
@@ -106,11 +186,12 @@ export class OpinionService {
     }
   }
 
-  static async delete(writerId: number, workId: number): Promise<void> {
-    const response = await fetch(`${this.BASE_URL}/opinions/writer/${writerId}/work/${workId}`, {
+  static async delete(id: number): Promise<void> {
+    const response = await fetch(`${this.BASE_URL}/opinions/${id}`, {
       method: "DELETE",
       headers: {
         "Content-Type": "application/json",
+        ...authHeaders(),
       },
     });
 
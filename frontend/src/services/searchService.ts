@@ -0,0 +1,35 @@
+import type { SearchHit, SearchHitType } from "@/types/search";
+
+export class SearchService {
+  private static readonly BASE_URL =
+    process.env.NEXT_PUBLIC_API_URL || "http://localhost:8080/api/v1";
+
+  static async search(
+    query: string,
+    types: SearchHitType[] = [],
+    limit: number = 20,
+    offset: number = 0
+  ): Promise<SearchHit[]> {
+    const params = new URLSearchParams({
+      q: query,
+      limit: String(limit),
+      offset: String(offset),
+    });
+    if (types.length > 0) {
+      params.set("types", types.join(","));
+    }
+    const response = await fetch(`${this.BASE_URL}/search?${params.toString()}`, {
+      method: "GET",
+      headers: {
+        "Content-Type": "application/json",
+      },
+    });
+
+    if (!response.ok) {
+      const error = await response.json().catch(() => ({ error: response.statusText }));
+      throw new Error(error.error || `SearchService.search failed: ${response.statusText}`);
+    }
+
+    return response.json();
+  }
+}
@@ -1,3 +1,5 @@
+import { authHeaders } from "@/services/authToken";
+import { MAX_PAGE_SIZE, type Page } from "@/types/page";
 import type { CreateWorkRequest, UpdateWorkRequest, Work } from "@/types/work";
 
 export class WorkService {
@@ -9,6 +11,7 @@ export class WorkService {
       method: "POST",
       headers: {
         "Content-Type": "application/json",
+        ...authHeaders(),
       },
       body: JSON.stringify(params),
     });
@@ -57,8 +60,9 @@ export class WorkService {
     return response.json();
   }
 
-  static async list(limit: number = 10, offset: number = 0): Promise<Work[]> {
-    const response = await fetch(`${this.BASE_URL}/works?limit=${limit}&offset=${offset}`, {
+  static async list(limit: number = 10, cursor?: string): Promise<Page<Work>> {
+    const cursorParam = cursor ? `&cursor=${encodeURIComponent(cursor)}` : "";
+    const response = await fetch(`${this.BASE_URL}/works?limit=${limit}${cursorParam}`, {
       method: "GET",
       headers: {
         "Content-Type": "application/json",
@@ -73,10 +77,23 @@ export class WorkService {
     return response.json();
   }
 
-  static async search(query: string, limit: number = 20, offset: number = 0): Promise<Work[]> {
+  // Follows the cursors through every page
+  static async listAll(): Promise<Work[]> {
+    const items: Work[] = [];
+    let cursor: string | undefined;
+    do {
+      const page = await this.list(MAX_PAGE_SIZE, cursor);
+      items.push(...page.items);
+      cursor = page.next_cursor ?? undefined;
+    } while (cursor);
+    return items;
+  }
+
+  static async search(query: string, limit: number = 20, cursor?: string): Promise<Page<Work>> {
     const searchParam = encodeURIComponent(query);
+    const cursorParam = cursor ? `&cursor=${encodeURIComponent(cursor)}` : "";
     const response = await fetch(
-      `${this.BASE_URL}/works?search=${searchParam}&limit=${limit}&offset=${offset}`,
+      `${this.BASE_URL}/works?search=${searchParam}&limit=${limit}${cursorParam}`,
       {
         method: "GET",
         headers: {
@@ -102,6 +119,7 @@ export class WorkService {
       method: "PUT",
       headers: {
         "Content-Type": "application/json",
+        ...authHeaders(),
       },
       body: JSON.stringify(params),
     });
@@ -121,6 +139,7 @@ export class WorkService {
       method: "DELETE",
       headers: {
         "Content-Type": "application/json",
+        ...authHeaders(),
       },
     });
 
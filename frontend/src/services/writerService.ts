@@ -1,3 +1,5 @@
+import { authHeaders } from "@/services/authToken";
+import { MAX_PAGE_SIZE, type Page } from "@/types/page";
 import type { CreateWriterRequest, UpdateWriterRequest, Writer } from "@/types/writer";
 
 export class WriterService {
@@ -9,6 +11,7 @@ export class WriterService {
       method: "POST",
       headers: {
         "Content-Type": "application/json",
+        ...authHeaders(),
       },
       body: JSON.stringify(params),
     });
@@ -41,8 +44,9 @@ export class WriterService {
     return response.json();
   }
 
-  static async list(limit: number = 10, offset: number = 0): Promise<Writer[]> {
-    const response = await fetch(`${this.BASE_URL}/writers?limit=${limit}&offset=${offset}`, {
+  static async list(limit: number = 10, cursor?: string): Promise<Page<Writer>> {
+    const cursorParam = cursor ? `&cursor=${encodeURIComponent(cursor)}` : "";
+    const response = await fetch(`${this.BASE_URL}/writers?limit=${limit}${cursorParam}`, {
       method: "GET",
       headers: {
         "Content-Type": "application/json",
@@ -57,10 +61,23 @@ export class WriterService {
     return response.json();
   }
 
-  static async search(query: string, limit: number = 20, offset: number = 0): Promise<Writer[]> {
+  // Follows the cursors through every page
+  static async listAll(): Promise<Writer[]> {
+    const items: Writer[] = [];
+    let cursor: string | undefined;
+    do {
+      const page = await this.list(MAX_PAGE_SIZE, cursor);
+      items.push(...page.items);
+      cursor = page.next_cursor ?? undefined;
+    } while (cursor);
+    return items;
+  }
+
+  static async search(query: string, limit: number = 20, cursor?: string): Promise<Page<Writer>> {
     const searchParam = encodeURIComponent(query);
+    const cursorParam = cursor ? `&cursor=${encodeURIComponent(cursor)}` : "";
     const response = await fetch(
-      `${this.BASE_URL}/writers?search=${searchParam}&limit=${limit}&offset=${offset}`,
+      `${this.BASE_URL}/writers?search=${searchParam}&limit=${limit}${cursorParam}`,
       {
         method: "GET",
         headers: {
@@ -86,6 +103,7 @@ export class WriterService {
       method: "PUT",
       headers: {
         "Content-Type": "application/json",
+        ...authHeaders(),
       },
       body: JSON.stringify(params),
     });
@@ -105,6 +123,7 @@ export class WriterService {
       method: "DELETE",
       headers: {
         "Content-Type": "application/json",
+        ...authHeaders(),
       },
     });
 
@@ -9,13 +9,15 @@ interface OpinionState {
 }
 
 interface OpinionActions {
-  fetchOpinions: (limit?: number, offset?: number) => Promise<void>;
-  fetchOpinionByWriterAndWork: (writerId: number, workId: number) => Promise<Opinion | null>;
+  // Loads every page
+  fetchOpinions: () => Promise<void>;
+  fetchOpinion: (id: number) => Promise<Opinion | null>;
+  fetchOpinionsByWriterAndWork: (writerId: number, workId: number) => Promise<Opinion[]>;
   fetchOpinionsByWriter: (writerId: number) => Promise<Opinion[]>;
   fetchOpinionsByWork: (workId: number) => Promise<Opinion[]>;
   createOpinion: (params: CreateOpinionRequest) => Promise<Opinion | null>;
-  updateOpinion: (writerId: number, workId: number, params: UpdateOpinionRequest) => Promise<void>;
-  deleteOpinion: (writerId: number, workId: number) => Promise<void>;
+  updateOpinion: (id: number, params: UpdateOpinionRequest) => Promise<void>;
+  deleteOpinion: (id: number) => Promise<void>;
   clearError: () => void;
 }
 
@@ -24,10 +26,10 @@ export const useOpinionStore = create<OpinionState & OpinionActions>((set) => ({
   isLoading: false,
   error: null,
 
-  fetchOpinions: async (limit = 10, offset = 0) => {
+  fetchOpinions: async () => {
     set({ isLoading: true, error: null });
     try {
-      const opinions = await OpinionService.list(limit, offset);
+      const opinions = await OpinionService.listAll();
       set({ opinions, isLoading: false });
     } catch (error) {
       set({
@@ -37,10 +39,10 @@ export const useOpinionStore = create<OpinionState & OpinionActions>((set) => ({
     }
   },
 
-  fetchOpinionByWriterAndWork: async (writerId: number, workId: number) => {
+  fetchOpinion: async (id: number) => {
     set({ isLoading: true, error: null });
     try {
-      const opinion = await OpinionService.getByWriterAndWork(writerId, workId);
+      const opinion = await OpinionService.getById(id);
       set({ isLoading: false });
       return opinion;
     } catch (error) {
@@ -52,6 +54,21 @@ export const useOpinionStore = create<OpinionState & OpinionActions>((set) => ({
     }
   },
 
+  fetchOpinionsByWriterAndWork: async (writerId: number, workId: number) => {
+    set({ isLoading: true, error: null });
+    try {
+      const opinions = await OpinionService.getByWriterAndWork(writerId, workId);
+      set({ isLoading: false });
+      return opinions;
+    } catch (error) {
+      set({
+        error: error instanceof Error ? error.message : "Failed to fetch opinions",
+        isLoading: false,
+      });
+      return [];
+    }
+  },
+
   fetchOpinionsByWriter: async (writerId: number) => {
     set({ isLoading: true, error: null });
     try {
@@ -100,15 +117,13 @@ export const useOpinionStore = create<OpinionState & OpinionActions>((set) => ({
     }
   },
 
-  updateOpinion: async (writerId: number, workId: number, params: UpdateOpinionRequest) => {
+  updateOpinion: async (id: number, params: UpdateOpinionRequest) => {
     set({ isLoading: true, error: null });
     try {
-      await OpinionService.update(writerId, workId, params);
-      const updatedOpinion = await OpinionService.getByWriterAndWork(writerId, workId);
+      await OpinionService.update(id, params);
+      const updatedOpinion = await OpinionService.getById(id);
       set((state) => ({
-        opinions: state.opinions.map((o) =>
-          o.writer_id === writerId && o.work_id === workId ? updatedOpinion : o
-        ),
+        opinions: state.opinions.map((o) => (o.id === id ? updatedOpinion : o)),
         isLoading: false,
       }));
     } catch (error) {
@@ -119,12 +134,12 @@ export const useOpinionStore = create<OpinionState & OpinionActions>((set) => ({
     }
   },
 
-  deleteOpinion: async (writerId: number, workId: number) => {
+  deleteOpinion: async (id: number) => {
     set({ isLoading: true, error: null });
     try {
-      await OpinionService.delete(writerId, workId);
+      await OpinionService.delete(id);
       set((state) => ({
-        opinions: state.opinions.filter((o) => !(o.writer_id === writerId && o.work_id === workId)),
+        opinions: state.opinions.filter((o) => o.id !== id),
         isLoading: false,
       }));
     } catch (error) {
@@ -9,7 +9,8 @@ interface WorkState {
 }
 
 interface WorkActions {
-  fetchWorks: (limit?: number, offset?: number) => Promise<void>;
+  // Loads every page
+  fetchWorks: () => Promise<void>;
   fetchWorkById: (id: number) => Promise<Work | null>;
   fetchWorksByAuthor: (authorId: number) => Promise<Work[]>;
   createWork: (params: CreateWorkRequest) => Promise<Work | null>;
@@ -23,10 +24,10 @@ export const useWorkStore = create<WorkState & WorkActions>((set) => ({
   isLoading: false,
   error: null,
 
-  fetchWorks: async (limit = 10, offset = 0) => {
+  fetchWorks: async () => {
     set({ isLoading: true, error: null });
     try {
-      const works = await WorkService.list(limit, offset);
+      const works = await WorkService.listAll();
       set({ works, isLoading: false });
     } catch (error) {
       set({
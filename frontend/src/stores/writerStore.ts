@@ -9,7 +9,8 @@ interface WriterState {
 }
 
 interface WriterActions {
-  fetchWriters: (limit?: number, offset?: number) => Promise<void>;
+  // Loads every page
+  fetchWriters: () => Promise<void>;
   fetchWriterById: (id: number) => Promise<Writer | null>;
   createWriter: (params: CreateWriterRequest) => Promise<Writer | null>;
   updateWriter: (id: number, params: UpdateWriterRequest) => Promise<void>;
@@ -22,10 +23,10 @@ export const useWriterStore = create<WriterState & WriterActions>((set) => ({
   isLoading: false,
   error: null,
 
-  fetchWriters: async (limit = 10, offset = 0) => {
+  fetchWriters: async () => {
     set({ isLoading: true, error: null });
     try {
-      const writers = await WriterService.list(limit, offset);
+      const writers = await WriterService.listAll();
       set({ writers, isLoading: false });
     } catch (error) {
       set({
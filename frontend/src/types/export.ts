@@ -0,0 +1,11 @@
+export type ExportEntity = "writers" | "works" | "opinions" | "sources" | "all";
+
+// csv has the columns the import reads; json and ndjson also carry the
+// translations of quotes
+export type ExportFormat = "csv" | "json" | "ndjson";
+
+export const EXPORT_FORMATS: { value: ExportFormat; label: string }[] = [
+  { value: "csv", label: "CSV" },
+  { value: "json", label: "JSON" },
+  { value: "ndjson", label: "NDJSON" },
+];
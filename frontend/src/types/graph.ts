@@ -0,0 +1,34 @@
+import type { Opinion } from "@/types/opinion";
+
+export interface GraphNodeData {
+  id: string;
+  type: "writer" | "work";
+  label: string;
+  writer_id?: number;
+  birth_year?: number;
+  death_year?: number | null;
+  work_id?: number;
+  author_id?: number | null;
+  author_ids?: number[];
+}
+
+// Opinion edges point at a work, or at a writer for opinions about the
+// writer as a whole.
+export interface GraphEdgeData {
+  id: string;
+  type: "authored" | "opinion";
+  source: string;
+  target: string;
+  opinion?: Opinion;
+}
+
+export interface GraphData {
+  nodes: GraphNodeData[];
+  edges: GraphEdgeData[];
+}
+
+export interface GraphFilter {
+  writerIds?: number[];
+  workIds?: number[];
+  sentiment?: "positive" | "negative";
+}
@@ -0,0 +1,33 @@
+// What an import does with a row matching a stored record by its natural key
+export type ConflictPolicy = "error" | "skip" | "update";
+
+export const CONFLICT_POLICIES: { value: ConflictPolicy; label: string }[] = [
+  { value: "error", label: "Reject rows matching existing records" },
+  { value: "skip", label: "Skip rows matching existing records" },
+  { value: "update", label: "Update existing records" },
+];
+
+export type ImportEntity = "writers" | "works" | "opinions";
+
+// A problem with one row; row is the line of the file, the header being line 1,
+// and field is empty when the row as a whole breaks a rule
+export interface ImportError {
+  row: number;
+  field: string;
+  message: string;
+}
+
+export interface ImportReport {
+  dry_run: boolean;
+  applied: boolean;
+  rows: number;
+  created: number;
+  updated: number;
+  skipped: number;
+  errors: ImportError[];
+}
+
+export interface ImportOptions {
+  dryRun: boolean;
+  onConflict: ConflictPolicy;
+}
@@ -1,27 +1,103 @@
+import type { HighlightSegment } from "@/types/search";
+
+// Sentiment grades run from -2 (scathing) to +2 (glowing); "mixed" marks a
+// statement that praises and condemns at once.
+export type SentimentGrade = "-2" | "-1" | "0" | "+1" | "+2" | "mixed";
+
+export const SENTIMENT_GRADES: { value: SentimentGrade; label: string }[] = [
+  { value: "+2", label: "Very positive" },
+  { value: "+1", label: "Positive" },
+  { value: "0", label: "Neutral" },
+  { value: "mixed", label: "Mixed" },
+  { value: "-1", label: "Negative" },
+  { value: "-2", label: "Very negative" },
+];
+
+// An opinion is about either a work or a writer as a whole; target_type
+// tells which of work_id and target_writer_id is set.
+export type OpinionTargetType = "work" | "writer";
+
+// A translation of a quote, crediting the published translation it is
+// taken from if known.
+export interface Translation {
+  // BCP 47 language tag, such as "en" or "pt-BR"
+  language: string;
+  text: string;
+  translator: string | null;
+  source: string | null;
+}
+
+// The quote in the language the reader asked for with the lang parameter or
+// Accept-Language, or the original when no translation matches.
+export interface LocalizedQuote {
+  language: string | null;
+  quote: string;
+  translator: string | null;
+  source: string | null;
+}
+
 export interface Opinion {
+  id: number;
   writer_id: number;
-  work_id: number;
+  target_type: OpinionTargetType;
+  work_id: number | null;
+  target_writer_id: number | null;
+  sentiment_grade: SentimentGrade;
+  // True for grades above the middle of the scale; kept for older clients
   sentiment: boolean;
   quote: string;
   source: string;
+  // Catalogued source the quote is taken from, if linked
+  source_id: number | null;
   page: string | null;
   statement_year: number | null;
+  // Language of the original quote, null when not recorded
+  language: string | null;
+  translations: Translation[];
+  localized: LocalizedQuote;
+}
+
+// An opinion found by a full-text search of quotes, with the part of the
+// quote that matched and its relevance from 0 towards 1
+export interface QuoteMatch extends Opinion {
+  snippet: HighlightSegment[];
+  rank: number;
 }
 
+// Translations require the language of the original quote.
+export interface TranslationRequest {
+  language: string;
+  text: string;
+  translator?: string | null;
+  source?: string | null;
+}
+
+// Exactly one of work_id and target_writer_id must be given.
 export interface CreateOpinionRequest {
   writer_id: number;
-  work_id: number;
-  sentiment: boolean;
+  work_id?: number;
+  target_writer_id?: number;
+  sentiment_grade: SentimentGrade;
   quote: string;
+  // May be left empty when source_id names a catalogued source
   source: string;
+  source_id?: number;
   page?: string | null;
   statement_year?: number | null;
+  language?: string;
+  translations?: TranslationRequest[];
 }
 
 export interface UpdateOpinionRequest {
-  sentiment: boolean;
+  sentiment_grade: SentimentGrade;
   quote: string;
+  // May be left empty when source_id names a catalogued source
   source: string;
+  // Left out, the catalogued source is kept; null unlinks it
+  source_id?: number | null;
   page?: string | null;
   statement_year?: number | null;
+  // Left out, the recorded language and translations are kept
+  language?: string;
+  translations?: TranslationRequest[];
 }
@@ -0,0 +1,9 @@
+// One page of a list endpoint; next_cursor fetches the following page and is null on the last
+export interface Page<T> {
+  items: T[];
+  total: number;
+  next_cursor: string | null;
+}
+
+// The most items the list endpoints return a page
+export const MAX_PAGE_SIZE = 100;
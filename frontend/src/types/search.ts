@@ -0,0 +1,16 @@
+export type SearchHitType = "writer" | "work" | "opinion";
+
+// A run of the matched field's text; match marks the words the query was found in
+export interface HighlightSegment {
+  text: string;
+  match: boolean;
+}
+
+export interface SearchHit {
+  type: SearchHitType;
+  id: number;
+  label: string;
+  field: string;
+  highlight: HighlightSegment[];
+  score: number;
+}
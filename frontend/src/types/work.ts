@@ -1,15 +1,32 @@
 export interface Work {
   id: number;
   title: string;
-  author_id: number;
+  author_ids: number[];
+  // First of author_ids, null for an anonymous work
+  author_id: number | null;
+  publication_year?: number | null;
+  genre?: string | null;
+  original_language?: string | null;
+  original_title?: string | null;
 }
 
 export interface CreateWorkRequest {
   title: string;
-  author_id: number;
+  author_ids?: number[];
+  author_id?: number;
+  publication_year?: number;
+  genre?: string;
+  original_language?: string;
+  original_title?: string;
 }
 
+// Details left out of an update are kept; null clears them
 export interface UpdateWorkRequest {
   title: string;
-  author_id: number;
+  author_ids?: number[];
+  author_id?: number;
+  publication_year?: number | null;
+  genre?: string | null;
+  original_language?: string | null;
+  original_title?: string | null;
 }
@@ -4,6 +4,22 @@ export interface Writer {
   birth_year: number;
   death_year: number | null;
   bio: string | null;
+  // Set on search results: the alias the writer was found by, if any
+  matched_alias?: WriterAlias | null;
+}
+
+export type AliasKind = "pseudonym" | "birth_name" | "transliteration" | "native_script";
+
+export interface WriterAlias {
+  id: number;
+  writer_id: number;
+  name: string;
+  kind: AliasKind;
+}
+
+export interface WriterAliasRequest {
+  name: string;
+  kind: AliasKind;
 }
 
 export interface CreateWriterRequest {